@@ -0,0 +1,235 @@
+package main
+
+import (
+	"app/internal"
+	"app/internal/application"
+	"app/platform/web/auth"
+	"app/platform/web/ratelimit"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// configFromEnv returns the configuration of the application read from the environment variables.
+// - a variable left unset keeps the default of the application
+// - a malformed variable is an error, the application must not start with a configuration it misread
+//
+// The variables are:
+//   - ADDR: address to listen, e.g. :8080
+//   - LOW_STOCK_WEBHOOK_URL: url the low stock alerts are posted to
+//   - IDEMPOTENCY_TTL: time the responses of the requests sent with an idempotency key are replayed, e.g. 24h
+//   - AUTH_API_KEYS: JSON array of api keys, e.g. [{"key":"k","subject":"ci","role":"operator","warehouses":[1],"tenant":"acme"}]
+//   - AUTH_JWT_SECRET: shared secret of the HS256 tokens
+//   - AUTH_JWT_PUBLIC_KEY_FILE: PEM file of the public key of the RS256 tokens
+//   - AUTH_JWT_KEY_ID: kid of the tokens signed with the keys above
+//   - AUTH_JWT_ISSUER, AUTH_JWT_AUDIENCE: required iss and aud claims of the tokens
+//   - AUTH_JWT_LEEWAY: clock skew tolerated when checking the time claims of the tokens, e.g. 30s
+//   - RATE_LIMIT_RATE, RATE_LIMIT_BURST: limit of the requests of a client, per second and at once
+//   - RATE_LIMIT_IP_RATE, RATE_LIMIT_IP_BURST: limit of the requests of an ip, per second and at once
+//   - RATE_LIMIT_GROUPS: JSON array of limits of groups of routes, e.g. [{"method":"POST","pattern":"/products/*","rate":1,"burst":5}]
+func configFromEnv() (cfg *application.ConfigApplicationSql, err error) {
+	cfg = &application.ConfigApplicationSql{
+		Addr:               os.Getenv("ADDR"),
+		LowStockWebhookURL: os.Getenv("LOW_STOCK_WEBHOOK_URL"),
+	}
+	if cfg.LowStockWebhookURL != "" {
+		u, e := url.Parse(cfg.LowStockWebhookURL)
+		if e != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			err = errors.New("config: LOW_STOCK_WEBHOOK_URL: must be an absolute http url")
+			return
+		}
+	}
+	cfg.IdempotencyTTL, err = envDuration("IDEMPOTENCY_TTL")
+	if err != nil {
+		return
+	}
+
+	cfg.Auth, err = authFromEnv()
+	if err != nil {
+		return
+	}
+	cfg.RateLimit, err = rateLimitFromEnv()
+	if err != nil {
+		return
+	}
+	return
+}
+
+// apiKeyJSON is an api key of AUTH_API_KEYS.
+type apiKeyJSON struct {
+	Key        string `json:"key"`
+	Subject    string `json:"subject"`
+	Role       string `json:"role"`
+	Warehouses []int  `json:"warehouses"`
+	Tenant     string `json:"tenant"`
+}
+
+// authFromEnv returns the configuration of the authentication, nil if no key is set.
+func authFromEnv() (cfg *auth.Config, err error) {
+	c := auth.Config{
+		Issuer:   os.Getenv("AUTH_JWT_ISSUER"),
+		Audience: os.Getenv("AUTH_JWT_AUDIENCE"),
+	}
+	c.Leeway, err = envDuration("AUTH_JWT_LEEWAY")
+	if err != nil {
+		return
+	}
+
+	// api keys
+	var keys []apiKeyJSON
+	err = envJSON("AUTH_API_KEYS", &keys)
+	if err != nil {
+		return
+	}
+	seen := make(map[string]bool, len(keys))
+	for i, k := range keys {
+		switch {
+		case k.Key == "" || k.Subject == "":
+			err = fmt.Errorf("config: AUTH_API_KEYS: key %d: key and subject are required", i)
+		case seen[k.Key]:
+			err = fmt.Errorf("config: AUTH_API_KEYS: key %d: key of subject %s repeated", i, k.Subject)
+		case !slices.Contains(auth.Roles, k.Role):
+			err = fmt.Errorf("config: AUTH_API_KEYS: key %d: unknown role %q", i, k.Role)
+		case k.Tenant != "" && !internal.TenantValid(k.Tenant):
+			err = fmt.Errorf("config: AUTH_API_KEYS: key %d: invalid tenant %q", i, k.Tenant)
+		}
+		if err != nil {
+			return
+		}
+		seen[k.Key] = true
+		c.APIKeys = append(c.APIKeys, auth.APIKey{Key: k.Key, Subject: k.Subject, Role: k.Role, Warehouses: k.Warehouses, Tenant: k.Tenant})
+	}
+
+	// jwt keys
+	kid := os.Getenv("AUTH_JWT_KEY_ID")
+	if secret := os.Getenv("AUTH_JWT_SECRET"); secret != "" {
+		c.JWTKeys = append(c.JWTKeys, auth.JWTKey{Id: kid, Algorithm: auth.AlgorithmHS256, Secret: []byte(secret)})
+	}
+	if path := os.Getenv("AUTH_JWT_PUBLIC_KEY_FILE"); path != "" {
+		data, e := os.ReadFile(path)
+		if e != nil {
+			err = fmt.Errorf("config: AUTH_JWT_PUBLIC_KEY_FILE: %w", e)
+			return
+		}
+		pub, e := auth.ParseRSAPublicKeyPEM(data)
+		if e != nil {
+			err = fmt.Errorf("config: AUTH_JWT_PUBLIC_KEY_FILE: %w", e)
+			return
+		}
+		c.JWTKeys = append(c.JWTKeys, auth.JWTKey{Id: kid, Algorithm: auth.AlgorithmRS256, PublicKey: pub})
+	}
+
+	if len(c.APIKeys) == 0 && len(c.JWTKeys) == 0 {
+		return
+	}
+	cfg = &c
+	return
+}
+
+// groupJSON is a group of routes of RATE_LIMIT_GROUPS.
+type groupJSON struct {
+	Name    string  `json:"name"`
+	Method  string  `json:"method"`
+	Pattern string  `json:"pattern"`
+	Rate    float64 `json:"rate"`
+	Burst   int     `json:"burst"`
+}
+
+// rateLimitFromEnv returns the configuration of the rate limiting, nil if no limit is set.
+func rateLimitFromEnv() (cfg *ratelimit.Config, err error) {
+	var c ratelimit.Config
+	c.Default, err = envLimit("RATE_LIMIT_RATE", "RATE_LIMIT_BURST")
+	if err != nil {
+		return
+	}
+	c.IP, err = envLimit("RATE_LIMIT_IP_RATE", "RATE_LIMIT_IP_BURST")
+	if err != nil {
+		return
+	}
+
+	var groups []groupJSON
+	err = envJSON("RATE_LIMIT_GROUPS", &groups)
+	if err != nil {
+		return
+	}
+	for i, g := range groups {
+		switch {
+		case g.Pattern == "":
+			err = fmt.Errorf("config: RATE_LIMIT_GROUPS: group %d: pattern is required", i)
+		case g.Rate <= 0 || g.Burst < 0:
+			err = fmt.Errorf("config: RATE_LIMIT_GROUPS: group %d: rate must be positive and burst non negative", i)
+		}
+		if err != nil {
+			return
+		}
+		c.Groups = append(c.Groups, ratelimit.Group{Name: g.Name, Method: g.Method, Pattern: g.Pattern, Limit: ratelimit.Limit{Rate: g.Rate, Burst: g.Burst}})
+	}
+
+	if c.Default.Rate == 0 && c.IP.Rate == 0 && len(c.Groups) == 0 {
+		return
+	}
+	cfg = &c
+	return
+}
+
+// envLimit returns the limit of a pair of variables, the rate per second and the burst.
+// - a burst without rate is an error, the limit would be ignored
+func envLimit(nameRate, nameBurst string) (l ratelimit.Limit, err error) {
+	if v := os.Getenv(nameRate); v != "" {
+		l.Rate, err = strconv.ParseFloat(v, 64)
+		if err != nil || !(l.Rate > 0) || math.IsInf(l.Rate, 0) {
+			err = fmt.Errorf("config: %s: must be a positive number", nameRate)
+			return
+		}
+	}
+	if v := os.Getenv(nameBurst); v != "" {
+		l.Burst, err = strconv.Atoi(v)
+		if err != nil || l.Burst < 0 {
+			err = fmt.Errorf("config: %s: must be a non negative integer", nameBurst)
+			return
+		}
+		if l.Rate == 0 {
+			err = fmt.Errorf("config: %s: set without %s", nameBurst, nameRate)
+			return
+		}
+	}
+	return
+}
+
+// envDuration returns the duration of a variable, 0 if it is unset.
+func envDuration(name string) (d time.Duration, err error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return
+	}
+	d, err = time.ParseDuration(v)
+	if err != nil || d < 0 {
+		err = fmt.Errorf("config: %s: must be a non negative duration, e.g. 30s", name)
+		return
+	}
+	return
+}
+
+// envJSON decodes the JSON of a variable into v, left untouched if it is unset.
+// - unknown fields are an error, a misspelled one would be silently ignored
+func envJSON(name string, v any) (err error) {
+	s := os.Getenv(name)
+	if s == "" {
+		return
+	}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.DisallowUnknownFields()
+	err = dec.Decode(v)
+	if err != nil {
+		err = fmt.Errorf("config: %s: %w", name, err)
+		return
+	}
+	return
+}
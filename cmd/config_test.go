@@ -0,0 +1,101 @@
+package main
+
+import (
+	"app/platform/web/auth"
+	"app/platform/web/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for configFromEnv
+func TestConfigFromEnv(t *testing.T) {
+	t.Run("success - nothing set, defaults of the application", func(t *testing.T) {
+		// act
+		cfg, err := configFromEnv()
+
+		// assert
+		require.NoError(t, err)
+		require.Empty(t, cfg.Addr)
+		require.Nil(t, cfg.Auth)
+		require.Nil(t, cfg.RateLimit)
+		require.Zero(t, cfg.IdempotencyTTL)
+	})
+
+	t.Run("success - every variable set", func(t *testing.T) {
+		// arrange
+		t.Setenv("ADDR", ":9090")
+		t.Setenv("LOW_STOCK_WEBHOOK_URL", "https://example.com/alerts")
+		t.Setenv("IDEMPOTENCY_TTL", "1h")
+		t.Setenv("AUTH_API_KEYS", `[{"key":"k1","subject":"ci","role":"operator","warehouses":[1,2],"tenant":"acme"},{"key":"k2","subject":"ops","role":"admin"}]`)
+		t.Setenv("AUTH_JWT_SECRET", "s3cret")
+		t.Setenv("AUTH_JWT_KEY_ID", "main")
+		t.Setenv("AUTH_JWT_ISSUER", "https://issuer.example.com")
+		t.Setenv("AUTH_JWT_AUDIENCE", "inventory")
+		t.Setenv("AUTH_JWT_LEEWAY", "30s")
+		t.Setenv("RATE_LIMIT_RATE", "10")
+		t.Setenv("RATE_LIMIT_BURST", "20")
+		t.Setenv("RATE_LIMIT_IP_RATE", "0.5")
+		t.Setenv("RATE_LIMIT_GROUPS", `[{"name":"imports","method":"POST","pattern":"/products/import","rate":0.1,"burst":1}]`)
+
+		// act
+		cfg, err := configFromEnv()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, ":9090", cfg.Addr)
+		require.Equal(t, "https://example.com/alerts", cfg.LowStockWebhookURL)
+		require.Equal(t, time.Hour, cfg.IdempotencyTTL)
+		require.Equal(t, &auth.Config{
+			APIKeys: []auth.APIKey{
+				{Key: "k1", Subject: "ci", Role: auth.RoleOperator, Warehouses: []int{1, 2}, Tenant: "acme"},
+				{Key: "k2", Subject: "ops", Role: auth.RoleAdmin},
+			},
+			JWTKeys:  []auth.JWTKey{{Id: "main", Algorithm: auth.AlgorithmHS256, Secret: []byte("s3cret")}},
+			Issuer:   "https://issuer.example.com",
+			Audience: "inventory",
+			Leeway:   30 * time.Second,
+		}, cfg.Auth)
+		require.Equal(t, &ratelimit.Config{
+			Default: ratelimit.Limit{Rate: 10, Burst: 20},
+			IP:      ratelimit.Limit{Rate: 0.5},
+			Groups:  []ratelimit.Group{{Name: "imports", Method: "POST", Pattern: "/products/import", Limit: ratelimit.Limit{Rate: 0.1, Burst: 1}}},
+		}, cfg.RateLimit)
+	})
+
+	malformed := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{"error - low stock webhook url not absolute", "LOW_STOCK_WEBHOOK_URL", "/alerts"},
+		{"error - idempotency ttl not a duration", "IDEMPOTENCY_TTL", "1 day"},
+		{"error - api keys not JSON", "AUTH_API_KEYS", `[{"key":"k1"`},
+		{"error - api key with an unknown field", "AUTH_API_KEYS", `[{"key":"k1","subject":"ci","role":"admin","tenants":["acme"]}]`},
+		{"error - api key without subject", "AUTH_API_KEYS", `[{"key":"k1","role":"admin"}]`},
+		{"error - api key repeated", "AUTH_API_KEYS", `[{"key":"k1","subject":"a","role":"admin"},{"key":"k1","subject":"b","role":"viewer"}]`},
+		{"error - api key with an unknown role", "AUTH_API_KEYS", `[{"key":"k1","subject":"ci","role":"root"}]`},
+		{"error - api key with an invalid tenant", "AUTH_API_KEYS", `[{"key":"k1","subject":"ci","role":"admin","tenant":"*"}]`},
+		{"error - public key file missing", "AUTH_JWT_PUBLIC_KEY_FILE", "/nonexistent/key.pem"},
+		{"error - leeway negative", "AUTH_JWT_LEEWAY", "-1s"},
+		{"error - rate not a number", "RATE_LIMIT_RATE", "ten"},
+		{"error - rate not positive", "RATE_LIMIT_IP_RATE", "0"},
+		{"error - rate not finite", "RATE_LIMIT_RATE", "NaN"},
+		{"error - burst without rate", "RATE_LIMIT_BURST", "5"},
+		{"error - group without pattern", "RATE_LIMIT_GROUPS", `[{"rate":1}]`},
+		{"error - group without rate", "RATE_LIMIT_GROUPS", `[{"pattern":"/products/*"}]`},
+	}
+	for _, c := range malformed {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			t.Setenv(c.key, c.value)
+
+			// act
+			_, err := configFromEnv()
+
+			// assert
+			require.ErrorContains(t, err, c.key)
+		})
+	}
+}
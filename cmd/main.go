@@ -7,11 +7,15 @@ import (
 
 func main() {
 	// env
-	// ...
+	cfg, err := configFromEnv()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 
 	// app
 	// - config
-	app := application.NewApplicationSql("")
+	app := application.NewApplicationSql(cfg)
 	// - tear down
 	defer app.TearDown()
 	// - set up
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"app/internal"
+	"app/internal/job"
+	"app/internal/repository"
+	"app/internal/store"
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// purge permanently removes the products and warehouses soft deleted beyond a retention period.
+// - by default it purges the mysql database, set -products and -warehouses to purge json stores instead
+func main() {
+	// env
+	retention := flag.Duration("retention", 30*24*time.Hour, "time a soft deleted row is kept before being purged")
+	filePathProducts := flag.String("products", "", "json store of products to purge instead of the database")
+	filePathWarehouses := flag.String("warehouses", "", "json store of warehouses to purge instead of the database")
+	filePathAudit := flag.String("audit", "audit.jsonl", "audit log of the json stores")
+	flag.Parse()
+
+	// dependencies
+	var rp internal.RepositoryProduct
+	var rw internal.RepositoryWarehouse
+	var ra internal.RepositoryAudit
+	switch {
+	case *filePathProducts != "" || *filePathWarehouses != "":
+		stProduct := store.NewStoreProductJSON(*filePathProducts)
+		stWarehouse := store.NewStoreWarehouseJSON(*filePathWarehouses)
+		ra = repository.NewRepositoryAuditFile(*filePathAudit)
+		if *filePathProducts != "" {
+			rp = repository.NewRepositoryProductStore(stProduct)
+		}
+		if *filePathWarehouses != "" {
+			rw = repository.NewRepositoryWarehouseStore(stWarehouse, stProduct)
+		}
+	default:
+		config := mysql.Config{
+			User:      "user1",
+			Passwd:    "secret_password",
+			Addr:      "localhost:3306",
+			Net:       "tcp",
+			DBName:    "my_db",
+			ParseTime: true,
+		}
+		db, err := sql.Open("mysql", config.FormatDSN())
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		defer db.Close()
+		rp = repository.NewRepositoryProductMySql(db)
+		rw = repository.NewRepositoryWarehouseMySql(db)
+		ra = repository.NewRepositoryAuditMysql(db)
+	}
+	// - purges are recorded to the audit log
+	if rp != nil {
+		rp = repository.NewRepositoryProductAudited(rp, ra)
+	}
+	if rw != nil {
+		rw = repository.NewRepositoryWarehouseAudited(rw, ra)
+	}
+
+	// purge
+	// - every tenant is purged, one after the other, as the purge job does
+	ctx := context.Background()
+	before := time.Now().Add(-*retention)
+	if rp != nil {
+		n, err := job.NewJobPurge(rp, nil, *retention).Purge(ctx, before)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("purged %d products deleted before %s\n", n, before.Format(time.RFC3339))
+	}
+	if rw != nil {
+		n, err := job.NewJobPurge(nil, rw, *retention).Purge(ctx, before)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("purged %d warehouses deleted before %s\n", n, before.Format(time.RFC3339))
+	}
+}
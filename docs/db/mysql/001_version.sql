@@ -0,0 +1,3 @@
+-- optimistic concurrency control: every update increments the version of the row
+ALTER TABLE `products` ADD COLUMN `version` INT NOT NULL DEFAULT 1;
+ALTER TABLE `warehouses` ADD COLUMN `version` INT NOT NULL DEFAULT 1;
@@ -0,0 +1,5 @@
+-- soft delete: deleted rows keep their data until they are purged
+ALTER TABLE `products` ADD COLUMN `deleted_at` DATETIME NULL DEFAULT NULL;
+ALTER TABLE `products` ADD INDEX `idx_products_deleted_at` (`deleted_at`);
+ALTER TABLE `warehouses` ADD COLUMN `deleted_at` DATETIME NULL DEFAULT NULL;
+ALTER TABLE `warehouses` ADD INDEX `idx_warehouses_deleted_at` (`deleted_at`);
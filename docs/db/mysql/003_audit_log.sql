@@ -0,0 +1,13 @@
+-- audit log: one row per mutation of a product or warehouse
+CREATE TABLE `audit_log` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `entity` VARCHAR(32) NOT NULL,
+    `entity_id` INT NOT NULL,
+    `operation` VARCHAR(32) NOT NULL,
+    `actor` VARCHAR(255) NOT NULL,
+    `created_at` DATETIME(6) NOT NULL,
+    `snapshot_before` JSON NULL,
+    `snapshot_after` JSON NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_audit_log_entity` (`entity`, `entity_id`)
+);
@@ -0,0 +1,11 @@
+-- price history: every price of a product with the date it takes effect, scheduled ones included
+CREATE TABLE `product_prices` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `id_product` INT NOT NULL,
+    `price` DOUBLE NOT NULL,
+    `effective_at` DATETIME NOT NULL,
+    `status` VARCHAR(16) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_product_prices_product` (`id_product`, `effective_at`),
+    INDEX `idx_product_prices_due` (`status`, `effective_at`)
+);
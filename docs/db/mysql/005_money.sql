@@ -0,0 +1,5 @@
+-- money: prices are exact decimals in a currency instead of floating point numbers
+ALTER TABLE `products` MODIFY COLUMN `price` DECIMAL(19, 2) NOT NULL;
+ALTER TABLE `products` ADD COLUMN `currency` CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE `product_prices` MODIFY COLUMN `price` DECIMAL(19, 2) NOT NULL;
+ALTER TABLE `product_prices` ADD COLUMN `currency` CHAR(3) NOT NULL DEFAULT 'USD';
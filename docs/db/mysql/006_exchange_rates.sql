@@ -0,0 +1,6 @@
+-- exchange rates: how many units of a currency a unit of the base currency (USD) is worth
+CREATE TABLE `exchange_rates` (
+    `currency` CHAR(3) NOT NULL,
+    `rate` DECIMAL(19, 8) NOT NULL,
+    PRIMARY KEY (`currency`)
+);
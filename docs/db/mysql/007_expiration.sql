@@ -0,0 +1,3 @@
+-- expiration: products are looked up by expiration, alone or within a warehouse
+ALTER TABLE `products` ADD INDEX `idx_products_expiration` (`expiration`);
+ALTER TABLE `products` ADD INDEX `idx_products_warehouse_expiration` (`id_warehouse`, `expiration`);
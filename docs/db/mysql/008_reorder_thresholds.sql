@@ -0,0 +1,7 @@
+-- reorder thresholds: the quantity below which a product, or every product of a warehouse, is low on stock
+CREATE TABLE `reorder_thresholds` (
+    `scope` VARCHAR(16) NOT NULL,
+    `scope_id` INT NOT NULL,
+    `quantity` INT NOT NULL,
+    PRIMARY KEY (`scope`, `scope_id`)
+);
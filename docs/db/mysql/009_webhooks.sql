@@ -0,0 +1,21 @@
+-- webhooks: urls the product and warehouse events are delivered to
+CREATE TABLE `webhooks` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `url` VARCHAR(2048) NOT NULL,
+    `secret` VARCHAR(255) NOT NULL,
+    `events` JSON NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    PRIMARY KEY (`id`)
+);
+
+-- webhook dead letters: events that could not be delivered after every retry
+CREATE TABLE `webhook_dead_letters` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `id_webhook` INT NOT NULL,
+    `event` JSON NOT NULL,
+    `attempts` INT NOT NULL,
+    `last_error` TEXT NOT NULL,
+    `failed_at` DATETIME NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_webhook_dead_letters_webhook` (`id_webhook`)
+);
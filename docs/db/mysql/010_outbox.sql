@@ -0,0 +1,9 @@
+-- outbox: events appended in the same transaction as their mutation, waiting to be published
+CREATE TABLE `outbox` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `event` JSON NOT NULL,
+    `created_at` DATETIME(6) NOT NULL,
+    `published_at` DATETIME(6) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_outbox_published_at` (`published_at`)
+);
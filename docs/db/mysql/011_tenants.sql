@@ -0,0 +1,20 @@
+-- tenants: every row belongs to a tenant, existing rows to the default one
+-- - a reorder threshold and an exchange rate are unique within their tenant
+ALTER TABLE `products` ADD COLUMN `tenant_id` VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE `products` ADD INDEX `idx_products_tenant_id` (`tenant_id`);
+ALTER TABLE `warehouses` ADD COLUMN `tenant_id` VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE `warehouses` ADD INDEX `idx_warehouses_tenant_id` (`tenant_id`);
+ALTER TABLE `audit_log` ADD COLUMN `tenant_id` VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE `audit_log` ADD INDEX `idx_audit_log_tenant_entity` (`tenant_id`, `entity`, `entity_id`);
+ALTER TABLE `webhooks` ADD COLUMN `tenant_id` VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE `webhooks` ADD INDEX `idx_webhooks_tenant_id` (`tenant_id`);
+ALTER TABLE `webhook_dead_letters` ADD COLUMN `tenant_id` VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE `webhook_dead_letters` ADD INDEX `idx_webhook_dead_letters_tenant_id` (`tenant_id`);
+ALTER TABLE `product_prices` ADD COLUMN `tenant_id` VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE `product_prices` ADD INDEX `idx_product_prices_tenant_id` (`tenant_id`);
+ALTER TABLE `reorder_thresholds` ADD COLUMN `tenant_id` VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE `reorder_thresholds` DROP PRIMARY KEY, ADD PRIMARY KEY (`tenant_id`, `scope`, `scope_id`);
+ALTER TABLE `outbox` ADD COLUMN `tenant_id` VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE `outbox` ADD INDEX `idx_outbox_tenant_id` (`tenant_id`);
+ALTER TABLE `exchange_rates` ADD COLUMN `tenant_id` VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE `exchange_rates` DROP PRIMARY KEY, ADD PRIMARY KEY (`tenant_id`, `currency`);
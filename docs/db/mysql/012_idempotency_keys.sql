@@ -0,0 +1,13 @@
+-- idempotency keys: the responses of the requests sent with an idempotency key, replayed to their retries until they expire
+CREATE TABLE `idempotency_keys` (
+    `key` CHAR(64) NOT NULL,
+    `tenant_id` VARCHAR(64) NOT NULL DEFAULT 'default',
+    `body_hash` CHAR(64) NOT NULL,
+    `status_code` INT NOT NULL DEFAULT 0,
+    `header` JSON NULL,
+    `body` MEDIUMBLOB NULL,
+    `created_at` DATETIME(6) NOT NULL,
+    `expires_at` DATETIME(6) NOT NULL,
+    PRIMARY KEY (`tenant_id`, `key`),
+    INDEX `idx_idempotency_keys_expires_at` (`expires_at`)
+);
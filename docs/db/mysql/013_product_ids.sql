@@ -0,0 +1,2 @@
+-- product ids: assigned by the database, concurrent inserts never get the same one
+ALTER TABLE `products` MODIFY COLUMN `id` INT NOT NULL AUTO_INCREMENT;
@@ -0,0 +1,23 @@
+package internal
+
+import "context"
+
+// ActorAnonymous is the actor of a request that does not identify who performs it.
+const ActorAnonymous = "anonymous"
+
+// actorKey is the context key of the actor.
+type actorKey struct{}
+
+// ContextWithActor returns a copy of ctx carrying the actor performing the request.
+func ContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor carried by ctx, ActorAnonymous if there is none.
+func ActorFromContext(ctx context.Context) (actor string) {
+	actor, ok := ctx.Value(actorKey{}).(string)
+	if !ok || actor == "" {
+		actor = ActorAnonymous
+	}
+	return
+}
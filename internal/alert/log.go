@@ -0,0 +1,34 @@
+package alert
+
+import (
+	"app/internal"
+	"context"
+	"log"
+)
+
+// NewAlertSinkLog creates a new sink writing the alerts to a logger.
+// - a nil logger writes to the standard logger
+func NewAlertSinkLog(l *log.Logger) (s *AlertSinkLog) {
+	// default config
+	defaultLogger := log.Default()
+	if l != nil {
+		defaultLogger = l
+	}
+
+	s = &AlertSinkLog{
+		l: defaultLogger,
+	}
+	return
+}
+
+// AlertSinkLog is a sink writing the alerts to a logger.
+type AlertSinkLog struct {
+	// l is the logger.
+	l *log.Logger
+}
+
+// Alert writes a low stock alert to the logger.
+func (s *AlertSinkLog) Alert(ctx context.Context, a internal.LowStockAlert) (err error) {
+	s.l.Printf("low stock: product %d %q of warehouse %d has %d left (threshold %d)", a.Product.Id, a.Product.Name, a.Product.WarehouseId, a.Product.Quantity, a.Threshold)
+	return
+}
@@ -0,0 +1,88 @@
+package alert
+
+import (
+	"app/internal"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// EventLowStock is the event of a low stock alert delivered to a webhook.
+const EventLowStock = "product.low_stock"
+
+// NewAlertSinkWebhook creates a new sink posting the alerts to a webhook url.
+// - a nil client uses a client timing out after 5 seconds
+func NewAlertSinkWebhook(url string, client *http.Client) (s *AlertSinkWebhook) {
+	// default config
+	defaultClient := &http.Client{Timeout: 5 * time.Second}
+	if client != nil {
+		defaultClient = client
+	}
+
+	s = &AlertSinkWebhook{
+		url:    url,
+		client: defaultClient,
+	}
+	return
+}
+
+// AlertSinkWebhook is a sink posting the alerts to a webhook url.
+type AlertSinkWebhook struct {
+	// url is the url of the webhook.
+	url string
+	// client is the http client posting the alerts.
+	client *http.Client
+}
+
+// LowStockAlertJSON is a low stock alert in JSON format.
+type LowStockAlertJSON struct {
+	Event       string `json:"event"`
+	ProductId   int    `json:"product_id"`
+	Name        string `json:"name"`
+	CodeValue   string `json:"code_value"`
+	WarehouseId int    `json:"warehouse_id"`
+	Quantity    int    `json:"quantity"`
+	Threshold   int    `json:"threshold"`
+	Timestamp   string `json:"timestamp"`
+}
+
+// Alert posts a low stock alert to the webhook.
+// - a response other than 2xx is an error
+func (s *AlertSinkWebhook) Alert(ctx context.Context, a internal.LowStockAlert) (err error) {
+	// serialize
+	b, err := json.Marshal(LowStockAlertJSON{
+		Event:       EventLowStock,
+		ProductId:   a.Product.Id,
+		Name:        a.Product.Name,
+		CodeValue:   a.Product.CodeValue,
+		WarehouseId: a.Product.WarehouseId,
+		Quantity:    a.Product.Quantity,
+		Threshold:   a.Threshold,
+		Timestamp:   a.Timestamp.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return
+	}
+
+	// post
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := s.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		err = fmt.Errorf("alert: webhook responded %d", res.StatusCode)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,71 @@
+package alert_test
+
+import (
+	"app/internal"
+	"app/internal/alert"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for AlertSinkWebhook.Alert
+func TestAlertSinkWebhook_Alert(t *testing.T) {
+	a := internal.LowStockAlert{
+		Product: internal.Product{
+			Id:                1,
+			ProductAttributes: internal.ProductAttributes{Name: "product 1", Quantity: 2, CodeValue: "code 1"},
+			WarehouseId:       3,
+		},
+		Threshold: 5,
+		Timestamp: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("success - alert posted", func(t *testing.T) {
+		// arrange
+		var received alert.LowStockAlertJSON
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+		s := alert.NewAlertSinkWebhook(srv.URL, nil)
+
+		// act
+		err := s.Alert(context.Background(), a)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, alert.LowStockAlertJSON{
+			Event:       alert.EventLowStock,
+			ProductId:   1,
+			Name:        "product 1",
+			CodeValue:   "code 1",
+			WarehouseId: 3,
+			Quantity:    2,
+			Threshold:   5,
+			Timestamp:   "2030-01-01T00:00:00Z",
+		}, received)
+	})
+
+	t.Run("fail - webhook responds an error", func(t *testing.T) {
+		// arrange
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+		s := alert.NewAlertSinkWebhook(srv.URL, nil)
+
+		// act
+		err := s.Alert(context.Background(), a)
+
+		// assert
+		require.EqualError(t, err, "alert: webhook responded 500")
+	})
+}
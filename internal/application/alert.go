@@ -0,0 +1,16 @@
+package application
+
+import (
+	"app/internal"
+	"app/internal/alert"
+)
+
+// newAlertSink returns the sink of the low stock alerts, a webhook if it has an url or else the log.
+func newAlertSink(webhookURL string) (s internal.AlertSink) {
+	if webhookURL != "" {
+		s = alert.NewAlertSinkWebhook(webhookURL, nil)
+		return
+	}
+	s = alert.NewAlertSinkLog(nil)
+	return
+}
@@ -1,28 +1,131 @@
 package application
 
 import (
+	"app/internal"
 	"app/internal/handler"
+	"app/internal/job"
 	"app/internal/repository"
 	"app/internal/store"
+	"app/internal/stream"
+	"app/internal/webhook"
+	"app/platform/web/auth"
+	"app/platform/web/logging"
+	"app/platform/web/metrics"
+	"app/platform/web/ratelimit"
+	"app/platform/web/tracing"
+	"context"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 )
 
+// ConfigApplicationDefault is the configuration of the default application.
+type ConfigApplicationDefault struct {
+	// Addr is the address to listen.
+	Addr string
+	// FilePathStore is the file path to store products.
+	FilePathStore string
+	// FilePathStoreWarehouse is the file path to store warehouses.
+	FilePathStoreWarehouse string
+	// FilePathStorePrice is the file path to store price changes.
+	FilePathStorePrice string
+	// FilePathStoreExchangeRate is the file path to store exchange rates.
+	FilePathStoreExchangeRate string
+	// FilePathStoreReorderThreshold is the file path to store reorder thresholds.
+	FilePathStoreReorderThreshold string
+	// FilePathStoreWebhook is the file path to store webhooks.
+	FilePathStoreWebhook string
+	// FilePathStoreDeadLetter is the file path to store the events that could not be delivered to the webhooks.
+	FilePathStoreDeadLetter string
+	// FilePathStoreOutbox is the file path to store the events waiting to be published.
+	FilePathStoreOutbox string
+	// FilePathAudit is the file path to the audit log.
+	FilePathAudit string
+	// LowStockWebhookURL is the url the low stock alerts are posted to, they are logged when it is empty.
+	LowStockWebhookURL string
+	// Webhook is the configuration of the delivery of the events to the webhooks.
+	Webhook *webhook.ConfigDispatcher
+	// EventStream is the configuration of the stream of the events.
+	EventStream *stream.ConfigBroker
+	// Auth is the configuration of the authentication of the requests, disabled without keys.
+	Auth *auth.Config
+	// RateLimit is the configuration of the rate limiting of the clients, disabled without limits.
+	RateLimit *ratelimit.Config
+	// IdempotencyTTL is the time the responses of the requests sent with an idempotency key are replayed, 24 hours if 0.
+	IdempotencyTTL time.Duration
+	// Log is the configuration of the logs, text on the standard error if nil.
+	Log *logging.Config
+	// Tracing is the configuration of the traces of the requests and the repositories, none exported if nil.
+	Tracing *tracing.Config
+	// Scheduler is the configuration of the background jobs.
+	Scheduler ConfigScheduler
+}
+
 // NewApplicationDefault creates a new default application.
-func NewApplicationDefault(addr, filePathStore string) (a *ApplicationDefault) {
+func NewApplicationDefault(cfg *ConfigApplicationDefault) (a *ApplicationDefault) {
 	// default config
 	defaultRouter := chi.NewRouter()
-	defaultAddr := ":8080"
-	if addr != "" {
-		defaultAddr = addr
+	defaultConfig := &ConfigApplicationDefault{
+		Addr:                          ":8080",
+		FilePathStore:                 "products.json",
+		FilePathStoreWarehouse:        "warehouses.json",
+		FilePathStorePrice:            "prices.json",
+		FilePathStoreExchangeRate:     "exchange_rates.json",
+		FilePathStoreReorderThreshold: "reorder_thresholds.json",
+		FilePathStoreWebhook:          "webhooks.json",
+		FilePathStoreDeadLetter:       "dead_letters.json",
+		FilePathStoreOutbox:           "outbox.json",
+		FilePathAudit:                 "audit.jsonl",
+	}
+	if cfg != nil {
+		if cfg.Addr != "" {
+			defaultConfig.Addr = cfg.Addr
+		}
+		if cfg.FilePathStore != "" {
+			defaultConfig.FilePathStore = cfg.FilePathStore
+		}
+		if cfg.FilePathStoreWarehouse != "" {
+			defaultConfig.FilePathStoreWarehouse = cfg.FilePathStoreWarehouse
+		}
+		if cfg.FilePathStorePrice != "" {
+			defaultConfig.FilePathStorePrice = cfg.FilePathStorePrice
+		}
+		if cfg.FilePathStoreExchangeRate != "" {
+			defaultConfig.FilePathStoreExchangeRate = cfg.FilePathStoreExchangeRate
+		}
+		if cfg.FilePathStoreReorderThreshold != "" {
+			defaultConfig.FilePathStoreReorderThreshold = cfg.FilePathStoreReorderThreshold
+		}
+		if cfg.FilePathStoreWebhook != "" {
+			defaultConfig.FilePathStoreWebhook = cfg.FilePathStoreWebhook
+		}
+		if cfg.FilePathStoreDeadLetter != "" {
+			defaultConfig.FilePathStoreDeadLetter = cfg.FilePathStoreDeadLetter
+		}
+		if cfg.FilePathStoreOutbox != "" {
+			defaultConfig.FilePathStoreOutbox = cfg.FilePathStoreOutbox
+		}
+		if cfg.FilePathAudit != "" {
+			defaultConfig.FilePathAudit = cfg.FilePathAudit
+		}
+		defaultConfig.LowStockWebhookURL = cfg.LowStockWebhookURL
+		defaultConfig.Webhook = cfg.Webhook
+		defaultConfig.EventStream = cfg.EventStream
+		defaultConfig.Auth = cfg.Auth
+		defaultConfig.RateLimit = cfg.RateLimit
+		defaultConfig.IdempotencyTTL = cfg.IdempotencyTTL
+		defaultConfig.Log = cfg.Log
+		defaultConfig.Tracing = cfg.Tracing
+		defaultConfig.Scheduler = cfg.Scheduler
 	}
+	defaultConfig.Scheduler = defaultConfig.Scheduler.withDefaults()
 
 	a = &ApplicationDefault{
-		rt:            defaultRouter,
-		addr:          defaultAddr,
-		filePathStore: filePathStore,
+		rt:  defaultRouter,
+		cfg: defaultConfig,
 	}
 	return
 }
@@ -31,51 +134,262 @@ func NewApplicationDefault(addr, filePathStore string) (a *ApplicationDefault) {
 type ApplicationDefault struct {
 	// rt is the router.
 	rt *chi.Mux
-	// addr is the address to listen.
-	addr string
-	// filePathStore is the file path to store.
-	filePathStore string
+	// cfg is the configuration of the application.
+	cfg *ConfigApplicationDefault
+	// sc is the scheduler running the background jobs.
+	sc *job.Scheduler
+	// dp is the dispatcher delivering the events to the webhooks.
+	dp *webhook.Dispatcher
+	// br is the broker streaming the events to the clients.
+	br *stream.Broker
+	// shutdownTracing flushes the spans not exported yet.
+	shutdownTracing func(ctx context.Context) error
 }
 
 // TearDown tears down the application.
 func (a *ApplicationDefault) TearDown() (err error) {
+	// stop background jobs
+	if a.sc != nil {
+		a.sc.Stop()
+	}
+	// - stopped last, so it gets the events of the jobs
+	if a.dp != nil {
+		a.dp.Stop()
+	}
+	// - ends the open streams
+	if a.br != nil {
+		a.br.Close()
+	}
+	// - flushes the spans of the requests served until now
+	if a.shutdownTracing != nil {
+		err = a.shutdownTracing(context.Background())
+	}
 	return
 }
 
 // SetUp sets up the application.
 func (a *ApplicationDefault) SetUp() (err error) {
 	// dependencies
+	// - logger, also the default one of the jobs and the repositories
+	logger := logging.NewLogger(a.cfg.Log)
+	slog.SetDefault(logger)
+	// - tracer provider, also the global one of the repositories
+	a.shutdownTracing, err = tracing.SetUp(a.cfg.Tracing)
+	if err != nil {
+		return
+	}
+	// - metrics of the requests, the repositories and the stores
+	mt := metrics.NewMetrics()
+	// - the calls to the repositories and the stores are measured and traced
+	obs := internal.Observers{mt, tracing.Observer{}}
 	// - store
-	st := store.NewStoreProductJSON(a.filePathStore)
+	st := store.NewStoreProductObserved(store.NewStoreProductJSON(a.cfg.FilePathStore), obs)
+	stWarehouse := store.NewStoreWarehouseObserved(store.NewStoreWarehouseJSON(a.cfg.FilePathStoreWarehouse), obs)
+	stPrice := store.NewStorePriceObserved(store.NewStorePriceJSON(a.cfg.FilePathStorePrice), obs)
+	stExchangeRate := store.NewStoreExchangeRateObserved(store.NewStoreExchangeRateJSON(a.cfg.FilePathStoreExchangeRate), obs)
+	stReorderThreshold := store.NewStoreReorderThresholdObserved(store.NewStoreReorderThresholdJSON(a.cfg.FilePathStoreReorderThreshold), obs)
+	stWebhook := store.NewStoreWebhookObserved(store.NewStoreWebhookJSON(a.cfg.FilePathStoreWebhook), obs)
+	stDeadLetter := store.NewStoreDeadLetterObserved(store.NewStoreDeadLetterJSON(a.cfg.FilePathStoreDeadLetter), obs)
+	stOutbox := store.NewStoreOutboxObserved(store.NewStoreOutboxJSON(a.cfg.FilePathStoreOutbox), obs)
+	// - alert
+	sink := newAlertSink(a.cfg.LowStockWebhookURL)
 	// - repository
-	rp := repository.NewRepositoryProductStore(st)
+	rpWebhook := repository.NewRepositoryWebhookStore(stWebhook)
+	// - the idempotency records are kept in memory, they only matter while the clients retry
+	rpIdempotency := repository.NewRepositoryIdempotencyStore(store.NewStoreIdempotencyMemory(nil))
+	rpDeadLetter := repository.NewRepositoryDeadLetterStore(stDeadLetter)
+	// - event
+	a.dp = webhook.NewDispatcher(rpWebhook, rpDeadLetter, a.cfg.Webhook)
+	a.br = stream.NewBroker(a.cfg.EventStream)
+	rpAudit := repository.NewRepositoryAuditFile(a.cfg.FilePathAudit)
+	// - mutations run in units of work appending their events to the outbox, and are recorded to the audit log
+	uowStore := repository.NewUnitOfWorkStore(st, stWarehouse, stPrice, stOutbox)
+	uow := repository.NewUnitOfWorkAudited(repository.NewUnitOfWorkOutboxed(uowStore), rpAudit)
+	// - price changes are recorded to the price history, in the units of work of the mutations
+	rpPrice := uowStore.Price()
+	// - quantities falling below their reorder threshold raise an alert
+	rpReorderThreshold := repository.NewRepositoryReorderThresholdStore(stReorderThreshold)
+	// - the calls to the products and the warehouses are observed
+	rp := repository.NewRepositoryProductObserved(repository.NewRepositoryProductStocked(repository.NewRepositoryProductPriced(repository.NewRepositoryProductTransactional(repository.NewRepositoryProductStore(st), uow), uow), rpReorderThreshold, sink), obs)
+	rpWarehouse := repository.NewRepositoryWarehouseObserved(repository.NewRepositoryWarehouseTransactional(repository.NewRepositoryWarehouseStore(stWarehouse, st), uow), obs)
+	rpExchangeRate := repository.NewRepositoryExchangeRateStore(stExchangeRate)
+	// - job
+	cfgScheduler := a.cfg.Scheduler
+	a.sc = job.NewScheduler()
+	// - relay the events of the outbox to the webhooks and the streams
+	a.sc.Add("outbox", cfgScheduler.IntervalOutbox, job.NewJobOutbox(uowStore.Outbox(), internal.Publishers{a.dp, a.br}, 0, 0))
+	// - apply scheduled prices once they are due
+	a.sc.Add("price", cfgScheduler.IntervalPrice, job.NewJobPrice(uow, rpPrice))
+	// - unpublish expired products
+	if cfgScheduler.UnpublishExpired {
+		a.sc.Add("expiration", cfgScheduler.IntervalExpiration, job.NewJobExpiration(rp))
+	}
+	// - report products low on stock
+	if cfgScheduler.CheckLowStock {
+		a.sc.Add("low_stock", cfgScheduler.IntervalLowStock, job.NewJobLowStock(rp, rpReorderThreshold, sink))
+	}
+	// - purge soft deleted rows beyond their retention
+	if cfgScheduler.PurgeRetention > 0 {
+		a.sc.Add("purge", cfgScheduler.IntervalPurge, job.NewJobPurge(rp, rpWarehouse, cfgScheduler.PurgeRetention))
+	}
+	// - remove the expired idempotency records
+	a.sc.Add("idempotency", cfgScheduler.IntervalIdempotency, job.NewJobIdempotency(rpIdempotency))
+	// - drop the records the json stores no longer need
+	a.sc.Add("compact", cfgScheduler.IntervalCompact, job.NewJobCompact(uowStore))
 	// - handler
-	hd := handler.NewHandlerProduct(rp)
+	hd := handler.NewHandlerProduct(rp, rpExchangeRate)
+	hdWarehouse := handler.NewHandlerWarehouse(rpWarehouse, uow)
+	hdWarehouseLive := handler.NewHandlerWarehouseLive(rpWarehouse, a.br)
+	hdAudit := handler.NewHandlerAudit(rpAudit)
+	hdPrice := handler.NewHandlerPrice(rp, rpPrice)
+	// - the create, batch and import requests are run once per idempotency key
+	idempotent := handler.NewIdempotency(rpIdempotency, a.cfg.IdempotencyTTL).Middleware
+	hdExchangeRate := handler.NewHandlerExchangeRate(rpExchangeRate)
+	hdReorderThreshold := handler.NewHandlerReorderThreshold(rp, rpWarehouse, rpReorderThreshold)
+	hdScheduler := handler.NewHandlerScheduler(a.sc)
+	hdWebhook := handler.NewHandlerWebhook(rpWebhook, rpDeadLetter, a.dp)
+	hdEvent := handler.NewHandlerEvent(a.br)
 
 	// router
 	// - middlewares
-	a.rt.Use(middleware.Logger)
+	// - each request is logged with its id once served
+	a.rt.Use(logging.RequestID)
+	a.rt.Use(logging.AccessLog(logger))
+	// - each request is traced, continuing the trace of its client
+	a.rt.Use(tracing.Middleware)
+	// - each request is counted and timed by route
+	a.rt.Use(mt.Middleware)
 	a.rt.Use(middleware.Recoverer)
+	// - the metrics are scraped without credentials, outside of the middlewares of the api
+	// GET /metrics
+	a.rt.Handle("/metrics", mt.Handler())
+	// - the middlewares of the api
+	limiter := ratelimit.NewLimiter(a.cfg.RateLimit)
+	api := a.rt.With(
+		// - the ips are limited before the authentication, so the attempts with wrong credentials are too
+		limiter.MiddlewareIP,
+		// - the actor of an authenticated request is its principal
+		auth.NewAuthenticator(a.cfg.Auth).Middleware,
+		// - the clients are limited once identified, by principal or ip
+		limiter.Middleware,
+		// - the data of a request is the one of its tenant
+		handler.Tenant,
+		// - the principal must have the role required by the route
+		auth.NewAuthorizer(newPolicy(rp)).Middleware,
+		handler.Actor,
+	)
 	// - endpoints
-	a.rt.Route("/products", func(r chi.Router) {
+	api.Route("/products", func(r chi.Router) {
 		// GET /products/{id}
 		r.Get("/{id}", hd.GetById())
 		// POST /products
-		r.Post("/", hd.Create())
+		r.With(idempotent).Post("/", hd.Create())
+		// POST /products/import
+		r.With(idempotent).Post("/import", hd.Import())
+		// POST /products/batch
+		r.With(idempotent).Post("/batch", hd.Batch())
+		// GET /products/low-stock
+		r.Get("/low-stock", hdReorderThreshold.LowStock())
+		// GET /products/expiring
+		r.Get("/expiring", hd.GetExpiring())
+		// GET /products/expired
+		r.Get("/expired", hd.GetExpired())
+		// POST /products/expired/unpublish
+		r.Post("/expired/unpublish", hd.UnpublishExpired())
 		// PUT /products/{id}
 		r.Put("/{id}", hd.UpdateOrCreate())
 		// PATCH /products/{id}
 		r.Patch("/{id}", hd.Update())
 		// DELETE /products/{id}
 		r.Delete("/{id}", hd.Delete())
+		// POST /products/{id}/restore
+		r.Post("/{id}/restore", hd.Restore())
+		// GET /products/{id}/history
+		r.Get("/{id}/history", hdAudit.ProductHistory())
+		// GET /products/{id}/prices
+		r.Get("/{id}/prices", hdPrice.GetAll())
+		// POST /products/{id}/prices
+		r.With(idempotent).Post("/{id}/prices", hdPrice.Schedule())
+		// PUT /products/{id}/reorder-threshold
+		r.Put("/{id}/reorder-threshold", hdReorderThreshold.SetProduct())
+		// DELETE /products/{id}/reorder-threshold
+		r.Delete("/{id}/reorder-threshold", hdReorderThreshold.DeleteProduct())
+		// GET /products
+		r.Get("/", hd.GetAll())
 
 	})
+	api.Route("/warehouses", func(r chi.Router) {
+		// GET /warehouses/reportProducts
+		r.Get("/reportProducts", hdWarehouse.ReportProducts())
+		// GET /warehouses/{id}
+		r.Get("/{id}", hdWarehouse.GetById())
+		// POST /warehouses
+		r.With(idempotent).Post("/", hdWarehouse.Create())
+		// PATCH /warehouses/{id}
+		r.Patch("/{id}", hdWarehouse.Update())
+		// DELETE /warehouses/{id}
+		r.Delete("/{id}", hdWarehouse.Delete())
+		// POST /warehouses/{id}/restore
+		r.Post("/{id}/restore", hdWarehouse.Restore())
+		// GET /warehouses/{id}/history
+		r.Get("/{id}/history", hdAudit.WarehouseHistory())
+		// POST /warehouses/{id}/transfer
+		r.Post("/{id}/transfer", hdWarehouse.Transfer())
+		// GET /warehouses/{id}/live
+		r.Get("/{id}/live", hdWarehouseLive.Live())
+		// PUT /warehouses/{id}/reorder-threshold
+		r.Put("/{id}/reorder-threshold", hdReorderThreshold.SetWarehouse())
+		// DELETE /warehouses/{id}/reorder-threshold
+		r.Delete("/{id}/reorder-threshold", hdReorderThreshold.DeleteWarehouse())
+		// GET /warehouses
+		r.Get("/", hdWarehouse.GetAll())
+	})
+	api.Route("/exchange-rates", func(r chi.Router) {
+		// GET /exchange-rates
+		r.Get("/", hdExchangeRate.GetAll())
+		// GET /exchange-rates/{currency}
+		r.Get("/{currency}", hdExchangeRate.GetByCurrency())
+		// PUT /exchange-rates/{currency}
+		r.Put("/{currency}", hdExchangeRate.Update())
+		// DELETE /exchange-rates/{currency}
+		r.Delete("/{currency}", hdExchangeRate.Delete())
+	})
+	api.Route("/reorder-thresholds", func(r chi.Router) {
+		// GET /reorder-thresholds
+		r.Get("/", hdReorderThreshold.GetAll())
+	})
+	api.Route("/webhooks", func(r chi.Router) {
+		// GET /webhooks/dead-letters
+		r.Get("/dead-letters", hdWebhook.GetDeadLetters())
+		// POST /webhooks/dead-letters/{id}/retry
+		r.Post("/dead-letters/{id}/retry", hdWebhook.RetryDeadLetter())
+		// DELETE /webhooks/dead-letters/{id}
+		r.Delete("/dead-letters/{id}", hdWebhook.DeleteDeadLetter())
+		// GET /webhooks/{id}
+		r.Get("/{id}", hdWebhook.GetById())
+		// DELETE /webhooks/{id}
+		r.Delete("/{id}", hdWebhook.Delete())
+		// POST /webhooks
+		r.With(idempotent).Post("/", hdWebhook.Create())
+		// GET /webhooks
+		r.Get("/", hdWebhook.GetAll())
+	})
+	// GET /events
+	api.Get("/events", hdEvent.Stream())
+	api.Route("/admin", func(r chi.Router) {
+		// GET /admin/jobs
+		r.Get("/jobs", hdScheduler.Status())
+	})
+
+	// background jobs
+	a.dp.Start()
+	a.sc.Start()
 
 	return
 }
 
 // Run runs the application.
 func (a *ApplicationDefault) Run() (err error) {
-	err = http.ListenAndServe(a.addr, a.rt)
+	err = http.ListenAndServe(a.cfg.Addr, a.rt)
 	return
 }
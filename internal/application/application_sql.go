@@ -1,29 +1,79 @@
 package application
 
 import (
+	"app/internal"
 	"app/internal/handler"
+	"app/internal/job"
 	"app/internal/repository"
+	"app/internal/stream"
+	"app/internal/webhook"
+	"app/platform/web/auth"
+	"app/platform/web/logging"
+	"app/platform/web/metrics"
+	"app/platform/web/ratelimit"
+	"app/platform/web/tracing"
+	"context"
 	"database/sql"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-sql-driver/mysql"
 )
 
-// NewApplicationDefault creates a new default application.
-func NewApplicationSql(addr string) (a *ApplicationSql) {
+// ConfigApplicationSql is the configuration of the sql application.
+type ConfigApplicationSql struct {
+	// Addr is the address to listen.
+	Addr string
+	// LowStockWebhookURL is the url the low stock alerts are posted to, they are logged when it is empty.
+	LowStockWebhookURL string
+	// Webhook is the configuration of the delivery of the events to the webhooks.
+	Webhook *webhook.ConfigDispatcher
+	// EventStream is the configuration of the stream of the events.
+	EventStream *stream.ConfigBroker
+	// Auth is the configuration of the authentication of the requests, disabled without keys.
+	Auth *auth.Config
+	// RateLimit is the configuration of the rate limiting of the clients, disabled without limits.
+	RateLimit *ratelimit.Config
+	// IdempotencyTTL is the time the responses of the requests sent with an idempotency key are replayed, 24 hours if 0.
+	IdempotencyTTL time.Duration
+	// Log is the configuration of the logs, text on the standard error if nil.
+	Log *logging.Config
+	// Tracing is the configuration of the traces of the requests and the repositories, none exported if nil.
+	Tracing *tracing.Config
+	// Scheduler is the configuration of the background jobs.
+	Scheduler ConfigScheduler
+}
+
+// NewApplicationSql creates a new sql application.
+func NewApplicationSql(cfg *ConfigApplicationSql) (a *ApplicationSql) {
 	// default config
 	defaultRouter := chi.NewRouter()
-	defaultAddr := ":8080"
-	if addr != "" {
-		defaultAddr = addr
+	defaultConfig := &ConfigApplicationSql{
+		Addr: ":8080",
 	}
+	if cfg != nil {
+		if cfg.Addr != "" {
+			defaultConfig.Addr = cfg.Addr
+		}
+		defaultConfig.LowStockWebhookURL = cfg.LowStockWebhookURL
+		defaultConfig.Webhook = cfg.Webhook
+		defaultConfig.EventStream = cfg.EventStream
+		defaultConfig.Auth = cfg.Auth
+		defaultConfig.RateLimit = cfg.RateLimit
+		defaultConfig.IdempotencyTTL = cfg.IdempotencyTTL
+		defaultConfig.Log = cfg.Log
+		defaultConfig.Tracing = cfg.Tracing
+		defaultConfig.Scheduler = cfg.Scheduler
+	}
+	defaultConfig.Scheduler = defaultConfig.Scheduler.withDefaults()
 
 	a = &ApplicationSql{
-		rt:   defaultRouter,
-		addr: defaultAddr,
-		db:   connectDatabase(),
+		rt:  defaultRouter,
+		cfg: defaultConfig,
+		db:  connectDatabase(),
 	}
 	return
 }
@@ -32,62 +82,251 @@ func NewApplicationSql(addr string) (a *ApplicationSql) {
 type ApplicationSql struct {
 	// rt is the router.
 	rt *chi.Mux
-	// addr is the address to listen.
-	addr string
+	// cfg is the configuration of the application.
+	cfg *ConfigApplicationSql
 
 	db *sql.DB
+	// sc is the scheduler running the background jobs.
+	sc *job.Scheduler
+	// dp is the dispatcher delivering the events to the webhooks.
+	dp *webhook.Dispatcher
+	// br is the broker streaming the events to the clients.
+	br *stream.Broker
+	// shutdownTracing flushes the spans not exported yet.
+	shutdownTracing func(ctx context.Context) error
 }
 
 // TearDown tears down the application.
 func (a *ApplicationSql) TearDown() (err error) {
+	// stop background jobs
+	if a.sc != nil {
+		a.sc.Stop()
+	}
+	// - stopped last, so it gets the events of the jobs
+	if a.dp != nil {
+		a.dp.Stop()
+	}
+	// - ends the open streams
+	if a.br != nil {
+		a.br.Close()
+	}
+	// - flushes the spans of the requests served until now
+	if a.shutdownTracing != nil {
+		err = a.shutdownTracing(context.Background())
+	}
 	return
 }
 
 // SetUp sets up the application.
 func (a *ApplicationSql) SetUp() (err error) {
 	// dependencies
+	// - logger, also the default one of the jobs and the repositories
+	logger := logging.NewLogger(a.cfg.Log)
+	slog.SetDefault(logger)
+	// - tracer provider, also the global one of the repositories
+	a.shutdownTracing, err = tracing.SetUp(a.cfg.Tracing)
+	if err != nil {
+		return
+	}
+	// - metrics of the requests, the repositories and the connection pool
+	mt := metrics.NewMetrics()
+	err = mt.RegisterDB("my_db", a.db)
+	if err != nil {
+		return
+	}
+	// - the calls to the repositories are measured and traced
+	obs := internal.Observers{mt, tracing.Observer{}}
 	// - repository
-	rp := repository.NewRepositoryProductMySql(a.db)
+	rpWebhook := repository.NewRepositoryWebhookMysql(a.db)
+	rpIdempotency := repository.NewRepositoryIdempotencyMysql(a.db)
+	rpDeadLetter := repository.NewRepositoryDeadLetterMysql(a.db)
+	// - event
+	a.dp = webhook.NewDispatcher(rpWebhook, rpDeadLetter, a.cfg.Webhook)
+	a.br = stream.NewBroker(a.cfg.EventStream)
+	rpAudit := repository.NewRepositoryAuditMysql(a.db)
+	// - mutations run in transactions appending their events to the outbox, and are recorded to the audit log
+	uow := repository.NewUnitOfWorkAudited(repository.NewUnitOfWorkOutboxed(repository.NewUnitOfWorkMysql(a.db)), rpAudit)
+	// - price changes are recorded to the price history, in the transactions of the mutations
+	rpPrice := repository.NewRepositoryPriceMysql(a.db)
+	rpExchangeRate := repository.NewRepositoryExchangeRateMysql(a.db)
+	// - quantities falling below their reorder threshold raise an alert
+	rpReorderThreshold := repository.NewRepositoryReorderThresholdMysql(a.db)
+	sink := newAlertSink(a.cfg.LowStockWebhookURL)
+	// - the calls to the products and the warehouses are observed
+	rp := repository.NewRepositoryProductObserved(repository.NewRepositoryProductStocked(repository.NewRepositoryProductPriced(repository.NewRepositoryProductTransactional(repository.NewRepositoryProductMySql(a.db), uow), uow), rpReorderThreshold, sink), obs)
+	rp2 := repository.NewRepositoryWarehouseObserved(repository.NewRepositoryWarehouseTransactional(repository.NewRepositoryWarehouseMySql(a.db), uow), obs)
 	// - handler
-	hd := handler.NewHandlerProduct(rp)
+	hd := handler.NewHandlerProduct(rp, rpExchangeRate)
+	hdAudit := handler.NewHandlerAudit(rpAudit)
+	hdPrice := handler.NewHandlerPrice(rp, rpPrice)
+	// - the create, batch and import requests are run once per idempotency key
+	idempotent := handler.NewIdempotency(rpIdempotency, a.cfg.IdempotencyTTL).Middleware
+	hdReorderThreshold := handler.NewHandlerReorderThreshold(rp, rp2, rpReorderThreshold)
 
 	// router
 	// - middlewares
-	a.rt.Use(middleware.Logger)
+	// - each request is logged with its id once served
+	a.rt.Use(logging.RequestID)
+	a.rt.Use(logging.AccessLog(logger))
+	// - each request is traced, continuing the trace of its client
+	a.rt.Use(tracing.Middleware)
+	// - each request is counted and timed by route
+	a.rt.Use(mt.Middleware)
 	a.rt.Use(middleware.Recoverer)
+	// - the metrics are scraped without credentials, outside of the middlewares of the api
+	// GET /metrics
+	a.rt.Handle("/metrics", mt.Handler())
+	// - the middlewares of the api
+	limiter := ratelimit.NewLimiter(a.cfg.RateLimit)
+	api := a.rt.With(
+		// - the ips are limited before the authentication, so the attempts with wrong credentials are too
+		limiter.MiddlewareIP,
+		// - the actor of an authenticated request is its principal
+		auth.NewAuthenticator(a.cfg.Auth).Middleware,
+		// - the clients are limited once identified, by principal or ip
+		limiter.Middleware,
+		// - the data of a request is the one of its tenant
+		handler.Tenant,
+		// - the principal must have the role required by the route
+		auth.NewAuthorizer(newPolicy(rp)).Middleware,
+		handler.Actor,
+	)
 	// - endpoints
-	a.rt.Route("/products", func(r chi.Router) {
+	api.Route("/products", func(r chi.Router) {
 		// GET /products/{id}
 		r.Get("/{id}", hd.GetById())
 		// POST /products
-		r.Post("/", hd.Create())
+		r.With(idempotent).Post("/", hd.Create())
+		// POST /products/import
+		r.With(idempotent).Post("/import", hd.Import())
+		// POST /products/batch
+		r.With(idempotent).Post("/batch", hd.Batch())
+		// GET /products/low-stock
+		r.Get("/low-stock", hdReorderThreshold.LowStock())
+		// GET /products/expiring
+		r.Get("/expiring", hd.GetExpiring())
+		// GET /products/expired
+		r.Get("/expired", hd.GetExpired())
+		// POST /products/expired/unpublish
+		r.Post("/expired/unpublish", hd.UnpublishExpired())
 		// PUT /products/{id}
 		r.Put("/{id}", hd.UpdateOrCreate())
 		// PATCH /products/{id}
 		r.Patch("/{id}", hd.Update())
 		// DELETE /products/{id}
 		r.Delete("/{id}", hd.Delete())
+		// POST /products/{id}/restore
+		r.Post("/{id}/restore", hd.Restore())
+		// GET /products/{id}/history
+		r.Get("/{id}/history", hdAudit.ProductHistory())
+		// GET /products/{id}/prices
+		r.Get("/{id}/prices", hdPrice.GetAll())
+		// POST /products/{id}/prices
+		r.With(idempotent).Post("/{id}/prices", hdPrice.Schedule())
+		// PUT /products/{id}/reorder-threshold
+		r.Put("/{id}/reorder-threshold", hdReorderThreshold.SetProduct())
+		// DELETE /products/{id}/reorder-threshold
+		r.Delete("/{id}/reorder-threshold", hdReorderThreshold.DeleteProduct())
 		r.Get("/", hd.GetAll())
 	})
 
-	rp2 := repository.NewRepositoryWarehouseMySql(a.db)
+	hd2 := handler.NewHandlerWarehouse(rp2, uow)
+	hdWarehouseLive := handler.NewHandlerWarehouseLive(rp2, a.br)
 
-	hd2 := handler.NewHandlerWarehouse(rp2)
-
-	a.rt.Route("/warehouses", func(r chi.Router) {
+	api.Route("/warehouses", func(r chi.Router) {
 		r.Get("/reportProducts", hd2.ReportProducts())
 		r.Get("/{id}", hd2.GetById())
-		r.Post("/", hd2.Create())
+		r.With(idempotent).Post("/", hd2.Create())
+		r.Patch("/{id}", hd2.Update())
+		r.Delete("/{id}", hd2.Delete())
+		r.Post("/{id}/restore", hd2.Restore())
+		r.Get("/{id}/history", hdAudit.WarehouseHistory())
+		r.Post("/{id}/transfer", hd2.Transfer())
+		r.Get("/{id}/live", hdWarehouseLive.Live())
+		r.Put("/{id}/reorder-threshold", hdReorderThreshold.SetWarehouse())
+		r.Delete("/{id}/reorder-threshold", hdReorderThreshold.DeleteWarehouse())
 		r.Get("/", hd2.GetAll())
 	})
 
+	hdExchangeRate := handler.NewHandlerExchangeRate(rpExchangeRate)
+
+	api.Route("/exchange-rates", func(r chi.Router) {
+		// GET /exchange-rates
+		r.Get("/", hdExchangeRate.GetAll())
+		// GET /exchange-rates/{currency}
+		r.Get("/{currency}", hdExchangeRate.GetByCurrency())
+		// PUT /exchange-rates/{currency}
+		r.Put("/{currency}", hdExchangeRate.Update())
+		// DELETE /exchange-rates/{currency}
+		r.Delete("/{currency}", hdExchangeRate.Delete())
+	})
+
+	// background jobs
+	cfgScheduler := a.cfg.Scheduler
+	a.sc = job.NewScheduler()
+	// - relay the events of the outbox to the webhooks and the streams
+	a.sc.Add("outbox", cfgScheduler.IntervalOutbox, job.NewJobOutbox(repository.NewRepositoryOutboxMysql(a.db), internal.Publishers{a.dp, a.br}, 0, 0))
+	// - apply scheduled prices once they are due
+	a.sc.Add("price", cfgScheduler.IntervalPrice, job.NewJobPrice(uow, rpPrice))
+	// - unpublish expired products
+	if cfgScheduler.UnpublishExpired {
+		a.sc.Add("expiration", cfgScheduler.IntervalExpiration, job.NewJobExpiration(rp))
+	}
+	// - report products low on stock
+	if cfgScheduler.CheckLowStock {
+		a.sc.Add("low_stock", cfgScheduler.IntervalLowStock, job.NewJobLowStock(rp, rpReorderThreshold, sink))
+	}
+	// - remove the expired idempotency records
+	a.sc.Add("idempotency", cfgScheduler.IntervalIdempotency, job.NewJobIdempotency(rpIdempotency))
+	// - purge soft deleted rows beyond their retention
+	if cfgScheduler.PurgeRetention > 0 {
+		a.sc.Add("purge", cfgScheduler.IntervalPurge, job.NewJobPurge(rp, rp2, cfgScheduler.PurgeRetention))
+	}
+
+	hdScheduler := handler.NewHandlerScheduler(a.sc)
+	hdWebhook := handler.NewHandlerWebhook(rpWebhook, rpDeadLetter, a.dp)
+	hdEvent := handler.NewHandlerEvent(a.br)
+
+	api.Route("/webhooks", func(r chi.Router) {
+		// GET /webhooks/dead-letters
+		r.Get("/dead-letters", hdWebhook.GetDeadLetters())
+		// POST /webhooks/dead-letters/{id}/retry
+		r.Post("/dead-letters/{id}/retry", hdWebhook.RetryDeadLetter())
+		// DELETE /webhooks/dead-letters/{id}
+		r.Delete("/dead-letters/{id}", hdWebhook.DeleteDeadLetter())
+		// GET /webhooks/{id}
+		r.Get("/{id}", hdWebhook.GetById())
+		// DELETE /webhooks/{id}
+		r.Delete("/{id}", hdWebhook.Delete())
+		// POST /webhooks
+		r.With(idempotent).Post("/", hdWebhook.Create())
+		// GET /webhooks
+		r.Get("/", hdWebhook.GetAll())
+	})
+
+	api.Route("/reorder-thresholds", func(r chi.Router) {
+		// GET /reorder-thresholds
+		r.Get("/", hdReorderThreshold.GetAll())
+	})
+
+	// GET /events
+	api.Get("/events", hdEvent.Stream())
+
+	api.Route("/admin", func(r chi.Router) {
+		// GET /admin/jobs
+		r.Get("/jobs", hdScheduler.Status())
+	})
+
+	a.dp.Start()
+	a.sc.Start()
+
 	return
 }
 
 // Run runs the application.
 func (a *ApplicationSql) Run() (err error) {
 	defer a.db.Close()
-	err = http.ListenAndServe(a.addr, a.rt)
+	err = http.ListenAndServe(a.cfg.Addr, a.rt)
 	return
 }
 
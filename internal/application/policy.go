@@ -0,0 +1,36 @@
+package application
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/platform/web/auth"
+	"net/http"
+)
+
+// newPolicy returns the roles required by the routes.
+// - viewers read everything but the webhooks, the audit history and the admin routes, the handlers list the deleted rows to admins only
+// - operators also adjust the stock and the reorder thresholds of the warehouses they are assigned to
+// - everything else, creating, deleting, pricing and transferring, is for admins
+func newPolicy(rp internal.RepositoryProduct) (p auth.Policy) {
+	warehouseOfProduct := handler.WarehouseOfProduct(rp)
+
+	p = auth.Policy{
+		Rules: []auth.Rule{
+			{Method: http.MethodGet, Pattern: "/webhooks/*", Role: auth.RoleAdmin},
+			{Method: http.MethodGet, Pattern: "/admin/*", Role: auth.RoleAdmin},
+			// - audit history: the snapshots of the deleted rows and who changed them
+			{Method: http.MethodGet, Pattern: "/products/{id}/history", Role: auth.RoleAdmin},
+			{Method: http.MethodGet, Pattern: "/warehouses/{id}/history", Role: auth.RoleAdmin},
+			{Method: http.MethodGet, Pattern: "/*", Role: auth.RoleViewer},
+			// - stock: the handler rejects an operator patching anything but the quantity
+			{Method: http.MethodPatch, Pattern: "/products/{id}", Role: auth.RoleOperator, Warehouse: warehouseOfProduct},
+			// - reorder thresholds
+			{Method: http.MethodPut, Pattern: "/products/{id}/reorder-threshold", Role: auth.RoleOperator, Warehouse: warehouseOfProduct},
+			{Method: http.MethodDelete, Pattern: "/products/{id}/reorder-threshold", Role: auth.RoleOperator, Warehouse: warehouseOfProduct},
+			{Method: http.MethodPut, Pattern: "/warehouses/{id}/reorder-threshold", Role: auth.RoleOperator, Warehouse: handler.WarehouseOfPath},
+			{Method: http.MethodDelete, Pattern: "/warehouses/{id}/reorder-threshold", Role: auth.RoleOperator, Warehouse: handler.WarehouseOfPath},
+		},
+		Default: auth.RoleAdmin,
+	}
+	return
+}
@@ -0,0 +1,54 @@
+package application
+
+import "time"
+
+// ConfigScheduler is the configuration of the background jobs.
+// - an interval left to 0 takes its default
+type ConfigScheduler struct {
+	// IntervalPrice is the time between runs applying the due price changes.
+	IntervalPrice time.Duration
+	// UnpublishExpired tells whether expired products are unpublished in the background.
+	UnpublishExpired bool
+	// IntervalExpiration is the time between runs unpublishing the expired products.
+	IntervalExpiration time.Duration
+	// CheckLowStock tells whether an alert is raised for every product below its reorder threshold in the background.
+	CheckLowStock bool
+	// IntervalLowStock is the time between runs checking the products low on stock.
+	IntervalLowStock time.Duration
+	// PurgeRetention is the time a soft deleted row is kept before being purged, 0 disables the purge.
+	PurgeRetention time.Duration
+	// IntervalPurge is the time between runs purging the soft deleted rows.
+	IntervalPurge time.Duration
+	// IntervalOutbox is the time between runs relaying the events of the outbox.
+	IntervalOutbox time.Duration
+	// IntervalCompact is the time between runs compacting the JSON stores, ignored by the sql application.
+	IntervalCompact time.Duration
+	// IntervalIdempotency is the time between runs removing the expired idempotency records.
+	IntervalIdempotency time.Duration
+}
+
+// withDefaults returns the configuration with the intervals left to 0 set to their default.
+func (c ConfigScheduler) withDefaults() ConfigScheduler {
+	if c.IntervalPrice <= 0 {
+		c.IntervalPrice = time.Minute
+	}
+	if c.IntervalExpiration <= 0 {
+		c.IntervalExpiration = time.Hour
+	}
+	if c.IntervalLowStock <= 0 {
+		c.IntervalLowStock = time.Hour
+	}
+	if c.IntervalPurge <= 0 {
+		c.IntervalPurge = 24 * time.Hour
+	}
+	if c.IntervalOutbox <= 0 {
+		c.IntervalOutbox = time.Second
+	}
+	if c.IntervalCompact <= 0 {
+		c.IntervalCompact = 24 * time.Hour
+	}
+	if c.IntervalIdempotency <= 0 {
+		c.IntervalIdempotency = time.Hour
+	}
+	return c
+}
@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// AuditEntityProduct is the entity of the audit entries of products.
+	AuditEntityProduct = "product"
+	// AuditEntityWarehouse is the entity of the audit entries of warehouses.
+	AuditEntityWarehouse = "warehouse"
+)
+
+const (
+	// AuditOperationCreate is the operation of an entity being created.
+	AuditOperationCreate = "create"
+	// AuditOperationUpdate is the operation of an entity being updated.
+	AuditOperationUpdate = "update"
+	// AuditOperationDelete is the operation of an entity being soft deleted.
+	AuditOperationDelete = "delete"
+	// AuditOperationRestore is the operation of an entity being restored.
+	AuditOperationRestore = "restore"
+	// AuditOperationPurge is the operation of soft deleted entities being purged.
+	AuditOperationPurge = "purge"
+)
+
+// AuditEntry is a record of a mutation of an entity
+type AuditEntry struct {
+	// Id is the unique identifier of the entry
+	Id int
+	// Entity is the kind of the mutated entity: product or warehouse
+	Entity string
+	// EntityId is the id of the mutated entity, 0 for mutations of many entities
+	EntityId int
+	// Operation is the mutation: create, update, delete, restore or purge
+	Operation string
+	// Actor is who performed the mutation
+	Actor string
+	// Tenant is the tenant owning the mutated entity
+	Tenant string
+	// Timestamp is when the mutation was performed
+	Timestamp time.Time
+	// Before is the JSON snapshot of the entity before the mutation, nil if it did not exist
+	Before json.RawMessage
+	// After is the JSON snapshot of the entity after the mutation, nil if it no longer exists
+	After json.RawMessage
+}
@@ -0,0 +1,11 @@
+package internal
+
+import "context"
+
+// RepositoryAudit is an interface that contains the methods for an audit log repository
+type RepositoryAudit interface {
+	// Save appends an entry to the audit log
+	Save(ctx context.Context, e *AuditEntry) (err error)
+	// FindByEntity returns the entries of an entity, oldest first
+	FindByEntity(ctx context.Context, entity string, id int) (e []AuditEntry, err error)
+}
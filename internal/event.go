@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// EventProductCreated is the type of the event of a product being created.
+	EventProductCreated = "product.created"
+	// EventProductUpdated is the type of the event of a product being updated.
+	EventProductUpdated = "product.updated"
+	// EventProductDeleted is the type of the event of a product being soft deleted.
+	EventProductDeleted = "product.deleted"
+	// EventProductRestored is the type of the event of a product being restored.
+	EventProductRestored = "product.restored"
+	// EventProductPurged is the type of the event of soft deleted products being purged.
+	EventProductPurged = "product.purged"
+	// EventWarehouseCreated is the type of the event of a warehouse being created.
+	EventWarehouseCreated = "warehouse.created"
+	// EventWarehouseUpdated is the type of the event of a warehouse being updated.
+	EventWarehouseUpdated = "warehouse.updated"
+	// EventWarehouseDeleted is the type of the event of a warehouse being soft deleted.
+	EventWarehouseDeleted = "warehouse.deleted"
+	// EventWarehouseRestored is the type of the event of a warehouse being restored.
+	EventWarehouseRestored = "warehouse.restored"
+	// EventWarehousePurged is the type of the event of soft deleted warehouses being purged.
+	EventWarehousePurged = "warehouse.purged"
+)
+
+// EventTypes are the types of all the events.
+var EventTypes = []string{
+	EventProductCreated, EventProductUpdated, EventProductDeleted, EventProductRestored, EventProductPurged,
+	EventWarehouseCreated, EventWarehouseUpdated, EventWarehouseDeleted, EventWarehouseRestored, EventWarehousePurged,
+}
+
+// eventOperations are the past tense of the audit operations, as used in the event types.
+var eventOperations = map[string]string{
+	AuditOperationCreate:  "created",
+	AuditOperationUpdate:  "updated",
+	AuditOperationDelete:  "deleted",
+	AuditOperationRestore: "restored",
+	AuditOperationPurge:   "purged",
+}
+
+// Event is a change of a product or a warehouse delivered to the outside
+// - it is serialized as is to the receivers, so it carries json tags
+type Event struct {
+	// Id is the unique identifier of the event, receivers may use it to discard duplicates
+	Id string `json:"id"`
+	// Type is the type of the event, e.g. product.created
+	Type string `json:"type"`
+	// EntityId is the id of the changed entity, 0 for changes of many entities
+	EntityId int `json:"entity_id"`
+	// Actor is who performed the change
+	Actor string `json:"actor"`
+	// Tenant is the tenant owning the changed entity
+	Tenant string `json:"tenant"`
+	// Timestamp is when the change was performed
+	Timestamp time.Time `json:"timestamp"`
+	// Before is the JSON snapshot of the entity before the change, nil if it did not exist
+	Before json.RawMessage `json:"before,omitempty"`
+	// After is the JSON snapshot of the entity after the change, nil if it no longer exists
+	After json.RawMessage `json:"after,omitempty"`
+}
+
+// Entity returns the kind of the changed entity, the prefix of the type.
+func (e Event) Entity() string {
+	entity, _, _ := strings.Cut(e.Type, ".")
+	return entity
+}
+
+// InWarehouse tells whether the changed entity is, or was before the change, in a warehouse.
+// - a warehouse is in itself, a product is in the warehouse of its snapshots
+// - changes of many entities are in no warehouse
+func (e Event) InWarehouse(id int) bool {
+	switch e.Entity() {
+	case AuditEntityWarehouse:
+		return e.EntityId != 0 && e.EntityId == id
+	case AuditEntityProduct:
+		for _, raw := range []json.RawMessage{e.Before, e.After} {
+			var p struct{ Id, WarehouseId int }
+			if json.Unmarshal(raw, &p) == nil && p.Id != 0 && p.WarehouseId == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// InTenant tells whether the changed entity is of a tenant.
+// - changes predating tenants are of the default one, changes across tenants are of none
+func (e Event) InTenant(tenant string) bool {
+	return e.Tenant != TenantAll && tenantOrDefault(e.Tenant) == tenantOrDefault(tenant)
+}
+
+// EventFromAudit returns the event of the mutation of an audit entry.
+// - the id of the event is the one of the entry, an outbox replaces it with the one of its message
+func EventFromAudit(a AuditEntry) (e Event) {
+	e = Event{
+		Id:        strconv.Itoa(a.Id),
+		Type:      a.Entity + "." + eventOperations[a.Operation],
+		EntityId:  a.EntityId,
+		Actor:     a.Actor,
+		Tenant:    a.Tenant,
+		Timestamp: a.Timestamp,
+		Before:    a.Before,
+		After:     a.After,
+	}
+	return
+}
+
+// EventMatches tells whether an event type matches a pattern.
+// - * matches every type, product.* every type of products
+func EventMatches(pattern, typ string) bool {
+	if pattern == "*" || pattern == typ {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(pattern, ".*")
+	return ok && strings.HasPrefix(typ, prefix+".")
+}
+
+// Publisher is an interface for a destination of events
+type Publisher interface {
+	// Publish publishes an event
+	Publish(ctx context.Context, e Event) (err error)
+}
+
+// Publishers is a publisher publishing the events to every one of its publishers
+type Publishers []Publisher
+
+// Publish publishes an event to every publisher, failing if any of them fails
+func (ps Publishers) Publish(ctx context.Context, e Event) (err error) {
+	var errs []error
+	for _, p := range ps {
+		if err := p.Publish(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	err = errors.Join(errs...)
+	return
+}
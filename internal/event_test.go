@@ -0,0 +1,99 @@
+package internal_test
+
+import (
+	"app/internal"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for EventMatches
+func TestEventMatches(t *testing.T) {
+	cases := []struct {
+		pattern string
+		typ     string
+		match   bool
+	}{
+		{pattern: "*", typ: internal.EventProductCreated, match: true},
+		{pattern: internal.EventProductCreated, typ: internal.EventProductCreated, match: true},
+		{pattern: internal.EventProductCreated, typ: internal.EventProductUpdated, match: false},
+		{pattern: "product.*", typ: internal.EventProductDeleted, match: true},
+		{pattern: "product.*", typ: internal.EventWarehouseDeleted, match: false},
+		{pattern: "prod.*", typ: internal.EventProductDeleted, match: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.pattern+" "+c.typ, func(t *testing.T) {
+			// act
+			match := internal.EventMatches(c.pattern, c.typ)
+
+			// assert
+			require.Equal(t, c.match, match)
+		})
+	}
+}
+
+// Tests for EventFromAudit
+func TestEventFromAudit(t *testing.T) {
+	// act
+	e := internal.EventFromAudit(internal.AuditEntry{Id: 7, Entity: internal.AuditEntityWarehouse, EntityId: 3, Operation: internal.AuditOperationRestore, Actor: "alice"})
+
+	// assert
+	require.Equal(t, "7", e.Id)
+	require.Equal(t, internal.EventWarehouseRestored, e.Type)
+	require.Equal(t, internal.AuditEntityWarehouse, e.Entity())
+	require.Equal(t, 3, e.EntityId)
+	require.Equal(t, "alice", e.Actor)
+}
+
+// Tests for Event.InWarehouse
+func TestEvent_InWarehouse(t *testing.T) {
+	cases := []struct {
+		name  string
+		event internal.Event
+		in    bool
+	}{
+		{name: "warehouse itself", event: internal.Event{Type: internal.EventWarehouseUpdated, EntityId: 1}, in: true},
+		{name: "other warehouse", event: internal.Event{Type: internal.EventWarehouseUpdated, EntityId: 2}, in: false},
+		{name: "product after the change", event: internal.Event{Type: internal.EventProductCreated, EntityId: 3, After: []byte(`{"Id":3,"WarehouseId":1}`)}, in: true},
+		{name: "product moved out", event: internal.Event{Type: internal.EventProductUpdated, EntityId: 3, Before: []byte(`{"Id":3,"WarehouseId":1}`), After: []byte(`{"Id":3,"WarehouseId":2}`)}, in: true},
+		{name: "product of another warehouse", event: internal.Event{Type: internal.EventProductDeleted, EntityId: 3, Before: []byte(`{"Id":3,"WarehouseId":2}`)}, in: false},
+		{name: "purge", event: internal.Event{Type: internal.EventProductPurged, After: []byte(`{"count":2}`)}, in: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			in := c.event.InWarehouse(1)
+
+			// assert
+			require.Equal(t, c.in, in)
+		})
+	}
+}
+
+// Tests for Event.InTenant
+func TestEvent_InTenant(t *testing.T) {
+	cases := []struct {
+		name   string
+		event  string
+		tenant string
+		in     bool
+	}{
+		{name: "same tenant", event: "acme", tenant: "acme", in: true},
+		{name: "other tenant", event: "acme", tenant: "globex", in: false},
+		{name: "event without tenant is of the default tenant", event: "", tenant: internal.TenantDefault, in: true},
+		{name: "event without tenant is not of another tenant", event: "", tenant: "acme", in: false},
+		{name: "event across tenants is of no tenant", event: internal.TenantAll, tenant: "acme", in: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			in := internal.Event{Tenant: c.event}.InTenant(c.tenant)
+
+			// assert
+			require.Equal(t, c.in, in)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// CurrencyBase is the currency products are priced in, the rates of the other currencies are relative to it.
+const CurrencyBase = CurrencyDefault
+
+// ExchangeRateScale is the number of decimal places of a rate.
+const ExchangeRateScale = 8
+
+// ExchangeRate is the rate to convert amounts of the base currency to another currency
+type ExchangeRate struct {
+	// Currency is the ISO 4217 code of the currency
+	Currency string
+	// Rate is how many units of the currency a unit of the base currency is worth
+	Rate *big.Rat
+}
+
+var (
+	// ErrExchangeRateInvalid is returned when a rate is not a positive number.
+	ErrExchangeRateInvalid = errors.New("exchange rate: invalid rate")
+)
+
+// exchangeRatePattern is the pattern of a decimal rate, e.g. 0.9215: no sign, no exponent, no fraction.
+// - up to 11 integer digits and ExchangeRateScale decimal places, what a DECIMAL(19, 8) column holds
+var exchangeRatePattern = regexp.MustCompile(`^\d{1,11}(\.\d{1,8})?$`)
+
+// ParseExchangeRate parses a decimal rate, e.g. "0.9215", of a currency.
+// - fractions and exponents, e.g. "1/4" or "1e3", are rejected
+// - rates with more than ExchangeRateScale decimal places are rejected, not rounded, so a rate never reads as 0
+func ParseExchangeRate(currency, rate string) (e ExchangeRate, err error) {
+	if !exchangeRatePattern.MatchString(rate) {
+		err = ErrExchangeRateInvalid
+		return
+	}
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || r.Sign() <= 0 {
+		err = ErrExchangeRateInvalid
+		return
+	}
+
+	e = ExchangeRate{
+		Currency: NormalizeCurrency(currency),
+		Rate:     r,
+	}
+	return
+}
+
+// String returns the decimal rate, e.g. "0.9215", with up to ExchangeRateScale decimal places.
+func (e ExchangeRate) String() string {
+	s := e.Rate.FloatString(ExchangeRateScale)
+	s = strings.TrimRight(s, "0")
+	s = strings.TrimSuffix(s, ".")
+	return s
+}
+
+// Convert converts an amount of money between the currencies of two rates.
+// - from must be the rate of the currency of m, the result is rounded half away from zero
+// - the result is rounded to the decimal places of the currency of to, e.g. none for JPY
+func Convert(m Money, from, to ExchangeRate) (c Money, err error) {
+	err = m.sameCurrency(Money{Currency: from.Currency})
+	if err != nil {
+		return
+	}
+
+	// amount * to / from, in units of the currency of to
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(MoneyScale-CurrencyScale(to.Currency))), nil)
+	r := new(big.Rat).SetInt64(m.Amount)
+	r.Mul(r, to.Rate)
+	r.Quo(r, from.Rate)
+	r.Quo(r, new(big.Rat).SetInt(unit))
+
+	// round to units
+	q, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(int64(r.Num().Sign())))
+	}
+	q.Mul(q, unit)
+	if !q.IsInt64() {
+		err = ErrMoneyInvalid
+		return
+	}
+
+	c = NewMoney(q.Int64(), to.Currency)
+	return
+}
@@ -0,0 +1,24 @@
+package internal
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrRepositoryExchangeRateNotFound is returned when the rate of a currency is not found.
+	ErrRepositoryExchangeRateNotFound = errors.New("repository: exchange rate not found")
+)
+
+// RepositoryExchangeRate is an interface that contains the methods for an exchange rate repository
+// - the base currency is not stored, its rate is always 1
+type RepositoryExchangeRate interface {
+	// FindByCurrency returns the rate of a currency
+	FindByCurrency(ctx context.Context, currency string) (e ExchangeRate, err error)
+	// Save saves the rate of a currency, replacing the previous one
+	Save(ctx context.Context, e *ExchangeRate) (err error)
+	// Delete deletes the rate of a currency
+	Delete(ctx context.Context, currency string) (err error)
+	// GetAll returns the rates of all currencies sorted by currency
+	GetAll(ctx context.Context) (e []ExchangeRate, err error)
+}
@@ -0,0 +1,9 @@
+package internal
+
+// StoreExchangeRate is an interface for an exchange rate store.
+type StoreExchangeRate interface {
+	// ReadAll reads all rates from the store, by tenant, then by currency.
+	ReadAll() (e map[string]map[string]ExchangeRate, err error)
+	// WriteAll writes all rates to the store.
+	WriteAll(e map[string]map[string]ExchangeRate) (err error)
+}
@@ -0,0 +1,83 @@
+package internal_test
+
+import (
+	"app/internal"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Convert function
+func TestConvert(t *testing.T) {
+	usd, err := internal.ParseExchangeRate("usd", "1")
+	require.NoError(t, err)
+	eur, err := internal.ParseExchangeRate("eur", "0.9215")
+	require.NoError(t, err)
+	ars, err := internal.ParseExchangeRate("ars", "970.5")
+	require.NoError(t, err)
+
+	t.Run("success - from the base currency", func(t *testing.T) {
+		// act
+		m, err := internal.Convert(internal.NewMoney(35279, "USD"), usd, eur)
+
+		// assert
+		// - 352.79 * 0.9215 = 325.095985
+		require.NoError(t, err)
+		require.Equal(t, internal.NewMoney(32510, "EUR"), m)
+	})
+
+	t.Run("success - between other currencies", func(t *testing.T) {
+		// act
+		m, err := internal.Convert(internal.NewMoney(1000, "EUR"), eur, ars)
+
+		// assert
+		// - 10 / 0.9215 * 970.5 = 10531.741725...
+		require.NoError(t, err)
+		require.Equal(t, internal.NewMoney(1053174, "ARS"), m)
+	})
+
+	t.Run("success - rounded half away from zero", func(t *testing.T) {
+		// arrange
+		half, err := internal.ParseExchangeRate("xxx", "0.5")
+		require.NoError(t, err)
+
+		// act
+		m, err := internal.Convert(internal.NewMoney(-1, "USD"), usd, half)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, int64(-1), m.Amount)
+	})
+
+	t.Run("success - rounded to the decimal places of the currency", func(t *testing.T) {
+		// arrange
+		jpy, err := internal.ParseExchangeRate("jpy", "149.87")
+		require.NoError(t, err)
+
+		// act
+		m, err := internal.Convert(internal.NewMoney(35279, "USD"), usd, jpy)
+
+		// assert
+		// - 352.79 * 149.87 = 52872.6373
+		require.NoError(t, err)
+		require.Equal(t, internal.NewMoney(5287300, "JPY"), m)
+	})
+
+	t.Run("error - rate of another currency", func(t *testing.T) {
+		// act
+		_, err := internal.Convert(internal.NewMoney(100, "USD"), eur, ars)
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrMoneyCurrencyMismatch)
+	})
+
+	t.Run("error - invalid rate", func(t *testing.T) {
+		for _, input := range []string{"-1", "0", "1/4", "1e3", "", "0.000000001", "1.123456789", "123456789012"} {
+			// act
+			_, err := internal.ParseExchangeRate("eur", input)
+
+			// assert
+			require.ErrorIs(t, err, internal.ErrExchangeRateInvalid, input)
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/auth"
+	"net/http"
+)
+
+// HeaderActor is the header identifying who performs a request.
+const HeaderActor = "X-Actor"
+
+// Actor is a middleware that attaches the actor of a request to its context.
+// - an authenticated request is performed by the subject of its principal, the X-Actor header is ignored
+// - otherwise the actor is the one of the X-Actor header, internal.ActorAnonymous without it
+func Actor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := r.Header.Get(HeaderActor)
+		if p, ok := auth.PrincipalFromContext(r.Context()); ok {
+			actor = p.Subject
+		}
+		if actor == "" {
+			actor = internal.ActorAnonymous
+		}
+		next.ServeHTTP(w, r.WithContext(internal.ContextWithActor(r.Context(), actor)))
+	})
+}
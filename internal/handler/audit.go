@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/logging"
+	"app/platform/web/response"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// NewHandlerAudit creates a new handler for the audit log.
+func NewHandlerAudit(ra internal.RepositoryAudit) (h *HandlerAudit) {
+	h = &HandlerAudit{
+		ra: ra,
+	}
+	return
+}
+
+// HandlerAudit is a handler for the audit log.
+type HandlerAudit struct {
+	// ra is the repository for the audit log.
+	ra internal.RepositoryAudit
+}
+
+// AuditEntryJSON is an audit entry in JSON format.
+type AuditEntryJSON struct {
+	Id        int             `json:"id"`
+	Operation string          `json:"operation"`
+	Actor     string          `json:"actor"`
+	Timestamp string          `json:"timestamp"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+}
+
+// ProductHistory gets the audit log of a product.
+func (h *HandlerAudit) ProductHistory() http.HandlerFunc {
+	return h.history(internal.AuditEntityProduct)
+}
+
+// WarehouseHistory gets the audit log of a warehouse.
+func (h *HandlerAudit) WarehouseHistory() http.HandlerFunc {
+	return h.history(internal.AuditEntityWarehouse)
+}
+
+// history gets the audit log of an entity, oldest entry first.
+func (h *HandlerAudit) history(entity string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: id
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || id <= 0 {
+			response.JSON(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		// - find entries of the entity
+		es, err := h.ra.FindByEntity(r.Context(), entity, id)
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		// response
+		// - serialize entries to JSON
+		data := make([]AuditEntryJSON, len(es))
+		for i, e := range es {
+			data[i] = AuditEntryJSON{
+				Id:        e.Id,
+				Operation: e.Operation,
+				Actor:     e.Actor,
+				Timestamp: e.Timestamp.Format(time.RFC3339),
+				Before:    e.Before,
+				After:     e.After,
+			}
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
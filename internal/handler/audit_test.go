@@ -0,0 +1,102 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for HandlerAudit.ProductHistory and HandlerAudit.WarehouseHistory
+func TestHandlerAudit_History(t *testing.T) {
+	// - product 1 was created then updated, warehouse 1 was created
+	newRepositoryAudit := func(t *testing.T) *repository.RepositoryAuditFile {
+		ra := repository.NewRepositoryAuditFile(filepath.Join(t.TempDir(), "audit.jsonl"))
+		ts := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		es := []internal.AuditEntry{
+			{Entity: internal.AuditEntityProduct, EntityId: 1, Operation: internal.AuditOperationCreate, Actor: "alice", Timestamp: ts, After: json.RawMessage(`{"name":"p"}`)},
+			{Entity: internal.AuditEntityWarehouse, EntityId: 1, Operation: internal.AuditOperationCreate, Actor: "alice", Timestamp: ts, After: json.RawMessage(`{"name":"w"}`)},
+			{Entity: internal.AuditEntityProduct, EntityId: 1, Operation: internal.AuditOperationUpdate, Actor: "bob", Timestamp: ts.Add(time.Hour), Before: json.RawMessage(`{"name":"p"}`), After: json.RawMessage(`{"name":"q"}`)},
+		}
+		for i := range es {
+			require.NoError(t, ra.Save(context.Background(), &es[i]))
+		}
+		return ra
+	}
+
+	t.Run("success - product entries, oldest first", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerAudit(newRepositoryAudit(t))
+
+		// act
+		res := serve(hd.ProductHistory(), http.MethodGet, "/products/{id}/history", "/products/1/history", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":[
+			{"id":1,"operation":"create","actor":"alice","timestamp":"2030-01-01T00:00:00Z","before":null,"after":{"name":"p"}},
+			{"id":3,"operation":"update","actor":"bob","timestamp":"2030-01-01T01:00:00Z","before":{"name":"p"},"after":{"name":"q"}}
+		]}`, res.Body.String())
+	})
+
+	t.Run("success - warehouse entries", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerAudit(newRepositoryAudit(t))
+
+		// act
+		res := serve(hd.WarehouseHistory(), http.MethodGet, "/warehouses/{id}/history", "/warehouses/1/history", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":[
+			{"id":2,"operation":"create","actor":"alice","timestamp":"2030-01-01T00:00:00Z","before":null,"after":{"name":"w"}}
+		]}`, res.Body.String())
+	})
+
+	t.Run("success - no entries", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerAudit(newRepositoryAudit(t))
+
+		// act
+		res := serve(hd.ProductHistory(), http.MethodGet, "/products/{id}/history", "/products/9/history", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":[]}`, res.Body.String())
+	})
+
+	t.Run("error - invalid id", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerAudit(newRepositoryAudit(t))
+
+		// act
+		resText := serve(hd.ProductHistory(), http.MethodGet, "/products/{id}/history", "/products/a/history", "", "")
+		resZero := serve(hd.WarehouseHistory(), http.MethodGet, "/warehouses/{id}/history", "/warehouses/0/history", "", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, resText.Code)
+		require.JSONEq(t, `"invalid id"`, resText.Body.String())
+		require.Equal(t, http.StatusBadRequest, resZero.Code)
+		require.JSONEq(t, `"invalid id"`, resZero.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		// - a directory can not be read as the audit log
+		hd := handler.NewHandlerAudit(repository.NewRepositoryAuditFile(t.TempDir()))
+
+		// act
+		res := serve(hd.ProductHistory(), http.MethodGet, "/products/{id}/history", "/products/1/history", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/stream"
+	"app/platform/web/logging"
+	"app/platform/web/response"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// NewHandlerEvent creates a new handler for the stream of events.
+func NewHandlerEvent(br *stream.Broker) (h *HandlerEvent) {
+	h = &HandlerEvent{
+		br: br,
+	}
+	return
+}
+
+// HandlerEvent is a handler for the stream of events.
+type HandlerEvent struct {
+	// br is the broker streaming the events.
+	br *stream.Broker
+}
+
+// Stream streams the product and warehouse events as server-sent events.
+// - query parameter warehouse_id selects the events of a warehouse and of its products
+// - query parameter type selects the event types, repeated or comma separated, e.g. product.*
+// - header Last-Event-ID resumes the stream after the last event received
+func (h *HandlerEvent) Stream() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - query parameter: warehouse_id
+		warehouseId, err := queryInt(r, "warehouse_id")
+		if err != nil || warehouseId < 0 {
+			response.JSON(w, http.StatusBadRequest, "invalid warehouse_id")
+			return
+		}
+		// - query parameter: type
+		var types []string
+		for _, v := range r.URL.Query()["type"] {
+			for _, pattern := range strings.Split(v, ",") {
+				pattern = strings.TrimSpace(pattern)
+				if !slices.ContainsFunc(internal.EventTypes, func(typ string) bool { return internal.EventMatches(pattern, typ) }) {
+					response.JSON(w, http.StatusBadRequest, "invalid type")
+					return
+				}
+				types = append(types, pattern)
+			}
+		}
+		// - header: Last-Event-ID
+		lastEventId := r.Header.Get("Last-Event-ID")
+
+		fl, ok := w.(http.Flusher)
+		if !ok {
+			logging.Error(r.Context(), errors.New("handler: response writer can not flush"))
+			response.JSON(w, http.StatusInternalServerError, "streaming not supported")
+			return
+		}
+
+		// process
+		// - only the events of the tenant of the request
+		s, replay := h.br.Subscribe(stream.Filter{WarehouseId: warehouseId, Types: types, Tenant: internal.TenantFromContext(r.Context())}, lastEventId)
+		defer h.br.Unsubscribe(s)
+
+		// response
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		for _, e := range replay {
+			if writeEvent(w, e) != nil {
+				return
+			}
+		}
+		fl.Flush()
+
+		heartbeat := time.NewTicker(h.br.Heartbeat())
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case e, ok := <-s.C:
+				// - a closed subscription ends the stream, the client resumes it
+				if !ok {
+					return
+				}
+				if writeEvent(w, e) != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+					return
+				}
+			}
+			fl.Flush()
+		}
+	}
+}
+
+// writeEvent writes an event in the server-sent events format.
+func writeEvent(w http.ResponseWriter, e internal.Event) (err error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.Id, e.Type, data)
+	return
+}
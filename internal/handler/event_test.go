@@ -0,0 +1,166 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/stream"
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newBrokerEvents returns a broker holding the events of the tests of the stream.
+// - e1 and e4 are of product 1 in warehouse 1, e2 of warehouse 2, e3 of the tenant acme
+func newBrokerEvents(t *testing.T, cfg *stream.ConfigBroker) *stream.Broker {
+	br := stream.NewBroker(cfg)
+	es := []internal.Event{
+		{Id: "e1", Type: internal.EventProductCreated, EntityId: 1, After: json.RawMessage(`{"Id":1,"WarehouseId":1}`)},
+		{Id: "e2", Type: internal.EventWarehouseUpdated, EntityId: 2},
+		{Id: "e3", Type: internal.EventProductCreated, EntityId: 2, Tenant: "acme", After: json.RawMessage(`{"Id":2,"WarehouseId":1}`)},
+		{Id: "e4", Type: internal.EventProductDeleted, EntityId: 1, Before: json.RawMessage(`{"Id":1,"WarehouseId":1}`)},
+	}
+	for _, e := range es {
+		require.NoError(t, br.Publish(context.Background(), e))
+	}
+	return br
+}
+
+// replay serves a request to the stream whose client is already gone, so only the replayed events are written.
+func replay(hd http.HandlerFunc, target, lastEventId, tenant string) *httptest.ResponseRecorder {
+	ctx, cancel := context.WithCancel(internal.ContextWithTenant(context.Background(), tenant))
+	cancel()
+
+	req := httptest.NewRequest(http.MethodGet, target, nil).WithContext(ctx)
+	if lastEventId != "" {
+		req.Header.Set("Last-Event-ID", lastEventId)
+	}
+	res := httptest.NewRecorder()
+	hd(res, req)
+	return res
+}
+
+// eventIds returns the ids of the server-sent events of a stream, in order.
+func eventIds(body string) (ids []string) {
+	for _, line := range strings.Split(body, "\n") {
+		if id, ok := strings.CutPrefix(line, "id: "); ok {
+			ids = append(ids, id)
+		}
+	}
+	return
+}
+
+// Tests for HandlerEvent.Stream
+func TestHandlerEvent_Stream(t *testing.T) {
+	t.Run("success - events after the last one received replayed", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerEvent(newBrokerEvents(t, nil))
+
+		// act
+		res := replay(hd.Stream(), "/events", "e1", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, "text/event-stream", res.Header().Get("Content-Type"))
+		require.Equal(t, []string{"e2", "e4"}, eventIds(res.Body.String()))
+		require.Contains(t, res.Body.String(), "id: e2\nevent: warehouse.updated\ndata: {\"id\":\"e2\",\"type\":\"warehouse.updated\",\"entity_id\":2,")
+	})
+
+	t.Run("success - nothing replayed without a last event", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerEvent(newBrokerEvents(t, nil))
+
+		// act
+		res := replay(hd.Stream(), "/events", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Empty(t, eventIds(res.Body.String()))
+	})
+
+	filtered := []struct {
+		name   string
+		target string
+		tenant string
+		ids    []string
+	}{
+		{"success - events of a warehouse", "/events?warehouse_id=1", "", []string{"e4"}},
+		{"success - events of a type", "/events?type=warehouse.*", "", []string{"e2"}},
+		{"success - events of comma separated types", "/events?type=product.deleted,%20warehouse.updated", "", []string{"e2", "e4"}},
+		{"success - events of the tenant of the request", "/events", "acme", []string{"e3"}},
+	}
+	for _, c := range filtered {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			hd := handler.NewHandlerEvent(newBrokerEvents(t, nil))
+
+			// act
+			res := replay(hd.Stream(), c.target, "e1", c.tenant)
+
+			// assert
+			require.Equal(t, http.StatusOK, res.Code)
+			require.Equal(t, c.ids, eventIds(res.Body.String()))
+		})
+	}
+
+	t.Run("success - published events and heartbeats streamed", func(t *testing.T) {
+		// arrange
+		br := newBrokerEvents(t, &stream.ConfigBroker{Heartbeat: 10 * time.Millisecond})
+		hd := handler.NewHandlerEvent(br)
+		srv := httptest.NewServer(hd.Stream())
+		defer srv.Close()
+		defer br.Close()
+
+		// act
+		res, err := http.Get(srv.URL + "/events?type=product.*")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.NoError(t, br.Publish(context.Background(), internal.Event{Id: "e5", Type: internal.EventWarehouseCreated, EntityId: 3}))
+		require.NoError(t, br.Publish(context.Background(), internal.Event{Id: "e6", Type: internal.EventProductUpdated, EntityId: 3}))
+
+		// assert
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		sc := bufio.NewScanner(res.Body)
+		var heartbeat bool
+		var id string
+		for sc.Scan() && (id == "" || !heartbeat) {
+			switch line := sc.Text(); {
+			case line == ": heartbeat":
+				heartbeat = true
+			case strings.HasPrefix(line, "id: "):
+				id = strings.TrimPrefix(line, "id: ")
+			}
+		}
+		require.Equal(t, "e6", id)
+		require.True(t, heartbeat)
+	})
+
+	invalid := []struct {
+		name    string
+		target  string
+		message string
+	}{
+		{"error - invalid warehouse_id", "/events?warehouse_id=a", "invalid warehouse_id"},
+		{"error - negative warehouse_id", "/events?warehouse_id=-1", "invalid warehouse_id"},
+		{"error - invalid type", "/events?type=order.*", "invalid type"},
+	}
+	for _, c := range invalid {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			hd := handler.NewHandlerEvent(newBrokerEvents(t, nil))
+
+			// act
+			res := replay(hd.Stream(), c.target, "", "")
+
+			// assert
+			require.Equal(t, http.StatusBadRequest, res.Code)
+			require.JSONEq(t, `"`+c.message+`"`, res.Body.String())
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/logging"
+	"app/platform/web/request"
+	"app/platform/web/response"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// NewHandlerExchangeRate creates a new handler for exchange rates.
+func NewHandlerExchangeRate(rr internal.RepositoryExchangeRate) (h *HandlerExchangeRate) {
+	h = &HandlerExchangeRate{
+		rr: rr,
+	}
+	return
+}
+
+// HandlerExchangeRate is a handler for exchange rates.
+type HandlerExchangeRate struct {
+	// rr is the repository for exchange rates.
+	rr internal.RepositoryExchangeRate
+}
+
+// ExchangeRateJSON is an exchange rate in JSON format.
+type ExchangeRateJSON struct {
+	Currency string      `json:"currency"`
+	Base     string      `json:"base"`
+	Rate     json.Number `json:"rate"`
+}
+
+// RequestBodyExchangeRate is a request body for setting an exchange rate.
+type RequestBodyExchangeRate struct {
+	Rate json.Number `json:"rate"`
+}
+
+// GetAll gets the rates of all currencies.
+func (h *HandlerExchangeRate) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		es, err := h.rr.GetAll(r.Context())
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		// response
+		// - serialize rates to JSON
+		data := make([]ExchangeRateJSON, len(es))
+		for i, e := range es {
+			data[i] = exchangeRateJSON(e)
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// GetByCurrency gets the rate of a currency.
+func (h *HandlerExchangeRate) GetByCurrency() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: currency
+		currency := chi.URLParam(r, "currency")
+
+		// process
+		e, err := h.rr.FindByCurrency(r.Context(), currency)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRepositoryExchangeRateNotFound):
+				response.JSON(w, http.StatusNotFound, "exchange rate not found")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    exchangeRateJSON(e),
+		})
+	}
+}
+
+// Update sets the rate of a currency, creating it if it does not exist.
+func (h *HandlerExchangeRate) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: currency
+		currency := internal.NormalizeCurrency(chi.URLParam(r, "currency"))
+		if len(currency) != 3 {
+			response.JSON(w, http.StatusBadRequest, "invalid currency")
+			return
+		}
+		if currency == internal.CurrencyBase {
+			response.JSON(w, http.StatusBadRequest, "the rate of the base currency can not be set")
+			return
+		}
+		// - body
+		var body RequestBodyExchangeRate
+		err := request.JSON(r, &body)
+		if err != nil {
+			invalidBody(w, err)
+			return
+		}
+		e, err := internal.ParseExchangeRate(currency, body.Rate.String())
+		if err != nil {
+			response.JSON(w, http.StatusBadRequest, "rate must be a positive number")
+			return
+		}
+
+		// process
+		err = h.rr.Save(r.Context(), &e)
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    exchangeRateJSON(e),
+		})
+	}
+}
+
+// Delete deletes the rate of a currency.
+func (h *HandlerExchangeRate) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: currency
+		currency := chi.URLParam(r, "currency")
+
+		// process
+		err := h.rr.Delete(r.Context(), currency)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRepositoryExchangeRateNotFound):
+				response.JSON(w, http.StatusNotFound, "exchange rate not found")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusNoContent, nil)
+	}
+}
+
+// exchangeRateJSON serializes an exchange rate to JSON.
+func exchangeRateJSON(e internal.ExchangeRate) ExchangeRateJSON {
+	return ExchangeRateJSON{
+		Currency: e.Currency,
+		Base:     internal.CurrencyBase,
+		Rate:     json.Number(e.String()),
+	}
+}
+
+// convert converts an amount of money to a currency with the rates of a repository.
+func convert(ctx context.Context, rr internal.RepositoryExchangeRate, m internal.Money, currency string) (c internal.Money, err error) {
+	from, err := rr.FindByCurrency(ctx, m.Currency)
+	if err != nil {
+		return
+	}
+	to, err := rr.FindByCurrency(ctx, currency)
+	if err != nil {
+		return
+	}
+
+	c, err = internal.Convert(m, from, to)
+	return
+}
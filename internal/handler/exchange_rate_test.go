@@ -0,0 +1,220 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newStoreExchangeRate returns a store of exchange rates of the default tenant, parsed from their decimals by currency.
+func newStoreExchangeRate(t *testing.T, rates map[string]string) *storeExchangeRateMemory {
+	t.Helper()
+	es := make(map[string]internal.ExchangeRate, len(rates))
+	for currency, rate := range rates {
+		e, err := internal.ParseExchangeRate(currency, rate)
+		require.NoError(t, err)
+		es[e.Currency] = e
+	}
+	return &storeExchangeRateMemory{e: map[string]map[string]internal.ExchangeRate{internal.TenantDefault: es}}
+}
+
+// Tests for HandlerExchangeRate.GetAll
+func TestHandlerExchangeRate_GetAll(t *testing.T) {
+	t.Run("success - rates sorted by currency", func(t *testing.T) {
+		// arrange
+		st := newStoreExchangeRate(t, map[string]string{"JPY": "151.2", "EUR": "0.9215"})
+		hd := handler.NewHandlerExchangeRate(repository.NewRepositoryExchangeRateStore(st))
+
+		// act
+		res := serve(hd.GetAll(), http.MethodGet, "/exchange-rates", "/exchange-rates", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":[
+			{"currency":"EUR","base":"USD","rate":0.9215},
+			{"currency":"JPY","base":"USD","rate":151.2}
+		]}`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerExchangeRate(repository.NewRepositoryExchangeRateStore(storeExchangeRateFailing{}))
+
+		// act
+		res := serve(hd.GetAll(), http.MethodGet, "/exchange-rates", "/exchange-rates", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerExchangeRate.GetByCurrency
+func TestHandlerExchangeRate_GetByCurrency(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		st := newStoreExchangeRate(t, map[string]string{"EUR": "0.9215"})
+		hd := handler.NewHandlerExchangeRate(repository.NewRepositoryExchangeRateStore(st))
+
+		// act
+		res := serve(hd.GetByCurrency(), http.MethodGet, "/exchange-rates/{currency}", "/exchange-rates/eur", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":{"currency":"EUR","base":"USD","rate":0.9215}}`, res.Body.String())
+	})
+
+	t.Run("success - base currency", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerExchangeRate(repository.NewRepositoryExchangeRateStore(newStoreExchangeRate(t, nil)))
+
+		// act
+		res := serve(hd.GetByCurrency(), http.MethodGet, "/exchange-rates/{currency}", "/exchange-rates/USD", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":{"currency":"USD","base":"USD","rate":1}}`, res.Body.String())
+	})
+
+	t.Run("error - exchange rate not found", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerExchangeRate(repository.NewRepositoryExchangeRateStore(newStoreExchangeRate(t, nil)))
+
+		// act
+		res := serve(hd.GetByCurrency(), http.MethodGet, "/exchange-rates/{currency}", "/exchange-rates/EUR", "", "")
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `"exchange rate not found"`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerExchangeRate(repository.NewRepositoryExchangeRateStore(storeExchangeRateFailing{}))
+
+		// act
+		res := serve(hd.GetByCurrency(), http.MethodGet, "/exchange-rates/{currency}", "/exchange-rates/EUR", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerExchangeRate.Update
+func TestHandlerExchangeRate_Update(t *testing.T) {
+	t.Run("success - rate created", func(t *testing.T) {
+		// arrange
+		st := newStoreExchangeRate(t, nil)
+		hd := handler.NewHandlerExchangeRate(repository.NewRepositoryExchangeRateStore(st))
+
+		// act
+		res := serve(hd.Update(), http.MethodPut, "/exchange-rates/{currency}", "/exchange-rates/eur", "application/json", `{"rate":0.9215}`)
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":{"currency":"EUR","base":"USD","rate":0.9215}}`, res.Body.String())
+		es, err := st.ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, "0.9215", es[internal.TenantDefault]["EUR"].String())
+	})
+
+	t.Run("success - rate replaced", func(t *testing.T) {
+		// arrange
+		st := newStoreExchangeRate(t, map[string]string{"EUR": "0.9215"})
+		hd := handler.NewHandlerExchangeRate(repository.NewRepositoryExchangeRateStore(st))
+
+		// act
+		res := serve(hd.Update(), http.MethodPut, "/exchange-rates/{currency}", "/exchange-rates/EUR", "application/json", `{"rate":"0.93"}`)
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":{"currency":"EUR","base":"USD","rate":0.93}}`, res.Body.String())
+	})
+
+	invalid := []struct {
+		name    string
+		target  string
+		body    string
+		message string
+	}{
+		{"error - invalid currency", "/exchange-rates/EURO", `{"rate":0.9215}`, "invalid currency"},
+		{"error - base currency", "/exchange-rates/usd", `{"rate":1}`, "the rate of the base currency can not be set"},
+		{"error - invalid body", "/exchange-rates/EUR", `{"rate":}`, "invalid body"},
+		{"error - rate not positive", "/exchange-rates/EUR", `{"rate":0}`, "rate must be a positive number"},
+		{"error - rate below the scale", "/exchange-rates/EUR", `{"rate":0.000000001}`, "rate must be a positive number"},
+		{"error - rate beyond the scale", "/exchange-rates/EUR", `{"rate":1.123456789}`, "rate must be a positive number"},
+		{"error - rate with an exponent", "/exchange-rates/EUR", `{"rate":1e2}`, "rate must be a positive number"},
+	}
+	for _, c := range invalid {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			hd := handler.NewHandlerExchangeRate(repository.NewRepositoryExchangeRateStore(newStoreExchangeRate(t, nil)))
+
+			// act
+			res := serve(hd.Update(), http.MethodPut, "/exchange-rates/{currency}", c.target, "application/json", c.body)
+
+			// assert
+			require.Equal(t, http.StatusBadRequest, res.Code)
+			require.JSONEq(t, `"`+c.message+`"`, res.Body.String())
+		})
+	}
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerExchangeRate(repository.NewRepositoryExchangeRateStore(storeExchangeRateFailing{}))
+
+		// act
+		res := serve(hd.Update(), http.MethodPut, "/exchange-rates/{currency}", "/exchange-rates/EUR", "application/json", `{"rate":0.9215}`)
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerExchangeRate.Delete
+func TestHandlerExchangeRate_Delete(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		st := newStoreExchangeRate(t, map[string]string{"EUR": "0.9215"})
+		hd := handler.NewHandlerExchangeRate(repository.NewRepositoryExchangeRateStore(st))
+
+		// act
+		res := serve(hd.Delete(), http.MethodDelete, "/exchange-rates/{currency}", "/exchange-rates/eur", "", "")
+
+		// assert
+		require.Equal(t, http.StatusNoContent, res.Code)
+		es, err := st.ReadAll()
+		require.NoError(t, err)
+		require.Empty(t, es[internal.TenantDefault])
+	})
+
+	t.Run("error - exchange rate not found", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerExchangeRate(repository.NewRepositoryExchangeRateStore(newStoreExchangeRate(t, nil)))
+
+		// act
+		res := serve(hd.Delete(), http.MethodDelete, "/exchange-rates/{currency}", "/exchange-rates/EUR", "", "")
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `"exchange rate not found"`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerExchangeRate(repository.NewRepositoryExchangeRateStore(storeExchangeRateFailing{}))
+
+		// act
+		res := serve(hd.Delete(), http.MethodDelete, "/exchange-rates/{currency}", "/exchange-rates/EUR", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
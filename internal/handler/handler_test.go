@@ -0,0 +1,183 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/platform/web/auth"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// serve serves a request with a handler routed on a chi pattern, so it reads its path parameters.
+func serve(hd http.HandlerFunc, method, pattern, target, contentType, body string) *httptest.ResponseRecorder {
+	rt := chi.NewRouter()
+	rt.Method(method, pattern, hd)
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	res := httptest.NewRecorder()
+	rt.ServeHTTP(res, req)
+	return res
+}
+
+// asPrincipal returns a handler serving the requests as performed by an authenticated principal.
+func asPrincipal(hd http.HandlerFunc, p auth.Principal) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hd(w, r.WithContext(auth.ContextWithPrincipal(r.Context(), p)))
+	}
+}
+
+// date parses a date, failing the test if it is invalid.
+func date(t *testing.T, s string) time.Time {
+	t.Helper()
+	d, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// errStore is the error of the failing stores.
+var errStore = errors.New("store: connection refused")
+
+// storeProductFailing is a store of products failing to be read and written.
+type storeProductFailing struct{}
+
+// ReadAll fails.
+func (s storeProductFailing) ReadAll() (p map[int]internal.Product, err error) {
+	err = errStore
+	return
+}
+
+// WriteAll fails.
+func (s storeProductFailing) WriteAll(p map[int]internal.Product) (err error) {
+	err = errStore
+	return
+}
+
+// storePriceFailing is a store of price changes failing to be read and written.
+type storePriceFailing struct{}
+
+// ReadAll fails.
+func (s storePriceFailing) ReadAll() (c map[int]internal.PriceChange, err error) {
+	err = errStore
+	return
+}
+
+// WriteAll fails.
+func (s storePriceFailing) WriteAll(c map[int]internal.PriceChange) (err error) {
+	err = errStore
+	return
+}
+
+// storeExchangeRateMemory is a store of exchange rates kept in memory.
+type storeExchangeRateMemory struct {
+	// e is the stored exchange rates, by tenant, then by currency.
+	e map[string]map[string]internal.ExchangeRate
+}
+
+// ReadAll reads a copy of the exchange rates.
+func (s *storeExchangeRateMemory) ReadAll() (e map[string]map[string]internal.ExchangeRate, err error) {
+	e = make(map[string]map[string]internal.ExchangeRate, len(s.e))
+	for tenant, es := range s.e {
+		e[tenant] = make(map[string]internal.ExchangeRate, len(es))
+		for k, v := range es {
+			e[tenant][k] = v
+		}
+	}
+	return
+}
+
+// WriteAll writes a copy of the exchange rates.
+func (s *storeExchangeRateMemory) WriteAll(e map[string]map[string]internal.ExchangeRate) (err error) {
+	s.e = make(map[string]map[string]internal.ExchangeRate, len(e))
+	for tenant, es := range e {
+		s.e[tenant] = make(map[string]internal.ExchangeRate, len(es))
+		for k, v := range es {
+			s.e[tenant][k] = v
+		}
+	}
+	return
+}
+
+// storeExchangeRateFailing is a store of exchange rates failing to be read and written.
+type storeExchangeRateFailing struct{}
+
+// ReadAll fails.
+func (s storeExchangeRateFailing) ReadAll() (e map[string]map[string]internal.ExchangeRate, err error) {
+	err = errStore
+	return
+}
+
+// WriteAll fails.
+func (s storeExchangeRateFailing) WriteAll(e map[string]map[string]internal.ExchangeRate) (err error) {
+	err = errStore
+	return
+}
+
+// storeWarehouseFailing is a store of warehouses failing to be read and written.
+type storeWarehouseFailing struct{}
+
+// ReadAll fails.
+func (s storeWarehouseFailing) ReadAll() (w map[int]internal.Warehouse, err error) {
+	err = errStore
+	return
+}
+
+// WriteAll fails.
+func (s storeWarehouseFailing) WriteAll(w map[int]internal.Warehouse) (err error) {
+	err = errStore
+	return
+}
+
+// storeWebhookFailing is a store of webhooks failing to be read and written.
+type storeWebhookFailing struct{}
+
+// ReadAll fails.
+func (s storeWebhookFailing) ReadAll() (w map[int]internal.Webhook, err error) {
+	err = errStore
+	return
+}
+
+// WriteAll fails.
+func (s storeWebhookFailing) WriteAll(w map[int]internal.Webhook) (err error) {
+	err = errStore
+	return
+}
+
+// storeDeadLetterFailing is a store of dead letters failing to be read and written.
+type storeDeadLetterFailing struct{}
+
+// ReadAll fails.
+func (s storeDeadLetterFailing) ReadAll() (d map[int]internal.DeadLetter, err error) {
+	err = errStore
+	return
+}
+
+// WriteAll fails.
+func (s storeDeadLetterFailing) WriteAll(d map[int]internal.DeadLetter) (err error) {
+	err = errStore
+	return
+}
+
+// storeIdempotencyFailing is a store of idempotency records failing to be read and written.
+type storeIdempotencyFailing struct{}
+
+// ReadAll fails.
+func (s storeIdempotencyFailing) ReadAll() (r map[string]internal.IdempotencyRecord, err error) {
+	err = errStore
+	return
+}
+
+// WriteAll fails.
+func (s storeIdempotencyFailing) WriteAll(r map[string]internal.IdempotencyRecord) (err error) {
+	err = errStore
+	return
+}
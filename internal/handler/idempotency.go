@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/auth"
+	"app/platform/web/logging"
+	"app/platform/web/request"
+	"app/platform/web/response"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+const (
+	// HeaderIdempotencyKey is the header carrying the idempotency key of a request.
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed is the header set on a response replayed to the retry of a request.
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+	// idempotencyKeyMaxLength is the maximum length of an idempotency key.
+	idempotencyKeyMaxLength = 255
+)
+
+// idempotentHeaders are the headers of a response replayed to the retries of its request.
+var idempotentHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// NewIdempotency creates a new middleware replaying the responses of the requests sent with an idempotency key.
+// - a response is replayed for ttl, 24 hours if 0
+func NewIdempotency(rp internal.RepositoryIdempotency, ttl time.Duration) (m *Idempotency) {
+	// default config
+	defaultTTL := 24 * time.Hour
+	if ttl > 0 {
+		defaultTTL = ttl
+	}
+
+	m = &Idempotency{
+		rp:  rp,
+		ttl: defaultTTL,
+	}
+	return
+}
+
+// Idempotency is a middleware replaying the responses of the requests sent with an idempotency key.
+type Idempotency struct {
+	// rp is the repository for idempotency records.
+	rp internal.RepositoryIdempotency
+	// ttl is the time a response is replayed.
+	ttl time.Duration
+}
+
+// Middleware runs a request sent with an Idempotency-Key header once, and replays its response to its retries.
+// - a key is scoped to the tenant, the principal and the route of the request
+// - a retry with another body is rejected with 422, one while the request is in progress with 409
+// - a body larger than request.MaxBodySize is rejected with 413, as the handlers do
+// - a response with a 5xx status is not recorded, the request may be retried
+// - requests without the header are let through
+func (m *Idempotency) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - header: Idempotency-Key
+		key := r.Header.Get(HeaderIdempotencyKey)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > idempotencyKeyMaxLength {
+			response.JSON(w, http.StatusBadRequest, "invalid idempotency key")
+			return
+		}
+		// - body, read to be hashed and given back to the handler, up to the size the handlers read
+		body, err := request.Body(r)
+		if err != nil {
+			invalidBody(w, err)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// process
+		// - reserve the key, only the first request gets it
+		now := time.Now()
+		rec := internal.IdempotencyRecord{
+			Key:       internal.IdempotencyKey(internal.TenantFromContext(r.Context()), idempotencyClient(r), idempotencyRoute(r), key),
+			BodyHash:  internal.IdempotencyBodyHash(body),
+			CreatedAt: now,
+			ExpiresAt: now.Add(m.ttl),
+		}
+		err = m.rp.Reserve(r.Context(), &rec)
+		if err != nil {
+			if !errors.Is(err, internal.ErrRepositoryIdempotencyKeyExists) {
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+				return
+			}
+			m.replay(w, r, rec)
+			return
+		}
+
+		// - run the request, recording its response
+		// - the key is released if the request panics or fails, so it can be retried
+		ctx := context.WithoutCancel(r.Context())
+		completed := false
+		defer func() {
+			if !completed {
+				m.rp.Delete(ctx, rec.Key)
+			}
+		}()
+		var buf bytes.Buffer
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		ww.Tee(&buf)
+		next.ServeHTTP(ww, r)
+
+		rec.StatusCode = ww.Status()
+		if rec.StatusCode == 0 {
+			rec.StatusCode = http.StatusOK
+		}
+		if rec.StatusCode >= http.StatusInternalServerError {
+			return
+		}
+		rec.Header = make(map[string]string)
+		for _, h := range idempotentHeaders {
+			if v := ww.Header().Get(h); v != "" {
+				rec.Header[h] = v
+			}
+		}
+		rec.Body = buf.Bytes()
+		// - a record failing to complete stays in progress until it expires, the request is not run twice
+		m.rp.Complete(ctx, &rec)
+		completed = true
+	})
+}
+
+// replay responds to the retry of a request with the recorded response of the request.
+func (m *Idempotency) replay(w http.ResponseWriter, r *http.Request, retry internal.IdempotencyRecord) {
+	rec, err := m.rp.FindByKey(r.Context(), retry.Key)
+	if err != nil {
+		switch {
+		case errors.Is(err, internal.ErrRepositoryIdempotencyRecordNotFound):
+			// - released or expired since the reservation failed
+			response.JSON(w, http.StatusConflict, "request in progress")
+		default:
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	switch {
+	case rec.BodyHash != retry.BodyHash:
+		response.JSON(w, http.StatusUnprocessableEntity, "idempotency key reused with another request")
+	case !rec.Completed():
+		response.JSON(w, http.StatusConflict, "request in progress")
+	default:
+		for h, v := range rec.Header {
+			w.Header().Set(h, v)
+		}
+		w.Header().Set(HeaderIdempotentReplayed, "true")
+		w.WriteHeader(rec.StatusCode)
+		w.Write(rec.Body)
+	}
+}
+
+// idempotencyClient returns the client of a request: the principal performing it, anonymous without authentication.
+func idempotencyClient(r *http.Request) string {
+	if p, ok := auth.PrincipalFromContext(r.Context()); ok {
+		return p.Method + ":" + p.Subject
+	}
+	return internal.ActorAnonymous
+}
+
+// idempotencyRoute returns the route of a request: its method and path.
+// - the path tells apart the resources of a route pattern, e.g. the product of /products/{id}/prices
+func idempotencyRoute(r *http.Request) string {
+	return r.Method + " " + r.URL.Path
+}
@@ -0,0 +1,242 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/store"
+	"app/platform/web/auth"
+	"app/platform/web/request"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// sendIdempotent sends a request creating a product through a middleware, with an idempotency key.
+func sendIdempotent(hd http.Handler, ctx func(r *http.Request) *http.Request, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(handler.HeaderIdempotencyKey, key)
+	}
+	if ctx != nil {
+		req = ctx(req)
+	}
+	res := httptest.NewRecorder()
+	hd.ServeHTTP(res, req)
+	return res
+}
+
+// createProduct is a handler creating a product, counting its runs.
+// - the body is echoed back, so the handler is checked to be given the body read by the middleware
+func createProduct(runs *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := runs.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/products/1")
+		w.Header().Set("ETag", `"1"`)
+		w.Header().Set("X-Run", strconv.Itoa(int(n)))
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	}
+}
+
+// Tests for Idempotency.Middleware
+func TestIdempotency_Middleware(t *testing.T) {
+	newMiddleware := func() *handler.Idempotency {
+		return handler.NewIdempotency(repository.NewRepositoryIdempotencyStore(store.NewStoreIdempotencyMemory(nil)), 0)
+	}
+
+	t.Run("success - response of a retry replayed", func(t *testing.T) {
+		// arrange
+		var runs atomic.Int32
+		hd := newMiddleware().Middleware(createProduct(&runs))
+
+		// act
+		first := sendIdempotent(hd, nil, "k1", `{"name":"p"}`)
+		retry := sendIdempotent(hd, nil, "k1", `{"name":"p"}`)
+
+		// assert
+		require.Equal(t, int32(1), runs.Load())
+		require.Equal(t, http.StatusCreated, first.Code)
+		require.Empty(t, first.Header().Get(handler.HeaderIdempotentReplayed))
+		require.Equal(t, http.StatusCreated, retry.Code)
+		require.Equal(t, "true", retry.Header().Get(handler.HeaderIdempotentReplayed))
+		require.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+		require.Equal(t, "/products/1", retry.Header().Get("Location"))
+		require.Equal(t, `"1"`, retry.Header().Get("ETag"))
+		require.Empty(t, retry.Header().Get("X-Run"))
+		require.JSONEq(t, `{"name":"p"}`, retry.Body.String())
+	})
+
+	t.Run("success - requests without a key all run", func(t *testing.T) {
+		// arrange
+		var runs atomic.Int32
+		hd := newMiddleware().Middleware(createProduct(&runs))
+
+		// act
+		sendIdempotent(hd, nil, "", `{"name":"p"}`)
+		res := sendIdempotent(hd, nil, "", `{"name":"p"}`)
+
+		// assert
+		require.Equal(t, int32(2), runs.Load())
+		require.Equal(t, http.StatusCreated, res.Code)
+		require.Empty(t, res.Header().Get(handler.HeaderIdempotentReplayed))
+	})
+
+	t.Run("success - key of another tenant or principal not replayed", func(t *testing.T) {
+		// arrange
+		var runs atomic.Int32
+		hd := newMiddleware().Middleware(createProduct(&runs))
+		acme := func(r *http.Request) *http.Request {
+			return r.WithContext(internal.ContextWithTenant(r.Context(), "acme"))
+		}
+		alice := func(r *http.Request) *http.Request {
+			return r.WithContext(auth.ContextWithPrincipal(r.Context(), auth.Principal{Subject: "alice", Method: auth.MethodAPIKey}))
+		}
+
+		// act
+		sendIdempotent(hd, nil, "k1", `{"name":"p"}`)
+		resTenant := sendIdempotent(hd, acme, "k1", `{"name":"p"}`)
+		resPrincipal := sendIdempotent(hd, alice, "k1", `{"name":"p"}`)
+
+		// assert
+		require.Equal(t, int32(3), runs.Load())
+		require.Empty(t, resTenant.Header().Get(handler.HeaderIdempotentReplayed))
+		require.Empty(t, resPrincipal.Header().Get(handler.HeaderIdempotentReplayed))
+	})
+
+	t.Run("success - server error not recorded, request retried", func(t *testing.T) {
+		// arrange
+		var runs atomic.Int32
+		hd := newMiddleware().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if runs.Add(1) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		// act
+		first := sendIdempotent(hd, nil, "k1", `{"name":"p"}`)
+		retry := sendIdempotent(hd, nil, "k1", `{"name":"p"}`)
+
+		// assert
+		require.Equal(t, int32(2), runs.Load())
+		require.Equal(t, http.StatusInternalServerError, first.Code)
+		require.Equal(t, http.StatusCreated, retry.Code)
+		require.Empty(t, retry.Header().Get(handler.HeaderIdempotentReplayed))
+	})
+
+	t.Run("success - key released by a panic, request retried", func(t *testing.T) {
+		// arrange
+		var runs atomic.Int32
+		hd := newMiddleware().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if runs.Add(1) == 1 {
+				panic("handler failed")
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		// act
+		require.Panics(t, func() { sendIdempotent(hd, nil, "k1", `{"name":"p"}`) })
+		retry := sendIdempotent(hd, nil, "k1", `{"name":"p"}`)
+
+		// assert
+		require.Equal(t, int32(2), runs.Load())
+		require.Equal(t, http.StatusCreated, retry.Code)
+	})
+
+	t.Run("error - key reused with another body", func(t *testing.T) {
+		// arrange
+		var runs atomic.Int32
+		hd := newMiddleware().Middleware(createProduct(&runs))
+
+		// act
+		sendIdempotent(hd, nil, "k1", `{"name":"p"}`)
+		res := sendIdempotent(hd, nil, "k1", `{"name":"q"}`)
+
+		// assert
+		require.Equal(t, int32(1), runs.Load())
+		require.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		require.JSONEq(t, `"idempotency key reused with another request"`, res.Body.String())
+	})
+
+	t.Run("error - retry while the request is in progress", func(t *testing.T) {
+		// arrange
+		var runs atomic.Int32
+		entered := make(chan struct{})
+		release := make(chan struct{})
+		hd := newMiddleware().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			runs.Add(1)
+			close(entered)
+			<-release
+			w.WriteHeader(http.StatusCreated)
+		}))
+		done := make(chan *httptest.ResponseRecorder)
+		go func() {
+			done <- sendIdempotent(hd, nil, "k1", `{"name":"p"}`)
+		}()
+		<-entered
+
+		// act
+		res := sendIdempotent(hd, nil, "k1", `{"name":"p"}`)
+		close(release)
+		first := <-done
+
+		// assert
+		require.Equal(t, int32(1), runs.Load())
+		require.Equal(t, http.StatusConflict, res.Code)
+		require.JSONEq(t, `"request in progress"`, res.Body.String())
+		require.Equal(t, http.StatusCreated, first.Code)
+	})
+
+	t.Run("error - key too long", func(t *testing.T) {
+		// arrange
+		var runs atomic.Int32
+		hd := newMiddleware().Middleware(createProduct(&runs))
+
+		// act
+		res := sendIdempotent(hd, nil, strings.Repeat("k", 256), `{"name":"p"}`)
+
+		// assert
+		require.Equal(t, int32(0), runs.Load())
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"invalid idempotency key"`, res.Body.String())
+	})
+
+	t.Run("error - body too large", func(t *testing.T) {
+		// arrange
+		var runs atomic.Int32
+		hd := newMiddleware().Middleware(createProduct(&runs))
+
+		// act
+		res := sendIdempotent(hd, nil, "k1", strings.Repeat(" ", request.MaxBodySize+1))
+
+		// assert
+		require.Equal(t, int32(0), runs.Load())
+		require.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+		require.JSONEq(t, `"request body too large"`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		var runs atomic.Int32
+		hd := handler.NewIdempotency(repository.NewRepositoryIdempotencyStore(storeIdempotencyFailing{}), 0).Middleware(createProduct(&runs))
+
+		// act
+		res := sendIdempotent(hd, nil, "k1", `{"name":"p"}`)
+
+		// assert
+		require.Equal(t, int32(0), runs.Load())
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
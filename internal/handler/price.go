@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/logging"
+	"app/platform/web/request"
+	"app/platform/web/response"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// NewHandlerPrice creates a new handler for the prices of products.
+func NewHandlerPrice(rp internal.RepositoryProduct, rpr internal.RepositoryPrice) (h *HandlerPrice) {
+	h = &HandlerPrice{
+		rp:  rp,
+		rpr: rpr,
+	}
+	return
+}
+
+// HandlerPrice is a handler for the prices of products.
+type HandlerPrice struct {
+	// rp is the repository for products.
+	rp internal.RepositoryProduct
+	// rpr is the repository for price changes.
+	rpr internal.RepositoryPrice
+}
+
+// PriceChangeJSON is a price change in JSON format.
+type PriceChangeJSON struct {
+	Id          int            `json:"id"`
+	Price       internal.Money `json:"price"`
+	Currency    string         `json:"currency"`
+	EffectiveAt string         `json:"effective_at"`
+	Status      string         `json:"status"`
+}
+
+// RequestBodyPriceSchedule is a request body for scheduling a price.
+type RequestBodyPriceSchedule struct {
+	Price       internal.Money `json:"price"`
+	Currency    string         `json:"currency"`
+	EffectiveAt string         `json:"effective_at"`
+}
+
+// GetAll gets the price timeline of a product, past and scheduled prices ordered by effective date.
+func (h *HandlerPrice) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: id
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.JSON(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		// - check product
+		_, err = h.rp.FindById(r.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRepositoryProductNotFound):
+				response.JSON(w, http.StatusNotFound, "product not found")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+		// - find price changes
+		cs, err := h.rpr.FindByProduct(r.Context(), id)
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		// response
+		// - serialize price changes to JSON
+		data := make([]PriceChangeJSON, len(cs))
+		for i, c := range cs {
+			data[i] = priceChangeJSON(c)
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// Schedule schedules a future price of a product.
+func (h *HandlerPrice) Schedule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: id
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.JSON(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		// - body
+		var body RequestBodyPriceSchedule
+		err = request.JSON(r, &body)
+		if err != nil {
+			invalidBody(w, err)
+			return
+		}
+		if body.Price.IsNegative() {
+			response.JSON(w, http.StatusBadRequest, "price must not be negative")
+			return
+		}
+		price := internal.NewMoney(body.Price.Amount, body.Currency)
+		if price.Validate() != nil {
+			response.JSON(w, http.StatusBadRequest, "invalid price")
+			return
+		}
+		// - effective date, which must be in the future
+		effectiveAt, err := time.Parse(time.RFC3339, body.EffectiveAt)
+		if err != nil {
+			response.JSON(w, http.StatusBadRequest, "invalid effective_at")
+			return
+		}
+		if !effectiveAt.After(time.Now()) {
+			response.JSON(w, http.StatusBadRequest, "effective_at must be in the future")
+			return
+		}
+
+		// process
+		// - check product
+		_, err = h.rp.FindById(r.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRepositoryProductNotFound):
+				response.JSON(w, http.StatusNotFound, "product not found")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+		// - save price change
+		c := internal.PriceChange{
+			ProductId:   id,
+			Price:       price,
+			EffectiveAt: effectiveAt,
+			Status:      internal.PriceStatusScheduled,
+		}
+		err = h.rpr.Save(r.Context(), &c)
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"message": "success",
+			"data":    priceChangeJSON(c),
+		})
+	}
+}
+
+// priceChangeJSON serializes a price change to JSON.
+func priceChangeJSON(c internal.PriceChange) PriceChangeJSON {
+	return PriceChangeJSON{
+		Id:          c.Id,
+		Price:       c.Price,
+		Currency:    c.Price.Currency,
+		EffectiveAt: c.EffectiveAt.Format(time.RFC3339),
+		Status:      c.Status,
+	}
+}
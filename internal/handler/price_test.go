@@ -0,0 +1,163 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/store"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newHandlerPrice returns a handler of the prices of product 1, with the price changes of a store.
+func newHandlerPrice(stPrice internal.StorePrice) *handler.HandlerPrice {
+	stProduct := store.NewStoreProductMemory(map[int]internal.Product{
+		1: {Id: 1, ProductAttributes: internal.ProductAttributes{Name: "p", CodeValue: "A1", Price: internal.NewMoney(150, "USD")}, Version: 1},
+	})
+	return handler.NewHandlerPrice(repository.NewRepositoryProductStore(stProduct), repository.NewRepositoryPriceStore(stPrice))
+}
+
+// Tests for HandlerPrice.GetAll
+func TestHandlerPrice_GetAll(t *testing.T) {
+	t.Run("success - price timeline ordered by effective date", func(t *testing.T) {
+		// arrange
+		hd := newHandlerPrice(store.NewStorePriceMemory(map[int]internal.PriceChange{
+			1: {Id: 1, ProductId: 1, Price: internal.NewMoney(200, "USD"), EffectiveAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), Status: internal.PriceStatusScheduled},
+			2: {Id: 2, ProductId: 1, Price: internal.NewMoney(150, "USD"), EffectiveAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Status: internal.PriceStatusApplied},
+			3: {Id: 3, ProductId: 2, Price: internal.NewMoney(100, "USD"), EffectiveAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Status: internal.PriceStatusApplied},
+		}))
+
+		// act
+		res := serve(hd.GetAll(), http.MethodGet, "/products/{id}/prices", "/products/1/prices", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":[
+			{"id":2,"price":1.50,"currency":"USD","effective_at":"2020-01-01T00:00:00Z","status":"applied"},
+			{"id":1,"price":2.00,"currency":"USD","effective_at":"2030-01-01T00:00:00Z","status":"scheduled"}
+		]}`, res.Body.String())
+	})
+
+	t.Run("error - invalid id", func(t *testing.T) {
+		// arrange
+		hd := newHandlerPrice(store.NewStorePriceMemory(nil))
+
+		// act
+		res := serve(hd.GetAll(), http.MethodGet, "/products/{id}/prices", "/products/a/prices", "", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"invalid id"`, res.Body.String())
+	})
+
+	t.Run("error - product not found", func(t *testing.T) {
+		// arrange
+		hd := newHandlerPrice(store.NewStorePriceMemory(nil))
+
+		// act
+		res := serve(hd.GetAll(), http.MethodGet, "/products/{id}/prices", "/products/9/prices", "", "")
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `"product not found"`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := newHandlerPrice(storePriceFailing{})
+
+		// act
+		res := serve(hd.GetAll(), http.MethodGet, "/products/{id}/prices", "/products/1/prices", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerPrice.Schedule
+func TestHandlerPrice_Schedule(t *testing.T) {
+	t.Run("success - price scheduled", func(t *testing.T) {
+		// arrange
+		st := store.NewStorePriceMemory(nil)
+		hd := newHandlerPrice(st)
+
+		// act
+		res := serve(hd.Schedule(), http.MethodPost, "/products/{id}/prices", "/products/1/prices", "application/json",
+			`{"price":2.5,"currency":"eur","effective_at":"2099-01-01T00:00:00Z"}`)
+
+		// assert
+		require.Equal(t, http.StatusCreated, res.Code)
+		require.JSONEq(t, `{"message":"success","data":{"id":1,"price":2.50,"currency":"EUR","effective_at":"2099-01-01T00:00:00Z","status":"scheduled"}}`, res.Body.String())
+		cs, err := st.ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, internal.NewMoney(250, "EUR"), cs[1].Price)
+	})
+
+	t.Run("error - invalid body", func(t *testing.T) {
+		// arrange
+		hd := newHandlerPrice(store.NewStorePriceMemory(nil))
+
+		// act
+		res := serve(hd.Schedule(), http.MethodPost, "/products/{id}/prices", "/products/1/prices", "text/plain", `2.5`)
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"invalid body"`, res.Body.String())
+	})
+
+	invalid := []struct {
+		name    string
+		target  string
+		body    string
+		message string
+	}{
+		{"error - invalid id", "/products/a/prices", `{"price":2.5,"effective_at":"2099-01-01T00:00:00Z"}`, "invalid id"},
+		{"error - negative price", "/products/1/prices", `{"price":-2.5,"effective_at":"2099-01-01T00:00:00Z"}`, "price must not be negative"},
+		{"error - price beyond the scale of its currency", "/products/1/prices", `{"price":2.5,"currency":"JPY","effective_at":"2099-01-01T00:00:00Z"}`, "invalid price"},
+		{"error - invalid effective_at", "/products/1/prices", `{"price":2.5,"effective_at":"2099-01-01"}`, "invalid effective_at"},
+		{"error - past effective_at", "/products/1/prices", `{"price":2.5,"effective_at":"2000-01-01T00:00:00Z"}`, "effective_at must be in the future"},
+	}
+	for _, c := range invalid {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			hd := newHandlerPrice(store.NewStorePriceMemory(nil))
+
+			// act
+			res := serve(hd.Schedule(), http.MethodPost, "/products/{id}/prices", c.target, "application/json", c.body)
+
+			// assert
+			require.Equal(t, http.StatusBadRequest, res.Code)
+			require.JSONEq(t, `"`+c.message+`"`, res.Body.String())
+		})
+	}
+
+	t.Run("error - product not found", func(t *testing.T) {
+		// arrange
+		hd := newHandlerPrice(store.NewStorePriceMemory(nil))
+
+		// act
+		res := serve(hd.Schedule(), http.MethodPost, "/products/{id}/prices", "/products/9/prices", "application/json",
+			`{"price":2.5,"effective_at":"2099-01-01T00:00:00Z"}`)
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `"product not found"`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := newHandlerPrice(storePriceFailing{})
+
+		// act
+		res := serve(hd.Schedule(), http.MethodPost, "/products/{id}/prices", "/products/1/prices", "application/json",
+			`{"price":2.5,"effective_at":"2099-01-01T00:00:00Z"}`)
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
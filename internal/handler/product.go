@@ -2,6 +2,8 @@ package handler
 
 import (
 	"app/internal"
+	"app/platform/web/auth"
+	"app/platform/web/logging"
 	"app/platform/web/request"
 	"app/platform/web/response"
 	"errors"
@@ -13,9 +15,10 @@ import (
 )
 
 // NewHandlerProduct creates a new handler for products.
-func NewHandlerProduct(rp internal.RepositoryProduct) (h *HandlerProduct) {
+func NewHandlerProduct(rp internal.RepositoryProduct, rr internal.RepositoryExchangeRate) (h *HandlerProduct) {
 	h = &HandlerProduct{
 		rp: rp,
+		rr: rr,
 	}
 	return
 }
@@ -24,20 +27,24 @@ func NewHandlerProduct(rp internal.RepositoryProduct) (h *HandlerProduct) {
 type HandlerProduct struct {
 	// rp is the repository for products.
 	rp internal.RepositoryProduct
+	// rr is the repository for exchange rates.
+	rr internal.RepositoryExchangeRate
 }
 
 // ProductJSON is a product in JSON format.
 type ProductJSON struct {
-	Id          int     `json:"id"`
-	Name        string  `json:"name"`
-	Quantity    int     `json:"quantity"`
-	CodeValue   string  `json:"code_value"`
-	IsPublished bool    `json:"is_published"`
-	Expiration  string  `json:"expiration"`
-	Price       float64 `json:"price"`
+	Id          int            `json:"id"`
+	Name        string         `json:"name"`
+	Quantity    int            `json:"quantity"`
+	CodeValue   string         `json:"code_value"`
+	IsPublished bool           `json:"is_published"`
+	Expiration  string         `json:"expiration"`
+	Price       internal.Money `json:"price"`
+	Currency    string         `json:"currency"`
 }
 
 // GetById gets a product by id.
+// - the price is converted to the currency of the currency query parameter, if sent
 func (h *HandlerProduct) GetById() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
@@ -47,19 +54,36 @@ func (h *HandlerProduct) GetById() http.HandlerFunc {
 			response.JSON(w, http.StatusBadRequest, "invalid id")
 			return
 		}
+		// - query parameter: currency
+		currency := r.URL.Query().Get("currency")
 
 		// process
 		// - find product by id
-		p, err := h.rp.FindById(id)
+		p, err := h.rp.FindById(r.Context(), id)
 		if err != nil {
 			switch {
 			case errors.Is(err, internal.ErrRepositoryProductNotFound):
 				response.JSON(w, http.StatusNotFound, "product not found")
 			default:
+				logging.Error(r.Context(), err)
 				response.JSON(w, http.StatusInternalServerError, "internal server error")
 			}
 			return
 		}
+		// - convert price
+		if currency != "" {
+			p.Price, err = convert(r.Context(), h.rr, p.Price, currency)
+			if err != nil {
+				switch {
+				case errors.Is(err, internal.ErrRepositoryExchangeRateNotFound):
+					response.JSON(w, http.StatusBadRequest, "unsupported currency")
+				default:
+					logging.Error(r.Context(), err)
+					response.JSON(w, http.StatusInternalServerError, "internal server error")
+				}
+				return
+			}
+		}
 
 		// response
 		// - serialize product to JSON
@@ -71,7 +95,14 @@ func (h *HandlerProduct) GetById() http.HandlerFunc {
 			IsPublished: p.IsPublished,
 			Expiration:  p.Expiration.Format(time.DateOnly),
 			Price:       p.Price,
+			Currency:    p.Price.Currency,
+		}
+		// - a price converted to another currency is another representation of the version, with a tag of its own
+		tag := strconv.Itoa(p.Version)
+		if currency != "" {
+			tag += "-" + p.Price.Currency
 		}
+		response.ETag(w, tag)
 		response.JSON(w, http.StatusOK, map[string]any{
 			"message": "success",
 			"data":    data,
@@ -81,12 +112,61 @@ func (h *HandlerProduct) GetById() http.HandlerFunc {
 
 // RequestBodyProductCreate is a request body for creating a product.
 type RequestBodyProductCreate struct {
-	Name        string  `json:"name"`
-	Quantity    int     `json:"quantity"`
-	CodeValue   string  `json:"code_value"`
-	IsPublished bool    `json:"is_published"`
-	Expiration  string  `json:"expiration"`
-	Price       float64 `json:"price"`
+	Name        string         `json:"name"`
+	Quantity    int            `json:"quantity"`
+	CodeValue   string         `json:"code_value"`
+	IsPublished bool           `json:"is_published"`
+	Expiration  string         `json:"expiration"`
+	Price       internal.Money `json:"price"`
+	Currency    string         `json:"currency"`
+}
+
+// price returns the price of the body in its currency, the default one if it does not state it.
+func (b RequestBodyProductCreate) price() internal.Money {
+	return internal.NewMoney(b.Price.Amount, b.Currency)
+}
+
+// sameBut tells whether the body is the same as another one but for the quantity.
+func (b RequestBodyProductCreate) sameBut(o RequestBodyProductCreate) bool {
+	return b.Name == o.Name && b.CodeValue == o.CodeValue && b.IsPublished == o.IsPublished &&
+		b.Expiration == o.Expiration && b.price() == o.price()
+}
+
+// productAttributes validates a product body and returns the attributes of its product.
+func productAttributes(body RequestBodyProductCreate) (a internal.ProductAttributes, err error) {
+	switch {
+	case body.Name == "":
+		err = errors.New("name is required")
+		return
+	case body.CodeValue == "":
+		err = errors.New("code_value is required")
+		return
+	case body.Quantity < 0:
+		err = errors.New("quantity must not be negative")
+		return
+	case body.Price.IsNegative():
+		err = errors.New("price must not be negative")
+		return
+	case body.price().Validate() != nil:
+		err = errors.New("invalid price")
+		return
+	}
+
+	exp, err := time.Parse(time.DateOnly, body.Expiration)
+	if err != nil {
+		err = errors.New("invalid expiration")
+		return
+	}
+
+	a = internal.ProductAttributes{
+		Name:        body.Name,
+		Quantity:    body.Quantity,
+		CodeValue:   body.CodeValue,
+		IsPublished: body.IsPublished,
+		Expiration:  exp,
+		Price:       body.price(),
+	}
+	return
 }
 
 // Create creates a product.
@@ -97,30 +177,24 @@ func (h *HandlerProduct) Create() http.HandlerFunc {
 		var body RequestBodyProductCreate
 		err := request.JSON(r, &body)
 		if err != nil {
-			response.JSON(w, http.StatusBadRequest, "invalid body")
+			invalidBody(w, err)
 			return
 		}
-		// - expiration
-		exp, err := time.Parse(time.DateOnly, body.Expiration)
+		// - attributes
+		attributes, err := productAttributes(body)
 		if err != nil {
-			response.JSON(w, http.StatusBadRequest, "invalid expiration")
+			response.JSON(w, http.StatusBadRequest, err.Error())
 			return
 		}
 
 		// process
 		// - save product
 		p := internal.Product{
-			ProductAttributes: internal.ProductAttributes{
-				Name:        body.Name,
-				Quantity:    body.Quantity,
-				CodeValue:   body.CodeValue,
-				IsPublished: body.IsPublished,
-				Expiration:  exp,
-				Price:       body.Price,
-			},
-		}
-		err = h.rp.Save(&p)
+			ProductAttributes: attributes,
+		}
+		err = h.rp.Save(r.Context(), &p)
 		if err != nil {
+			logging.Error(r.Context(), err)
 			response.JSON(w, http.StatusInternalServerError, "internal server error")
 			return
 		}
@@ -135,7 +209,9 @@ func (h *HandlerProduct) Create() http.HandlerFunc {
 			IsPublished: p.IsPublished,
 			Expiration:  p.Expiration.Format(time.DateOnly),
 			Price:       p.Price,
+			Currency:    p.Price.Currency,
 		}
+		response.ETag(w, strconv.Itoa(p.Version))
 		response.JSON(w, http.StatusCreated, map[string]any{
 			"message": "success",
 			"data":    data,
@@ -157,32 +233,47 @@ func (h *HandlerProduct) UpdateOrCreate() http.HandlerFunc {
 		var body RequestBodyProductCreate
 		err = request.JSON(r, &body)
 		if err != nil {
-			response.JSON(w, http.StatusBadRequest, "invalid body")
+			invalidBody(w, err)
 			return
 		}
-		// - expiration
-		exp, err := time.Parse(time.DateOnly, body.Expiration)
+		// - attributes
+		attributes, err := productAttributes(body)
 		if err != nil {
-			response.JSON(w, http.StatusBadRequest, "invalid expiration")
+			response.JSON(w, http.StatusBadRequest, err.Error())
 			return
 		}
 
 		// process
+		// - check the version when the If-Match header is sent
+		var version int
+		if _, present := ifMatch(r, 0); present {
+			current, err := h.rp.FindById(r.Context(), id)
+			if err != nil && !errors.Is(err, internal.ErrRepositoryProductNotFound) {
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+				return
+			}
+			if match, _ := ifMatch(r, current.Version); err != nil || !match {
+				response.JSON(w, http.StatusPreconditionFailed, "precondition failed")
+				return
+			}
+			version = current.Version
+		}
 		// - update or save product
 		p := internal.Product{
-			Id: id,
-			ProductAttributes: internal.ProductAttributes{
-				Name:        body.Name,
-				Quantity:    body.Quantity,
-				CodeValue:   body.CodeValue,
-				IsPublished: body.IsPublished,
-				Expiration:  exp,
-				Price:       body.Price,
-			},
-		}
-		err = h.rp.UpdateOrSave(&p)
+			Id:                id,
+			Version:           version,
+			ProductAttributes: attributes,
+		}
+		err = h.rp.UpdateOrSave(r.Context(), &p)
 		if err != nil {
-			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			switch {
+			case errors.Is(err, internal.ErrRepositoryProductVersionConflict):
+				response.JSON(w, http.StatusPreconditionFailed, "precondition failed")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
 			return
 		}
 
@@ -196,7 +287,9 @@ func (h *HandlerProduct) UpdateOrCreate() http.HandlerFunc {
 			IsPublished: p.IsPublished,
 			Expiration:  p.Expiration.Format(time.DateOnly),
 			Price:       p.Price,
+			Currency:    p.Price.Currency,
 		}
+		response.ETag(w, strconv.Itoa(p.Version))
 		response.JSON(w, http.StatusOK, map[string]any{
 			"message": "success",
 			"data":    data,
@@ -205,6 +298,7 @@ func (h *HandlerProduct) UpdateOrCreate() http.HandlerFunc {
 }
 
 // Update updates a product.
+// - a principal below admin, an operator, may only patch its quantity
 func (h *HandlerProduct) Update() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
@@ -217,16 +311,23 @@ func (h *HandlerProduct) Update() http.HandlerFunc {
 
 		// process
 		// - find product by id
-		p, err := h.rp.FindById(id)
+		p, err := h.rp.FindById(r.Context(), id)
 		if err != nil {
 			switch {
 			case errors.Is(err, internal.ErrRepositoryProductNotFound):
 				response.JSON(w, http.StatusNotFound, "product not found")
 			default:
+				logging.Error(r.Context(), err)
 				response.JSON(w, http.StatusInternalServerError, "internal server error")
 			}
 			return
 		}
+		// - check the version
+		match, present := ifMatch(r, p.Version)
+		if !match {
+			response.JSON(w, http.StatusPreconditionFailed, "precondition failed")
+			return
+		}
 		// - patch product
 		body := RequestBodyProductCreate{
 			Name:        p.Name,
@@ -235,28 +336,45 @@ func (h *HandlerProduct) Update() http.HandlerFunc {
 			IsPublished: p.IsPublished,
 			Expiration:  p.Expiration.Format(time.DateOnly),
 			Price:       p.Price,
+			Currency:    p.Price.Currency,
 		}
-		err = request.JSON(r, &body)
+		before := body
+		err = request.Patch(r, &body)
 		if err != nil {
-			response.JSON(w, http.StatusBadRequest, "invalid body")
+			switch {
+			case errors.Is(err, request.ErrRequestContentTypeNotPatch):
+				response.JSON(w, http.StatusUnsupportedMediaType, "unsupported media type")
+			case errors.Is(err, request.ErrRequestBodyTooLarge):
+				response.JSON(w, http.StatusRequestEntityTooLarge, "request body too large")
+			default:
+				response.JSON(w, http.StatusBadRequest, "invalid body")
+			}
 			return
 		}
-		// - expiration
-		exp, err := time.Parse(time.DateOnly, body.Expiration)
+		// - a principal below admin only adjusts the stock, the quantity
+		if pr, ok := auth.PrincipalFromContext(r.Context()); ok && !auth.RoleAllows(pr.Role, auth.RoleAdmin) && !body.sameBut(before) {
+			response.JSON(w, http.StatusForbidden, "only the quantity can be patched")
+			return
+		}
+		// - attributes of the patched product
+		attributes, err := productAttributes(body)
 		if err != nil {
-			response.JSON(w, http.StatusBadRequest, "invalid expiration")
+			response.JSON(w, http.StatusBadRequest, err.Error())
 			return
 		}
 		// - update product
-		p.Name = body.Name
-		p.Quantity = body.Quantity
-		p.CodeValue = body.CodeValue
-		p.IsPublished = body.IsPublished
-		p.Expiration = exp
-		p.Price = body.Price
-		err = h.rp.Update(&p)
+		p.ProductAttributes = attributes
+		err = h.rp.Update(r.Context(), &p)
 		if err != nil {
-			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			switch {
+			case errors.Is(err, internal.ErrRepositoryProductVersionConflict) && present:
+				response.JSON(w, http.StatusPreconditionFailed, "precondition failed")
+			case errors.Is(err, internal.ErrRepositoryProductVersionConflict):
+				response.JSON(w, http.StatusConflict, "product was modified concurrently")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
 			return
 		}
 
@@ -270,7 +388,9 @@ func (h *HandlerProduct) Update() http.HandlerFunc {
 			IsPublished: p.IsPublished,
 			Expiration:  p.Expiration.Format(time.DateOnly),
 			Price:       p.Price,
+			Currency:    p.Price.Currency,
 		}
+		response.ETag(w, strconv.Itoa(p.Version))
 		response.JSON(w, http.StatusOK, map[string]any{
 			"message": "success",
 			"data":    data,
@@ -290,13 +410,36 @@ func (h *HandlerProduct) Delete() http.HandlerFunc {
 		}
 
 		// process
+		// - check the version when the If-Match header is sent, the delete fails if it changes meanwhile
+		var version int
+		if _, present := ifMatch(r, 0); present {
+			current, err := h.rp.FindById(r.Context(), id)
+			if err != nil {
+				switch {
+				case errors.Is(err, internal.ErrRepositoryProductNotFound):
+					response.JSON(w, http.StatusPreconditionFailed, "precondition failed")
+				default:
+					logging.Error(r.Context(), err)
+					response.JSON(w, http.StatusInternalServerError, "internal server error")
+				}
+				return
+			}
+			if match, _ := ifMatch(r, current.Version); !match {
+				response.JSON(w, http.StatusPreconditionFailed, "precondition failed")
+				return
+			}
+			version = current.Version
+		}
 		// - delete product by id
-		err = h.rp.Delete(id)
+		err = h.rp.Delete(r.Context(), id, version)
 		if err != nil {
 			switch {
+			case errors.Is(err, internal.ErrRepositoryProductVersionConflict):
+				response.JSON(w, http.StatusPreconditionFailed, "precondition failed")
 			case errors.Is(err, internal.ErrRepositoryProductNotFound):
 				response.JSON(w, http.StatusNotFound, "product not found")
 			default:
+				logging.Error(r.Context(), err)
 				response.JSON(w, http.StatusInternalServerError, "internal server error")
 			}
 			return
@@ -309,9 +452,29 @@ func (h *HandlerProduct) Delete() http.HandlerFunc {
 
 func (h *HandlerProduct) GetAll() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		products, err := h.rp.GetAll()
+		// request
+		// - query parameter: include_deleted
+		includeDeleted, err := queryBool(r, "include_deleted")
+		if err != nil {
+			response.JSON(w, http.StatusBadRequest, "invalid include_deleted")
+			return
+		}
+		// - the deleted products are only listed to admins
+		if pr, ok := auth.PrincipalFromContext(r.Context()); ok && includeDeleted && !auth.RoleAllows(pr.Role, auth.RoleAdmin) {
+			response.JSON(w, http.StatusForbidden, "forbidden")
+			return
+		}
+
+		var products []internal.Product
+		switch includeDeleted {
+		case true:
+			products, err = h.rp.GetAllIncludingDeleted(r.Context())
+		default:
+			products, err = h.rp.GetAll(r.Context())
+		}
 
 		if err != nil {
+			logging.Error(r.Context(), err)
 			response.Error(w, http.StatusInternalServerError, "internal server error")
 			return
 		}
@@ -319,3 +482,55 @@ func (h *HandlerProduct) GetAll() http.HandlerFunc {
 		response.JSON(w, http.StatusOK, products)
 	}
 }
+
+// Restore restores a deleted product.
+func (h *HandlerProduct) Restore() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: id
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.JSON(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		// - restore product by id
+		err = h.rp.Restore(r.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRepositoryProductNotFound):
+				response.JSON(w, http.StatusNotFound, "deleted product not found")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+		// - find restored product
+		p, err := h.rp.FindById(r.Context(), id)
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		// response
+		// - serialize product to JSON
+		data := ProductJSON{
+			Id:          p.Id,
+			Name:        p.Name,
+			Quantity:    p.Quantity,
+			CodeValue:   p.CodeValue,
+			IsPublished: p.IsPublished,
+			Expiration:  p.Expiration.Format(time.DateOnly),
+			Price:       p.Price,
+			Currency:    p.Price.Currency,
+		}
+		response.ETag(w, strconv.Itoa(p.Version))
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/logging"
+	"app/platform/web/request"
+	"app/platform/web/response"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// RequestBodyProductOperation is an operation of a batch of products.
+// - id is required to update and delete, product is required to create and update
+type RequestBodyProductOperation struct {
+	Op      string                    `json:"op"`
+	Id      int                       `json:"id"`
+	Product *RequestBodyProductCreate `json:"product"`
+}
+
+// RequestBodyProductBatch is a request body for a batch of products.
+type RequestBodyProductBatch struct {
+	Operations []RequestBodyProductOperation `json:"operations"`
+}
+
+// ProductOperationJSON is the result of an operation in JSON format.
+type ProductOperationJSON struct {
+	Index int          `json:"index"`
+	Op    string       `json:"op"`
+	Id    int          `json:"id"`
+	Data  *ProductJSON `json:"data,omitempty"`
+}
+
+// Batch creates, updates and deletes products, applying all the operations or none of them.
+func (h *HandlerProduct) Batch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - body
+		var body RequestBodyProductBatch
+		err := request.JSON(r, &body)
+		if err != nil {
+			invalidBody(w, err)
+			return
+		}
+		if len(body.Operations) == 0 {
+			response.JSON(w, http.StatusBadRequest, "operations are required")
+			return
+		}
+		// - operations
+		ops := make([]internal.ProductOperation, len(body.Operations))
+		for i, o := range body.Operations {
+			ops[i], err = productOperation(o)
+			if err != nil {
+				response.Errorf(w, http.StatusBadRequest, "operation %d: %s", i, err.Error())
+				return
+			}
+		}
+
+		// process
+		// - apply operations
+		ps, err := h.rp.Batch(r.Context(), ops)
+		if err != nil {
+			var opErr *internal.ProductOperationError
+			switch {
+			case errors.As(err, &opErr) && errors.Is(err, internal.ErrRepositoryProductNotFound):
+				response.Errorf(w, http.StatusNotFound, "operation %d: product not found", opErr.Index)
+			case errors.As(err, &opErr) && errors.Is(err, internal.ErrRepositoryProductDuplicated):
+				response.Errorf(w, http.StatusConflict, "operation %d: product duplicated", opErr.Index)
+			case errors.As(err, &opErr) && errors.Is(err, internal.ErrRepositoryProductVersionConflict):
+				response.Errorf(w, http.StatusConflict, "operation %d: product was modified concurrently", opErr.Index)
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+
+		// response
+		// - serialize results to JSON
+		data := make([]ProductOperationJSON, len(ps))
+		for i, p := range ps {
+			data[i] = ProductOperationJSON{
+				Index: i,
+				Op:    ops[i].Kind,
+				Id:    p.Id,
+			}
+			if ops[i].Kind == internal.ProductOperationDelete {
+				continue
+			}
+			data[i].Data = &ProductJSON{
+				Id:          p.Id,
+				Name:        p.Name,
+				Quantity:    p.Quantity,
+				CodeValue:   p.CodeValue,
+				IsPublished: p.IsPublished,
+				Expiration:  p.Expiration.Format(time.DateOnly),
+				Price:       p.Price,
+				Currency:    p.Price.Currency,
+			}
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// productOperation validates an operation of a batch.
+func productOperation(o RequestBodyProductOperation) (op internal.ProductOperation, err error) {
+	switch o.Op {
+	case internal.ProductOperationCreate:
+	case internal.ProductOperationUpdate, internal.ProductOperationDelete:
+		if o.Id <= 0 {
+			err = errors.New("invalid id")
+			return
+		}
+	default:
+		err = errors.New("invalid op")
+		return
+	}
+
+	op = internal.ProductOperation{
+		Kind:    o.Op,
+		Product: internal.Product{Id: o.Id},
+	}
+	if o.Op == internal.ProductOperationDelete {
+		return
+	}
+
+	if o.Product == nil {
+		err = errors.New("product is required")
+		return
+	}
+	op.Product.ProductAttributes, err = productAttributes(*o.Product)
+	if err != nil {
+		return
+	}
+
+	return
+}
@@ -0,0 +1,133 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/store"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// repositoryProductConflicting is a repository of products whose batches conflict with a concurrent modification.
+type repositoryProductConflicting struct {
+	internal.RepositoryProduct
+}
+
+// Batch fails on the last operation, its product modified concurrently.
+func (r repositoryProductConflicting) Batch(ctx context.Context, ops []internal.ProductOperation) (p []internal.Product, err error) {
+	err = &internal.ProductOperationError{Index: len(ops) - 1, Err: internal.ErrRepositoryProductVersionConflict}
+	return
+}
+
+// Tests for HandlerProduct.Batch
+func TestHandlerProduct_Batch(t *testing.T) {
+	t.Run("success - operations applied", func(t *testing.T) {
+		// arrange
+		st := store.NewStoreProductMemory(map[int]internal.Product{
+			1: {Id: 1, ProductAttributes: internal.ProductAttributes{Name: "old", CodeValue: "A1"}, Version: 1},
+			2: {Id: 2, ProductAttributes: internal.ProductAttributes{Name: "gone", CodeValue: "B1"}, Version: 1},
+		})
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(st), nil)
+
+		// act
+		res := serve(hd.Batch(), http.MethodPost, "/products/batch", "/products/batch", "application/json", `{"operations":[
+			{"op":"create","product":{"name":"new","quantity":1,"code_value":"C1","expiration":"2030-01-01","price":1.5}},
+			{"op":"update","id":1,"product":{"name":"renamed","quantity":2,"code_value":"A1","expiration":"2030-01-01","price":2}},
+			{"op":"delete","id":2}
+		]}`)
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":[
+			{"index":0,"op":"create","id":3,"data":{"id":3,"name":"new","quantity":1,"code_value":"C1","is_published":false,"expiration":"2030-01-01","price":1.50,"currency":"USD"}},
+			{"index":1,"op":"update","id":1,"data":{"id":1,"name":"renamed","quantity":2,"code_value":"A1","is_published":false,"expiration":"2030-01-01","price":2.00,"currency":"USD"}},
+			{"index":2,"op":"delete","id":2}
+		]}`, res.Body.String())
+		ps, err := st.ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, "renamed", ps[1].Name)
+		require.False(t, ps[2].DeletedAt.IsZero())
+		require.Equal(t, "new", ps[3].Name)
+	})
+
+	t.Run("error - no operations", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(store.NewStoreProductMemory(nil)), nil)
+
+		// act
+		res := serve(hd.Batch(), http.MethodPost, "/products/batch", "/products/batch", "application/json", `{"operations":[]}`)
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"operations are required"`, res.Body.String())
+	})
+
+	t.Run("error - invalid operation", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(store.NewStoreProductMemory(nil)), nil)
+
+		// act
+		res := serve(hd.Batch(), http.MethodPost, "/products/batch", "/products/batch", "application/json", `{"operations":[
+			{"op":"delete","id":1},
+			{"op":"update","id":1}
+		]}`)
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `{"status":"Bad Request","message":"operation 1: product is required"}`, res.Body.String())
+	})
+
+	t.Run("error - product not found, no operation applied", func(t *testing.T) {
+		// arrange
+		st := store.NewStoreProductMemory(map[int]internal.Product{
+			1: {Id: 1, ProductAttributes: internal.ProductAttributes{Name: "old", CodeValue: "A1"}, Version: 1},
+		})
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(st), nil)
+
+		// act
+		res := serve(hd.Batch(), http.MethodPost, "/products/batch", "/products/batch", "application/json", `{"operations":[
+			{"op":"delete","id":1},
+			{"op":"delete","id":9}
+		]}`)
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `{"status":"Not Found","message":"operation 1: product not found"}`, res.Body.String())
+		ps, err := st.ReadAll()
+		require.NoError(t, err)
+		require.True(t, ps[1].DeletedAt.IsZero())
+	})
+
+	t.Run("error - product modified concurrently", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repositoryProductConflicting{}, nil)
+
+		// act
+		res := serve(hd.Batch(), http.MethodPost, "/products/batch", "/products/batch", "application/json", `{"operations":[
+			{"op":"delete","id":1},
+			{"op":"delete","id":2}
+		]}`)
+
+		// assert
+		require.Equal(t, http.StatusConflict, res.Code)
+		require.JSONEq(t, `{"status":"Conflict","message":"operation 1: product was modified concurrently"}`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(storeProductFailing{}), nil)
+
+		// act
+		res := serve(hd.Batch(), http.MethodPost, "/products/batch", "/products/batch", "application/json", `{"operations":[
+			{"op":"delete","id":1}
+		]}`)
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/logging"
+	"app/platform/web/response"
+	"net/http"
+	"time"
+)
+
+// expiringWithinDefault is the period of the expiring products when it is not sent.
+const expiringWithinDefault = 30 * 24 * time.Hour
+
+// today returns the current date, expirations are dates without time.
+func today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+// GetExpiring gets the products expiring from today to within a period.
+// - query parameter within is the period, e.g. 30d or 72h, 30 days by default
+// - query parameter warehouse_id scopes the products to a warehouse
+func (h *HandlerProduct) GetExpiring() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - query parameter: within
+		within, err := queryDays(r, "within", expiringWithinDefault)
+		if err != nil || within < 0 {
+			response.JSON(w, http.StatusBadRequest, "invalid within")
+			return
+		}
+		// - query parameter: warehouse_id
+		warehouseId, err := queryInt(r, "warehouse_id")
+		if err != nil || warehouseId < 0 {
+			response.JSON(w, http.StatusBadRequest, "invalid warehouse_id")
+			return
+		}
+
+		// process
+		from := today()
+		p, err := h.rp.FindExpiring(r.Context(), from, from.Add(within), warehouseId)
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    productsJSON(p),
+		})
+	}
+}
+
+// GetExpired gets the products expired before today.
+// - query parameter warehouse_id scopes the products to a warehouse
+func (h *HandlerProduct) GetExpired() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - query parameter: warehouse_id
+		warehouseId, err := queryInt(r, "warehouse_id")
+		if err != nil || warehouseId < 0 {
+			response.JSON(w, http.StatusBadRequest, "invalid warehouse_id")
+			return
+		}
+
+		// process
+		p, err := h.rp.FindExpired(r.Context(), today(), warehouseId)
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    productsJSON(p),
+		})
+	}
+}
+
+// UnpublishExpired unpublishes the published products expired before today.
+func (h *HandlerProduct) UnpublishExpired() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		p, err := h.rp.UnpublishExpired(r.Context(), today())
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    productsJSON(p),
+		})
+	}
+}
+
+// productsJSON serializes products to JSON.
+func productsJSON(p []internal.Product) (data []ProductJSON) {
+	data = make([]ProductJSON, len(p))
+	for i, v := range p {
+		data[i] = ProductJSON{
+			Id:          v.Id,
+			Name:        v.Name,
+			Quantity:    v.Quantity,
+			CodeValue:   v.CodeValue,
+			IsPublished: v.IsPublished,
+			Expiration:  v.Expiration.Format(time.DateOnly),
+			Price:       v.Price,
+			Currency:    v.Price.Currency,
+		}
+	}
+	return
+}
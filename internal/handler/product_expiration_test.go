@@ -0,0 +1,208 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/store"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newStoreProductExpiring returns a store of products expiring around today, by the given days from it and warehouse.
+func newStoreProductExpiring(published bool, days map[int][2]int) *store.StoreProductMemory {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	ps := make(map[int]internal.Product, len(days))
+	for id, d := range days {
+		ps[id] = internal.Product{
+			Id:                id,
+			ProductAttributes: internal.ProductAttributes{Name: "p", CodeValue: "c", IsPublished: published, Expiration: today.AddDate(0, 0, d[0])},
+			WarehouseId:       d[1],
+			Version:           1,
+		}
+	}
+	return store.NewStoreProductMemory(ps)
+}
+
+// productIds returns the ids of the products of a response, in order.
+func productIds(t *testing.T, res *httptest.ResponseRecorder) (ids []int) {
+	t.Helper()
+	var body struct {
+		Data []handler.ProductJSON `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+	ids = make([]int, len(body.Data))
+	for i, p := range body.Data {
+		ids[i] = p.Id
+	}
+	return
+}
+
+// Tests for HandlerProduct.GetExpiring
+func TestHandlerProduct_GetExpiring(t *testing.T) {
+	// - product 1 expired yesterday, 2 and 4 expire within days, 3 within months
+	days := map[int][2]int{1: {-1, 1}, 2: {10, 1}, 3: {60, 1}, 4: {5, 2}}
+
+	t.Run("success - expiring within 30 days by default", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(newStoreProductExpiring(true, days)), nil)
+
+		// act
+		res := serve(hd.GetExpiring(), http.MethodGet, "/products/expiring", "/products/expiring", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, []int{4, 2}, productIds(t, res))
+	})
+
+	t.Run("success - expiring within a period in a warehouse", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(newStoreProductExpiring(true, days)), nil)
+
+		// act
+		res := serve(hd.GetExpiring(), http.MethodGet, "/products/expiring", "/products/expiring?within=90d&warehouse_id=1", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, []int{2, 3}, productIds(t, res))
+	})
+
+	t.Run("error - invalid within", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(newStoreProductExpiring(true, days)), nil)
+
+		// act
+		res := serve(hd.GetExpiring(), http.MethodGet, "/products/expiring", "/products/expiring?within=-1d", "", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"invalid within"`, res.Body.String())
+	})
+
+	t.Run("error - invalid warehouse_id", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(newStoreProductExpiring(true, days)), nil)
+
+		// act
+		res := serve(hd.GetExpiring(), http.MethodGet, "/products/expiring", "/products/expiring?warehouse_id=a", "", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"invalid warehouse_id"`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(storeProductFailing{}), nil)
+
+		// act
+		res := serve(hd.GetExpiring(), http.MethodGet, "/products/expiring", "/products/expiring", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerProduct.GetExpired
+func TestHandlerProduct_GetExpired(t *testing.T) {
+	// - products 1 and 4 expired, in warehouses 1 and 2
+	days := map[int][2]int{1: {-1, 1}, 2: {10, 1}, 4: {-5, 2}}
+
+	t.Run("success - expired before today", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(newStoreProductExpiring(true, days)), nil)
+
+		// act
+		res := serve(hd.GetExpired(), http.MethodGet, "/products/expired", "/products/expired", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, []int{4, 1}, productIds(t, res))
+	})
+
+	t.Run("success - expired in a warehouse", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(newStoreProductExpiring(true, days)), nil)
+
+		// act
+		res := serve(hd.GetExpired(), http.MethodGet, "/products/expired", "/products/expired?warehouse_id=2", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, []int{4}, productIds(t, res))
+	})
+
+	t.Run("error - invalid warehouse_id", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(newStoreProductExpiring(true, days)), nil)
+
+		// act
+		res := serve(hd.GetExpired(), http.MethodGet, "/products/expired", "/products/expired?warehouse_id=-1", "", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"invalid warehouse_id"`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(storeProductFailing{}), nil)
+
+		// act
+		res := serve(hd.GetExpired(), http.MethodGet, "/products/expired", "/products/expired", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerProduct.UnpublishExpired
+func TestHandlerProduct_UnpublishExpired(t *testing.T) {
+	t.Run("success - published expired products unpublished", func(t *testing.T) {
+		// arrange
+		st := newStoreProductExpiring(true, map[int][2]int{1: {-1, 1}, 2: {10, 1}})
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(st), nil)
+
+		// act
+		res := serve(hd.UnpublishExpired(), http.MethodPost, "/products/expired/unpublish", "/products/expired/unpublish", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, []int{1}, productIds(t, res))
+		ps, err := st.ReadAll()
+		require.NoError(t, err)
+		require.False(t, ps[1].IsPublished)
+		require.True(t, ps[2].IsPublished)
+	})
+
+	t.Run("success - nothing to unpublish", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(newStoreProductExpiring(false, map[int][2]int{1: {-1, 1}})), nil)
+
+		// act
+		res := serve(hd.UnpublishExpired(), http.MethodPost, "/products/expired/unpublish", "/products/expired/unpublish", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":[]}`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(storeProductFailing{}), nil)
+
+		// act
+		res := serve(hd.UnpublishExpired(), http.MethodPost, "/products/expired/unpublish", "/products/expired/unpublish", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/logging"
+	"app/platform/web/request"
+	"app/platform/web/response"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ProductImportCreated is the status of an imported row that created a product.
+	ProductImportCreated = "created"
+	// ProductImportUpdated is the status of an imported row that updated a product.
+	ProductImportUpdated = "updated"
+	// ProductImportFailed is the status of an imported row that could not be imported.
+	ProductImportFailed = "failed"
+)
+
+// RequestBodyProductImport is a row of a product import.
+// - an empty id creates the product, otherwise the product with that id is updated
+type RequestBodyProductImport struct {
+	Id          int            `json:"id"`
+	Name        string         `json:"name"`
+	Quantity    int            `json:"quantity"`
+	CodeValue   string         `json:"code_value"`
+	IsPublished bool           `json:"is_published"`
+	Expiration  string         `json:"expiration"`
+	Price       internal.Money `json:"price"`
+	Currency    string         `json:"currency"`
+}
+
+// ProductImportRowJSON is the result of importing a row in JSON format.
+type ProductImportRowJSON struct {
+	Row    int    `json:"row"`
+	Status string `json:"status"`
+	Id     int    `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ProductImportJSON is the report of a product import in JSON format.
+type ProductImportJSON struct {
+	Created int                    `json:"created"`
+	Updated int                    `json:"updated"`
+	Failed  int                    `json:"failed"`
+	Rows    []ProductImportRowJSON `json:"rows"`
+}
+
+// Import creates or updates products from a csv or a json array.
+// - each row is validated and imported on its own, so a failing row does not stop the rest
+func (h *HandlerProduct) Import() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - body: csv or json array
+		var rows []RequestBodyProductImport
+		var rowsErr []error
+		switch {
+		case strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv"):
+			records, err := request.CSV(r)
+			if err != nil {
+				invalidBody(w, err)
+				return
+			}
+			rows = make([]RequestBodyProductImport, len(records))
+			rowsErr = make([]error, len(records))
+			for i, record := range records {
+				rows[i], rowsErr[i] = productImportFromCSV(record)
+			}
+		default:
+			err := request.JSON(r, &rows)
+			if err != nil {
+				invalidBody(w, err)
+				return
+			}
+			rowsErr = make([]error, len(rows))
+		}
+
+		// process
+		// - import each row
+		report := ProductImportJSON{
+			Rows: make([]ProductImportRowJSON, 0, len(rows)),
+		}
+		for i, row := range rows {
+			result := ProductImportRowJSON{Row: i + 1}
+
+			var status string
+			err := rowsErr[i]
+			if err == nil {
+				result.Id, status, err = h.importRow(r.Context(), row)
+			}
+			if err != nil {
+				result.Status = ProductImportFailed
+				result.Error = err.Error()
+				report.Failed++
+				report.Rows = append(report.Rows, result)
+				continue
+			}
+
+			result.Status = status
+			switch status {
+			case ProductImportCreated:
+				report.Created++
+			case ProductImportUpdated:
+				report.Updated++
+			}
+			report.Rows = append(report.Rows, result)
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    report,
+		})
+	}
+}
+
+// importRow validates a row and creates or updates its product.
+func (h *HandlerProduct) importRow(ctx context.Context, row RequestBodyProductImport) (id int, status string, err error) {
+	// validate
+	if row.Id < 0 {
+		err = errors.New("invalid id")
+		return
+	}
+	attributes, err := productAttributes(RequestBodyProductCreate{
+		Name:        row.Name,
+		Quantity:    row.Quantity,
+		CodeValue:   row.CodeValue,
+		IsPublished: row.IsPublished,
+		Expiration:  row.Expiration,
+		Price:       row.Price,
+		Currency:    row.Currency,
+	})
+	if err != nil {
+		return
+	}
+
+	// create
+	if row.Id == 0 {
+		p := internal.Product{ProductAttributes: attributes}
+		err = h.rp.Save(ctx, &p)
+		if err != nil {
+			logging.Error(ctx, fmt.Errorf("import: create product: %w", err))
+			err = errors.New("could not create product")
+			return
+		}
+		id, status = p.Id, ProductImportCreated
+		return
+	}
+
+	// update
+	p, err := h.rp.FindById(ctx, row.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, internal.ErrRepositoryProductNotFound):
+			err = errors.New("product not found")
+		default:
+			logging.Error(ctx, fmt.Errorf("import: find product %d: %w", row.Id, err))
+			err = errors.New("could not find product")
+		}
+		return
+	}
+	p.ProductAttributes = attributes
+	err = h.rp.Update(ctx, &p)
+	if err != nil {
+		logging.Error(ctx, fmt.Errorf("import: update product %d: %w", row.Id, err))
+		err = errors.New("could not update product")
+		return
+	}
+	id, status = p.Id, ProductImportUpdated
+	return
+}
+
+// productImportFromCSV deserializes a csv record into a row.
+func productImportFromCSV(record map[string]string) (row RequestBodyProductImport, err error) {
+	row.Name = record["name"]
+	row.CodeValue = record["code_value"]
+	row.Expiration = record["expiration"]
+	row.Currency = record["currency"]
+
+	if v := record["id"]; v != "" {
+		row.Id, err = strconv.Atoi(v)
+		if err != nil {
+			err = fmt.Errorf("invalid id %q", v)
+			return
+		}
+	}
+	if v := record["quantity"]; v != "" {
+		row.Quantity, err = strconv.Atoi(v)
+		if err != nil {
+			err = fmt.Errorf("invalid quantity %q", v)
+			return
+		}
+	}
+	if v := record["is_published"]; v != "" {
+		row.IsPublished, err = strconv.ParseBool(v)
+		if err != nil {
+			err = fmt.Errorf("invalid is_published %q", v)
+			return
+		}
+	}
+	if v := record["price"]; v != "" {
+		row.Price, err = internal.ParseMoney(v, row.Currency)
+		if err != nil {
+			err = fmt.Errorf("invalid price %q", v)
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,115 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/store"
+	"app/platform/web/logging"
+	"app/platform/web/request"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for HandlerProduct.Import
+func TestHandlerProduct_Import(t *testing.T) {
+	t.Run("success - json rows created, updated and failed on their own", func(t *testing.T) {
+		// arrange
+		st := store.NewStoreProductMemory(map[int]internal.Product{
+			1: {Id: 1, ProductAttributes: internal.ProductAttributes{Name: "old", CodeValue: "A1"}, Version: 1},
+		})
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(st), nil)
+
+		// act
+		res := serve(hd.Import(), http.MethodPost, "/products/import", "/products/import", "application/json", `[
+			{"name":"new","quantity":1,"code_value":"B1","expiration":"2030-01-01","price":1.5},
+			{"id":1,"name":"renamed","quantity":2,"code_value":"A1","expiration":"2030-01-01","price":2,"currency":"EUR"},
+			{"quantity":1,"code_value":"C1","expiration":"2030-01-01"},
+			{"id":9,"name":"missing","code_value":"D1","expiration":"2030-01-01"}
+		]`)
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":{"created":1,"updated":1,"failed":2,"rows":[
+			{"row":1,"status":"created","id":2},
+			{"row":2,"status":"updated","id":1},
+			{"row":3,"status":"failed","error":"name is required"},
+			{"row":4,"status":"failed","error":"product not found"}
+		]}}`, res.Body.String())
+		ps, err := st.ReadAll()
+		require.NoError(t, err)
+		require.Len(t, ps, 2)
+		require.Equal(t, "renamed", ps[1].Name)
+		require.Equal(t, internal.NewMoney(200, "EUR"), ps[1].Price)
+	})
+
+	t.Run("success - csv rows", func(t *testing.T) {
+		// arrange
+		st := store.NewStoreProductMemory(nil)
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(st), nil)
+
+		// act
+		res := serve(hd.Import(), http.MethodPost, "/products/import", "/products/import", "text/csv",
+			"name,quantity,code_value,is_published,expiration,price\n"+
+				"new,1,A1,true,2030-01-01,1.50\n"+
+				"bad,x,B1,false,2030-01-01,1.50\n")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":{"created":1,"updated":0,"failed":1,"rows":[
+			{"row":1,"status":"created","id":1},
+			{"row":2,"status":"failed","error":"invalid quantity \"x\""}
+		]}}`, res.Body.String())
+	})
+
+	t.Run("success - rows failing to be saved are reported, their causes logged", func(t *testing.T) {
+		// arrange
+		var buf bytes.Buffer
+		l := logging.NewLogger(&logging.Config{Format: logging.FormatJSON, Output: &buf})
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(storeProductFailing{}), nil)
+
+		// act
+		res := serve(logging.AccessLog(l)(hd.Import()).ServeHTTP, http.MethodPost, "/products/import", "/products/import", "application/json",
+			`[{"name":"new","code_value":"A1","expiration":"2030-01-01"},{"id":1,"name":"old","code_value":"B1","expiration":"2030-01-01"}]`)
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":{"created":0,"updated":0,"failed":2,"rows":[
+			{"row":1,"status":"failed","error":"could not create product"},
+			{"row":2,"status":"failed","error":"could not find product"}
+		]}}`, res.Body.String())
+		var rec map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &rec))
+		require.Equal(t, "import: create product: store: connection refused\nimport: find product 1: store: connection refused", rec["error"])
+	})
+
+	t.Run("error - invalid body", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(store.NewStoreProductMemory(nil)), nil)
+
+		// act
+		res := serve(hd.Import(), http.MethodPost, "/products/import", "/products/import", "application/json", `{"name":"new"}`)
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"invalid body"`, res.Body.String())
+	})
+
+	t.Run("error - body too large", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(store.NewStoreProductMemory(nil)), nil)
+
+		// act
+		res := serve(hd.Import(), http.MethodPost, "/products/import", "/products/import", "text/csv",
+			"name\n"+strings.Repeat("new\n", request.MaxBodySize/4+1))
+
+		// assert
+		require.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+		require.JSONEq(t, `"request body too large"`, res.Body.String())
+	})
+}
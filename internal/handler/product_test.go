@@ -0,0 +1,227 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/store"
+	"app/platform/web/auth"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for HandlerProduct.GetById
+func TestHandlerProduct_GetById(t *testing.T) {
+	// - product 1 costs 1.50 USD
+	newStoreProduct := func() *store.StoreProductMemory {
+		return store.NewStoreProductMemory(map[int]internal.Product{
+			1: {Id: 1, ProductAttributes: internal.ProductAttributes{Name: "p", Quantity: 1, CodeValue: "A1", Expiration: date(t, "2030-01-01"), Price: internal.NewMoney(150, "USD")}, Version: 2},
+		})
+	}
+
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(newStoreProduct()), nil)
+
+		// act
+		res := serve(hd.GetById(), http.MethodGet, "/products/{id}", "/products/1", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, `"2"`, res.Header().Get("ETag"))
+		require.JSONEq(t, `{"message":"success","data":{"id":1,"name":"p","quantity":1,"code_value":"A1","is_published":false,"expiration":"2030-01-01","price":1.50,"currency":"USD"}}`, res.Body.String())
+	})
+
+	t.Run("success - price converted to a currency, tagged with it", func(t *testing.T) {
+		// arrange
+		rr := repository.NewRepositoryExchangeRateStore(newStoreExchangeRate(t, map[string]string{"EUR": "0.92", "JPY": "151.2"}))
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(newStoreProduct()), rr)
+
+		// act
+		resEUR := serve(hd.GetById(), http.MethodGet, "/products/{id}", "/products/1?currency=eur", "", "")
+		resJPY := serve(hd.GetById(), http.MethodGet, "/products/{id}", "/products/1?currency=JPY", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, resEUR.Code)
+		require.Equal(t, `"2-EUR"`, resEUR.Header().Get("ETag"))
+		require.JSONEq(t, `{"message":"success","data":{"id":1,"name":"p","quantity":1,"code_value":"A1","is_published":false,"expiration":"2030-01-01","price":1.38,"currency":"EUR"}}`, resEUR.Body.String())
+		require.Equal(t, http.StatusOK, resJPY.Code)
+		require.Equal(t, `"2-JPY"`, resJPY.Header().Get("ETag"))
+		require.JSONEq(t, `{"message":"success","data":{"id":1,"name":"p","quantity":1,"code_value":"A1","is_published":false,"expiration":"2030-01-01","price":227,"currency":"JPY"}}`, resJPY.Body.String())
+	})
+
+	t.Run("error - unsupported currency", func(t *testing.T) {
+		// arrange
+		rr := repository.NewRepositoryExchangeRateStore(newStoreExchangeRate(t, nil))
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(newStoreProduct()), rr)
+
+		// act
+		res := serve(hd.GetById(), http.MethodGet, "/products/{id}", "/products/1?currency=EUR", "", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"unsupported currency"`, res.Body.String())
+	})
+
+	t.Run("error - product not found", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(newStoreProduct()), nil)
+
+		// act
+		res := serve(hd.GetById(), http.MethodGet, "/products/{id}", "/products/9", "", "")
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `"product not found"`, res.Body.String())
+	})
+
+	t.Run("error - exchange rates failing", func(t *testing.T) {
+		// arrange
+		rr := repository.NewRepositoryExchangeRateStore(storeExchangeRateFailing{})
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(newStoreProduct()), rr)
+
+		// act
+		res := serve(hd.GetById(), http.MethodGet, "/products/{id}", "/products/1?currency=EUR", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerProduct.GetAll
+func TestHandlerProduct_GetAll(t *testing.T) {
+	// - product 2 is deleted
+	newStoreProduct := func() *store.StoreProductMemory {
+		return store.NewStoreProductMemory(map[int]internal.Product{
+			1: {Id: 1, ProductAttributes: internal.ProductAttributes{Name: "p1", CodeValue: "A1"}, Version: 1},
+			2: {Id: 2, ProductAttributes: internal.ProductAttributes{Name: "p2", CodeValue: "B1"}, Version: 2, DeletedAt: time.Now()},
+		})
+	}
+
+	admin := &auth.Principal{Role: auth.RoleAdmin}
+	viewer := &auth.Principal{Role: auth.RoleViewer}
+
+	listed := []struct {
+		name      string
+		principal *auth.Principal
+		target    string
+		count     int
+	}{
+		{"success - deleted products not listed", viewer, "/products", 1},
+		{"success - deleted products listed to an admin", admin, "/products?include_deleted=true", 2},
+		{"success - deleted products listed without authentication", nil, "/products?include_deleted=true", 2},
+	}
+	for _, c := range listed {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(newStoreProduct()), nil).GetAll()
+			if c.principal != nil {
+				hd = asPrincipal(hd, *c.principal)
+			}
+
+			// act
+			res := serve(hd, http.MethodGet, "/products", c.target, "", "")
+
+			// assert
+			require.Equal(t, http.StatusOK, res.Code)
+			var ps []map[string]any
+			require.NoError(t, json.Unmarshal(res.Body.Bytes(), &ps))
+			require.Len(t, ps, c.count)
+		})
+	}
+
+	t.Run("error - deleted products listed to an operator", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(newStoreProduct()), nil)
+
+		// act
+		res := serve(asPrincipal(hd.GetAll(), auth.Principal{Role: auth.RoleOperator}), http.MethodGet, "/products", "/products?include_deleted=true", "", "")
+
+		// assert
+		require.Equal(t, http.StatusForbidden, res.Code)
+		require.JSONEq(t, `"forbidden"`, res.Body.String())
+	})
+
+	t.Run("error - invalid include_deleted", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(newStoreProduct()), nil)
+
+		// act
+		res := serve(hd.GetAll(), http.MethodGet, "/products", "/products?include_deleted=maybe", "", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"invalid include_deleted"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerProduct.Restore
+func TestHandlerProduct_Restore(t *testing.T) {
+	t.Run("success - deleted product restored", func(t *testing.T) {
+		// arrange
+		st := store.NewStoreProductMemory(map[int]internal.Product{
+			1: {
+				Id:                1,
+				ProductAttributes: internal.ProductAttributes{Name: "old", Quantity: 1, CodeValue: "A1", Expiration: date(t, "2030-01-01"), Price: internal.NewMoney(150, "USD")},
+				Version:           2,
+				DeletedAt:         time.Now(),
+			},
+		})
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(st), nil)
+
+		// act
+		res := serve(hd.Restore(), http.MethodPost, "/products/{id}/restore", "/products/1/restore", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, `"3"`, res.Header().Get("ETag"))
+		require.JSONEq(t, `{"message":"success","data":{"id":1,"name":"old","quantity":1,"code_value":"A1","is_published":false,"expiration":"2030-01-01","price":1.50,"currency":"USD"}}`, res.Body.String())
+		ps, err := st.ReadAll()
+		require.NoError(t, err)
+		require.True(t, ps[1].DeletedAt.IsZero())
+	})
+
+	t.Run("error - invalid id", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(store.NewStoreProductMemory(nil)), nil)
+
+		// act
+		res := serve(hd.Restore(), http.MethodPost, "/products/{id}/restore", "/products/a/restore", "", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"invalid id"`, res.Body.String())
+	})
+
+	t.Run("error - product not deleted", func(t *testing.T) {
+		// arrange
+		st := store.NewStoreProductMemory(map[int]internal.Product{
+			1: {Id: 1, ProductAttributes: internal.ProductAttributes{Name: "old", CodeValue: "A1"}, Version: 1},
+		})
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(st), nil)
+
+		// act
+		res := serve(hd.Restore(), http.MethodPost, "/products/{id}/restore", "/products/1/restore", "", "")
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `"deleted product not found"`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := handler.NewHandlerProduct(repository.NewRepositoryProductStore(storeProductFailing{}), nil)
+
+		// act
+		res := serve(hd.Restore(), http.MethodPost, "/products/{id}/restore", "/products/1/restore", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
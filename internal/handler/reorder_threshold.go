@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/logging"
+	"app/platform/web/request"
+	"app/platform/web/response"
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// NewHandlerReorderThreshold creates a new handler for reorder thresholds.
+func NewHandlerReorderThreshold(rp internal.RepositoryProduct, rw internal.RepositoryWarehouse, rt internal.RepositoryReorderThreshold) (h *HandlerReorderThreshold) {
+	h = &HandlerReorderThreshold{
+		rp: rp,
+		rw: rw,
+		rt: rt,
+	}
+	return
+}
+
+// HandlerReorderThreshold is a handler for reorder thresholds.
+type HandlerReorderThreshold struct {
+	// rp is the repository for products.
+	rp internal.RepositoryProduct
+	// rw is the repository for warehouses.
+	rw internal.RepositoryWarehouse
+	// rt is the repository for reorder thresholds.
+	rt internal.RepositoryReorderThreshold
+}
+
+// ReorderThresholdJSON is a reorder threshold in JSON format.
+type ReorderThresholdJSON struct {
+	Scope    string `json:"scope"`
+	ScopeId  int    `json:"scope_id"`
+	Quantity int    `json:"quantity"`
+}
+
+// RequestBodyReorderThreshold is a request body for setting a reorder threshold.
+type RequestBodyReorderThreshold struct {
+	Quantity *int `json:"quantity"`
+}
+
+// ProductLowStockJSON is a product low on stock in JSON format.
+type ProductLowStockJSON struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name"`
+	CodeValue   string `json:"code_value"`
+	WarehouseId int    `json:"warehouse_id"`
+	Quantity    int    `json:"quantity"`
+	Threshold   int    `json:"threshold"`
+	Missing     int    `json:"missing"`
+}
+
+// GetAll gets all reorder thresholds.
+// - only the thresholds of the products and warehouses of the tenant of the request
+func (h *HandlerReorderThreshold) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		ts, err := h.rt.GetAll(r.Context())
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		ps, err := h.rp.GetAllIncludingDeleted(r.Context())
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		ws, err := h.rw.GetAllIncludingDeleted(r.Context())
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		visible := make(map[internal.ReorderScope]bool, len(ps)+len(ws))
+		for _, p := range ps {
+			visible[internal.ReorderScope{Kind: internal.ReorderScopeProduct, Id: p.Id}] = true
+		}
+		for _, wh := range ws {
+			visible[internal.ReorderScope{Kind: internal.ReorderScopeWarehouse, Id: wh.Id}] = true
+		}
+
+		// response
+		data := make([]ReorderThresholdJSON, 0, len(ts))
+		for _, t := range ts {
+			if !visible[t.ReorderScope] {
+				continue
+			}
+			data = append(data, reorderThresholdJSON(t))
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// LowStock gets the products below their reorder threshold.
+// - query parameter warehouse_id scopes the products to a warehouse
+func (h *HandlerReorderThreshold) LowStock() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - query parameter: warehouse_id
+		warehouseId, err := queryInt(r, "warehouse_id")
+		if err != nil || warehouseId < 0 {
+			response.JSON(w, http.StatusBadRequest, "invalid warehouse_id")
+			return
+		}
+
+		// process
+		ps, err := h.rp.GetAll(r.Context())
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		ts, err := h.rt.GetAll(r.Context())
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		thresholds := internal.NewReorderThresholds(ts)
+
+		// response
+		// - sorted by id
+		data := make([]ProductLowStockJSON, 0)
+		for _, p := range ps {
+			if warehouseId != 0 && p.WarehouseId != warehouseId {
+				continue
+			}
+			q, low := thresholds.IsLow(p)
+			if !low {
+				continue
+			}
+			data = append(data, ProductLowStockJSON{
+				Id:          p.Id,
+				Name:        p.Name,
+				CodeValue:   p.CodeValue,
+				WarehouseId: p.WarehouseId,
+				Quantity:    p.Quantity,
+				Threshold:   q,
+				Missing:     q - p.Quantity,
+			})
+		}
+		sort.Slice(data, func(i, j int) bool {
+			return data[i].Id < data[j].Id
+		})
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// SetProduct sets the reorder threshold of a product.
+func (h *HandlerReorderThreshold) SetProduct() http.HandlerFunc {
+	return h.set(internal.ReorderScopeProduct)
+}
+
+// DeleteProduct deletes the reorder threshold of a product, it falls back to the one of its warehouse.
+func (h *HandlerReorderThreshold) DeleteProduct() http.HandlerFunc {
+	return h.delete(internal.ReorderScopeProduct)
+}
+
+// SetWarehouse sets the reorder threshold of every product of a warehouse.
+func (h *HandlerReorderThreshold) SetWarehouse() http.HandlerFunc {
+	return h.set(internal.ReorderScopeWarehouse)
+}
+
+// DeleteWarehouse deletes the reorder threshold of a warehouse.
+func (h *HandlerReorderThreshold) DeleteWarehouse() http.HandlerFunc {
+	return h.delete(internal.ReorderScopeWarehouse)
+}
+
+// set sets the reorder threshold of a scope, the product or warehouse of the id path parameter.
+func (h *HandlerReorderThreshold) set(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: id
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || id <= 0 {
+			response.JSON(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		// - body
+		var body RequestBodyReorderThreshold
+		err = request.JSON(r, &body)
+		if err != nil {
+			invalidBody(w, err)
+			return
+		}
+		if body.Quantity == nil || *body.Quantity < 0 {
+			response.JSON(w, http.StatusBadRequest, "quantity must be a non negative number")
+			return
+		}
+
+		// process
+		// - the scope must exist
+		err = h.exists(r.Context(), kind, id)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRepositoryProductNotFound):
+				response.JSON(w, http.StatusNotFound, "product not found")
+			case errors.Is(err, internal.ErrRepositoryWarehouseNotFound):
+				response.JSON(w, http.StatusNotFound, "warehouse not found")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+		t := internal.ReorderThreshold{
+			ReorderScope: internal.ReorderScope{Kind: kind, Id: id},
+			Quantity:     *body.Quantity,
+		}
+		err = h.rt.Save(r.Context(), &t)
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    reorderThresholdJSON(t),
+		})
+	}
+}
+
+// delete deletes the reorder threshold of a scope, the product or warehouse of the id path parameter.
+func (h *HandlerReorderThreshold) delete(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: id
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.JSON(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		// - the scope must be of the tenant of the request
+		err = h.exists(r.Context(), kind, id)
+		if err == nil {
+			err = h.rt.Delete(r.Context(), internal.ReorderScope{Kind: kind, Id: id})
+		}
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRepositoryReorderThresholdNotFound),
+				errors.Is(err, internal.ErrRepositoryProductNotFound),
+				errors.Is(err, internal.ErrRepositoryWarehouseNotFound):
+				response.JSON(w, http.StatusNotFound, "reorder threshold not found")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusNoContent, nil)
+	}
+}
+
+// exists checks that the product or warehouse of a scope exists.
+func (h *HandlerReorderThreshold) exists(ctx context.Context, kind string, id int) (err error) {
+	switch kind {
+	case internal.ReorderScopeProduct:
+		_, err = h.rp.FindById(ctx, id)
+	case internal.ReorderScopeWarehouse:
+		_, err = h.rw.FindById(ctx, id)
+	}
+	return
+}
+
+// reorderThresholdJSON serializes a reorder threshold to JSON.
+func reorderThresholdJSON(t internal.ReorderThreshold) ReorderThresholdJSON {
+	return ReorderThresholdJSON{
+		Scope:    t.Kind,
+		ScopeId:  t.Id,
+		Quantity: t.Quantity,
+	}
+}
@@ -0,0 +1,283 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/store"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newHandlerReorderThreshold returns a handler of the reorder thresholds of a store, with the products and warehouses of two stores.
+func newHandlerReorderThreshold(stProduct internal.StoreProduct, stThreshold internal.StoreReorderThreshold) *handler.HandlerReorderThreshold {
+	stWarehouse := store.NewStoreWarehouseMemory(map[int]internal.Warehouse{
+		1: {Id: 1, WarehouseAttributes: internal.WarehouseAttributes{Name: "w1", Capacity: 10}, Version: 1},
+		2: {Id: 2, WarehouseAttributes: internal.WarehouseAttributes{Name: "w2", Capacity: 10}, Version: 1},
+	})
+	return handler.NewHandlerReorderThreshold(
+		repository.NewRepositoryProductStore(stProduct),
+		repository.NewRepositoryWarehouseStore(stWarehouse, stProduct),
+		repository.NewRepositoryReorderThresholdStore(stThreshold),
+	)
+}
+
+// newStoreReorderThresholdProducts returns the products of the tests of the reorder thresholds.
+// - products 1 and 2 are in warehouse 1, product 3 is in warehouse 2
+func newStoreReorderThresholdProducts() *store.StoreProductMemory {
+	return store.NewStoreProductMemory(map[int]internal.Product{
+		1: {Id: 1, ProductAttributes: internal.ProductAttributes{Name: "p1", Quantity: 2, CodeValue: "A1"}, WarehouseId: 1, Version: 1},
+		2: {Id: 2, ProductAttributes: internal.ProductAttributes{Name: "p2", Quantity: 10, CodeValue: "B1"}, WarehouseId: 1, Version: 1},
+		3: {Id: 3, ProductAttributes: internal.ProductAttributes{Name: "p3", Quantity: 1, CodeValue: "C1"}, WarehouseId: 2, Version: 1},
+	})
+}
+
+// newStoreReorderThresholds returns the thresholds of the tests of the reorder thresholds.
+// - warehouse 1 is reordered below 5 units, product 2 below 20 units
+func newStoreReorderThresholds() *store.StoreReorderThresholdMemory {
+	return store.NewStoreReorderThresholdMemory(map[string]map[internal.ReorderScope]internal.ReorderThreshold{
+		internal.TenantDefault: {
+			{Kind: internal.ReorderScopeWarehouse, Id: 1}: {ReorderScope: internal.ReorderScope{Kind: internal.ReorderScopeWarehouse, Id: 1}, Quantity: 5},
+			{Kind: internal.ReorderScopeProduct, Id: 2}:   {ReorderScope: internal.ReorderScope{Kind: internal.ReorderScopeProduct, Id: 2}, Quantity: 20},
+		},
+	})
+}
+
+// Tests for HandlerReorderThreshold.GetAll
+func TestHandlerReorderThreshold_GetAll(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		hd := newHandlerReorderThreshold(newStoreReorderThresholdProducts(), newStoreReorderThresholds())
+
+		// act
+		res := serve(hd.GetAll(), http.MethodGet, "/reorder-thresholds", "/reorder-thresholds", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":[
+			{"scope":"product","scope_id":2,"quantity":20},
+			{"scope":"warehouse","scope_id":1,"quantity":5}
+		]}`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := newHandlerReorderThreshold(storeProductFailing{}, newStoreReorderThresholds())
+
+		// act
+		res := serve(hd.GetAll(), http.MethodGet, "/reorder-thresholds", "/reorder-thresholds", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerReorderThreshold.LowStock
+func TestHandlerReorderThreshold_LowStock(t *testing.T) {
+	t.Run("success - products below their threshold, the product one over the warehouse one", func(t *testing.T) {
+		// arrange
+		hd := newHandlerReorderThreshold(newStoreReorderThresholdProducts(), newStoreReorderThresholds())
+
+		// act
+		res := serve(hd.LowStock(), http.MethodGet, "/products/low-stock", "/products/low-stock", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":[
+			{"id":1,"name":"p1","code_value":"A1","warehouse_id":1,"quantity":2,"threshold":5,"missing":3},
+			{"id":2,"name":"p2","code_value":"B1","warehouse_id":1,"quantity":10,"threshold":20,"missing":10}
+		]}`, res.Body.String())
+	})
+
+	t.Run("success - scoped to a warehouse", func(t *testing.T) {
+		// arrange
+		hd := newHandlerReorderThreshold(newStoreReorderThresholdProducts(), newStoreReorderThresholds())
+
+		// act
+		res := serve(hd.LowStock(), http.MethodGet, "/products/low-stock", "/products/low-stock?warehouse_id=2", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":[]}`, res.Body.String())
+	})
+
+	t.Run("error - invalid warehouse_id", func(t *testing.T) {
+		// arrange
+		hd := newHandlerReorderThreshold(newStoreReorderThresholdProducts(), newStoreReorderThresholds())
+
+		// act
+		res := serve(hd.LowStock(), http.MethodGet, "/products/low-stock", "/products/low-stock?warehouse_id=a", "", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"invalid warehouse_id"`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := newHandlerReorderThreshold(storeProductFailing{}, newStoreReorderThresholds())
+
+		// act
+		res := serve(hd.LowStock(), http.MethodGet, "/products/low-stock", "/products/low-stock", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerReorderThreshold.SetProduct and HandlerReorderThreshold.SetWarehouse
+func TestHandlerReorderThreshold_Set(t *testing.T) {
+	t.Run("success - product threshold set", func(t *testing.T) {
+		// arrange
+		st := newStoreReorderThresholds()
+		hd := newHandlerReorderThreshold(newStoreReorderThresholdProducts(), st)
+
+		// act
+		res := serve(hd.SetProduct(), http.MethodPut, "/products/{id}/reorder-threshold", "/products/3/reorder-threshold", "application/json", `{"quantity":4}`)
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":{"scope":"product","scope_id":3,"quantity":4}}`, res.Body.String())
+		ts, err := st.ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, 4, ts[internal.TenantDefault][internal.ReorderScope{Kind: internal.ReorderScopeProduct, Id: 3}].Quantity)
+	})
+
+	t.Run("success - warehouse threshold replaced", func(t *testing.T) {
+		// arrange
+		st := newStoreReorderThresholds()
+		hd := newHandlerReorderThreshold(newStoreReorderThresholdProducts(), st)
+
+		// act
+		res := serve(hd.SetWarehouse(), http.MethodPut, "/warehouses/{id}/reorder-threshold", "/warehouses/1/reorder-threshold", "application/json", `{"quantity":0}`)
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":{"scope":"warehouse","scope_id":1,"quantity":0}}`, res.Body.String())
+		ts, err := st.ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, 0, ts[internal.TenantDefault][internal.ReorderScope{Kind: internal.ReorderScopeWarehouse, Id: 1}].Quantity)
+	})
+
+	invalid := []struct {
+		name    string
+		target  string
+		body    string
+		message string
+	}{
+		{"error - invalid id", "/products/a/reorder-threshold", `{"quantity":4}`, "invalid id"},
+		{"error - invalid body", "/products/1/reorder-threshold", `{"quantity":"4"}`, "invalid body"},
+		{"error - missing quantity", "/products/1/reorder-threshold", `{}`, "quantity must be a non negative number"},
+		{"error - negative quantity", "/products/1/reorder-threshold", `{"quantity":-1}`, "quantity must be a non negative number"},
+	}
+	for _, c := range invalid {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			hd := newHandlerReorderThreshold(newStoreReorderThresholdProducts(), newStoreReorderThresholds())
+
+			// act
+			res := serve(hd.SetProduct(), http.MethodPut, "/products/{id}/reorder-threshold", c.target, "application/json", c.body)
+
+			// assert
+			require.Equal(t, http.StatusBadRequest, res.Code)
+			require.JSONEq(t, `"`+c.message+`"`, res.Body.String())
+		})
+	}
+
+	t.Run("error - product not found", func(t *testing.T) {
+		// arrange
+		hd := newHandlerReorderThreshold(newStoreReorderThresholdProducts(), newStoreReorderThresholds())
+
+		// act
+		res := serve(hd.SetProduct(), http.MethodPut, "/products/{id}/reorder-threshold", "/products/9/reorder-threshold", "application/json", `{"quantity":4}`)
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `"product not found"`, res.Body.String())
+	})
+
+	t.Run("error - warehouse not found", func(t *testing.T) {
+		// arrange
+		hd := newHandlerReorderThreshold(newStoreReorderThresholdProducts(), newStoreReorderThresholds())
+
+		// act
+		res := serve(hd.SetWarehouse(), http.MethodPut, "/warehouses/{id}/reorder-threshold", "/warehouses/9/reorder-threshold", "application/json", `{"quantity":4}`)
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `"warehouse not found"`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := newHandlerReorderThreshold(storeProductFailing{}, newStoreReorderThresholds())
+
+		// act
+		res := serve(hd.SetProduct(), http.MethodPut, "/products/{id}/reorder-threshold", "/products/1/reorder-threshold", "application/json", `{"quantity":4}`)
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerReorderThreshold.DeleteProduct and HandlerReorderThreshold.DeleteWarehouse
+func TestHandlerReorderThreshold_Delete(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		st := newStoreReorderThresholds()
+		hd := newHandlerReorderThreshold(newStoreReorderThresholdProducts(), st)
+
+		// act
+		res := serve(hd.DeleteWarehouse(), http.MethodDelete, "/warehouses/{id}/reorder-threshold", "/warehouses/1/reorder-threshold", "", "")
+
+		// assert
+		require.Equal(t, http.StatusNoContent, res.Code)
+		ts, err := st.ReadAll()
+		require.NoError(t, err)
+		require.NotContains(t, ts[internal.TenantDefault], internal.ReorderScope{Kind: internal.ReorderScopeWarehouse, Id: 1})
+	})
+
+	t.Run("error - invalid id", func(t *testing.T) {
+		// arrange
+		hd := newHandlerReorderThreshold(newStoreReorderThresholdProducts(), newStoreReorderThresholds())
+
+		// act
+		res := serve(hd.DeleteProduct(), http.MethodDelete, "/products/{id}/reorder-threshold", "/products/a/reorder-threshold", "", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"invalid id"`, res.Body.String())
+	})
+
+	t.Run("error - reorder threshold not found", func(t *testing.T) {
+		// arrange
+		hd := newHandlerReorderThreshold(newStoreReorderThresholdProducts(), newStoreReorderThresholds())
+
+		// act
+		resNoThreshold := serve(hd.DeleteProduct(), http.MethodDelete, "/products/{id}/reorder-threshold", "/products/1/reorder-threshold", "", "")
+		resNoProduct := serve(hd.DeleteProduct(), http.MethodDelete, "/products/{id}/reorder-threshold", "/products/9/reorder-threshold", "", "")
+
+		// assert
+		require.Equal(t, http.StatusNotFound, resNoThreshold.Code)
+		require.JSONEq(t, `"reorder threshold not found"`, resNoThreshold.Body.String())
+		require.Equal(t, http.StatusNotFound, resNoProduct.Code)
+		require.JSONEq(t, `"reorder threshold not found"`, resNoProduct.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := newHandlerReorderThreshold(storeProductFailing{}, newStoreReorderThresholds())
+
+		// act
+		res := serve(hd.DeleteProduct(), http.MethodDelete, "/products/{id}/reorder-threshold", "/products/2/reorder-threshold", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
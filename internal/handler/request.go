@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"app/platform/web/request"
+	"app/platform/web/response"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ifMatch checks the If-Match header of a request against the version of a resource.
+// - present is false when the header is not sent, in which case any version matches
+func ifMatch(r *http.Request, version int) (match, present bool) {
+	tags, present := request.IfMatch(r)
+	if !present {
+		match = true
+		return
+	}
+
+	for _, tag := range tags {
+		if tag == "*" || tag == strconv.Itoa(version) {
+			match = true
+			return
+		}
+	}
+	return
+}
+
+// queryBool parses a boolean query parameter, false when it is not sent.
+func queryBool(r *http.Request, key string) (b bool, err error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return
+	}
+
+	b, err = strconv.ParseBool(v)
+	return
+}
+
+// queryInt parses an integer query parameter, 0 when it is not sent.
+func queryInt(r *http.Request, key string) (i int, err error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return
+	}
+
+	i, err = strconv.Atoi(v)
+	return
+}
+
+// queryDays parses a duration query parameter in days, e.g. 30d, or as a go duration, e.g. 72h.
+// - def is returned when it is not sent
+func queryDays(r *http.Request, key string, def time.Duration) (d time.Duration, err error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		d = def
+		return
+	}
+
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+		return
+	}
+
+	d, err = time.ParseDuration(v)
+	return
+}
+
+// invalidBody responds to a request whose body could not be decoded: 413 if it is too large, 400 otherwise.
+func invalidBody(w http.ResponseWriter, err error) {
+	if errors.Is(err, request.ErrRequestBodyTooLarge) {
+		response.JSON(w, http.StatusRequestEntityTooLarge, "request body too large")
+		return
+	}
+	response.JSON(w, http.StatusBadRequest, "invalid body")
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"app/internal/job"
+	"app/platform/web/response"
+	"net/http"
+	"time"
+)
+
+// NewHandlerScheduler creates a new handler for the background scheduler.
+func NewHandlerScheduler(sc *job.Scheduler) (h *HandlerScheduler) {
+	h = &HandlerScheduler{
+		sc: sc,
+	}
+	return
+}
+
+// HandlerScheduler is a handler for the background scheduler.
+type HandlerScheduler struct {
+	// sc is the scheduler running the background jobs.
+	sc *job.Scheduler
+}
+
+// TaskStatusJSON is the status of a scheduled task in JSON format.
+type TaskStatusJSON struct {
+	Name         string `json:"name"`
+	Interval     string `json:"interval"`
+	Running      bool   `json:"running"`
+	Runs         int    `json:"runs"`
+	Failures     int    `json:"failures"`
+	LastRunAt    string `json:"last_run_at,omitempty"`
+	LastDuration string `json:"last_duration,omitempty"`
+	LastResult   int    `json:"last_result"`
+	LastError    string `json:"last_error,omitempty"`
+	NextRunAt    string `json:"next_run_at,omitempty"`
+}
+
+// Status gets the status of the background jobs.
+func (h *HandlerScheduler) Status() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		ts := h.sc.Status()
+
+		// response
+		data := make([]TaskStatusJSON, len(ts))
+		for i, v := range ts {
+			data[i] = TaskStatusJSON{
+				Name:       v.Name,
+				Interval:   v.Interval.String(),
+				Running:    v.Running,
+				Runs:       v.Runs,
+				Failures:   v.Failures,
+				LastResult: v.LastResult,
+				LastError:  v.LastError,
+			}
+			if !v.LastRunAt.IsZero() {
+				data[i].LastRunAt = v.LastRunAt.UTC().Format(time.RFC3339)
+				data[i].LastDuration = v.LastDuration.String()
+			}
+			if !v.NextRunAt.IsZero() {
+				data[i].NextRunAt = v.NextRunAt.UTC().Format(time.RFC3339)
+			}
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
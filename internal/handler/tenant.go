@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/auth"
+	"app/platform/web/response"
+	"net/http"
+)
+
+// HeaderTenant is the header selecting the tenant of a request.
+const HeaderTenant = "X-Tenant-ID"
+
+// Tenant is a middleware that attaches the tenant of a request to its context.
+// - an authenticated request is of the tenant of its principal, a header selecting another one is forbidden
+// - otherwise the tenant is the one of the X-Tenant-ID header, internal.TenantDefault without it
+func Tenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant := r.Header.Get(HeaderTenant)
+		if tenant != "" && !internal.TenantValid(tenant) {
+			response.JSON(w, http.StatusBadRequest, "invalid tenant")
+			return
+		}
+
+		if p, ok := auth.PrincipalFromContext(r.Context()); ok {
+			principalTenant := p.Tenant
+			if principalTenant == "" {
+				principalTenant = internal.TenantDefault
+			}
+			if tenant != "" && tenant != principalTenant {
+				response.JSON(w, http.StatusForbidden, "forbidden")
+				return
+			}
+			tenant = principalTenant
+		}
+		if tenant == "" {
+			tenant = internal.TenantDefault
+		}
+
+		next.ServeHTTP(w, r.WithContext(internal.ContextWithTenant(r.Context(), tenant)))
+	})
+}
@@ -0,0 +1,68 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/platform/web/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Tenant
+func TestTenant(t *testing.T) {
+	acme := &auth.Principal{Subject: "a", Method: auth.MethodAPIKey, Role: auth.RoleViewer, Tenant: "acme"}
+	legacy := &auth.Principal{Subject: "l", Method: auth.MethodAPIKey, Role: auth.RoleViewer}
+
+	cases := []struct {
+		name           string
+		principal      *auth.Principal
+		header         string
+		expectedCode   int
+		expectedTenant string
+	}{
+		{"success - anonymous, default tenant", nil, "", http.StatusOK, internal.TenantDefault},
+		{"success - anonymous, tenant of the header", nil, "globex", http.StatusOK, "globex"},
+		{"success - principal, tenant of the principal", acme, "", http.StatusOK, "acme"},
+		{"success - principal, header of its tenant", acme, "acme", http.StatusOK, "acme"},
+		{"success - principal without tenant, default tenant", legacy, "", http.StatusOK, internal.TenantDefault},
+		{"success - principal without tenant, header of the default tenant", legacy, internal.TenantDefault, http.StatusOK, internal.TenantDefault},
+		{"error - principal, header of another tenant", acme, "globex", http.StatusForbidden, ""},
+		{"error - principal without tenant, header of another tenant", legacy, "acme", http.StatusForbidden, ""},
+		{"error - invalid tenant", nil, "Acme!", http.StatusBadRequest, ""},
+		{"error - tenant of every tenant", nil, internal.TenantAll, http.StatusBadRequest, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			var tenant string
+			hd := handler.Tenant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tenant = internal.TenantFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/products", nil)
+			if c.principal != nil {
+				req = req.WithContext(auth.ContextWithPrincipal(req.Context(), *c.principal))
+			}
+			if c.header != "" {
+				req.Header.Set(handler.HeaderTenant, c.header)
+			}
+			res := httptest.NewRecorder()
+
+			// act
+			hd.ServeHTTP(res, req)
+
+			// assert
+			require.Equal(t, c.expectedCode, res.Code)
+			require.Equal(t, c.expectedTenant, tenant)
+			switch c.expectedCode {
+			case http.StatusForbidden:
+				require.JSONEq(t, `"forbidden"`, res.Body.String())
+			case http.StatusBadRequest:
+				require.JSONEq(t, `"invalid tenant"`, res.Body.String())
+			}
+		})
+	}
+}
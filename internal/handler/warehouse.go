@@ -2,6 +2,8 @@ package handler
 
 import (
 	"app/internal"
+	"app/platform/web/auth"
+	"app/platform/web/logging"
 	"app/platform/web/request"
 	"app/platform/web/response"
 	"errors"
@@ -12,9 +14,10 @@ import (
 )
 
 // NewHandlerWarehouse creates a new handler for Warehouse.
-func NewHandlerWarehouse(rp internal.RepositoryWarehouse) (h *HandlerWarehouse) {
+func NewHandlerWarehouse(rp internal.RepositoryWarehouse, uow internal.UnitOfWork) (h *HandlerWarehouse) {
 	h = &HandlerWarehouse{
-		rp: rp,
+		rp:  rp,
+		uow: uow,
 	}
 	return
 }
@@ -23,6 +26,8 @@ func NewHandlerWarehouse(rp internal.RepositoryWarehouse) (h *HandlerWarehouse)
 type HandlerWarehouse struct {
 	// rp is the repository for Warehouse.
 	rp internal.RepositoryWarehouse
+	// uow runs the operations that span Warehouse and products.
+	uow internal.UnitOfWork
 }
 
 // ProductJSON is a Warehouse in JSON format.
@@ -42,6 +47,14 @@ type WarehouseJSONRequest struct {
 	Capacity  int    `json:"capacity"`
 }
 
+// RequestBodyWarehousePatch is the document a Warehouse patch applies to, the id is not part of it.
+type RequestBodyWarehousePatch struct {
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	Telephone string `json:"telephone"`
+	Capacity  int    `json:"capacity"`
+}
+
 // GetById gets a string by id.
 func (h *HandlerWarehouse) GetById() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -55,12 +68,13 @@ func (h *HandlerWarehouse) GetById() http.HandlerFunc {
 
 		// process
 		// - find Warehouse by id
-		p, err := h.rp.FindById(id)
+		p, err := h.rp.FindById(r.Context(), id)
 		if err != nil {
 			switch {
 			case errors.Is(err, internal.ErrRepositoryWarehouseNotFound):
 				response.JSON(w, http.StatusNotFound, "Warehouse not found")
 			default:
+				logging.Error(r.Context(), err)
 				response.JSON(w, http.StatusInternalServerError, "internal server error")
 			}
 			return
@@ -75,6 +89,7 @@ func (h *HandlerWarehouse) GetById() http.HandlerFunc {
 			Telephone: p.Telephone,
 			Capacity:  p.Capacity,
 		}
+		response.ETag(w, strconv.Itoa(p.Version))
 		response.JSON(w, http.StatusOK, map[string]any{
 			"message": "success",
 			"data":    data,
@@ -90,7 +105,7 @@ func (h *HandlerWarehouse) Create() http.HandlerFunc {
 		var body WarehouseJSONRequest
 		err := request.JSON(r, &body)
 		if err != nil {
-			response.JSON(w, http.StatusBadRequest, "invalid body")
+			invalidBody(w, err)
 			return
 		}
 
@@ -106,8 +121,9 @@ func (h *HandlerWarehouse) Create() http.HandlerFunc {
 			},
 		}
 
-		err = h.rp.Save(&warehouse)
+		err = h.rp.Save(r.Context(), &warehouse)
 		if err != nil {
+			logging.Error(r.Context(), err)
 			response.JSON(w, http.StatusInternalServerError, "internal server error")
 			return
 		}
@@ -121,6 +137,7 @@ func (h *HandlerWarehouse) Create() http.HandlerFunc {
 			Telephone: warehouse.Telephone,
 			Capacity:  warehouse.Capacity,
 		}
+		response.ETag(w, strconv.Itoa(warehouse.Version))
 		response.JSON(w, http.StatusCreated, map[string]any{
 			"message": "success",
 			"data":    data,
@@ -128,6 +145,91 @@ func (h *HandlerWarehouse) Create() http.HandlerFunc {
 	}
 }
 
+// Update updates a Warehouse.
+func (h *HandlerWarehouse) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: id
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.JSON(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		// - find Warehouse by id
+		warehouse, err := h.rp.FindById(r.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRepositoryWarehouseNotFound):
+				response.JSON(w, http.StatusNotFound, "Warehouse not found")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+		// - check the version
+		match, present := ifMatch(r, warehouse.Version)
+		if !match {
+			response.JSON(w, http.StatusPreconditionFailed, "precondition failed")
+			return
+		}
+		// - patch Warehouse
+		body := RequestBodyWarehousePatch{
+			Name:      warehouse.Name,
+			Address:   warehouse.Address,
+			Telephone: warehouse.Telephone,
+			Capacity:  warehouse.Capacity,
+		}
+		err = request.Patch(r, &body)
+		if err != nil {
+			switch {
+			case errors.Is(err, request.ErrRequestContentTypeNotPatch):
+				response.JSON(w, http.StatusUnsupportedMediaType, "unsupported media type")
+			case errors.Is(err, request.ErrRequestBodyTooLarge):
+				response.JSON(w, http.StatusRequestEntityTooLarge, "request body too large")
+			default:
+				response.JSON(w, http.StatusBadRequest, "invalid body")
+			}
+			return
+		}
+		// - update Warehouse
+		warehouse.Name = body.Name
+		warehouse.Address = body.Address
+		warehouse.Telephone = body.Telephone
+		warehouse.Capacity = body.Capacity
+		err = h.rp.Update(r.Context(), &warehouse)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRepositoryWarehouseVersionConflict) && present:
+				response.JSON(w, http.StatusPreconditionFailed, "precondition failed")
+			case errors.Is(err, internal.ErrRepositoryWarehouseVersionConflict):
+				response.JSON(w, http.StatusConflict, "Warehouse was modified concurrently")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+
+		// response
+		// - serialize Warehouse to JSON
+		data := WarehouseJSONResponse{
+			Id:        warehouse.Id,
+			Name:      warehouse.Name,
+			Address:   warehouse.Address,
+			Telephone: warehouse.Telephone,
+			Capacity:  warehouse.Capacity,
+		}
+		response.ETag(w, strconv.Itoa(warehouse.Version))
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
 // ReportProducts gets the number of products by warehouse.
 func (h *HandlerWarehouse) ReportProducts() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -147,11 +249,12 @@ func (h *HandlerWarehouse) ReportProducts() http.HandlerFunc {
 
 		// process
 		// - find Warehouse by id
-		warehouses, err := h.rp.ReportProducts(id)
+		warehouses, err := h.rp.ReportProducts(r.Context(), id)
 
 		if err != nil {
 			switch {
 			default:
+				logging.Error(r.Context(), err)
 				response.JSON(w, http.StatusInternalServerError, "internal server error")
 			}
 			return
@@ -166,9 +269,29 @@ func (h *HandlerWarehouse) ReportProducts() http.HandlerFunc {
 
 func (h *HandlerWarehouse) GetAll() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		warehouses, err := h.rp.GetAll()
+		// request
+		// - query parameter: include_deleted
+		includeDeleted, err := queryBool(r, "include_deleted")
+		if err != nil {
+			response.JSON(w, http.StatusBadRequest, "invalid include_deleted")
+			return
+		}
+		// - the deleted warehouses are only listed to admins
+		if pr, ok := auth.PrincipalFromContext(r.Context()); ok && includeDeleted && !auth.RoleAllows(pr.Role, auth.RoleAdmin) {
+			response.JSON(w, http.StatusForbidden, "forbidden")
+			return
+		}
+
+		var warehouses []internal.Warehouse
+		switch includeDeleted {
+		case true:
+			warehouses, err = h.rp.GetAllIncludingDeleted(r.Context())
+		default:
+			warehouses, err = h.rp.GetAll(r.Context())
+		}
 
 		if err != nil {
+			logging.Error(r.Context(), err)
 			response.Error(w, http.StatusInternalServerError, "internal server error")
 			return
 		}
@@ -176,3 +299,187 @@ func (h *HandlerWarehouse) GetAll() http.HandlerFunc {
 		response.JSON(w, http.StatusOK, warehouses)
 	}
 }
+
+// Delete deletes a Warehouse.
+func (h *HandlerWarehouse) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: id
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.JSON(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		// - check the version when the If-Match header is sent, the delete fails if it changes meanwhile
+		var version int
+		if _, present := ifMatch(r, 0); present {
+			current, err := h.rp.FindById(r.Context(), id)
+			if err != nil {
+				switch {
+				case errors.Is(err, internal.ErrRepositoryWarehouseNotFound):
+					response.JSON(w, http.StatusPreconditionFailed, "precondition failed")
+				default:
+					logging.Error(r.Context(), err)
+					response.JSON(w, http.StatusInternalServerError, "internal server error")
+				}
+				return
+			}
+			if match, _ := ifMatch(r, current.Version); !match {
+				response.JSON(w, http.StatusPreconditionFailed, "precondition failed")
+				return
+			}
+			version = current.Version
+		}
+		// - delete Warehouse by id
+		err = h.rp.Delete(r.Context(), id, version)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRepositoryWarehouseVersionConflict):
+				response.JSON(w, http.StatusPreconditionFailed, "precondition failed")
+			case errors.Is(err, internal.ErrRepositoryWarehouseNotFound):
+				response.JSON(w, http.StatusNotFound, "Warehouse not found")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusNoContent, nil)
+	}
+}
+
+// Restore restores a deleted Warehouse.
+func (h *HandlerWarehouse) Restore() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: id
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.JSON(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		// - restore Warehouse by id
+		err = h.rp.Restore(r.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRepositoryWarehouseNotFound):
+				response.JSON(w, http.StatusNotFound, "deleted Warehouse not found")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+		// - find restored Warehouse
+		warehouse, err := h.rp.FindById(r.Context(), id)
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		// response
+		// - serialize Warehouse to JSON
+		data := WarehouseJSONResponse{
+			Id:        warehouse.Id,
+			Name:      warehouse.Name,
+			Address:   warehouse.Address,
+			Telephone: warehouse.Telephone,
+			Capacity:  warehouse.Capacity,
+		}
+		response.ETag(w, strconv.Itoa(warehouse.Version))
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// errWarehouseCapacityExceeded is returned when a transfer exceeds the capacity of the Warehouse.
+var errWarehouseCapacityExceeded = errors.New("handler: warehouse capacity exceeded")
+
+// RequestBodyWarehouseTransfer is a request body for transferring products to a Warehouse.
+type RequestBodyWarehouseTransfer struct {
+	ProductIds []int `json:"product_ids"`
+}
+
+// Transfer moves products to a Warehouse, moving all of them or none.
+func (h *HandlerWarehouse) Transfer() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: id
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.JSON(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		// - body
+		var body RequestBodyWarehouseTransfer
+		err = request.JSON(r, &body)
+		if err != nil || len(body.ProductIds) == 0 {
+			invalidBody(w, err)
+			return
+		}
+
+		// process
+		// - move products and check capacity in the same unit of work
+		err = h.uow.Do(r.Context(), func(rt internal.RepositoriesTx) (err error) {
+			warehouse, err := rt.Warehouse.FindById(r.Context(), id)
+			if err != nil {
+				return
+			}
+
+			for _, productId := range body.ProductIds {
+				var p internal.Product
+				p, err = rt.Product.FindById(r.Context(), productId)
+				if err != nil {
+					return
+				}
+				p.WarehouseId = warehouse.Id
+				err = rt.Product.Update(r.Context(), &p)
+				if err != nil {
+					return
+				}
+			}
+
+			report, err := rt.Warehouse.ReportProducts(r.Context(), warehouse.Id)
+			if err != nil {
+				return
+			}
+			if len(report) > 0 && report[0].Count > warehouse.Capacity {
+				err = errWarehouseCapacityExceeded
+				return
+			}
+			return
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRepositoryWarehouseNotFound):
+				response.JSON(w, http.StatusNotFound, "Warehouse not found")
+			case errors.Is(err, internal.ErrRepositoryProductNotFound):
+				response.JSON(w, http.StatusNotFound, "product not found")
+			case errors.Is(err, errWarehouseCapacityExceeded):
+				response.JSON(w, http.StatusConflict, "warehouse capacity exceeded")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data": map[string]any{
+				"warehouse_id": id,
+				"product_ids":  body.ProductIds,
+			},
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/stream"
+	"app/platform/web/logging"
+	"app/platform/web/response"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// LiveActionSubscribe is the action of a client subscribing to products, or to every product when none is given.
+	LiveActionSubscribe = "subscribe"
+	// LiveActionUnsubscribe is the action of a client unsubscribing from products.
+	LiveActionUnsubscribe = "unsubscribe"
+)
+
+// NewHandlerWarehouseLive creates a new handler for the live feeds of the warehouses.
+func NewHandlerWarehouseLive(rp internal.RepositoryWarehouse, br *stream.Broker) (h *HandlerWarehouseLive) {
+	h = &HandlerWarehouseLive{
+		rp: rp,
+		br: br,
+	}
+	return
+}
+
+// HandlerWarehouseLive is a handler for the live feeds of the warehouses.
+type HandlerWarehouseLive struct {
+	// rp is the repository for warehouses.
+	rp internal.RepositoryWarehouse
+	// br is the broker streaming the events.
+	br *stream.Broker
+	// up upgrades the requests to websocket connections.
+	up websocket.Upgrader
+}
+
+// LiveRequestJSON is a message sent by a client of a live feed.
+type LiveRequestJSON struct {
+	Action     string `json:"action"`
+	ProductIds []int  `json:"product_ids"`
+}
+
+// LiveQuantityJSON is a message pushed to a live feed on a quantity change.
+type LiveQuantityJSON struct {
+	Type             string `json:"type"`
+	EventId          string `json:"event_id"`
+	ProductId        int    `json:"product_id"`
+	WarehouseId      int    `json:"warehouse_id"`
+	Quantity         int    `json:"quantity"`
+	PreviousQuantity int    `json:"previous_quantity"`
+	Removed          bool   `json:"removed"`
+}
+
+// LiveSubscriptionJSON is a message pushed to a live feed once its subscription changes.
+type LiveSubscriptionJSON struct {
+	Type       string `json:"type"`
+	All        bool   `json:"all"`
+	ProductIds []int  `json:"product_ids"`
+}
+
+// LiveErrorJSON is a message pushed to a live feed on an invalid request.
+type LiveErrorJSON struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// Live pushes the quantity changes of the products of a warehouse over a websocket.
+// - every product of the warehouse is fed until the client subscribes to some of them
+// - the connection is kept alive with pings, a client missing two of them is disconnected
+func (h *HandlerWarehouseLive) Live() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: id
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.JSON(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		// - find warehouse by id
+		_, err = h.rp.FindById(r.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRepositoryWarehouseNotFound):
+				response.JSON(w, http.StatusNotFound, "Warehouse not found")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+
+		// response
+		// - the upgrader responds on failure
+		conn, err := h.up.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		s, _ := h.br.Subscribe(stream.Filter{WarehouseId: id, Types: []string{"product.*"}, Tenant: internal.TenantFromContext(r.Context())}, "")
+		defer h.br.Unsubscribe(s)
+
+		// read the requests of the client
+		// - a connection can only have one reader and one writer, so the requests are handed to the writer
+		heartbeat := h.br.Heartbeat()
+		conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+		})
+		reqs := make(chan LiveRequestJSON)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for {
+				_, data, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+				// - a malformed message is answered as an invalid action
+				var req LiveRequestJSON
+				if json.Unmarshal(data, &req) != nil {
+					req = LiveRequestJSON{}
+				}
+				select {
+				case reqs <- req:
+				case <-r.Context().Done():
+					return
+				}
+			}
+		}()
+
+		// write the changes
+		// - products is nil while every product is fed
+		var products map[int]bool
+		ping := time.NewTicker(heartbeat)
+		defer ping.Stop()
+		for {
+			var msg any
+			select {
+			case <-done:
+				return
+			case <-r.Context().Done():
+				return
+			case e, ok := <-s.C:
+				// - a closed subscription ends the feed, the client reconnects
+				if !ok {
+					conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+					return
+				}
+				c, ok := stream.QuantityChangeOf(e, id)
+				if !ok || (products != nil && !products[c.ProductId]) {
+					continue
+				}
+				msg = LiveQuantityJSON{
+					Type:             "quantity",
+					EventId:          c.EventId,
+					ProductId:        c.ProductId,
+					WarehouseId:      c.WarehouseId,
+					Quantity:         c.Quantity,
+					PreviousQuantity: c.PreviousQuantity,
+					Removed:          c.Removed,
+				}
+			case req := <-reqs:
+				switch req.Action {
+				case LiveActionSubscribe:
+					if len(req.ProductIds) == 0 {
+						products = nil
+						break
+					}
+					if products == nil {
+						products = make(map[int]bool)
+					}
+					for _, pid := range req.ProductIds {
+						products[pid] = true
+					}
+				case LiveActionUnsubscribe:
+					if products == nil {
+						products = make(map[int]bool)
+					}
+					for _, pid := range req.ProductIds {
+						delete(products, pid)
+					}
+				default:
+					msg = LiveErrorJSON{Type: "error", Message: "invalid action"}
+				}
+				if msg == nil {
+					msg = liveSubscriptionJSON(products)
+				}
+			case <-ping.C:
+				if conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(heartbeat)) != nil {
+					return
+				}
+				continue
+			}
+
+			conn.SetWriteDeadline(time.Now().Add(heartbeat))
+			if conn.WriteJSON(msg) != nil {
+				return
+			}
+		}
+	}
+}
+
+// liveSubscriptionJSON serializes the subscription of a live feed to JSON.
+func liveSubscriptionJSON(products map[int]bool) LiveSubscriptionJSON {
+	data := LiveSubscriptionJSON{
+		Type:       "subscribed",
+		All:        products == nil,
+		ProductIds: []int{},
+	}
+	for pid := range products {
+		data.ProductIds = append(data.ProductIds, pid)
+	}
+	sort.Ints(data.ProductIds)
+	return data
+}
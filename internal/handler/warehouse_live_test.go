@@ -0,0 +1,201 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/store"
+	"app/internal/stream"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// newHandlerWarehouseLive returns a handler of the live feeds of the warehouses of a store, streaming the events of a broker.
+func newHandlerWarehouseLive(stWarehouse internal.StoreWarehouse, br *stream.Broker) *handler.HandlerWarehouseLive {
+	return handler.NewHandlerWarehouseLive(repository.NewRepositoryWarehouseStore(stWarehouse, store.NewStoreProductMemory(nil)), br)
+}
+
+// newStoreWarehouseLive returns the warehouses of the tests of the live feeds.
+func newStoreWarehouseLive() *store.StoreWarehouseMemory {
+	return store.NewStoreWarehouseMemory(map[int]internal.Warehouse{
+		1: {Id: 1, WarehouseAttributes: internal.WarehouseAttributes{Name: "w1", Capacity: 10}, Version: 1},
+	})
+}
+
+// dialLive connects to the live feed of a warehouse, served on a test server.
+func dialLive(t *testing.T, hd *handler.HandlerWarehouseLive, target string) *websocket.Conn {
+	t.Helper()
+	rt := chi.NewRouter()
+	rt.Get("/warehouses/{id}/live", hd.Live())
+	srv := httptest.NewServer(rt)
+	t.Cleanup(srv.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+target, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+// quantityEvent returns the event of the quantity of a product of warehouse 1 changing.
+func quantityEvent(id string, productId, before, after int, tenant string) internal.Event {
+	return internal.Event{
+		Id:       id,
+		Type:     internal.EventProductUpdated,
+		EntityId: productId,
+		Tenant:   tenant,
+		Before:   json.RawMessage(fmt.Sprintf(`{"Id":%d,"WarehouseId":1,"Quantity":%d}`, productId, before)),
+		After:    json.RawMessage(fmt.Sprintf(`{"Id":%d,"WarehouseId":1,"Quantity":%d}`, productId, after)),
+	}
+}
+
+// Tests for HandlerWarehouseLive.Live
+func TestHandlerWarehouseLive_Live(t *testing.T) {
+	t.Run("success - quantity changes of the subscribed products pushed", func(t *testing.T) {
+		// arrange
+		br := stream.NewBroker(nil)
+		defer br.Close()
+		conn := dialLive(t, newHandlerWarehouseLive(newStoreWarehouseLive(), br), "/warehouses/1/live")
+
+		// act
+		// - the subscription is confirmed once the feed listens to the broker
+		require.NoError(t, conn.WriteJSON(handler.LiveRequestJSON{Action: handler.LiveActionSubscribe, ProductIds: []int{1}}))
+		var subscribed handler.LiveSubscriptionJSON
+		require.NoError(t, conn.ReadJSON(&subscribed))
+		require.NoError(t, br.Publish(context.Background(), quantityEvent("e1", 2, 1, 3, "")))
+		require.NoError(t, br.Publish(context.Background(), quantityEvent("e2", 1, 1, 5, "")))
+		var quantity handler.LiveQuantityJSON
+		require.NoError(t, conn.ReadJSON(&quantity))
+
+		// assert
+		require.Equal(t, handler.LiveSubscriptionJSON{Type: "subscribed", All: false, ProductIds: []int{1}}, subscribed)
+		require.Equal(t, handler.LiveQuantityJSON{Type: "quantity", EventId: "e2", ProductId: 1, WarehouseId: 1, Quantity: 5, PreviousQuantity: 1}, quantity)
+	})
+
+	t.Run("success - changes of other tenants not pushed", func(t *testing.T) {
+		// arrange
+		br := stream.NewBroker(nil)
+		defer br.Close()
+		conn := dialLive(t, newHandlerWarehouseLive(newStoreWarehouseLive(), br), "/warehouses/1/live")
+
+		// act
+		require.NoError(t, conn.WriteJSON(handler.LiveRequestJSON{Action: handler.LiveActionSubscribe}))
+		var subscribed handler.LiveSubscriptionJSON
+		require.NoError(t, conn.ReadJSON(&subscribed))
+		require.NoError(t, br.Publish(context.Background(), quantityEvent("e1", 1, 1, 3, "acme")))
+		require.NoError(t, br.Publish(context.Background(), quantityEvent("e2", 1, 1, 5, "")))
+		var quantity handler.LiveQuantityJSON
+		require.NoError(t, conn.ReadJSON(&quantity))
+
+		// assert
+		require.Equal(t, handler.LiveSubscriptionJSON{Type: "subscribed", All: true, ProductIds: []int{}}, subscribed)
+		require.Equal(t, "e2", quantity.EventId)
+	})
+
+	t.Run("success - products unsubscribed", func(t *testing.T) {
+		// arrange
+		br := stream.NewBroker(nil)
+		defer br.Close()
+		conn := dialLive(t, newHandlerWarehouseLive(newStoreWarehouseLive(), br), "/warehouses/1/live")
+
+		// act
+		require.NoError(t, conn.WriteJSON(handler.LiveRequestJSON{Action: handler.LiveActionSubscribe, ProductIds: []int{1, 2}}))
+		var subscribed handler.LiveSubscriptionJSON
+		require.NoError(t, conn.ReadJSON(&subscribed))
+		require.NoError(t, conn.WriteJSON(handler.LiveRequestJSON{Action: handler.LiveActionUnsubscribe, ProductIds: []int{1}}))
+		var unsubscribed handler.LiveSubscriptionJSON
+		require.NoError(t, conn.ReadJSON(&unsubscribed))
+
+		// assert
+		require.Equal(t, []int{1, 2}, subscribed.ProductIds)
+		require.Equal(t, handler.LiveSubscriptionJSON{Type: "subscribed", All: false, ProductIds: []int{2}}, unsubscribed)
+	})
+
+	t.Run("success - invalid action answered", func(t *testing.T) {
+		// arrange
+		br := stream.NewBroker(nil)
+		defer br.Close()
+		conn := dialLive(t, newHandlerWarehouseLive(newStoreWarehouseLive(), br), "/warehouses/1/live")
+
+		// act
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"action":`)))
+		var msg handler.LiveErrorJSON
+		require.NoError(t, conn.ReadJSON(&msg))
+
+		// assert
+		require.Equal(t, handler.LiveErrorJSON{Type: "error", Message: "invalid action"}, msg)
+	})
+
+	t.Run("success - feed closed with the broker", func(t *testing.T) {
+		// arrange
+		br := stream.NewBroker(nil)
+		conn := dialLive(t, newHandlerWarehouseLive(newStoreWarehouseLive(), br), "/warehouses/1/live")
+		require.NoError(t, conn.WriteJSON(handler.LiveRequestJSON{Action: handler.LiveActionSubscribe}))
+		var subscribed handler.LiveSubscriptionJSON
+		require.NoError(t, conn.ReadJSON(&subscribed))
+
+		// act
+		br.Close()
+		_, _, err := conn.ReadMessage()
+
+		// assert
+		require.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway))
+	})
+
+	t.Run("error - invalid id", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWarehouseLive(newStoreWarehouseLive(), stream.NewBroker(nil))
+
+		// act
+		res := serve(hd.Live(), http.MethodGet, "/warehouses/{id}/live", "/warehouses/a/live", "", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"invalid id"`, res.Body.String())
+	})
+
+	t.Run("error - warehouse not found", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWarehouseLive(newStoreWarehouseLive(), stream.NewBroker(nil))
+
+		// act
+		res := serve(hd.Live(), http.MethodGet, "/warehouses/{id}/live", "/warehouses/9/live", "", "")
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `"Warehouse not found"`, res.Body.String())
+	})
+
+	t.Run("error - not a websocket request", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWarehouseLive(newStoreWarehouseLive(), stream.NewBroker(nil))
+
+		// act
+		res := serve(hd.Live(), http.MethodGet, "/warehouses/{id}/live", "/warehouses/1/live", "", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWarehouseLive(storeWarehouseFailing{}, stream.NewBroker(nil))
+
+		// act
+		res := serve(hd.Live(), http.MethodGet, "/warehouses/{id}/live", "/warehouses/1/live", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
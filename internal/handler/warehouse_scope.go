@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/auth"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// WarehouseOfPath returns the warehouse of the id path parameter of a request.
+// - an invalid id touches no warehouse, the handler responds bad request
+func WarehouseOfPath(r *http.Request) (warehouseId int, err error) {
+	warehouseId, err = strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		err = auth.ErrScopeNone
+	}
+	return
+}
+
+// WarehouseOfProduct returns a function returning the warehouse of the product of the id path parameter of a request.
+// - an invalid id or a product not found touches no warehouse, the handler responds as it would to anyone
+func WarehouseOfProduct(rp internal.RepositoryProduct) auth.WarehouseFunc {
+	return func(r *http.Request) (warehouseId int, err error) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			err = auth.ErrScopeNone
+			return
+		}
+
+		p, err := rp.FindById(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, internal.ErrRepositoryProductNotFound) {
+				err = auth.ErrScopeNone
+			}
+			return
+		}
+		warehouseId = p.WarehouseId
+		return
+	}
+}
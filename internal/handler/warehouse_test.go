@@ -0,0 +1,241 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/store"
+	"app/platform/web/auth"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newHandlerWarehouse returns a handler of the warehouses and the products of two stores, moved in units of work.
+func newHandlerWarehouse(stWarehouse internal.StoreWarehouse, stProduct internal.StoreProduct) *handler.HandlerWarehouse {
+	uow := repository.NewUnitOfWorkStore(stProduct, stWarehouse, store.NewStorePriceMemory(nil), store.NewStoreOutboxMemory(nil))
+	return handler.NewHandlerWarehouse(repository.NewRepositoryWarehouseStore(stWarehouse, stProduct), uow)
+}
+
+// Tests for HandlerWarehouse.Transfer
+func TestHandlerWarehouse_Transfer(t *testing.T) {
+	// - warehouse 1 holds product 1 and has room for another one, warehouse 2 holds products 2 and 3
+	newStores := func() (*store.StoreWarehouseMemory, *store.StoreProductMemory) {
+		stWarehouse := store.NewStoreWarehouseMemory(map[int]internal.Warehouse{
+			1: {Id: 1, WarehouseAttributes: internal.WarehouseAttributes{Name: "w1", Capacity: 2}, Version: 1},
+			2: {Id: 2, WarehouseAttributes: internal.WarehouseAttributes{Name: "w2", Capacity: 10}, Version: 1},
+		})
+		stProduct := store.NewStoreProductMemory(map[int]internal.Product{
+			1: {Id: 1, ProductAttributes: internal.ProductAttributes{Name: "p1", CodeValue: "A1"}, WarehouseId: 1, Version: 1},
+			2: {Id: 2, ProductAttributes: internal.ProductAttributes{Name: "p2", CodeValue: "B1"}, WarehouseId: 2, Version: 1},
+			3: {Id: 3, ProductAttributes: internal.ProductAttributes{Name: "p3", CodeValue: "C1"}, WarehouseId: 2, Version: 1},
+		})
+		return stWarehouse, stProduct
+	}
+
+	t.Run("success - products moved", func(t *testing.T) {
+		// arrange
+		stWarehouse, stProduct := newStores()
+		hd := newHandlerWarehouse(stWarehouse, stProduct)
+
+		// act
+		res := serve(hd.Transfer(), http.MethodPost, "/warehouses/{id}/transfer", "/warehouses/1/transfer", "application/json", `{"product_ids":[2]}`)
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":{"warehouse_id":1,"product_ids":[2]}}`, res.Body.String())
+		ps, err := stProduct.ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, 1, ps[2].WarehouseId)
+	})
+
+	t.Run("error - capacity exceeded, no product moved", func(t *testing.T) {
+		// arrange
+		stWarehouse, stProduct := newStores()
+		hd := newHandlerWarehouse(stWarehouse, stProduct)
+
+		// act
+		res := serve(hd.Transfer(), http.MethodPost, "/warehouses/{id}/transfer", "/warehouses/1/transfer", "application/json", `{"product_ids":[2,3]}`)
+
+		// assert
+		require.Equal(t, http.StatusConflict, res.Code)
+		require.JSONEq(t, `"warehouse capacity exceeded"`, res.Body.String())
+		ps, err := stProduct.ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, 2, ps[2].WarehouseId)
+		require.Equal(t, 2, ps[3].WarehouseId)
+	})
+
+	t.Run("error - product not found, no product moved", func(t *testing.T) {
+		// arrange
+		stWarehouse, stProduct := newStores()
+		hd := newHandlerWarehouse(stWarehouse, stProduct)
+
+		// act
+		res := serve(hd.Transfer(), http.MethodPost, "/warehouses/{id}/transfer", "/warehouses/1/transfer", "application/json", `{"product_ids":[2,9]}`)
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `"product not found"`, res.Body.String())
+		ps, err := stProduct.ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, 2, ps[2].WarehouseId)
+	})
+
+	t.Run("error - warehouse not found", func(t *testing.T) {
+		// arrange
+		stWarehouse, stProduct := newStores()
+		hd := newHandlerWarehouse(stWarehouse, stProduct)
+
+		// act
+		res := serve(hd.Transfer(), http.MethodPost, "/warehouses/{id}/transfer", "/warehouses/9/transfer", "application/json", `{"product_ids":[2]}`)
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `"Warehouse not found"`, res.Body.String())
+	})
+
+	invalid := []struct {
+		name    string
+		target  string
+		body    string
+		message string
+	}{
+		{"error - invalid id", "/warehouses/a/transfer", `{"product_ids":[2]}`, "invalid id"},
+		{"error - invalid body", "/warehouses/1/transfer", `{"product_ids":"2"}`, "invalid body"},
+		{"error - no products", "/warehouses/1/transfer", `{"product_ids":[]}`, "invalid body"},
+	}
+	for _, c := range invalid {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			stWarehouse, stProduct := newStores()
+			hd := newHandlerWarehouse(stWarehouse, stProduct)
+
+			// act
+			res := serve(hd.Transfer(), http.MethodPost, "/warehouses/{id}/transfer", c.target, "application/json", c.body)
+
+			// assert
+			require.Equal(t, http.StatusBadRequest, res.Code)
+			require.JSONEq(t, `"`+c.message+`"`, res.Body.String())
+		})
+	}
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		stWarehouse, _ := newStores()
+		hd := newHandlerWarehouse(stWarehouse, storeProductFailing{})
+
+		// act
+		res := serve(hd.Transfer(), http.MethodPost, "/warehouses/{id}/transfer", "/warehouses/1/transfer", "application/json", `{"product_ids":[2]}`)
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerWarehouse.GetAll
+func TestHandlerWarehouse_GetAll(t *testing.T) {
+	// - warehouse 2 is deleted
+	newStoreWarehouse := func() *store.StoreWarehouseMemory {
+		return store.NewStoreWarehouseMemory(map[int]internal.Warehouse{
+			1: {Id: 1, WarehouseAttributes: internal.WarehouseAttributes{Name: "w1"}, Version: 1},
+			2: {Id: 2, WarehouseAttributes: internal.WarehouseAttributes{Name: "w2"}, Version: 2, DeletedAt: time.Now()},
+		})
+	}
+
+	t.Run("success - deleted warehouses listed to an admin", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWarehouse(newStoreWarehouse(), store.NewStoreProductMemory(nil))
+
+		// act
+		res := serve(asPrincipal(hd.GetAll(), auth.Principal{Role: auth.RoleAdmin}), http.MethodGet, "/warehouses", "/warehouses?include_deleted=true", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		var ws []map[string]any
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &ws))
+		require.Len(t, ws, 2)
+	})
+
+	t.Run("error - deleted warehouses listed to a viewer", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWarehouse(newStoreWarehouse(), store.NewStoreProductMemory(nil))
+
+		// act
+		res := serve(asPrincipal(hd.GetAll(), auth.Principal{Role: auth.RoleViewer}), http.MethodGet, "/warehouses", "/warehouses?include_deleted=true", "", "")
+
+		// assert
+		require.Equal(t, http.StatusForbidden, res.Code)
+		require.JSONEq(t, `"forbidden"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerWarehouse.Restore
+func TestHandlerWarehouse_Restore(t *testing.T) {
+	t.Run("success - deleted warehouse restored", func(t *testing.T) {
+		// arrange
+		stWarehouse := store.NewStoreWarehouseMemory(map[int]internal.Warehouse{
+			1: {
+				Id:                  1,
+				WarehouseAttributes: internal.WarehouseAttributes{Name: "w1", Address: "street 1", Telephone: "555", Capacity: 2},
+				Version:             2,
+				DeletedAt:           time.Now(),
+			},
+		})
+		hd := newHandlerWarehouse(stWarehouse, store.NewStoreProductMemory(nil))
+
+		// act
+		res := serve(hd.Restore(), http.MethodPost, "/warehouses/{id}/restore", "/warehouses/1/restore", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, `"3"`, res.Header().Get("ETag"))
+		require.JSONEq(t, `{"message":"success","data":{"id":1,"name":"w1","address":"street 1","telephone":"555","capacity":2}}`, res.Body.String())
+		ws, err := stWarehouse.ReadAll()
+		require.NoError(t, err)
+		require.True(t, ws[1].DeletedAt.IsZero())
+	})
+
+	t.Run("error - invalid id", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWarehouse(store.NewStoreWarehouseMemory(nil), store.NewStoreProductMemory(nil))
+
+		// act
+		res := serve(hd.Restore(), http.MethodPost, "/warehouses/{id}/restore", "/warehouses/a/restore", "", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"invalid id"`, res.Body.String())
+	})
+
+	t.Run("error - warehouse not deleted", func(t *testing.T) {
+		// arrange
+		stWarehouse := store.NewStoreWarehouseMemory(map[int]internal.Warehouse{
+			1: {Id: 1, WarehouseAttributes: internal.WarehouseAttributes{Name: "w1"}, Version: 1},
+		})
+		hd := newHandlerWarehouse(stWarehouse, store.NewStoreProductMemory(nil))
+
+		// act
+		res := serve(hd.Restore(), http.MethodPost, "/warehouses/{id}/restore", "/warehouses/1/restore", "", "")
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `"deleted Warehouse not found"`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWarehouse(storeWarehouseFailing{}, store.NewStoreProductMemory(nil))
+
+		// act
+		res := serve(hd.Restore(), http.MethodPost, "/warehouses/{id}/restore", "/warehouses/1/restore", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
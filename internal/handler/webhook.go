@@ -0,0 +1,318 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/webhook"
+	"app/platform/web/logging"
+	"app/platform/web/request"
+	"app/platform/web/response"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// NewHandlerWebhook creates a new handler for webhooks.
+func NewHandlerWebhook(rw internal.RepositoryWebhook, rd internal.RepositoryDeadLetter, dp *webhook.Dispatcher) (h *HandlerWebhook) {
+	h = &HandlerWebhook{
+		rw: rw,
+		rd: rd,
+		dp: dp,
+	}
+	return
+}
+
+// HandlerWebhook is a handler for webhooks.
+type HandlerWebhook struct {
+	// rw is the repository for webhooks.
+	rw internal.RepositoryWebhook
+	// rd is the repository for dead letters.
+	rd internal.RepositoryDeadLetter
+	// dp is the dispatcher delivering the events.
+	dp *webhook.Dispatcher
+}
+
+// WebhookJSON is a webhook in JSON format.
+// - the secret is only sent back when the webhook is created
+type WebhookJSON struct {
+	Id        int      `json:"id"`
+	URL       string   `json:"url"`
+	Secret    string   `json:"secret,omitempty"`
+	Events    []string `json:"events"`
+	CreatedAt string   `json:"created_at"`
+}
+
+// RequestBodyWebhook is a request body for registering a webhook.
+// - an empty secret is generated, empty events subscribes to every event
+type RequestBodyWebhook struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+// DeadLetterJSON is a dead letter in JSON format.
+type DeadLetterJSON struct {
+	Id        int            `json:"id"`
+	WebhookId int            `json:"webhook_id"`
+	Event     internal.Event `json:"event"`
+	Attempts  int            `json:"attempts"`
+	LastError string         `json:"last_error"`
+	FailedAt  string         `json:"failed_at"`
+}
+
+// GetAll gets all webhooks.
+func (h *HandlerWebhook) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		ws, err := h.rw.GetAll(r.Context())
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		// response
+		data := make([]WebhookJSON, len(ws))
+		for i, v := range ws {
+			data[i] = webhookJSON(v)
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// GetById gets a webhook by id.
+func (h *HandlerWebhook) GetById() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: id
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.JSON(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		wh, err := h.rw.FindById(r.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRepositoryWebhookNotFound):
+				response.JSON(w, http.StatusNotFound, "webhook not found")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    webhookJSON(wh),
+		})
+	}
+}
+
+// Create registers a webhook.
+func (h *HandlerWebhook) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - body
+		var body RequestBodyWebhook
+		err := request.JSON(r, &body)
+		if err != nil {
+			invalidBody(w, err)
+			return
+		}
+
+		// process
+		wh := internal.Webhook{
+			URL:       body.URL,
+			Secret:    body.Secret,
+			Events:    body.Events,
+			CreatedAt: time.Now().UTC().Truncate(time.Second),
+		}
+		if wh.Secret == "" {
+			wh.Secret, err = newSecret()
+			if err != nil {
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+				return
+			}
+		}
+		err = h.rw.Save(r.Context(), &wh)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrWebhookInvalid):
+				response.JSON(w, http.StatusBadRequest, "url must be an absolute http url")
+			case errors.Is(err, internal.ErrWebhookEventInvalid):
+				response.JSON(w, http.StatusBadRequest, "unknown event")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+
+		// response
+		// - the secret is sent back once, so the receiver can verify the signatures
+		data := webhookJSON(wh)
+		data.Secret = wh.Secret
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// Delete unregisters a webhook.
+func (h *HandlerWebhook) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: id
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.JSON(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		err = h.rw.Delete(r.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRepositoryWebhookNotFound):
+				response.JSON(w, http.StatusNotFound, "webhook not found")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusNoContent, nil)
+	}
+}
+
+// GetDeadLetters gets the events that could not be delivered.
+func (h *HandlerWebhook) GetDeadLetters() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		ds, err := h.rd.GetAll(r.Context())
+		if err != nil {
+			logging.Error(r.Context(), err)
+			response.JSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		// response
+		data := make([]DeadLetterJSON, len(ds))
+		for i, v := range ds {
+			data[i] = DeadLetterJSON{
+				Id:        v.Id,
+				WebhookId: v.WebhookId,
+				Event:     v.Event,
+				Attempts:  v.Attempts,
+				LastError: v.LastError,
+				FailedAt:  v.FailedAt.UTC().Format(time.RFC3339),
+			}
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// RetryDeadLetter queues the event of a dead letter for delivery again.
+func (h *HandlerWebhook) RetryDeadLetter() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: id
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.JSON(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		err = h.dp.Redeliver(r.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRepositoryDeadLetterNotFound):
+				response.JSON(w, http.StatusNotFound, "dead letter not found")
+			case errors.Is(err, internal.ErrRepositoryWebhookNotFound):
+				response.JSON(w, http.StatusConflict, "webhook of the dead letter no longer exists")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusAccepted, map[string]any{
+			"message": "success",
+		})
+	}
+}
+
+// DeleteDeadLetter discards a dead letter.
+func (h *HandlerWebhook) DeleteDeadLetter() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path parameter: id
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.JSON(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		err = h.rd.Delete(r.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRepositoryDeadLetterNotFound):
+				response.JSON(w, http.StatusNotFound, "dead letter not found")
+			default:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusNoContent, nil)
+	}
+}
+
+// webhookJSON serializes a webhook to JSON, without its secret.
+func webhookJSON(w internal.Webhook) WebhookJSON {
+	events := w.Events
+	if events == nil {
+		events = []string{}
+	}
+	return WebhookJSON{
+		Id:        w.Id,
+		URL:       w.URL,
+		Events:    events,
+		CreatedAt: w.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// newSecret returns a random secret to sign the events of a webhook.
+func newSecret() (secret string, err error) {
+	b := make([]byte, 32)
+	_, err = rand.Read(b)
+	if err != nil {
+		return
+	}
+	secret = hex.EncodeToString(b)
+	return
+}
@@ -0,0 +1,416 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/store"
+	"app/internal/webhook"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newHandlerWebhook returns a handler of the webhooks and dead letters of two stores, with a dispatcher that is not started.
+func newHandlerWebhook(stWebhook internal.StoreWebhook, stDeadLetter internal.StoreDeadLetter) *handler.HandlerWebhook {
+	rw := repository.NewRepositoryWebhookStore(stWebhook)
+	rd := repository.NewRepositoryDeadLetterStore(stDeadLetter)
+	return handler.NewHandlerWebhook(rw, rd, webhook.NewDispatcher(rw, rd, nil))
+}
+
+// newStoreWebhooks returns the webhooks of the tests of the webhooks.
+// - webhook 1 is delivered the product events, webhook 2 every event
+func newStoreWebhooks() *store.StoreWebhookMemory {
+	createdAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	return store.NewStoreWebhookMemory(map[int]internal.Webhook{
+		1: {Id: 1, URL: "https://example.com/hook", Secret: "s1", Events: []string{"product.*"}, CreatedAt: createdAt},
+		2: {Id: 2, URL: "http://example.com/all", Secret: "s2", CreatedAt: createdAt},
+	})
+}
+
+// newStoreDeadLetters returns the dead letters of the tests of the webhooks.
+// - dead letter 1 is of webhook 1, dead letter 2 of a webhook that no longer exists
+func newStoreDeadLetters() *store.StoreDeadLetterMemory {
+	failedAt := time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)
+	e := internal.Event{Id: "e1", Type: internal.EventProductCreated, EntityId: 1, Actor: "alice", Timestamp: failedAt}
+	return store.NewStoreDeadLetterMemory(map[int]internal.DeadLetter{
+		1: {Id: 1, WebhookId: 1, Event: e, Attempts: 5, LastError: "status 500", FailedAt: failedAt},
+		2: {Id: 2, WebhookId: 9, Event: e, Attempts: 5, LastError: "status 500", FailedAt: failedAt},
+	})
+}
+
+// Tests for HandlerWebhook.GetAll
+func TestHandlerWebhook_GetAll(t *testing.T) {
+	t.Run("success - secrets not sent back", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(newStoreWebhooks(), newStoreDeadLetters())
+
+		// act
+		res := serve(hd.GetAll(), http.MethodGet, "/webhooks", "/webhooks", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":[
+			{"id":1,"url":"https://example.com/hook","events":["product.*"],"created_at":"2030-01-01T00:00:00Z"},
+			{"id":2,"url":"http://example.com/all","events":[],"created_at":"2030-01-01T00:00:00Z"}
+		]}`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(storeWebhookFailing{}, newStoreDeadLetters())
+
+		// act
+		res := serve(hd.GetAll(), http.MethodGet, "/webhooks", "/webhooks", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerWebhook.GetById
+func TestHandlerWebhook_GetById(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(newStoreWebhooks(), newStoreDeadLetters())
+
+		// act
+		res := serve(hd.GetById(), http.MethodGet, "/webhooks/{id}", "/webhooks/1", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"message":"success","data":{"id":1,"url":"https://example.com/hook","events":["product.*"],"created_at":"2030-01-01T00:00:00Z"}}`, res.Body.String())
+	})
+
+	t.Run("error - invalid id", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(newStoreWebhooks(), newStoreDeadLetters())
+
+		// act
+		res := serve(hd.GetById(), http.MethodGet, "/webhooks/{id}", "/webhooks/a", "", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"invalid id"`, res.Body.String())
+	})
+
+	t.Run("error - webhook not found", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(newStoreWebhooks(), newStoreDeadLetters())
+
+		// act
+		res := serve(hd.GetById(), http.MethodGet, "/webhooks/{id}", "/webhooks/9", "", "")
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `"webhook not found"`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(storeWebhookFailing{}, newStoreDeadLetters())
+
+		// act
+		res := serve(hd.GetById(), http.MethodGet, "/webhooks/{id}", "/webhooks/1", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerWebhook.Create
+func TestHandlerWebhook_Create(t *testing.T) {
+	t.Run("success - secret sent back once", func(t *testing.T) {
+		// arrange
+		st := newStoreWebhooks()
+		hd := newHandlerWebhook(st, newStoreDeadLetters())
+
+		// act
+		res := serve(hd.Create(), http.MethodPost, "/webhooks", "/webhooks", "application/json", `{"url":"https://example.com/new","secret":"s3","events":["warehouse.*"]}`)
+
+		// assert
+		require.Equal(t, http.StatusCreated, res.Code)
+		var body struct {
+			Data handler.WebhookJSON `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+		require.Equal(t, 3, body.Data.Id)
+		require.Equal(t, "https://example.com/new", body.Data.URL)
+		require.Equal(t, "s3", body.Data.Secret)
+		require.Equal(t, []string{"warehouse.*"}, body.Data.Events)
+		ws, err := st.ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, "s3", ws[3].Secret)
+	})
+
+	t.Run("success - secret generated", func(t *testing.T) {
+		// arrange
+		st := newStoreWebhooks()
+		hd := newHandlerWebhook(st, newStoreDeadLetters())
+
+		// act
+		res := serve(hd.Create(), http.MethodPost, "/webhooks", "/webhooks", "application/json", `{"url":"https://example.com/new"}`)
+
+		// assert
+		require.Equal(t, http.StatusCreated, res.Code)
+		var body struct {
+			Data handler.WebhookJSON `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+		require.Len(t, body.Data.Secret, 64)
+		ws, err := st.ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, body.Data.Secret, ws[3].Secret)
+	})
+
+	invalid := []struct {
+		name    string
+		body    string
+		message string
+	}{
+		{"error - invalid body", `{"url":1}`, "invalid body"},
+		{"error - relative url", `{"url":"/hook"}`, "url must be an absolute http url"},
+		{"error - not an http url", `{"url":"ftp://example.com/hook"}`, "url must be an absolute http url"},
+		{"error - unknown event", `{"url":"https://example.com/hook","events":["order.*"]}`, "unknown event"},
+	}
+	for _, c := range invalid {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			hd := newHandlerWebhook(newStoreWebhooks(), newStoreDeadLetters())
+
+			// act
+			res := serve(hd.Create(), http.MethodPost, "/webhooks", "/webhooks", "application/json", c.body)
+
+			// assert
+			require.Equal(t, http.StatusBadRequest, res.Code)
+			require.JSONEq(t, `"`+c.message+`"`, res.Body.String())
+		})
+	}
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(storeWebhookFailing{}, newStoreDeadLetters())
+
+		// act
+		res := serve(hd.Create(), http.MethodPost, "/webhooks", "/webhooks", "application/json", `{"url":"https://example.com/new"}`)
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerWebhook.Delete
+func TestHandlerWebhook_Delete(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		st := newStoreWebhooks()
+		hd := newHandlerWebhook(st, newStoreDeadLetters())
+
+		// act
+		res := serve(hd.Delete(), http.MethodDelete, "/webhooks/{id}", "/webhooks/1", "", "")
+
+		// assert
+		require.Equal(t, http.StatusNoContent, res.Code)
+		ws, err := st.ReadAll()
+		require.NoError(t, err)
+		require.NotContains(t, ws, 1)
+	})
+
+	t.Run("error - invalid id", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(newStoreWebhooks(), newStoreDeadLetters())
+
+		// act
+		res := serve(hd.Delete(), http.MethodDelete, "/webhooks/{id}", "/webhooks/a", "", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"invalid id"`, res.Body.String())
+	})
+
+	t.Run("error - webhook not found", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(newStoreWebhooks(), newStoreDeadLetters())
+
+		// act
+		res := serve(hd.Delete(), http.MethodDelete, "/webhooks/{id}", "/webhooks/9", "", "")
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `"webhook not found"`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(storeWebhookFailing{}, newStoreDeadLetters())
+
+		// act
+		res := serve(hd.Delete(), http.MethodDelete, "/webhooks/{id}", "/webhooks/1", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerWebhook.GetDeadLetters
+func TestHandlerWebhook_GetDeadLetters(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(newStoreWebhooks(), newStoreDeadLetters())
+
+		// act
+		res := serve(hd.GetDeadLetters(), http.MethodGet, "/webhooks/dead-letters", "/webhooks/dead-letters", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		event := `{"id":"e1","type":"product.created","entity_id":1,"actor":"alice","tenant":"","timestamp":"2030-01-02T00:00:00Z"}`
+		require.JSONEq(t, `{"message":"success","data":[
+			{"id":1,"webhook_id":1,"event":`+event+`,"attempts":5,"last_error":"status 500","failed_at":"2030-01-02T00:00:00Z"},
+			{"id":2,"webhook_id":9,"event":`+event+`,"attempts":5,"last_error":"status 500","failed_at":"2030-01-02T00:00:00Z"}
+		]}`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(newStoreWebhooks(), storeDeadLetterFailing{})
+
+		// act
+		res := serve(hd.GetDeadLetters(), http.MethodGet, "/webhooks/dead-letters", "/webhooks/dead-letters", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerWebhook.RetryDeadLetter
+func TestHandlerWebhook_RetryDeadLetter(t *testing.T) {
+	t.Run("success - dead letter queued again", func(t *testing.T) {
+		// arrange
+		st := newStoreDeadLetters()
+		hd := newHandlerWebhook(newStoreWebhooks(), st)
+
+		// act
+		res := serve(hd.RetryDeadLetter(), http.MethodPost, "/webhooks/dead-letters/{id}/retry", "/webhooks/dead-letters/1/retry", "", "")
+
+		// assert
+		require.Equal(t, http.StatusAccepted, res.Code)
+		require.JSONEq(t, `{"message":"success"}`, res.Body.String())
+		ds, err := st.ReadAll()
+		require.NoError(t, err)
+		require.NotContains(t, ds, 1)
+	})
+
+	t.Run("error - invalid id", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(newStoreWebhooks(), newStoreDeadLetters())
+
+		// act
+		res := serve(hd.RetryDeadLetter(), http.MethodPost, "/webhooks/dead-letters/{id}/retry", "/webhooks/dead-letters/a/retry", "", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"invalid id"`, res.Body.String())
+	})
+
+	t.Run("error - dead letter not found", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(newStoreWebhooks(), newStoreDeadLetters())
+
+		// act
+		res := serve(hd.RetryDeadLetter(), http.MethodPost, "/webhooks/dead-letters/{id}/retry", "/webhooks/dead-letters/9/retry", "", "")
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `"dead letter not found"`, res.Body.String())
+	})
+
+	t.Run("error - webhook no longer exists, dead letter kept", func(t *testing.T) {
+		// arrange
+		st := newStoreDeadLetters()
+		hd := newHandlerWebhook(newStoreWebhooks(), st)
+
+		// act
+		res := serve(hd.RetryDeadLetter(), http.MethodPost, "/webhooks/dead-letters/{id}/retry", "/webhooks/dead-letters/2/retry", "", "")
+
+		// assert
+		require.Equal(t, http.StatusConflict, res.Code)
+		require.JSONEq(t, `"webhook of the dead letter no longer exists"`, res.Body.String())
+		ds, err := st.ReadAll()
+		require.NoError(t, err)
+		require.Contains(t, ds, 2)
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(newStoreWebhooks(), storeDeadLetterFailing{})
+
+		// act
+		res := serve(hd.RetryDeadLetter(), http.MethodPost, "/webhooks/dead-letters/{id}/retry", "/webhooks/dead-letters/1/retry", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
+
+// Tests for HandlerWebhook.DeleteDeadLetter
+func TestHandlerWebhook_DeleteDeadLetter(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		st := newStoreDeadLetters()
+		hd := newHandlerWebhook(newStoreWebhooks(), st)
+
+		// act
+		res := serve(hd.DeleteDeadLetter(), http.MethodDelete, "/webhooks/dead-letters/{id}", "/webhooks/dead-letters/1", "", "")
+
+		// assert
+		require.Equal(t, http.StatusNoContent, res.Code)
+		ds, err := st.ReadAll()
+		require.NoError(t, err)
+		require.NotContains(t, ds, 1)
+	})
+
+	t.Run("error - invalid id", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(newStoreWebhooks(), newStoreDeadLetters())
+
+		// act
+		res := serve(hd.DeleteDeadLetter(), http.MethodDelete, "/webhooks/dead-letters/{id}", "/webhooks/dead-letters/a", "", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, `"invalid id"`, res.Body.String())
+	})
+
+	t.Run("error - dead letter not found", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(newStoreWebhooks(), newStoreDeadLetters())
+
+		// act
+		res := serve(hd.DeleteDeadLetter(), http.MethodDelete, "/webhooks/dead-letters/{id}", "/webhooks/dead-letters/9", "", "")
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `"dead letter not found"`, res.Body.String())
+	})
+
+	t.Run("error - internal server error", func(t *testing.T) {
+		// arrange
+		hd := newHandlerWebhook(newStoreWebhooks(), storeDeadLetterFailing{})
+
+		// act
+		res := serve(hd.DeleteDeadLetter(), http.MethodDelete, "/webhooks/dead-letters/{id}", "/webhooks/dead-letters/1", "", "")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `"internal server error"`, res.Body.String())
+	})
+}
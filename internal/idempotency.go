@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// IdempotencyRecord is the record of a request sent with an idempotency key, its response is replayed to the retries of the request.
+type IdempotencyRecord struct {
+	// Key identifies the request, see IdempotencyKey.
+	Key string
+	// BodyHash is the hash of the body of the request, a retry must send the same body.
+	BodyHash string
+	// StatusCode is the status code of the response, 0 while the request is in progress.
+	StatusCode int
+	// Header is the header of the response replayed, its content type and the headers identifying the resource.
+	Header map[string]string
+	// Body is the body of the response.
+	Body []byte
+	// CreatedAt is when the request was first received.
+	CreatedAt time.Time
+	// ExpiresAt is when the key can be used for another request.
+	ExpiresAt time.Time
+}
+
+// Completed tells whether the response of the request is recorded.
+func (r IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// IdempotencyKey returns the key of the record of a request, from the idempotency key sent by a client on a route.
+// - the keys of different tenants, clients or routes never collide, the same key may be sent to each
+func IdempotencyKey(tenant, client, route, key string) string {
+	sum := sha256.Sum256([]byte(tenant + "\x00" + client + "\x00" + route + "\x00" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IdempotencyBodyHash returns the hash of the body of a request.
+func IdempotencyBodyHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,29 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrRepositoryIdempotencyRecordNotFound is returned when an idempotency record is not found, or is expired.
+	ErrRepositoryIdempotencyRecordNotFound = errors.New("repository: idempotency record not found")
+	// ErrRepositoryIdempotencyKeyExists is returned when a key is reserved while it has a record not expired yet.
+	ErrRepositoryIdempotencyKeyExists = errors.New("repository: idempotency key exists")
+)
+
+// RepositoryIdempotency is an interface for a repository of idempotency records.
+type RepositoryIdempotency interface {
+	// Reserve saves the record of a request in progress, an expired record of its key is replaced.
+	// - ErrRepositoryIdempotencyKeyExists if its key has a record not expired yet
+	Reserve(ctx context.Context, r *IdempotencyRecord) (err error)
+	// FindByKey returns the record of a key, if not expired.
+	FindByKey(ctx context.Context, key string) (r IdempotencyRecord, err error)
+	// Complete records the response of the request of a record.
+	Complete(ctx context.Context, r *IdempotencyRecord) (err error)
+	// Delete deletes the record of a key, so the key can be sent again.
+	Delete(ctx context.Context, key string) (err error)
+	// Purge removes the records expired before a time, returning how many were removed.
+	Purge(ctx context.Context, before time.Time) (n int, err error)
+}
@@ -0,0 +1,9 @@
+package internal
+
+// StoreIdempotency is an interface for an idempotency record store.
+type StoreIdempotency interface {
+	// ReadAll reads all records from the store, by key.
+	ReadAll() (r map[string]IdempotencyRecord, err error)
+	// WriteAll writes all records to the store.
+	WriteAll(r map[string]IdempotencyRecord) (err error)
+}
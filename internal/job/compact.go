@@ -0,0 +1,30 @@
+package job
+
+import (
+	"context"
+)
+
+// Compacter is a storage dropping the records it no longer needs.
+type Compacter interface {
+	// Compact drops the records no longer needed, returning how many were dropped.
+	Compact(ctx context.Context) (n int, err error)
+}
+
+// NewJobCompact creates a new job compacting the JSON stores.
+func NewJobCompact(c Compacter) (j *JobCompact) {
+	j = &JobCompact{
+		c: c,
+	}
+	return
+}
+
+// JobCompact is a job compacting the JSON stores.
+type JobCompact struct {
+	// c is the storage to compact.
+	c Compacter
+}
+
+// Run compacts the stores, returning how many records were dropped.
+func (j *JobCompact) Run(ctx context.Context) (n int, err error) {
+	return j.c.Compact(ctx)
+}
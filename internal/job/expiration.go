@@ -0,0 +1,60 @@
+package job
+
+import (
+	"app/internal"
+	"context"
+	"time"
+)
+
+// ActorJobExpiration is the actor of the changes made by the expiration job.
+const ActorJobExpiration = "job:expiration"
+
+// NewJobExpiration creates a new job unpublishing the products once they expire.
+func NewJobExpiration(rp internal.RepositoryProduct) (j *JobExpiration) {
+	j = &JobExpiration{
+		rp: rp,
+	}
+	return
+}
+
+// JobExpiration is a job unpublishing the products once they expire.
+type JobExpiration struct {
+	// rp is the repository for products.
+	rp internal.RepositoryProduct
+}
+
+// Run unpublishes the products expired as of now.
+func (j *JobExpiration) Run(ctx context.Context) (n int, err error) {
+	return j.UnpublishExpired(ctx, time.Now())
+}
+
+// UnpublishExpired unpublishes the products expired before the date of a time, returning how many were unpublished.
+// - each tenant is unpublished on its own, so each change is recorded, and published, as one of its tenant
+func (j *JobExpiration) UnpublishExpired(ctx context.Context, at time.Time) (n int, err error) {
+	ctx = internal.ContextWithActor(ctx, ActorJobExpiration)
+	before := at.UTC().Truncate(24 * time.Hour)
+
+	// find the tenants of the products to unpublish
+	p, err := j.rp.FindExpired(internal.ContextWithTenant(ctx, internal.TenantAll), before, 0)
+	if err != nil {
+		return
+	}
+	tenants := make(map[string]bool)
+	for _, v := range p {
+		if v.IsPublished {
+			tenants[v.Tenant] = true
+		}
+	}
+
+	// unpublish each tenant
+	for _, tenant := range sortedTenants(tenants) {
+		var u []internal.Product
+		u, err = j.rp.UnpublishExpired(internal.ContextWithTenant(ctx, tenant), before)
+		n += len(u)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,48 @@
+package job_test
+
+import (
+	"app/internal"
+	"app/internal/job"
+	"app/internal/repository"
+	"app/internal/store"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for JobExpiration.UnpublishExpired
+func TestJobExpiration_UnpublishExpired(t *testing.T) {
+	now := time.Date(2030, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success - expired products of each tenant unpublished as changes of their tenant", func(t *testing.T) {
+		// arrange
+		stProduct := store.NewStoreProductMemory(map[int]internal.Product{
+			1: {Id: 1, ProductAttributes: internal.ProductAttributes{IsPublished: true, Expiration: now.AddDate(0, 0, -1)}, Version: 1, Tenant: "globex"},
+			2: {Id: 2, ProductAttributes: internal.ProductAttributes{IsPublished: true, Expiration: now.AddDate(0, 0, -1)}, Version: 1, Tenant: "acme"},
+			3: {Id: 3, ProductAttributes: internal.ProductAttributes{IsPublished: false, Expiration: now.AddDate(0, 0, -1)}, Version: 1, Tenant: "initech"},
+			4: {Id: 4, ProductAttributes: internal.ProductAttributes{IsPublished: true, Expiration: now.AddDate(0, 0, 1)}, Version: 1, Tenant: "acme"},
+		})
+		ra := &repositoryAuditRecorder{}
+		rp := repository.NewRepositoryProductAudited(repository.NewRepositoryProductStore(stProduct), ra)
+		jb := job.NewJobExpiration(rp)
+
+		// act
+		n, err := jb.UnpublishExpired(context.Background(), now)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+		require.Len(t, ra.e, 2)
+		require.Equal(t, 2, ra.e[0].EntityId)
+		require.Equal(t, "acme", ra.e[0].Tenant)
+		require.Equal(t, 1, ra.e[1].EntityId)
+		require.Equal(t, "globex", ra.e[1].Tenant)
+		ps, err := stProduct.ReadAll()
+		require.NoError(t, err)
+		require.False(t, ps[1].IsPublished)
+		require.False(t, ps[2].IsPublished)
+		require.True(t, ps[4].IsPublished)
+	})
+}
@@ -0,0 +1,28 @@
+package job
+
+import (
+	"app/internal"
+	"context"
+	"time"
+)
+
+// NewJobIdempotency creates a new job removing the expired idempotency records.
+func NewJobIdempotency(rp internal.RepositoryIdempotency) (j *JobIdempotency) {
+	j = &JobIdempotency{
+		rp: rp,
+	}
+	return
+}
+
+// JobIdempotency is a job removing the expired idempotency records.
+type JobIdempotency struct {
+	// rp is the repository for idempotency records.
+	rp internal.RepositoryIdempotency
+}
+
+// Run removes the records expired as of now, returning how many were removed.
+// - the records of every tenant are removed
+func (j *JobIdempotency) Run(ctx context.Context) (n int, err error) {
+	ctx = internal.ContextWithTenant(ctx, internal.TenantAll)
+	return j.rp.Purge(ctx, time.Now())
+}
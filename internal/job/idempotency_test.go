@@ -0,0 +1,37 @@
+package job_test
+
+import (
+	"app/internal"
+	"app/internal/job"
+	"app/internal/repository"
+	"app/internal/store"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for JobIdempotency.Run
+func TestJobIdempotency_Run(t *testing.T) {
+	t.Run("success - expired records removed", func(t *testing.T) {
+		// arrange
+		now := time.Now()
+		st := store.NewStoreIdempotencyMemory(map[string]internal.IdempotencyRecord{
+			"a": {Key: "a", StatusCode: 201, ExpiresAt: now.Add(-time.Hour)},
+			"b": {Key: "b", StatusCode: 201, ExpiresAt: now.Add(time.Hour)},
+		})
+		jb := job.NewJobIdempotency(repository.NewRepositoryIdempotencyStore(st))
+
+		// act
+		n, err := jb.Run(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+		rs, err := st.ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rs, 1)
+		require.Contains(t, rs, "b")
+	})
+}
@@ -0,0 +1,97 @@
+package job
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"time"
+)
+
+// NewJobOutbox creates a new job relaying the events of the outbox to a publisher.
+// - batch is how many messages are read from the outbox at once, 100 if not positive
+// - retention is the time a published message is kept before being purged, 24 hours if not positive
+func NewJobOutbox(ro internal.RepositoryOutbox, pb internal.Publisher, batch int, retention time.Duration) (j *JobOutbox) {
+	// default config
+	defaultBatch := 100
+	if batch > 0 {
+		defaultBatch = batch
+	}
+	defaultRetention := 24 * time.Hour
+	if retention > 0 {
+		defaultRetention = retention
+	}
+
+	j = &JobOutbox{
+		ro:        ro,
+		pb:        pb,
+		batch:     defaultBatch,
+		retention: defaultRetention,
+	}
+	return
+}
+
+// JobOutbox is a job relaying the events of the outbox to a publisher.
+// - a message is marked as published only once its event is published, so every event is published at least once
+// - the published messages are purged once beyond their retention
+// - a failing publish stops the relay, the message and the ones after it are retried by the next run in the same order
+type JobOutbox struct {
+	// ro is the outbox.
+	ro internal.RepositoryOutbox
+	// pb is the destination of the events.
+	pb internal.Publisher
+	// batch is how many messages are read from the outbox at once.
+	batch int
+	// retention is the time a published message is kept before being purged.
+	retention time.Duration
+}
+
+// Run relays the pending events until none is left and purges the published ones, returning how many were published.
+func (j *JobOutbox) Run(ctx context.Context) (n int, err error) {
+	ctx = internal.ContextWithTenant(ctx, internal.TenantAll)
+	n, err = j.Relay(ctx)
+	if err != nil {
+		return
+	}
+
+	_, err = j.ro.Purge(ctx, time.Now().Add(-j.retention))
+	return
+}
+
+// Relay relays the pending events until none is left, returning how many were published.
+func (j *JobOutbox) Relay(ctx context.Context) (n int, err error) {
+	ctx = internal.ContextWithTenant(ctx, internal.TenantAll)
+	for {
+		var ms []internal.OutboxMessage
+		ms, err = j.ro.FindPending(ctx, j.batch)
+		if err != nil {
+			return
+		}
+		if len(ms) == 0 {
+			return
+		}
+
+		// publish
+		// - a published message is marked, one already purged, e.g. by another relay, is not an error
+		var errPublish error
+		for _, m := range ms {
+			errPublish = j.pb.Publish(ctx, m.Event)
+			if errPublish != nil {
+				break
+			}
+			err = j.ro.MarkPublished(ctx, m.Id, time.Now())
+			if err != nil && !errors.Is(err, internal.ErrRepositoryOutboxMessageNotFound) {
+				return
+			}
+			err = nil
+			n++
+		}
+
+		if errPublish != nil {
+			err = errPublish
+			return
+		}
+		if len(ms) < j.batch {
+			return
+		}
+	}
+}
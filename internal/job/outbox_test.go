@@ -0,0 +1,141 @@
+package job_test
+
+import (
+	"app/internal"
+	"app/internal/job"
+	"app/internal/repository"
+	"app/internal/store"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// publisherRecorder is a publisher recording the events it receives, failing once fail events are recorded.
+type publisherRecorder struct {
+	events []internal.Event
+	fail   int
+}
+
+// Publish records an event.
+func (p *publisherRecorder) Publish(ctx context.Context, e internal.Event) (err error) {
+	if p.fail > 0 && len(p.events) == p.fail {
+		return errors.New("publisher down")
+	}
+	p.events = append(p.events, e)
+	return
+}
+
+// Tests for JobOutbox.Run
+func TestJobOutbox_Run(t *testing.T) {
+	t.Run("success - events of the mutations relayed in order", func(t *testing.T) {
+		// arrange
+		stProduct := store.NewStoreProductMemory(nil)
+		stOutbox := store.NewStoreOutboxMemory(nil)
+		uowStore := repository.NewUnitOfWorkStore(stProduct, store.NewStoreWarehouseMemory(nil), store.NewStorePriceMemory(nil), stOutbox)
+		rp := repository.NewRepositoryProductTransactional(repository.NewRepositoryProductStore(stProduct), repository.NewUnitOfWorkOutboxed(uowStore))
+		p := internal.Product{ProductAttributes: internal.ProductAttributes{Name: "product 1", Quantity: 1}}
+		require.NoError(t, rp.Save(context.Background(), &p))
+		require.NoError(t, rp.Delete(context.Background(), p.Id, 0))
+		pb := &publisherRecorder{}
+		jb := job.NewJobOutbox(uowStore.Outbox(), pb, 1, 0)
+
+		// act
+		n, err := jb.Run(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+		require.Len(t, pb.events, 2)
+		require.Equal(t, "1", pb.events[0].Id)
+		require.Equal(t, internal.EventProductCreated, pb.events[0].Type)
+		require.Equal(t, "2", pb.events[1].Id)
+		require.Equal(t, internal.EventProductDeleted, pb.events[1].Type)
+		ms, err := uowStore.Outbox().FindPending(context.Background(), 0)
+		require.NoError(t, err)
+		require.Empty(t, ms)
+	})
+
+	t.Run("success - published events purged without reusing their ids", func(t *testing.T) {
+		// arrange
+		stProduct := store.NewStoreProductMemory(nil)
+		stOutbox := store.NewStoreOutboxMemory(nil)
+		uowStore := repository.NewUnitOfWorkStore(stProduct, store.NewStoreWarehouseMemory(nil), store.NewStorePriceMemory(nil), stOutbox)
+		rp := repository.NewRepositoryProductTransactional(repository.NewRepositoryProductStore(stProduct), repository.NewUnitOfWorkOutboxed(uowStore))
+		p := internal.Product{ProductAttributes: internal.ProductAttributes{Name: "product 1", Quantity: 1}}
+		require.NoError(t, rp.Save(context.Background(), &p))
+		require.NoError(t, rp.Delete(context.Background(), p.Id, 0))
+		pb := &publisherRecorder{}
+		jb := job.NewJobOutbox(uowStore.Outbox(), pb, 0, time.Nanosecond)
+
+		// act
+		_, err := jb.Run(context.Background())
+		time.Sleep(time.Millisecond)
+		_, err2 := jb.Run(context.Background())
+		require.NoError(t, rp.Restore(context.Background(), p.Id))
+		_, err3 := jb.Run(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		require.NoError(t, err2)
+		require.NoError(t, err3)
+		require.Len(t, pb.events, 3)
+		require.Equal(t, "3", pb.events[2].Id)
+		ms, err := stOutbox.ReadAll()
+		require.NoError(t, err)
+		require.Len(t, ms, 1)
+		require.Contains(t, ms, 3)
+	})
+
+	t.Run("fail - events not published kept for the next run", func(t *testing.T) {
+		// arrange
+		stOutbox := store.NewStoreOutboxMemory(map[int]internal.OutboxMessage{
+			1: {Id: 1, Event: internal.Event{Id: "1", Type: internal.EventProductCreated}},
+			2: {Id: 2, Event: internal.Event{Id: "2", Type: internal.EventProductUpdated}},
+			3: {Id: 3, Event: internal.Event{Id: "3", Type: internal.EventProductDeleted}},
+		})
+		pb := &publisherRecorder{fail: 1}
+		jb := job.NewJobOutbox(repository.NewRepositoryOutboxStore(stOutbox), pb, 0, 0)
+
+		// act
+		n, err := jb.Run(context.Background())
+
+		// assert
+		require.Error(t, err)
+		require.Equal(t, 1, n)
+		ms, err := repository.NewRepositoryOutboxStore(stOutbox).FindPending(context.Background(), 0)
+		require.NoError(t, err)
+		require.Len(t, ms, 2)
+		require.Equal(t, 2, ms[0].Id)
+		require.Equal(t, 3, ms[1].Id)
+	})
+
+	t.Run("fail - events of a failed unit of work not appended", func(t *testing.T) {
+		// arrange
+		stOutbox := store.NewStoreOutboxMemory(nil)
+		uowStore := repository.NewUnitOfWorkStore(store.NewStoreProductMemory(nil), store.NewStoreWarehouseMemory(nil), store.NewStorePriceMemory(nil), stOutbox)
+		uow := repository.NewUnitOfWorkOutboxed(uowStore)
+		errAbort := errors.New("abort")
+		err := uow.Do(context.Background(), func(r internal.RepositoriesTx) (err error) {
+			p := internal.Product{ProductAttributes: internal.ProductAttributes{Name: "product 1"}}
+			err = r.Product.Save(context.Background(), &p)
+			if err != nil {
+				return
+			}
+			return errAbort
+		})
+		require.ErrorIs(t, err, errAbort)
+		pb := &publisherRecorder{}
+		jb := job.NewJobOutbox(uowStore.Outbox(), pb, 0, 0)
+
+		// act
+		n, err := jb.Run(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		require.Zero(t, n)
+		require.Empty(t, pb.events)
+	})
+}
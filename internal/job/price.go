@@ -0,0 +1,92 @@
+package job
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"time"
+)
+
+// ActorJobPrice is the actor of the changes made by the price job.
+const ActorJobPrice = "job:price"
+
+// NewJobPrice creates a new job applying the scheduled price changes once they are due.
+func NewJobPrice(uow internal.UnitOfWork, rpr internal.RepositoryPrice) (j *JobPrice) {
+	j = &JobPrice{
+		uow: uow,
+		rpr: rpr,
+	}
+	return
+}
+
+// JobPrice is a job applying the scheduled price changes once they are due.
+type JobPrice struct {
+	// uow is the unit of work updating a product and its price change at once.
+	uow internal.UnitOfWork
+	// rpr is the repository for price changes.
+	rpr internal.RepositoryPrice
+}
+
+// Run applies the price changes due now.
+func (j *JobPrice) Run(ctx context.Context) (n int, err error) {
+	return j.ApplyDue(ctx, time.Now())
+}
+
+// ApplyDue applies the price changes due at a time, returning how many were applied.
+// - a change of a product that no longer exists is canceled
+// - each change is applied in its own unit of work, so a failure does not hold back the others
+func (j *JobPrice) ApplyDue(ctx context.Context, at time.Time) (n int, err error) {
+	ctx = internal.ContextWithActor(ctx, ActorJobPrice)
+	ctx = internal.ContextWithTenant(ctx, internal.TenantAll)
+
+	cs, err := j.rpr.FindDue(ctx, at)
+	if err != nil {
+		return
+	}
+
+	var errs []error
+	for _, c := range cs {
+		var applied bool
+		e := j.uow.Do(ctx, func(r internal.RepositoriesTx) (err error) {
+			applied, err = applyPrice(ctx, r, c)
+			return
+		})
+		if e != nil {
+			errs = append(errs, e)
+			continue
+		}
+		if applied {
+			n++
+		}
+	}
+
+	err = errors.Join(errs...)
+	return
+}
+
+// applyPrice sets the price of a change to its product and marks the change as applied.
+func applyPrice(ctx context.Context, r internal.RepositoriesTx, c internal.PriceChange) (applied bool, err error) {
+	p, err := r.Product.FindById(ctx, c.ProductId)
+	if err != nil {
+		if errors.Is(err, internal.ErrRepositoryProductNotFound) {
+			err = r.Price.UpdateStatus(ctx, c.Id, internal.PriceStatusCanceled)
+		}
+		return
+	}
+
+	// the change applies whatever the version of the product
+	p.Price = c.Price
+	p.Version = 0
+	err = r.Product.Update(ctx, &p)
+	if err != nil {
+		return
+	}
+
+	err = r.Price.UpdateStatus(ctx, c.Id, internal.PriceStatusApplied)
+	if err != nil {
+		return
+	}
+
+	applied = true
+	return
+}
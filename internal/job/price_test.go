@@ -0,0 +1,68 @@
+package job_test
+
+import (
+	"app/internal"
+	"app/internal/job"
+	"app/internal/repository"
+	"app/internal/store"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for JobPrice.ApplyDue
+func TestJobPrice_ApplyDue(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success - due prices applied", func(t *testing.T) {
+		// arrange
+		stProduct := store.NewStoreProductMemory(map[int]internal.Product{
+			1: {Id: 1, ProductAttributes: internal.ProductAttributes{Name: "product 1", Price: internal.NewMoney(100, internal.CurrencyDefault)}, Version: 1},
+			2: {Id: 2, ProductAttributes: internal.ProductAttributes{Name: "product 2", Price: internal.NewMoney(200, internal.CurrencyDefault)}, Version: 1},
+		})
+		stPrice := store.NewStorePriceMemory(map[int]internal.PriceChange{
+			1: {Id: 1, ProductId: 1, Price: internal.NewMoney(1000, internal.CurrencyDefault), EffectiveAt: now.Add(-time.Hour), Status: internal.PriceStatusScheduled},
+			2: {Id: 2, ProductId: 2, Price: internal.NewMoney(2000, internal.CurrencyDefault), EffectiveAt: now.Add(time.Hour), Status: internal.PriceStatusScheduled},
+		})
+		uow := repository.NewUnitOfWorkStore(stProduct, store.NewStoreWarehouseMemory(nil), stPrice, store.NewStoreOutboxMemory(nil))
+		jb := job.NewJobPrice(uow, repository.NewRepositoryPriceStore(stPrice))
+
+		// act
+		n, err := jb.ApplyDue(context.Background(), now)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+		ps, err := stProduct.ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, internal.NewMoney(1000, internal.CurrencyDefault), ps[1].Price)
+		require.Equal(t, 2, ps[1].Version)
+		require.Equal(t, internal.NewMoney(200, internal.CurrencyDefault), ps[2].Price)
+		cs, err := stPrice.ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, internal.PriceStatusApplied, cs[1].Status)
+		require.Equal(t, internal.PriceStatusScheduled, cs[2].Status)
+	})
+
+	t.Run("success - product not found cancels the change", func(t *testing.T) {
+		// arrange
+		stProduct := store.NewStoreProductMemory(nil)
+		stPrice := store.NewStorePriceMemory(map[int]internal.PriceChange{
+			1: {Id: 1, ProductId: 1, Price: internal.NewMoney(1000, internal.CurrencyDefault), EffectiveAt: now.Add(-time.Hour), Status: internal.PriceStatusScheduled},
+		})
+		uow := repository.NewUnitOfWorkStore(stProduct, store.NewStoreWarehouseMemory(nil), stPrice, store.NewStoreOutboxMemory(nil))
+		jb := job.NewJobPrice(uow, repository.NewRepositoryPriceStore(stPrice))
+
+		// act
+		n, err := jb.ApplyDue(context.Background(), now)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 0, n)
+		cs, err := stPrice.ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, internal.PriceStatusCanceled, cs[1].Status)
+	})
+}
@@ -0,0 +1,116 @@
+package job
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"time"
+)
+
+// ActorJobPurge is the actor of the changes made by the purge job.
+const ActorJobPurge = "job:purge"
+
+// NewJobPurge creates a new job purging the products and warehouses soft deleted beyond a retention period.
+// - rp or rw may be nil to purge only the other
+func NewJobPurge(rp internal.RepositoryProduct, rw internal.RepositoryWarehouse, retention time.Duration) (j *JobPurge) {
+	// default config
+	defaultRetention := 30 * 24 * time.Hour
+	if retention > 0 {
+		defaultRetention = retention
+	}
+
+	j = &JobPurge{
+		rp:        rp,
+		rw:        rw,
+		retention: defaultRetention,
+	}
+	return
+}
+
+// JobPurge is a job purging the products and warehouses soft deleted beyond a retention period.
+type JobPurge struct {
+	// rp is the repository for products.
+	rp internal.RepositoryProduct
+	// rw is the repository for warehouses.
+	rw internal.RepositoryWarehouse
+	// retention is the time a soft deleted row is kept before being purged.
+	retention time.Duration
+}
+
+// Run purges the rows soft deleted beyond the retention period as of now.
+func (j *JobPurge) Run(ctx context.Context) (n int, err error) {
+	return j.Purge(ctx, time.Now().Add(-j.retention))
+}
+
+// Purge purges the products and warehouses soft deleted before a time, returning how many were purged.
+// - each tenant is purged on its own, so each purge is recorded, and published, as one of its tenant
+func (j *JobPurge) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	ctx = internal.ContextWithActor(ctx, ActorJobPurge)
+
+	var errs []error
+	if j.rp != nil {
+		np, e := j.purgeProducts(ctx, before)
+		n += np
+		errs = append(errs, e)
+	}
+	if j.rw != nil {
+		nw, e := j.purgeWarehouses(ctx, before)
+		n += nw
+		errs = append(errs, e)
+	}
+
+	err = errors.Join(errs...)
+	return
+}
+
+// purgeProducts purges the products soft deleted before a time, one tenant after the other.
+func (j *JobPurge) purgeProducts(ctx context.Context, before time.Time) (n int, err error) {
+	// find the tenants of the products to purge
+	p, err := j.rp.GetAllIncludingDeleted(internal.ContextWithTenant(ctx, internal.TenantAll))
+	if err != nil {
+		return
+	}
+	tenants := make(map[string]bool)
+	for _, v := range p {
+		if !v.DeletedAt.IsZero() && v.DeletedAt.Before(before) {
+			tenants[v.Tenant] = true
+		}
+	}
+
+	// purge each tenant
+	var errs []error
+	for _, tenant := range sortedTenants(tenants) {
+		np, e := j.rp.Purge(internal.ContextWithTenant(ctx, tenant), before)
+		n += np
+		errs = append(errs, e)
+	}
+
+	err = errors.Join(errs...)
+	return
+}
+
+// purgeWarehouses purges the warehouses soft deleted before a time, one tenant after the other.
+func (j *JobPurge) purgeWarehouses(ctx context.Context, before time.Time) (n int, err error) {
+	// find the tenants of the warehouses to purge
+	w, err := j.rw.GetAllIncludingDeleted(internal.ContextWithTenant(ctx, internal.TenantAll))
+	if err != nil {
+		return
+	}
+	tenants := make(map[string]bool)
+	for _, v := range w {
+		if !v.DeletedAt.IsZero() && v.DeletedAt.Before(before) {
+			tenants[v.Tenant] = true
+		}
+	}
+
+	// purge each tenant
+	var errs []error
+	for _, tenant := range sortedTenants(tenants) {
+		nw, e := j.rw.Purge(internal.ContextWithTenant(ctx, tenant), before)
+		n += nw
+		errs = append(errs, e)
+	}
+
+	err = errors.Join(errs...)
+	return
+}
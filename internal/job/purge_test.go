@@ -0,0 +1,91 @@
+package job_test
+
+import (
+	"app/internal"
+	"app/internal/job"
+	"app/internal/repository"
+	"app/internal/store"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for JobPurge.Purge
+func TestJobPurge_Purge(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success - rows deleted before the time purged", func(t *testing.T) {
+		// arrange
+		stProduct := store.NewStoreProductMemory(map[int]internal.Product{
+			1: {Id: 1, Version: 1, DeletedAt: now.Add(-2 * time.Hour)},
+			2: {Id: 2, Version: 1, DeletedAt: now.Add(time.Hour)},
+			3: {Id: 3, Version: 1},
+		})
+		stWarehouse := store.NewStoreWarehouseMemory(map[int]internal.Warehouse{
+			1: {Id: 1, Version: 1, DeletedAt: now.Add(-2 * time.Hour)},
+		})
+		jb := job.NewJobPurge(repository.NewRepositoryProductStore(stProduct), repository.NewRepositoryWarehouseStore(stWarehouse, stProduct), 0)
+
+		// act
+		n, err := jb.Purge(context.Background(), now)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+		ps, err := stProduct.ReadAll()
+		require.NoError(t, err)
+		require.Len(t, ps, 2)
+		require.NotContains(t, ps, 1)
+		ws, err := stWarehouse.ReadAll()
+		require.NoError(t, err)
+		require.Empty(t, ws)
+	})
+	t.Run("success - each tenant purged on its own, its purge recorded as one of it", func(t *testing.T) {
+		// arrange
+		stProduct := store.NewStoreProductMemory(map[int]internal.Product{
+			1: {Id: 1, Version: 1, Tenant: "acme", DeletedAt: now.Add(-2 * time.Hour)},
+			2: {Id: 2, Version: 1, Tenant: "acme", DeletedAt: now.Add(-2 * time.Hour)},
+			3: {Id: 3, Version: 1, DeletedAt: now.Add(-2 * time.Hour)},
+			4: {Id: 4, Version: 1, Tenant: "globex"},
+		})
+		ra := &repositoryAuditRecorder{}
+		rp := repository.NewRepositoryProductAudited(repository.NewRepositoryProductStore(stProduct), ra)
+		jb := job.NewJobPurge(rp, nil, 0)
+
+		// act
+		n, err := jb.Purge(context.Background(), now)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 3, n)
+		require.Len(t, ra.e, 2)
+		require.Equal(t, "acme", ra.e[0].Tenant)
+		require.JSONEq(t, `{"before":"2030-01-01T00:00:00Z","count":2}`, string(ra.e[0].After))
+		require.Equal(t, internal.TenantDefault, ra.e[1].Tenant)
+		require.JSONEq(t, `{"before":"2030-01-01T00:00:00Z","count":1}`, string(ra.e[1].After))
+	})
+}
+
+// repositoryAuditRecorder is an audit log recording its entries in memory.
+type repositoryAuditRecorder struct {
+	// e is the recorded entries, oldest first.
+	e []internal.AuditEntry
+}
+
+// Save records an entry.
+func (r *repositoryAuditRecorder) Save(ctx context.Context, e *internal.AuditEntry) (err error) {
+	r.e = append(r.e, *e)
+	return
+}
+
+// FindByEntity returns the recorded entries of an entity.
+func (r *repositoryAuditRecorder) FindByEntity(ctx context.Context, entity string, id int) (e []internal.AuditEntry, err error) {
+	for _, v := range r.e {
+		if v.Entity == entity && v.EntityId == id {
+			e = append(e, v)
+		}
+	}
+	return
+}
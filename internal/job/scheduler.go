@@ -0,0 +1,187 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Task is a unit of background work run periodically by a scheduler.
+type Task interface {
+	// Run runs the task once, returning how many items it processed.
+	Run(ctx context.Context) (n int, err error)
+}
+
+// TaskFunc is an adapter to use a function as a task.
+type TaskFunc func(ctx context.Context) (n int, err error)
+
+// Run calls f.
+func (f TaskFunc) Run(ctx context.Context) (n int, err error) {
+	return f(ctx)
+}
+
+// TaskStatus is the status of a scheduled task.
+type TaskStatus struct {
+	// Name is the name of the task.
+	Name string
+	// Interval is the time between runs.
+	Interval time.Duration
+	// Running tells whether the task is running right now.
+	Running bool
+	// Runs is how many times the task ran.
+	Runs int
+	// Failures is how many runs of the task failed.
+	Failures int
+	// LastRunAt is when the last run started, zero if it never ran.
+	LastRunAt time.Time
+	// LastDuration is how long the last run took.
+	LastDuration time.Duration
+	// LastResult is how many items the last run processed.
+	LastResult int
+	// LastError is the error of the last run, empty if it succeeded.
+	LastError string
+	// NextRunAt is when the next run starts, zero if the scheduler is stopped.
+	NextRunAt time.Time
+}
+
+// scheduledTask is a task along with its status.
+type scheduledTask struct {
+	task   Task
+	status TaskStatus
+}
+
+// NewScheduler creates a new scheduler.
+func NewScheduler() (s *Scheduler) {
+	s = &Scheduler{}
+	return
+}
+
+// Scheduler runs tasks periodically in the background.
+// - each task runs right away on start and then every interval, in its own goroutine
+// - a task never overlaps with itself, a run taking longer than the interval delays the next one
+type Scheduler struct {
+	// mu guards the tasks and their status.
+	mu sync.Mutex
+	// tasks are the scheduled tasks in the order they were added.
+	tasks []*scheduledTask
+	// cancel stops the running tasks, nil if the scheduler is stopped.
+	cancel context.CancelFunc
+	// wg waits for the running tasks to stop.
+	wg sync.WaitGroup
+}
+
+// Add schedules a task to run every interval.
+// - it must be called before Start
+func (s *Scheduler) Add(name string, interval time.Duration, t Task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tasks = append(s.tasks, &scheduledTask{
+		task:   t,
+		status: TaskStatus{Name: name, Interval: interval},
+	})
+}
+
+// Start starts running the tasks in the background.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return
+	}
+
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+	for _, st := range s.tasks {
+		s.wg.Add(1)
+		go func(st *scheduledTask) {
+			defer s.wg.Done()
+			s.loop(ctx, st)
+		}(st)
+	}
+}
+
+// Stop stops the tasks, waiting for the running ones to return.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	s.wg.Wait()
+
+	s.mu.Lock()
+	for _, st := range s.tasks {
+		st.status.NextRunAt = time.Time{}
+	}
+	s.mu.Unlock()
+}
+
+// Status returns the status of the tasks in the order they were added.
+func (s *Scheduler) Status() (ts []TaskStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ts = make([]TaskStatus, len(s.tasks))
+	for i, st := range s.tasks {
+		ts[i] = st.status
+	}
+	return
+}
+
+// loop runs a task right away and then every interval until ctx is done.
+func (s *Scheduler) loop(ctx context.Context, st *scheduledTask) {
+	ticker := time.NewTicker(st.status.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.run(ctx, st)
+
+		s.mu.Lock()
+		st.status.NextRunAt = time.Now().Add(st.status.Interval)
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run runs a task once, recording its status.
+func (s *Scheduler) run(ctx context.Context, st *scheduledTask) {
+	start := time.Now()
+	s.mu.Lock()
+	st.status.Running = true
+	st.status.LastRunAt = start
+	s.mu.Unlock()
+
+	n, err := st.task.Run(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st.status.Running = false
+	st.status.Runs++
+	st.status.LastDuration = time.Since(start)
+	st.status.LastResult = n
+	st.status.LastError = ""
+	if err != nil {
+		st.status.Failures++
+		st.status.LastError = err.Error()
+	}
+
+	// a task canceled on stop is not worth logging
+	if err != nil && ctx.Err() == nil {
+		slog.ErrorContext(ctx, "job failed", "job", st.status.Name, "error", err)
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "job run", "job", st.status.Name, "processed", n)
+	}
+}
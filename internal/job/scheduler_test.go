@@ -0,0 +1,75 @@
+package job_test
+
+import (
+	"app/internal/job"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Scheduler
+func TestScheduler(t *testing.T) {
+	t.Run("success - tasks run until stopped", func(t *testing.T) {
+		// arrange
+		runs := make(chan struct{}, 10)
+		sc := job.NewScheduler()
+		sc.Add("ok", time.Millisecond, job.TaskFunc(func(ctx context.Context) (n int, err error) {
+			select {
+			case runs <- struct{}{}:
+			default:
+			}
+			n = 2
+			return
+		}))
+		sc.Add("failing", time.Hour, job.TaskFunc(func(ctx context.Context) (n int, err error) {
+			err = errors.New("task failed")
+			return
+		}))
+
+		// act
+		sc.Start()
+		<-runs
+		<-runs
+		sc.Stop()
+
+		// assert
+		ts := sc.Status()
+		require.Len(t, ts, 2)
+		require.Equal(t, "ok", ts[0].Name)
+		require.GreaterOrEqual(t, ts[0].Runs, 2)
+		require.Equal(t, 0, ts[0].Failures)
+		require.Equal(t, 2, ts[0].LastResult)
+		require.False(t, ts[0].Running)
+		require.False(t, ts[0].LastRunAt.IsZero())
+		require.True(t, ts[0].NextRunAt.IsZero())
+		require.Equal(t, "failing", ts[1].Name)
+		require.Equal(t, 1, ts[1].Runs)
+		require.Equal(t, 1, ts[1].Failures)
+		require.Equal(t, "task failed", ts[1].LastError)
+	})
+
+	t.Run("success - stop cancels the running tasks", func(t *testing.T) {
+		// arrange
+		started := make(chan struct{})
+		sc := job.NewScheduler()
+		sc.Add("blocking", time.Hour, job.TaskFunc(func(ctx context.Context) (n int, err error) {
+			close(started)
+			<-ctx.Done()
+			err = ctx.Err()
+			return
+		}))
+
+		// act
+		sc.Start()
+		<-started
+		sc.Stop()
+
+		// assert
+		ts := sc.Status()
+		require.Equal(t, 1, ts[0].Runs)
+		require.Equal(t, context.Canceled.Error(), ts[0].LastError)
+	})
+}
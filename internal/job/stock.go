@@ -0,0 +1,70 @@
+package job
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"time"
+)
+
+// NewJobLowStock creates a new job raising an alert for every product below its reorder threshold.
+func NewJobLowStock(rp internal.RepositoryProduct, rt internal.RepositoryReorderThreshold, sink internal.AlertSink) (j *JobLowStock) {
+	j = &JobLowStock{
+		rp:   rp,
+		rt:   rt,
+		sink: sink,
+	}
+	return
+}
+
+// JobLowStock is a job raising an alert for every product below its reorder threshold.
+// - unlike the alerts raised on mutations, the products stay alerted on every run until they are restocked
+type JobLowStock struct {
+	// rp is the repository for products.
+	rp internal.RepositoryProduct
+	// rt is the repository for reorder thresholds.
+	rt internal.RepositoryReorderThreshold
+	// sink is the destination of the alerts.
+	sink internal.AlertSink
+}
+
+// Run raises an alert for every product low on stock, returning how many there are.
+// - a failing alert does not hold back the others
+func (j *JobLowStock) Run(ctx context.Context) (n int, err error) {
+	as, err := j.LowStock(ctx)
+	if err != nil {
+		return
+	}
+
+	var errs []error
+	for _, a := range as {
+		errs = append(errs, j.sink.Alert(ctx, a))
+	}
+
+	n = len(as)
+	err = errors.Join(errs...)
+	return
+}
+
+// LowStock returns an alert for every product below its reorder threshold.
+func (j *JobLowStock) LowStock(ctx context.Context) (as []internal.LowStockAlert, err error) {
+	ctx = internal.ContextWithTenant(ctx, internal.TenantAll)
+
+	ps, err := j.rp.GetAll(ctx)
+	if err != nil {
+		return
+	}
+	ts, err := j.rt.GetAll(ctx)
+	if err != nil {
+		return
+	}
+	thresholds := internal.NewReorderThresholds(ts)
+
+	now := time.Now()
+	for _, p := range ps {
+		if q, low := thresholds.IsLow(p); low {
+			as = append(as, internal.LowStockAlert{Product: p, Threshold: q, Timestamp: now})
+		}
+	}
+	return
+}
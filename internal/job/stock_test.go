@@ -0,0 +1,50 @@
+package job_test
+
+import (
+	"app/internal"
+	"app/internal/job"
+	"app/internal/repository"
+	"app/internal/store"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// alertSinkRecorder is a sink recording the alerts it receives.
+type alertSinkRecorder struct {
+	alerts []internal.LowStockAlert
+}
+
+// Alert records a low stock alert.
+func (s *alertSinkRecorder) Alert(ctx context.Context, a internal.LowStockAlert) (err error) {
+	s.alerts = append(s.alerts, a)
+	return
+}
+
+// Tests for JobLowStock.Run
+func TestJobLowStock_Run(t *testing.T) {
+	t.Run("success - products below their threshold alerted", func(t *testing.T) {
+		// arrange
+		stProduct := store.NewStoreProductMemory(map[int]internal.Product{
+			1: {Id: 1, ProductAttributes: internal.ProductAttributes{Quantity: 1}, WarehouseId: 1, Version: 1},
+			2: {Id: 2, ProductAttributes: internal.ProductAttributes{Quantity: 50}, WarehouseId: 1, Version: 1},
+			3: {Id: 3, ProductAttributes: internal.ProductAttributes{Quantity: 0}, WarehouseId: 2, Version: 1},
+		})
+		stThreshold := store.NewStoreReorderThresholdMemory(nil)
+		rt := repository.NewRepositoryReorderThresholdStore(stThreshold)
+		require.NoError(t, rt.Save(context.Background(), &internal.ReorderThreshold{ReorderScope: internal.ReorderScope{Kind: internal.ReorderScopeWarehouse, Id: 1}, Quantity: 10}))
+		sink := &alertSinkRecorder{}
+		jb := job.NewJobLowStock(repository.NewRepositoryProductStore(stProduct), rt, sink)
+
+		// act
+		n, err := jb.Run(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+		require.Len(t, sink.alerts, 1)
+		require.Equal(t, 1, sink.alerts[0].Product.Id)
+		require.Equal(t, 10, sink.alerts[0].Threshold)
+	})
+}
@@ -0,0 +1,24 @@
+package job
+
+import (
+	"app/internal"
+	"sort"
+)
+
+// sortedTenants returns a set of tenants sorted, so a job works on one tenant after the other.
+// - data without tenant predates tenants, it is of the default one
+func sortedTenants(tenants map[string]bool) (t []string) {
+	seen := make(map[string]bool, len(tenants))
+	for tenant := range tenants {
+		if tenant == "" {
+			tenant = internal.TenantDefault
+		}
+		if seen[tenant] {
+			continue
+		}
+		seen[tenant] = true
+		t = append(t, tenant)
+	}
+	sort.Strings(t)
+	return
+}
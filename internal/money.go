@@ -0,0 +1,233 @@
+package internal
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MoneyScale is the number of decimal places of an amount of money.
+	MoneyScale = 2
+	// CurrencyDefault is the currency of an amount of money that does not state one.
+	CurrencyDefault = "USD"
+)
+
+var (
+	// ErrMoneyInvalid is returned when an amount of money can not be parsed.
+	ErrMoneyInvalid = errors.New("money: invalid amount")
+	// ErrMoneyCurrencyMismatch is returned when operating amounts of money of different currencies.
+	ErrMoneyCurrencyMismatch = errors.New("money: currency mismatch")
+)
+
+// moneyUnit is the number of minor units in a major unit, 10^MoneyScale.
+const moneyUnit = 100
+
+// moneyPattern is the pattern of a decimal amount, e.g. -352.79: no exponent, no fraction.
+var moneyPattern = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// currencyScales are the currencies with fewer decimal places than MoneyScale, by ISO 4217 code.
+var currencyScales = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// CurrencyScale returns the number of decimal places of the amounts of a currency, at most MoneyScale.
+func CurrencyScale(currency string) int {
+	if scale, ok := currencyScales[NormalizeCurrency(currency)]; ok {
+		return scale
+	}
+	return MoneyScale
+}
+
+// Money is an exact amount of money in a currency
+// - the amount is kept in minor units, so it adds up without rounding errors
+type Money struct {
+	// Amount is the amount in minor units of the currency, e.g. cents
+	Amount int64
+	// Currency is the ISO 4217 code of the currency
+	Currency string
+}
+
+// NewMoney creates an amount of money from its minor units.
+func NewMoney(amount int64, currency string) Money {
+	return Money{
+		Amount:   amount,
+		Currency: NormalizeCurrency(currency),
+	}
+}
+
+// ParseMoney parses a decimal amount, e.g. "352.79", in a currency.
+// - amounts with more decimal places than the currency has are rejected, not rounded
+// - fractions and exponents, e.g. "1/4" or "1e3", are rejected
+func ParseMoney(s, currency string) (m Money, err error) {
+	s = strings.TrimSpace(s)
+	if !moneyPattern.MatchString(s) {
+		err = fmt.Errorf("%w: %q", ErrMoneyInvalid, s)
+		return
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		err = fmt.Errorf("%w: %q", ErrMoneyInvalid, s)
+		return
+	}
+	r.Mul(r, big.NewRat(moneyUnit, 1))
+	if !r.IsInt() {
+		err = fmt.Errorf("%w: %q has more than %d decimal places", ErrMoneyInvalid, s, MoneyScale)
+		return
+	}
+	if !r.Num().IsInt64() {
+		err = fmt.Errorf("%w: %q out of range", ErrMoneyInvalid, s)
+		return
+	}
+
+	m = NewMoney(r.Num().Int64(), currency)
+	err = m.Validate()
+	if err != nil {
+		m = Money{}
+		return
+	}
+	return
+}
+
+// Validate checks the amount has no more decimal places than its currency, e.g. none for JPY.
+func (m Money) Validate() (err error) {
+	scale := CurrencyScale(m.Currency)
+	unit := int64(math.Pow10(MoneyScale - scale))
+	if m.Amount%unit != 0 {
+		err = fmt.Errorf("%w: %s has more than %d decimal places in %s", ErrMoneyInvalid, m, scale, NormalizeCurrency(m.Currency))
+	}
+	return
+}
+
+// String returns the decimal amount, e.g. "352.79", without the currency.
+func (m Money) String() string {
+	sign := ""
+	amount := uint64(m.Amount)
+	if m.Amount < 0 {
+		sign = "-"
+		amount = uint64(-(m.Amount + 1)) + 1
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/moneyUnit, MoneyScale, amount%moneyUnit)
+}
+
+// IsNegative tells whether the amount is lower than zero.
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Equal tells whether two amounts of money are the same.
+func (m Money) Equal(o Money) bool {
+	return m.Amount == o.Amount && NormalizeCurrency(m.Currency) == NormalizeCurrency(o.Currency)
+}
+
+// Add returns the sum of two amounts of the same currency.
+func (m Money) Add(o Money) (s Money, err error) {
+	err = m.sameCurrency(o)
+	if err != nil {
+		return
+	}
+	if (o.Amount > 0 && m.Amount > math.MaxInt64-o.Amount) || (o.Amount < 0 && m.Amount < math.MinInt64-o.Amount) {
+		err = fmt.Errorf("%w: overflow", ErrMoneyInvalid)
+		return
+	}
+
+	s = NewMoney(m.Amount+o.Amount, m.Currency)
+	return
+}
+
+// Sub returns the difference of two amounts of the same currency.
+func (m Money) Sub(o Money) (d Money, err error) {
+	if o.Amount == math.MinInt64 {
+		err = fmt.Errorf("%w: overflow", ErrMoneyInvalid)
+		return
+	}
+	return m.Add(Money{Amount: -o.Amount, Currency: o.Currency})
+}
+
+// Mul returns the amount multiplied by a quantity.
+func (m Money) Mul(n int) (p Money, err error) {
+	if n != 0 && (m.Amount*int64(n))/int64(n) != m.Amount {
+		err = fmt.Errorf("%w: overflow", ErrMoneyInvalid)
+		return
+	}
+
+	p = NewMoney(m.Amount*int64(n), m.Currency)
+	return
+}
+
+// MarshalJSON encodes the amount as a JSON number, e.g. 352.79.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON decodes the amount from a JSON number, or a string holding one.
+// - the currency is left as it was, it is encoded apart from the amount
+func (m *Money) UnmarshalJSON(b []byte) (err error) {
+	s := string(b)
+	if s == "null" {
+		return
+	}
+	if unquoted, e := strconv.Unquote(s); e == nil {
+		s = unquoted
+	}
+
+	v, err := ParseMoney(s, m.Currency)
+	if err != nil {
+		return
+	}
+	m.Amount = v.Amount
+	return
+}
+
+// Value encodes the amount for a DECIMAL column.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan decodes the amount of a DECIMAL column.
+// - the currency is left as it was, it is stored in its own column
+func (m *Money) Scan(src any) (err error) {
+	var s string
+	switch v := src.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		err = fmt.Errorf("%w: can not scan %T", ErrMoneyInvalid, src)
+		return
+	}
+
+	v, err := ParseMoney(s, m.Currency)
+	if err != nil {
+		return
+	}
+	m.Amount = v.Amount
+	return
+}
+
+// sameCurrency checks two amounts are of the same currency.
+func (m Money) sameCurrency(o Money) (err error) {
+	if NormalizeCurrency(m.Currency) != NormalizeCurrency(o.Currency) {
+		err = fmt.Errorf("%w: %s and %s", ErrMoneyCurrencyMismatch, NormalizeCurrency(m.Currency), NormalizeCurrency(o.Currency))
+	}
+	return
+}
+
+// NormalizeCurrency returns the upper case code of a currency, CurrencyDefault if empty.
+func NormalizeCurrency(currency string) string {
+	if currency == "" {
+		return CurrencyDefault
+	}
+	return strings.ToUpper(currency)
+}
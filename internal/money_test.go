@@ -0,0 +1,153 @@
+package internal_test
+
+import (
+	"app/internal"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for ParseMoney function
+func TestParseMoney(t *testing.T) {
+	t.Run("success - decimal amounts", func(t *testing.T) {
+		// arrange
+		inputs := map[string]int64{
+			"352.79": 35279,
+			"0.1":    10,
+			"10":     1000,
+			"-0.05":  -5,
+		}
+
+		for input, expected := range inputs {
+			// act
+			m, err := internal.ParseMoney(input, "usd")
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, internal.NewMoney(expected, internal.CurrencyDefault), m)
+		}
+	})
+
+	t.Run("error - too many decimal places", func(t *testing.T) {
+		// act
+		_, err := internal.ParseMoney("1.234", "")
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrMoneyInvalid)
+	})
+
+	t.Run("error - not a number", func(t *testing.T) {
+		// act
+		_, err := internal.ParseMoney("ten", "")
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrMoneyInvalid)
+	})
+
+	t.Run("error - not a decimal", func(t *testing.T) {
+		for _, input := range []string{"1/4", "1e3", "+1", ".5", "1."} {
+			// act
+			_, err := internal.ParseMoney(input, "")
+
+			// assert
+			require.ErrorIs(t, err, internal.ErrMoneyInvalid, input)
+		}
+	})
+
+	t.Run("success - whole amounts of a currency without decimal places", func(t *testing.T) {
+		// act
+		m, err := internal.ParseMoney("150.00", "jpy")
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, internal.NewMoney(15000, "JPY"), m)
+	})
+
+	t.Run("error - more decimal places than the currency has", func(t *testing.T) {
+		// act
+		_, err := internal.ParseMoney("1.50", "JPY")
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrMoneyInvalid)
+	})
+}
+
+// Tests for Money arithmetic
+func TestMoney_Add(t *testing.T) {
+	t.Run("success - exact sum", func(t *testing.T) {
+		// arrange
+		m := internal.NewMoney(0, "")
+		var f float64
+
+		// act
+		var err error
+		for i := 0; i < 10; i++ {
+			m, err = m.Add(internal.NewMoney(10, ""))
+			require.NoError(t, err)
+			f += 0.1
+		}
+
+		// assert
+		require.Equal(t, "1.00", m.String())
+		require.NotEqual(t, 1.0, f)
+	})
+
+	t.Run("error - currency mismatch", func(t *testing.T) {
+		// act
+		_, err := internal.NewMoney(100, "USD").Add(internal.NewMoney(100, "EUR"))
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrMoneyCurrencyMismatch)
+	})
+
+	t.Run("success - mul and sub", func(t *testing.T) {
+		// act
+		m, err := internal.NewMoney(35279, "").Mul(3)
+		require.NoError(t, err)
+		m, err = m.Sub(internal.NewMoney(37, ""))
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "1058.00", m.String())
+	})
+}
+
+// Tests for Money JSON encoding
+func TestMoney_JSON(t *testing.T) {
+	t.Run("success - encoded as a number", func(t *testing.T) {
+		// act
+		b, err := json.Marshal(map[string]any{"price": internal.NewMoney(35279, "")})
+
+		// assert
+		require.NoError(t, err)
+		require.JSONEq(t, `{"price":352.79}`, string(b))
+	})
+
+	t.Run("success - decoded from a number or a string", func(t *testing.T) {
+		// arrange
+		var body struct {
+			A internal.Money `json:"a"`
+			B internal.Money `json:"b"`
+		}
+
+		// act
+		err := json.Unmarshal([]byte(`{"a":352.79,"b":"0.10"}`), &body)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, int64(35279), body.A.Amount)
+		require.Equal(t, int64(10), body.B.Amount)
+	})
+
+	t.Run("error - too many decimal places", func(t *testing.T) {
+		// arrange
+		var m internal.Money
+
+		// act
+		err := json.Unmarshal([]byte(`1.005`), &m)
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrMoneyInvalid)
+	})
+}
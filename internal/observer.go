@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"context"
+)
+
+// Observer observes the calls to the repositories and the stores, e.g. to measure their latency.
+type Observer interface {
+	// ObserveRepository starts observing the call to a method of a repository, e.g. FindById of product
+	// - done ends the call with its error
+	ObserveRepository(ctx context.Context, repository, method string) (c context.Context, done func(err error))
+	// ObserveStore starts observing the call to a method of a store, e.g. ReadAll of product
+	// - done ends the call with its error
+	ObserveStore(store, method string) (done func(err error))
+}
+
+// Observers is an observer passing the calls to every one of its observers
+type Observers []Observer
+
+// ObserveRepository starts observing the call to a method of a repository with every observer
+func (os Observers) ObserveRepository(ctx context.Context, repository, method string) (c context.Context, done func(err error)) {
+	dones := make([]func(err error), len(os))
+	for i, o := range os {
+		ctx, dones[i] = o.ObserveRepository(ctx, repository, method)
+	}
+	c = ctx
+	done = func(err error) {
+		for i := len(dones) - 1; i >= 0; i-- {
+			dones[i](err)
+		}
+	}
+	return
+}
+
+// ObserveStore starts observing the call to a method of a store with every observer
+func (os Observers) ObserveStore(store, method string) (done func(err error)) {
+	dones := make([]func(err error), len(os))
+	for i, o := range os {
+		dones[i] = o.ObserveStore(store, method)
+	}
+	done = func(err error) {
+		for i := len(dones) - 1; i >= 0; i-- {
+			dones[i](err)
+		}
+	}
+	return
+}
@@ -0,0 +1,67 @@
+package internal_test
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// observerKey is the context key set by an observerRecorder.
+type observerKey string
+
+// observerRecorder is an observer recording the calls it observes.
+type observerRecorder struct {
+	name  string
+	calls *[]string
+}
+
+func (o observerRecorder) ObserveRepository(ctx context.Context, repository, method string) (c context.Context, done func(err error)) {
+	*o.calls = append(*o.calls, o.name+" start "+repository+"."+method)
+	c = context.WithValue(ctx, observerKey(o.name), true)
+	done = func(err error) {
+		*o.calls = append(*o.calls, o.name+" done "+err.Error())
+	}
+	return
+}
+
+func (o observerRecorder) ObserveStore(store, method string) (done func(err error)) {
+	*o.calls = append(*o.calls, o.name+" start "+store+"."+method)
+	done = func(err error) {
+		*o.calls = append(*o.calls, o.name+" done "+err.Error())
+	}
+	return
+}
+
+// Tests for Observers
+func TestObservers(t *testing.T) {
+	t.Run("case 1: a repository call is observed by every observer, ended in reverse", func(t *testing.T) {
+		// arrange
+		var calls []string
+		os := internal.Observers{observerRecorder{name: "a", calls: &calls}, observerRecorder{name: "b", calls: &calls}}
+
+		// act
+		ctx, done := os.ObserveRepository(context.Background(), "product", "FindById")
+		done(errors.New("failed"))
+
+		// assert
+		require.Equal(t, []string{"a start product.FindById", "b start product.FindById", "b done failed", "a done failed"}, calls)
+		require.Equal(t, true, ctx.Value(observerKey("a")))
+		require.Equal(t, true, ctx.Value(observerKey("b")))
+	})
+
+	t.Run("case 2: a store call is observed by every observer, ended in reverse", func(t *testing.T) {
+		// arrange
+		var calls []string
+		os := internal.Observers{observerRecorder{name: "a", calls: &calls}, observerRecorder{name: "b", calls: &calls}}
+
+		// act
+		done := os.ObserveStore("product", "ReadAll")
+		done(errors.New("failed"))
+
+		// assert
+		require.Equal(t, []string{"a start product.ReadAll", "b start product.ReadAll", "b done failed", "a done failed"}, calls)
+	})
+}
@@ -0,0 +1,17 @@
+package internal
+
+import "time"
+
+// OutboxMessage is an event waiting in the outbox to be published
+// - it is appended in the same unit of work as the mutation of the event,
+// so an event is never lost once its mutation is committed
+type OutboxMessage struct {
+	// Id is the unique identifier of the message, also used as the id of its event
+	Id int
+	// Event is the event to publish
+	Event Event
+	// CreatedAt is when the message was appended
+	CreatedAt time.Time
+	// PublishedAt is when the event was published, zero while pending
+	PublishedAt time.Time
+}
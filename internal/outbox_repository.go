@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrRepositoryOutboxMessageNotFound is returned when an outbox message is not found.
+	ErrRepositoryOutboxMessageNotFound = errors.New("repository: outbox message not found")
+)
+
+// RepositoryOutbox is an interface for an outbox of events.
+type RepositoryOutbox interface {
+	// Append appends a message to the outbox, setting its id and the one of its event.
+	Append(ctx context.Context, m *OutboxMessage) (err error)
+	// FindPending returns up to limit messages not published yet, oldest first.
+	FindPending(ctx context.Context, limit int) (m []OutboxMessage, err error)
+	// MarkPublished marks a message as published.
+	MarkPublished(ctx context.Context, id int, at time.Time) (err error)
+	// Purge removes the messages published before a time, returning how many were removed.
+	// - the last message is kept, so its id is never given to another one
+	Purge(ctx context.Context, before time.Time) (n int, err error)
+}
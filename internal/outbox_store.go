@@ -0,0 +1,9 @@
+package internal
+
+// StoreOutbox is an interface for an outbox store.
+type StoreOutbox interface {
+	// ReadAll reads all outbox messages from the store.
+	ReadAll() (m map[int]OutboxMessage, err error)
+	// WriteAll writes all outbox messages to the store.
+	WriteAll(m map[int]OutboxMessage) (err error)
+}
@@ -0,0 +1,28 @@
+package internal
+
+import "time"
+
+const (
+	// PriceStatusScheduled is the status of a price change not yet in effect.
+	PriceStatusScheduled = "scheduled"
+	// PriceStatusApplied is the status of a price change applied to its product.
+	PriceStatusApplied = "applied"
+	// PriceStatusCanceled is the status of a scheduled price change whose product no longer exists.
+	PriceStatusCanceled = "canceled"
+)
+
+// PriceChange is a change of the price of a product
+type PriceChange struct {
+	// Id is the unique identifier of the change
+	Id int
+	// ProductId is the id of the product whose price changes
+	ProductId int
+	// Price is the new price of the product
+	Price Money
+	// EffectiveAt is when the price takes effect
+	EffectiveAt time.Time
+	// Status is the status of the change: scheduled, applied or canceled
+	Status string
+	// Tenant is the tenant of the product whose price changes
+	Tenant string
+}
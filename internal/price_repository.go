@@ -0,0 +1,24 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrRepositoryPriceNotFound is returned when a price change is not found.
+	ErrRepositoryPriceNotFound = errors.New("repository: price change not found")
+)
+
+// RepositoryPrice is an interface that contains the methods for a price change repository
+type RepositoryPrice interface {
+	// Save saves a price change
+	Save(ctx context.Context, c *PriceChange) (err error)
+	// UpdateStatus updates the status of a price change
+	UpdateStatus(ctx context.Context, id int, status string) (err error)
+	// FindByProduct returns the price changes of a product ordered by effective date
+	FindByProduct(ctx context.Context, productId int) (c []PriceChange, err error)
+	// FindDue returns the scheduled price changes effective at or before a time ordered by effective date
+	FindDue(ctx context.Context, at time.Time) (c []PriceChange, err error)
+}
@@ -0,0 +1,9 @@
+package internal
+
+// StorePrice is an interface for a price change store.
+type StorePrice interface {
+	// ReadAll reads all price changes from the store.
+	ReadAll() (c map[int]PriceChange, err error)
+	// WriteAll writes all price changes to the store.
+	WriteAll(c map[int]PriceChange) (err error)
+}
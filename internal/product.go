@@ -15,16 +15,25 @@ type ProductAttributes struct {
 	// Expiration
 	Expiration time.Time
 	// Price
-	Price float64
+	Price Money
 }
 
 // Product is a struct that contains the attributes of a product
 type Product struct {
-	// Id is the unique identifier of the product
+	// Id is the unique identifier of the product, across tenants
 	Id int
+	// Tenant is the tenant owning the product
+	Tenant string
 	// ProductAttributes is the attributes of the product
 	ProductAttributes
 
 	//WarehouseId is the id of the warehouse where the product is stored
 	WarehouseId int
+
+	// Version is the version of the product, incremented on every update
+	// - updating with version 0 skips the version check
+	Version int
+
+	// DeletedAt is the time the product was soft deleted, zero if it is not deleted
+	DeletedAt time.Time
 }
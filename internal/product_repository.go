@@ -1,24 +1,85 @@
 package internal
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
 	// ErrRepositoryProductNotFound is returned when a product is not found.
 	ErrRepositoryProductNotFound   = errors.New("repository: product not found")
 	ErrRepositoryProductDuplicated = errors.New("repository: product duplicated")
+	// ErrRepositoryProductVersionConflict is returned when a product was modified since the version being updated.
+	ErrRepositoryProductVersionConflict = errors.New("repository: product version conflict")
+	// ErrRepositoryProductOperationInvalid is returned when a batch operation kind is unknown.
+	ErrRepositoryProductOperationInvalid = errors.New("repository: product operation invalid")
+)
+
+const (
+	// ProductOperationCreate creates the product of the operation.
+	ProductOperationCreate = "create"
+	// ProductOperationUpdate updates the product of the operation.
+	ProductOperationUpdate = "update"
+	// ProductOperationDelete deletes the product with the id of the operation.
+	ProductOperationDelete = "delete"
 )
 
+// ProductOperation is an operation of a batch of products.
+type ProductOperation struct {
+	// Kind is the kind of the operation: create, update or delete
+	Kind string
+	// Product is the product of the operation (only the id and the version are used to delete)
+	Product Product
+}
+
+// ProductOperationError is returned when an operation of a batch fails.
+type ProductOperationError struct {
+	// Index is the position of the failing operation in the batch
+	Index int
+	// Err is the cause of the failure
+	Err error
+}
+
+// Error returns the message of the error.
+func (e *ProductOperationError) Error() string {
+	return fmt.Sprintf("operation %d: %s", e.Index, e.Err.Error())
+}
+
+// Unwrap returns the cause of the error.
+func (e *ProductOperationError) Unwrap() error {
+	return e.Err
+}
+
 // RepositoryProduct is an interface that contains the methods for a product repository
 type RepositoryProduct interface {
 	// FindById returns a product by its id
-	FindById(id int) (p Product, err error)
+	FindById(ctx context.Context, id int) (p Product, err error)
 	// Save saves a product
-	Save(p *Product) (err error)
+	Save(ctx context.Context, p *Product) (err error)
 	// UpdateOrSave updates or saves a product
-	UpdateOrSave(p *Product) (err error)
+	UpdateOrSave(ctx context.Context, p *Product) (err error)
 	// Update updates a product
-	Update(p *Product) (err error)
-	// Delete deletes a product
-	Delete(id int) (err error)
-	GetAll() (p []Product, err error)
+	Update(ctx context.Context, p *Product) (err error)
+	// Delete soft deletes a product, hiding it from every finder but GetAllIncludingDeleted
+	// - a version other than 0 must be the current one, otherwise ErrRepositoryProductVersionConflict is returned
+	Delete(ctx context.Context, id int, version int) (err error)
+	// Restore restores a soft deleted product
+	Restore(ctx context.Context, id int) (err error)
+	// Purge permanently removes the products soft deleted before a time, returning how many were removed
+	Purge(ctx context.Context, before time.Time) (n int, err error)
+	GetAll(ctx context.Context) (p []Product, err error)
+	// GetAllIncludingDeleted returns all products, soft deleted ones included
+	GetAllIncludingDeleted(ctx context.Context) (p []Product, err error)
+	// Batch applies all the operations or none of them, returning the product of each operation
+	Batch(ctx context.Context, ops []ProductOperation) (p []Product, err error)
+	// FindExpiring returns the products expiring between two dates, both included, sorted by expiration
+	// - of a warehouse, or of every warehouse when warehouseId is 0
+	FindExpiring(ctx context.Context, from, to time.Time, warehouseId int) (p []Product, err error)
+	// FindExpired returns the products expired before a date sorted by expiration
+	// - of a warehouse, or of every warehouse when warehouseId is 0
+	FindExpired(ctx context.Context, before time.Time, warehouseId int) (p []Product, err error)
+	// UnpublishExpired unpublishes the published products expired before a date, returning them
+	UnpublishExpired(ctx context.Context, before time.Time) (p []Product, err error)
 }
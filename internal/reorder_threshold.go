@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// ReorderScopeProduct is the scope of a threshold of a single product.
+	ReorderScopeProduct = "product"
+	// ReorderScopeWarehouse is the scope of a threshold of every product of a warehouse.
+	ReorderScopeWarehouse = "warehouse"
+)
+
+var (
+	// ErrReorderThresholdInvalid is returned when a threshold has an unknown scope or a negative quantity.
+	ErrReorderThresholdInvalid = errors.New("reorder threshold: invalid threshold")
+)
+
+// ReorderScope is what a reorder threshold applies to
+type ReorderScope struct {
+	// Kind is the kind of the scope: product or warehouse
+	Kind string
+	// Id is the id of the product or the warehouse
+	Id int
+}
+
+// ReorderThreshold is the quantity below which the products of a scope must be reordered
+type ReorderThreshold struct {
+	// ReorderScope is what the threshold applies to
+	ReorderScope
+	// Quantity is the quantity below which a product is low on stock
+	Quantity int
+}
+
+// Validate checks the scope and the quantity of a threshold.
+func (t ReorderThreshold) Validate() (err error) {
+	switch {
+	case t.Kind != ReorderScopeProduct && t.Kind != ReorderScopeWarehouse:
+		err = ErrReorderThresholdInvalid
+	case t.Id <= 0 || t.Quantity < 0:
+		err = ErrReorderThresholdInvalid
+	}
+	return
+}
+
+// NewReorderThresholds indexes thresholds to resolve the one of each product.
+func NewReorderThresholds(ts []ReorderThreshold) (r ReorderThresholds) {
+	r = make(ReorderThresholds, len(ts))
+	for _, t := range ts {
+		r[t.ReorderScope] = t.Quantity
+	}
+	return
+}
+
+// ReorderThresholds are thresholds indexed by scope.
+type ReorderThresholds map[ReorderScope]int
+
+// Of returns the threshold of a product, ok is false if it has none.
+// - the threshold of the product itself wins over the one of its warehouse
+func (r ReorderThresholds) Of(p Product) (q int, ok bool) {
+	q, ok = r[ReorderScope{Kind: ReorderScopeProduct, Id: p.Id}]
+	if ok {
+		return
+	}
+	if p.WarehouseId != 0 {
+		q, ok = r[ReorderScope{Kind: ReorderScopeWarehouse, Id: p.WarehouseId}]
+	}
+	return
+}
+
+// IsLow tells whether the quantity of a product is below its threshold, returning the threshold.
+func (r ReorderThresholds) IsLow(p Product) (q int, low bool) {
+	q, ok := r.Of(p)
+	low = ok && p.Quantity < q
+	return
+}
+
+// LowStockAlert is raised when the quantity of a product is below its threshold
+type LowStockAlert struct {
+	// Product is the product low on stock
+	Product Product
+	// Threshold is the threshold of the product
+	Threshold int
+	// Timestamp is when the alert was raised
+	Timestamp time.Time
+}
+
+// AlertSink is an interface for a destination of low stock alerts
+type AlertSink interface {
+	// Alert delivers a low stock alert
+	Alert(ctx context.Context, a LowStockAlert) (err error)
+}
@@ -0,0 +1,23 @@
+package internal
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrRepositoryReorderThresholdNotFound is returned when the threshold of a scope is not found.
+	ErrRepositoryReorderThresholdNotFound = errors.New("repository: reorder threshold not found")
+)
+
+// RepositoryReorderThreshold is an interface that contains the methods for a reorder threshold repository
+type RepositoryReorderThreshold interface {
+	// FindByScope returns the threshold of a scope
+	FindByScope(ctx context.Context, s ReorderScope) (t ReorderThreshold, err error)
+	// Save saves the threshold of a scope, replacing the previous one
+	Save(ctx context.Context, t *ReorderThreshold) (err error)
+	// Delete deletes the threshold of a scope
+	Delete(ctx context.Context, s ReorderScope) (err error)
+	// GetAll returns all thresholds sorted by scope kind and id
+	GetAll(ctx context.Context) (t []ReorderThreshold, err error)
+}
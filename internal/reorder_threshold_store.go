@@ -0,0 +1,9 @@
+package internal
+
+// StoreReorderThreshold is an interface for a reorder threshold store.
+type StoreReorderThreshold interface {
+	// ReadAll reads all thresholds from the store, by tenant, then by scope.
+	ReadAll() (t map[string]map[ReorderScope]ReorderThreshold, err error)
+	// WriteAll writes all thresholds to the store.
+	WriteAll(t map[string]map[ReorderScope]ReorderThreshold) (err error)
+}
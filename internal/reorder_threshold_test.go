@@ -0,0 +1,40 @@
+package internal_test
+
+import (
+	"app/internal"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for ReorderThresholds.IsLow
+func TestReorderThresholds_IsLow(t *testing.T) {
+	thresholds := internal.NewReorderThresholds([]internal.ReorderThreshold{
+		{ReorderScope: internal.ReorderScope{Kind: internal.ReorderScopeProduct, Id: 1}, Quantity: 5},
+		{ReorderScope: internal.ReorderScope{Kind: internal.ReorderScopeWarehouse, Id: 1}, Quantity: 20},
+	})
+
+	cases := []struct {
+		name      string
+		product   internal.Product
+		threshold int
+		low       bool
+	}{
+		{name: "product threshold wins over warehouse", product: internal.Product{Id: 1, ProductAttributes: internal.ProductAttributes{Quantity: 10}, WarehouseId: 1}, threshold: 5, low: false},
+		{name: "product below its own threshold", product: internal.Product{Id: 1, ProductAttributes: internal.ProductAttributes{Quantity: 4}, WarehouseId: 1}, threshold: 5, low: true},
+		{name: "warehouse threshold as fallback", product: internal.Product{Id: 2, ProductAttributes: internal.ProductAttributes{Quantity: 10}, WarehouseId: 1}, threshold: 20, low: true},
+		{name: "quantity equal to threshold is not low", product: internal.Product{Id: 2, ProductAttributes: internal.ProductAttributes{Quantity: 20}, WarehouseId: 1}, threshold: 20, low: false},
+		{name: "no threshold", product: internal.Product{Id: 3, ProductAttributes: internal.ProductAttributes{Quantity: 0}, WarehouseId: 2}, threshold: 0, low: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			threshold, low := thresholds.IsLow(c.product)
+
+			// assert
+			require.Equal(t, c.threshold, threshold)
+			require.Equal(t, c.low, low)
+		})
+	}
+}
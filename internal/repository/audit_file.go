@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"app/internal"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// NewRepositoryAuditFile creates a new audit log repository backed by an append-only JSON lines file.
+func NewRepositoryAuditFile(path string) (r *RepositoryAuditFile) {
+	r = &RepositoryAuditFile{
+		path: path,
+	}
+	return
+}
+
+// RepositoryAuditFile is an audit log repository backed by an append-only JSON lines file.
+// - each line of the file is an entry, new entries are appended at the end
+type RepositoryAuditFile struct {
+	// mu serializes the access to the file.
+	mu sync.Mutex
+	// path is the path to the file.
+	path string
+	// lastId is the id of the last entry.
+	lastId int
+	// loaded tells whether lastId was read from the file.
+	loaded bool
+}
+
+// AuditEntryJSON is a JSON representation of an audit entry.
+type AuditEntryJSON struct {
+	Id        int             `json:"id"`
+	Entity    string          `json:"entity"`
+	EntityId  int             `json:"entity_id"`
+	Operation string          `json:"operation"`
+	Actor     string          `json:"actor"`
+	Tenant    string          `json:"tenant,omitempty"`
+	Timestamp string          `json:"timestamp"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+}
+
+// Save appends an entry to the audit log.
+func (r *RepositoryAuditFile) Save(ctx context.Context, e *internal.AuditEntry) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// last id
+	if !r.loaded {
+		var es []internal.AuditEntry
+		es, err = r.readAll()
+		if err != nil {
+			return
+		}
+		for _, v := range es {
+			if v.Id > r.lastId {
+				r.lastId = v.Id
+			}
+		}
+		r.loaded = true
+	}
+
+	// serialize entry
+	line, err := json.Marshal(AuditEntryJSON{
+		Id:        r.lastId + 1,
+		Entity:    e.Entity,
+		EntityId:  e.EntityId,
+		Operation: e.Operation,
+		Actor:     e.Actor,
+		Tenant:    e.Tenant,
+		Timestamp: e.Timestamp.Format(time.RFC3339Nano),
+		Before:    e.Before,
+		After:     e.After,
+	})
+	if err != nil {
+		return
+	}
+
+	// append entry
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	if err != nil {
+		return
+	}
+
+	r.lastId++
+	e.Id = r.lastId
+	return
+}
+
+// FindByEntity returns the entries of an entity of the tenant of the context, oldest first.
+func (r *RepositoryAuditFile) FindByEntity(ctx context.Context, entity string, id int) (e []internal.AuditEntry, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	es, err := r.readAll()
+	if err != nil {
+		return
+	}
+
+	for _, v := range es {
+		if v.Entity == entity && v.EntityId == id && internal.InTenant(ctx, v.Tenant) {
+			e = append(e, v)
+		}
+	}
+	return
+}
+
+// readAll reads all the entries of the file.
+// - a missing file is read as an empty audit log
+func (r *RepositoryAuditFile) readAll() (e []internal.AuditEntry, err error) {
+	f, err := os.Open(r.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		return
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var v AuditEntryJSON
+		err = json.Unmarshal(sc.Bytes(), &v)
+		if err != nil {
+			return
+		}
+		var ts time.Time
+		ts, err = time.Parse(time.RFC3339Nano, v.Timestamp)
+		if err != nil {
+			return
+		}
+		// - entries predating tenants are of the default one
+		if v.Tenant == "" {
+			v.Tenant = internal.TenantDefault
+		}
+		e = append(e, internal.AuditEntry{
+			Id:        v.Id,
+			Entity:    v.Entity,
+			EntityId:  v.EntityId,
+			Operation: v.Operation,
+			Actor:     v.Actor,
+			Tenant:    v.Tenant,
+			Timestamp: ts,
+			Before:    v.Before,
+			After:     v.After,
+		})
+	}
+	err = sc.Err()
+	return
+}
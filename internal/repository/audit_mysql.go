@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"database/sql"
+	"encoding/json"
+)
+
+// NewRepositoryAuditMysql creates a new audit log repository backed by mysql.
+func NewRepositoryAuditMysql(db *sql.DB) *AuditMysql {
+	return &AuditMysql{
+		ex: traced(db),
+	}
+}
+
+// AuditMysql is an audit log repository backed by mysql.
+type AuditMysql struct {
+	// ex runs the queries, it is the db itself or the transaction the repository is bound to
+	ex executor
+}
+
+// Save appends an entry to the audit log.
+func (r *AuditMysql) Save(ctx context.Context, e *internal.AuditEntry) (err error) {
+	res, err := r.ex.ExecContext(ctx, "INSERT INTO `audit_log` (`entity`, `entity_id`, `operation`, `actor`, `tenant_id`, `created_at`, `snapshot_before`, `snapshot_after`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", e.Entity, e.EntityId, e.Operation, e.Actor, e.Tenant, e.Timestamp, nullJSON(e.Before), nullJSON(e.After))
+	if err != nil {
+		return
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+
+	e.Id = int(id)
+	return
+}
+
+// FindByEntity returns the entries of an entity of the tenant of the context, oldest first.
+func (r *AuditMysql) FindByEntity(ctx context.Context, entity string, id int) (e []internal.AuditEntry, err error) {
+	t := internal.TenantFromContext(ctx)
+	rows, err := r.ex.QueryContext(ctx, "SELECT `id`, `entity`, `entity_id`, `operation`, `actor`, `tenant_id`, `created_at`, `snapshot_before`, `snapshot_after` FROM `audit_log` WHERE `entity` = ? AND `entity_id` = ? AND (? = '*' OR `tenant_id` = ?) ORDER BY `id`", entity, id, t, t)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a internal.AuditEntry
+		var before, after []byte
+		err = rows.Scan(&a.Id, &a.Entity, &a.EntityId, &a.Operation, &a.Actor, &a.Tenant, &a.Timestamp, &before, &after)
+		if err != nil {
+			return
+		}
+		if before != nil {
+			a.Before = json.RawMessage(before)
+		}
+		if after != nil {
+			a.After = json.RawMessage(after)
+		}
+		e = append(e, a)
+	}
+	err = rows.Err()
+	return
+}
+
+// nullJSON returns nil for an empty snapshot, so it is stored as NULL.
+func nullJSON(raw json.RawMessage) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
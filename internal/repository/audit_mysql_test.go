@@ -0,0 +1,130 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditMysql_FindByEntity(t *testing.T) {
+
+	t.Run("success - mutations recorded", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `products` (`id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse`) VALUES (1, 'product 1', 1, 'code_value 1', true, '2021-01-01', 1, 0)")
+			require.NoError(t, err)
+		}(db)
+
+		ra := repository.NewRepositoryAuditMysql(db)
+		rp := repository.NewRepositoryProductAudited(repository.NewRepositoryProductMySql(db), ra)
+		ctx := internal.ContextWithActor(context.Background(), "alice")
+
+		p, err := rp.FindById(ctx, 1)
+		require.NoError(t, err)
+		p.Price = internal.NewMoney(200, internal.CurrencyDefault)
+		err = rp.Update(ctx, &p)
+		require.NoError(t, err)
+		err = rp.Delete(ctx, 1, 0)
+		require.NoError(t, err)
+
+		//act
+		es, err := ra.FindByEntity(context.Background(), internal.AuditEntityProduct, 1)
+
+		//assert
+		require.NoError(t, err)
+		require.Len(t, es, 2)
+		require.Equal(t, internal.AuditOperationUpdate, es[0].Operation)
+		require.Equal(t, "alice", es[0].Actor)
+		var before, after internal.Product
+		require.NoError(t, json.Unmarshal(es[0].Before, &before))
+		require.NoError(t, json.Unmarshal(es[0].After, &after))
+		require.Equal(t, int64(100), before.Price.Amount)
+		require.Equal(t, int64(200), after.Price.Amount)
+		require.Equal(t, internal.AuditOperationDelete, es[1].Operation)
+		require.Nil(t, es[1].After)
+	})
+
+	t.Run("success - no entries", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		ra := repository.NewRepositoryAuditMysql(db)
+
+		//act
+		es, err := ra.FindByEntity(context.Background(), internal.AuditEntityWarehouse, 1)
+
+		//assert
+		require.NoError(t, err)
+		require.Empty(t, es)
+	})
+
+}
+
+func TestUnitOfWorkAudited_Do(t *testing.T) {
+
+	t.Run("success - entries saved in the transaction", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `products` (`id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse`) VALUES (1, 'product 1', 1, 'code_value 1', true, '2021-01-01', 1, 0)")
+			require.NoError(t, err)
+		}(db)
+
+		ra := repository.NewRepositoryAuditMysql(db)
+		uow := repository.NewUnitOfWorkAudited(repository.NewUnitOfWorkMysql(db), ra)
+
+		//act
+		err := uow.Do(context.Background(), func(r internal.RepositoriesTx) (err error) {
+			return r.Product.Delete(context.Background(), 1, 0)
+		})
+
+		//assert
+		require.NoError(t, err)
+		es, err := ra.FindByEntity(context.Background(), internal.AuditEntityProduct, 1)
+		require.NoError(t, err)
+		require.Len(t, es, 1)
+		require.Equal(t, internal.AuditOperationDelete, es[0].Operation)
+	})
+
+	t.Run("fail - entries rolled back along with their mutations", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `products` (`id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse`) VALUES (1, 'product 1', 1, 'code_value 1', true, '2021-01-01', 1, 0)")
+			require.NoError(t, err)
+		}(db)
+
+		ra := repository.NewRepositoryAuditMysql(db)
+		uow := repository.NewUnitOfWorkAudited(repository.NewUnitOfWorkMysql(db), ra)
+		expectedErr := errors.New("fail")
+
+		//act
+		err := uow.Do(context.Background(), func(r internal.RepositoriesTx) (err error) {
+			err = r.Product.Delete(context.Background(), 1, 0)
+			if err != nil {
+				return
+			}
+			return expectedErr
+		})
+
+		//assert
+		require.ErrorIs(t, err, expectedErr)
+		es, err := ra.FindByEntity(context.Background(), internal.AuditEntityProduct, 1)
+		require.NoError(t, err)
+		require.Empty(t, es)
+	})
+
+}
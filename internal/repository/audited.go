@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// audit saves an entry of a mutation to the audit log.
+// - the actor and the tenant are read from the context, before and after are serialized to JSON
+// - a mutation across tenants is of the tenant of the entity snapshot, if any
+func audit(ctx context.Context, ra internal.RepositoryAudit, entity string, id int, op string, before, after any) (err error) {
+	e := internal.AuditEntry{
+		Entity:    entity,
+		EntityId:  id,
+		Operation: op,
+		Actor:     internal.ActorFromContext(ctx),
+		Tenant:    internal.TenantFromContext(ctx),
+		Timestamp: time.Now(),
+	}
+	e.Before, err = snapshot(before)
+	if err != nil {
+		return
+	}
+	e.After, err = snapshot(after)
+	if err != nil {
+		return
+	}
+
+	if e.Tenant == internal.TenantAll {
+		for _, raw := range []json.RawMessage{e.After, e.Before} {
+			var s struct{ Tenant string }
+			if json.Unmarshal(raw, &s) == nil && s.Tenant != "" {
+				e.Tenant = s.Tenant
+				break
+			}
+		}
+	}
+
+	err = ra.Save(ctx, &e)
+	return
+}
+
+// snapshot serializes v to JSON, nil values (typed nil pointers included) are left empty.
+func snapshot(v any) (raw json.RawMessage, err error) {
+	if v == nil {
+		return
+	}
+	raw, err = json.Marshal(v)
+	if err != nil {
+		return
+	}
+	if string(raw) == "null" {
+		raw = nil
+	}
+	return
+}
+
+// NewUnitOfWorkAudited creates a new unit of work that records the mutations of its repositories to an audit log.
+func NewUnitOfWorkAudited(uow internal.UnitOfWork, ra internal.RepositoryAudit) *UnitOfWorkAudited {
+	return &UnitOfWorkAudited{
+		uow: uow,
+		ra:  ra,
+	}
+}
+
+// UnitOfWorkAudited is a unit of work that records the mutations of its repositories to an audit log.
+// - entries are saved to the audit log of the unit of work, so they are committed or discarded along with their mutations
+// - without one, entries are buffered and saved to ra only once the unit of work succeeds
+type UnitOfWorkAudited struct {
+	// uow is the decorated unit of work.
+	uow internal.UnitOfWork
+	// ra is the audit log.
+	ra internal.RepositoryAudit
+}
+
+// Do runs fn with audited repositories.
+func (u *UnitOfWorkAudited) Do(ctx context.Context, fn func(r internal.RepositoriesTx) (err error)) (err error) {
+	buf := &auditBuffer{}
+	err = u.uow.Do(ctx, func(r internal.RepositoriesTx) (err error) {
+		buf.entries = nil
+		var ra internal.RepositoryAudit = buf
+		if r.Audit != nil {
+			ra = r.Audit
+		}
+		err = fn(internal.RepositoriesTx{
+			Product:   NewRepositoryProductAudited(r.Product, ra),
+			Warehouse: NewRepositoryWarehouseAudited(r.Warehouse, ra),
+			Price:     r.Price,
+			Outbox:    r.Outbox,
+			Audit:     r.Audit,
+		})
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	// save entries
+	for i := range buf.entries {
+		err = u.ra.Save(ctx, &buf.entries[i])
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// auditBuffer is an in-memory audit log holding the entries of a unit of work.
+type auditBuffer struct {
+	entries []internal.AuditEntry
+}
+
+// Save appends an entry to the buffer.
+func (b *auditBuffer) Save(ctx context.Context, e *internal.AuditEntry) (err error) {
+	b.entries = append(b.entries, *e)
+	return
+}
+
+// FindByEntity returns the buffered entries of an entity.
+func (b *auditBuffer) FindByEntity(ctx context.Context, entity string, id int) (e []internal.AuditEntry, err error) {
+	for _, v := range b.entries {
+		if v.Entity == entity && v.EntityId == id {
+			e = append(e, v)
+		}
+	}
+	return
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"database/sql"
+	"math/big"
+)
+
+// NewRepositoryExchangeRateMysql creates a new repository for exchange rates backed by mysql.
+func NewRepositoryExchangeRateMysql(db *sql.DB) *ExchangeRateMysql {
+	return &ExchangeRateMysql{
+		ex: traced(db),
+	}
+}
+
+// ExchangeRateMysql is a repository for exchange rates backed by mysql.
+// - each tenant has rates of its own, those across tenants are the ones of the default tenant
+type ExchangeRateMysql struct {
+	// ex runs the queries, it is the db itself or the transaction the repository is bound to
+	ex executor
+}
+
+// FindByCurrency returns the rate of a currency.
+func (r *ExchangeRateMysql) FindByCurrency(ctx context.Context, currency string) (e internal.ExchangeRate, err error) {
+	currency = internal.NormalizeCurrency(currency)
+	if currency == internal.CurrencyBase {
+		e = internal.ExchangeRate{Currency: currency, Rate: big.NewRat(1, 1)}
+		return
+	}
+
+	var rate string
+	err = r.ex.QueryRowContext(ctx, "SELECT `currency`, `rate` FROM `exchange_rates` WHERE `tenant_id` = ? AND `currency` = ?", tenantOf(ctx, ""), currency).Scan(&e.Currency, &rate)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = internal.ErrRepositoryExchangeRateNotFound
+		}
+		return
+	}
+
+	e, err = internal.ParseExchangeRate(e.Currency, rate)
+	return
+}
+
+// Save saves the rate of a currency, replacing the previous one.
+func (r *ExchangeRateMysql) Save(ctx context.Context, e *internal.ExchangeRate) (err error) {
+	(*e).Currency = internal.NormalizeCurrency(e.Currency)
+	if e.Currency == internal.CurrencyBase || e.Rate == nil || e.Rate.Sign() <= 0 {
+		err = internal.ErrExchangeRateInvalid
+		return
+	}
+
+	_, err = r.ex.ExecContext(ctx, "INSERT INTO `exchange_rates` (`tenant_id`, `currency`, `rate`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `rate` = VALUES(`rate`)", tenantOf(ctx, ""), e.Currency, e.String())
+	return
+}
+
+// Delete deletes the rate of a currency.
+func (r *ExchangeRateMysql) Delete(ctx context.Context, currency string) (err error) {
+	res, err := r.ex.ExecContext(ctx, "DELETE FROM `exchange_rates` WHERE `tenant_id` = ? AND `currency` = ?", tenantOf(ctx, ""), internal.NormalizeCurrency(currency))
+	if err != nil {
+		return
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if n == 0 {
+		err = internal.ErrRepositoryExchangeRateNotFound
+		return
+	}
+
+	return
+}
+
+// GetAll returns the rates of all currencies sorted by currency.
+func (r *ExchangeRateMysql) GetAll(ctx context.Context) (e []internal.ExchangeRate, err error) {
+	rows, err := r.ex.QueryContext(ctx, "SELECT `currency`, `rate` FROM `exchange_rates` WHERE `tenant_id` = ? ORDER BY `currency`", tenantOf(ctx, ""))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var currency, rate string
+		err = rows.Scan(&currency, &rate)
+		if err != nil {
+			return
+		}
+		var v internal.ExchangeRate
+		v, err = internal.ParseExchangeRate(currency, rate)
+		if err != nil {
+			return
+		}
+		e = append(e, v)
+	}
+	err = rows.Err()
+	return
+}
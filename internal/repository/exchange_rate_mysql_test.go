@@ -0,0 +1,54 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExchangeRateMysql_Save(t *testing.T) {
+
+	t.Run("success - replaces the previous rate", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `exchange_rates` (`currency`, `rate`) VALUES ('EUR', 0.9)")
+			require.NoError(t, err)
+		}(db)
+
+		rp := repository.NewRepositoryExchangeRateMysql(db)
+		e, err := internal.ParseExchangeRate("eur", "0.9215")
+		require.NoError(t, err)
+
+		//act
+		err = rp.Save(context.Background(), &e)
+
+		//assert
+		require.NoError(t, err)
+		found, err := rp.FindByCurrency(context.Background(), "EUR")
+		require.NoError(t, err)
+		require.Equal(t, "0.9215", found.String())
+	})
+
+	t.Run("fail - base currency", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		rp := repository.NewRepositoryExchangeRateMysql(db)
+		e, err := internal.ParseExchangeRate(internal.CurrencyBase, "2")
+		require.NoError(t, err)
+
+		//act
+		err = rp.Save(context.Background(), &e)
+
+		//assert
+		require.ErrorIs(t, err, internal.ErrExchangeRateInvalid)
+	})
+
+}
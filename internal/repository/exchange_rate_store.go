@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// NewRepositoryExchangeRateStore creates a new repository for exchange rates.
+func NewRepositoryExchangeRateStore(st internal.StoreExchangeRate) (r *RepositoryExchangeRateStore) {
+	r = &RepositoryExchangeRateStore{
+		st: st,
+	}
+	return
+}
+
+// RepositoryExchangeRateStore is a repository for exchange rates.
+// - each tenant has rates of its own, those across tenants are the ones of the default tenant
+type RepositoryExchangeRateStore struct {
+	// mu serializes the reads and writes of the store.
+	mu sync.Mutex
+	// st is the underlying store.
+	st internal.StoreExchangeRate
+}
+
+// FindByCurrency returns the rate of a currency.
+func (r *RepositoryExchangeRateStore) FindByCurrency(ctx context.Context, currency string) (e internal.ExchangeRate, err error) {
+	currency = internal.NormalizeCurrency(currency)
+	if currency == internal.CurrencyBase {
+		e = internal.ExchangeRate{Currency: currency, Rate: big.NewRat(1, 1)}
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// read all rates
+	es, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// find rate
+	e, ok := es[tenantOf(ctx, "")][currency]
+	if !ok {
+		err = internal.ErrRepositoryExchangeRateNotFound
+		return
+	}
+
+	return
+}
+
+// Save saves the rate of a currency, replacing the previous one.
+func (r *RepositoryExchangeRateStore) Save(ctx context.Context, e *internal.ExchangeRate) (err error) {
+	(*e).Currency = internal.NormalizeCurrency(e.Currency)
+	if e.Currency == internal.CurrencyBase || e.Rate == nil || e.Rate.Sign() <= 0 {
+		err = internal.ErrExchangeRateInvalid
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// read all rates
+	es, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// save rate
+	t := tenantOf(ctx, "")
+	if es[t] == nil {
+		es[t] = make(map[string]internal.ExchangeRate)
+	}
+	es[t][e.Currency] = *e
+
+	// write all rates
+	err = r.st.WriteAll(es)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Delete deletes the rate of a currency.
+func (r *RepositoryExchangeRateStore) Delete(ctx context.Context, currency string) (err error) {
+	currency = internal.NormalizeCurrency(currency)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// read all rates
+	es, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// delete rate
+	t := tenantOf(ctx, "")
+	if _, ok := es[t][currency]; !ok {
+		err = internal.ErrRepositoryExchangeRateNotFound
+		return
+	}
+	delete(es[t], currency)
+
+	// write all rates
+	err = r.st.WriteAll(es)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// GetAll returns the rates of all currencies sorted by currency.
+func (r *RepositoryExchangeRateStore) GetAll(ctx context.Context) (e []internal.ExchangeRate, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// read all rates
+	es, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	for _, v := range es[tenantOf(ctx, "")] {
+		e = append(e, v)
+	}
+	sort.Slice(e, func(i, j int) bool {
+		return e[i].Currency < e[j].Currency
+	})
+	return
+}
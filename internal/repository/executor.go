@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// executor runs queries, it is implemented by both *sql.DB and *sql.Tx
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// tracer returns the tracer of the queries, from the global provider: it records nothing until the application sets one
+func tracer() trace.Tracer {
+	return otel.Tracer("app/internal/repository")
+}
+
+// traced returns an executor running each query of ex in a span of its own, with its statement
+func traced(ex executor) executor {
+	return &executorTraced{ex: ex}
+}
+
+// executorTraced is an executor running each query in a span of its own.
+// - the span of a query ends once it is run, the rows it returns are read outside of it
+type executorTraced struct {
+	// ex is the decorated executor.
+	ex executor
+}
+
+// ExecContext runs a query returning no rows.
+func (e *executorTraced) ExecContext(ctx context.Context, query string, args ...any) (r sql.Result, err error) {
+	ctx, span := startQuery(ctx, query)
+	r, err = e.ex.ExecContext(ctx, query, args...)
+	endQuery(span, err)
+	return
+}
+
+// QueryContext runs a query returning rows.
+func (e *executorTraced) QueryContext(ctx context.Context, query string, args ...any) (r *sql.Rows, err error) {
+	ctx, span := startQuery(ctx, query)
+	r, err = e.ex.QueryContext(ctx, query, args...)
+	endQuery(span, err)
+	return
+}
+
+// QueryRowContext runs a query returning at most one row.
+func (e *executorTraced) QueryRowContext(ctx context.Context, query string, args ...any) (r *sql.Row) {
+	ctx, span := startQuery(ctx, query)
+	r = e.ex.QueryRowContext(ctx, query, args...)
+	endQuery(span, r.Err())
+	return
+}
+
+// startQuery starts the span of a query, named after its operation, e.g. SELECT
+func startQuery(ctx context.Context, query string) (context.Context, trace.Span) {
+	name := "query"
+	if fields := strings.Fields(query); len(fields) > 0 {
+		name = strings.ToUpper(fields[0])
+	}
+	return tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemMySQL, semconv.DBQueryText(query)),
+	)
+}
+
+// endQuery ends the span of a query with its error
+// - no rows is not an error, it is how the finders tell a row is not found
+func endQuery(span trace.Span, err error) {
+	if err != nil && err != sql.ErrNoRows {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
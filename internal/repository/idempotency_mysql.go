@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// NewRepositoryIdempotencyMysql creates a new repository for idempotency records backed by mysql.
+func NewRepositoryIdempotencyMysql(db *sql.DB) *IdempotencyMysql {
+	return &IdempotencyMysql{
+		ex: traced(db),
+	}
+}
+
+// IdempotencyMysql is a repository for idempotency records backed by mysql.
+// - a key is reserved by inserting its row, so of concurrent requests only one gets it
+type IdempotencyMysql struct {
+	// ex runs the queries, it is the db itself or the transaction the repository is bound to
+	ex executor
+}
+
+// Reserve saves the record of a request in progress.
+func (r *IdempotencyMysql) Reserve(ctx context.Context, rec *internal.IdempotencyRecord) (err error) {
+	// an expired record is dropped first, its key is free again
+	_, err = r.ex.ExecContext(ctx, "DELETE FROM `idempotency_keys` WHERE `key` = ? AND `tenant_id` = ? AND `expires_at` <= ?", rec.Key, tenantOf(ctx, ""), time.Now())
+	if err != nil {
+		return
+	}
+
+	header, err := json.Marshal(rec.Header)
+	if err != nil {
+		return
+	}
+
+	_, err = r.ex.ExecContext(ctx, "INSERT INTO `idempotency_keys` (`key`, `tenant_id`, `body_hash`, `status_code`, `header`, `body`, `created_at`, `expires_at`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", rec.Key, tenantOf(ctx, ""), rec.BodyHash, rec.StatusCode, header, rec.Body, rec.CreatedAt, rec.ExpiresAt)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			err = internal.ErrRepositoryIdempotencyKeyExists
+		}
+		return
+	}
+
+	return
+}
+
+// FindByKey returns the record of a key, if not expired.
+func (r *IdempotencyMysql) FindByKey(ctx context.Context, key string) (rec internal.IdempotencyRecord, err error) {
+	var header []byte
+	err = r.ex.QueryRowContext(ctx, "SELECT `key`, `body_hash`, `status_code`, `header`, `body`, `created_at`, `expires_at` FROM `idempotency_keys` WHERE `key` = ? AND `tenant_id` = ? AND `expires_at` > ?", key, tenantOf(ctx, ""), time.Now()).Scan(&rec.Key, &rec.BodyHash, &rec.StatusCode, &header, &rec.Body, &rec.CreatedAt, &rec.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = internal.ErrRepositoryIdempotencyRecordNotFound
+		}
+		return
+	}
+
+	err = json.Unmarshal(header, &rec.Header)
+	if err != nil {
+		rec = internal.IdempotencyRecord{}
+		return
+	}
+
+	return
+}
+
+// Complete records the response of the request of a record.
+func (r *IdempotencyMysql) Complete(ctx context.Context, rec *internal.IdempotencyRecord) (err error) {
+	header, err := json.Marshal(rec.Header)
+	if err != nil {
+		return
+	}
+
+	res, err := r.ex.ExecContext(ctx, "UPDATE `idempotency_keys` SET `status_code` = ?, `header` = ?, `body` = ? WHERE `key` = ? AND `tenant_id` = ?", rec.StatusCode, header, rec.Body, rec.Key, tenantOf(ctx, ""))
+	if err != nil {
+		return
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if rowsAffected == 0 {
+		err = internal.ErrRepositoryIdempotencyRecordNotFound
+		return
+	}
+
+	return
+}
+
+// Delete deletes the record of a key.
+func (r *IdempotencyMysql) Delete(ctx context.Context, key string) (err error) {
+	res, err := r.ex.ExecContext(ctx, "DELETE FROM `idempotency_keys` WHERE `key` = ? AND `tenant_id` = ?", key, tenantOf(ctx, ""))
+	if err != nil {
+		return
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if rowsAffected == 0 {
+		err = internal.ErrRepositoryIdempotencyRecordNotFound
+		return
+	}
+
+	return
+}
+
+// Purge removes the records expired before a time.
+func (r *IdempotencyMysql) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	t := internal.TenantFromContext(ctx)
+	res, err := r.ex.ExecContext(ctx, "DELETE FROM `idempotency_keys` WHERE (? = '*' OR `tenant_id` = ?) AND `expires_at` < ?", t, t, before)
+	if err != nil {
+		return
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	n = int(rowsAffected)
+	return
+}
@@ -0,0 +1,71 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyMysql_Reserve(t *testing.T) {
+
+	t.Run("success - key reserved then completed", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		rp := repository.NewRepositoryIdempotencyMysql(db)
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		rec := internal.IdempotencyRecord{Key: internal.IdempotencyKey("default", "anonymous", "POST /products", "k1"), BodyHash: internal.IdempotencyBodyHash([]byte("{}")), CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+		//act
+		err := rp.Reserve(context.Background(), &rec)
+		require.NoError(t, err)
+		rec.StatusCode = 201
+		rec.Header = map[string]string{"Content-Type": "application/json"}
+		rec.Body = []byte(`{"message":"success"}`)
+		err = rp.Complete(context.Background(), &rec)
+
+		//assert
+		require.NoError(t, err)
+		found, err := rp.FindByKey(context.Background(), rec.Key)
+		require.NoError(t, err)
+		require.Equal(t, rec, found)
+	})
+
+	t.Run("fail - key reserved twice", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		rp := repository.NewRepositoryIdempotencyMysql(db)
+		now := time.Now()
+		rec := internal.IdempotencyRecord{Key: "k1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+		require.NoError(t, rp.Reserve(context.Background(), &rec))
+
+		//act
+		err := rp.Reserve(context.Background(), &rec)
+
+		//assert
+		require.ErrorIs(t, err, internal.ErrRepositoryIdempotencyKeyExists)
+	})
+
+	t.Run("success - expired key reserved again", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		rp := repository.NewRepositoryIdempotencyMysql(db)
+		now := time.Now()
+		expired := internal.IdempotencyRecord{Key: "k1", CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)}
+		require.NoError(t, rp.Reserve(context.Background(), &expired))
+
+		//act
+		rec := internal.IdempotencyRecord{Key: "k1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+		err := rp.Reserve(context.Background(), &rec)
+
+		//assert
+		require.NoError(t, err)
+	})
+
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"sync"
+	"time"
+)
+
+// NewRepositoryIdempotencyStore creates a new repository for idempotency records.
+func NewRepositoryIdempotencyStore(st internal.StoreIdempotency) (r *RepositoryIdempotencyStore) {
+	r = &RepositoryIdempotencyStore{
+		st: st,
+	}
+	return
+}
+
+// RepositoryIdempotencyStore is a repository for idempotency records.
+// - it is safe for concurrent use, as a key must be reserved by a single request
+type RepositoryIdempotencyStore struct {
+	// mu serializes the reads and writes of the store.
+	mu sync.Mutex
+	// st is the underlying store.
+	st internal.StoreIdempotency
+}
+
+// Reserve saves the record of a request in progress.
+func (r *RepositoryIdempotencyStore) Reserve(ctx context.Context, rec *internal.IdempotencyRecord) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// read all records
+	rs, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// reserve key
+	if v, ok := rs[rec.Key]; ok && time.Now().Before(v.ExpiresAt) {
+		err = internal.ErrRepositoryIdempotencyKeyExists
+		return
+	}
+	rs[rec.Key] = *rec
+
+	// write all records
+	err = r.st.WriteAll(rs)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// FindByKey returns the record of a key, if not expired.
+func (r *RepositoryIdempotencyStore) FindByKey(ctx context.Context, key string) (rec internal.IdempotencyRecord, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// read all records
+	rs, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// find record
+	rec, ok := rs[key]
+	if !ok || !time.Now().Before(rec.ExpiresAt) {
+		rec = internal.IdempotencyRecord{}
+		err = internal.ErrRepositoryIdempotencyRecordNotFound
+		return
+	}
+
+	return
+}
+
+// Complete records the response of the request of a record.
+func (r *RepositoryIdempotencyStore) Complete(ctx context.Context, rec *internal.IdempotencyRecord) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// read all records
+	rs, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// complete record
+	if _, ok := rs[rec.Key]; !ok {
+		err = internal.ErrRepositoryIdempotencyRecordNotFound
+		return
+	}
+	rs[rec.Key] = *rec
+
+	// write all records
+	err = r.st.WriteAll(rs)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Delete deletes the record of a key.
+func (r *RepositoryIdempotencyStore) Delete(ctx context.Context, key string) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// read all records
+	rs, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// delete record
+	if _, ok := rs[key]; !ok {
+		err = internal.ErrRepositoryIdempotencyRecordNotFound
+		return
+	}
+	delete(rs, key)
+
+	// write all records
+	err = r.st.WriteAll(rs)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Purge removes the records expired before a time.
+func (r *RepositoryIdempotencyStore) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// read all records
+	rs, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// purge records
+	for k, v := range rs {
+		if v.ExpiresAt.Before(before) {
+			delete(rs, k)
+			n++
+		}
+	}
+	if n == 0 {
+		return
+	}
+
+	// write all records
+	err = r.st.WriteAll(rs)
+	if err != nil {
+		n = 0
+		return
+	}
+
+	return
+}
@@ -0,0 +1,43 @@
+package repository_test
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/DATA-DOG/go-txdb"
+	"github.com/go-sql-driver/mysql"
+)
+
+// TestMain registers once the txdb driver of the mysql tests, then runs the tests.
+func TestMain(m *testing.M) {
+	cfg := mysql.Config{
+		User:      "user1",
+		Passwd:    "secret_password",
+		Addr:      "localhost:3306",
+		Net:       "tcp",
+		DBName:    "test_db",
+		ParseTime: true,
+	}
+
+	txdb.Register("txdb", "mysql", cfg.FormatDSN())
+	os.Exit(m.Run())
+}
+
+// openTxdb opens the test database in a transaction, rolled back once closed.
+// - the test fails if the test database is not reachable, unless the mysql tests are opted out with MYSQL_TESTS_SKIP
+func openTxdb(t *testing.T) (db *sql.DB) {
+	t.Helper()
+	if os.Getenv("MYSQL_TESTS_SKIP") != "" {
+		t.Skip("mysql tests opted out with MYSQL_TESTS_SKIP")
+	}
+
+	db, err := sql.Open("txdb", "test_db")
+	if err == nil {
+		err = db.Ping()
+	}
+	if err != nil {
+		t.Fatalf("test database not reachable: %s", err)
+	}
+	return
+}
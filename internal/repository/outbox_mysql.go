@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// NewRepositoryOutboxMysql creates a new outbox repository backed by mysql.
+func NewRepositoryOutboxMysql(db *sql.DB) *OutboxMysql {
+	return &OutboxMysql{
+		ex: traced(db),
+	}
+}
+
+// OutboxMysql is an outbox repository backed by mysql.
+// - the events are stored as JSON, their ids are set from the ids of the rows
+type OutboxMysql struct {
+	// ex runs the queries, it is the db itself or the transaction the repository is bound to
+	ex executor
+}
+
+// Append appends a message to the outbox, setting its id and the one of its event.
+func (r *OutboxMysql) Append(ctx context.Context, m *internal.OutboxMessage) (err error) {
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now()
+	}
+
+	// the event id is known once the row is inserted, it is not stored
+	ev := m.Event
+	ev.Id = ""
+	event, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+
+	res, err := r.ex.ExecContext(ctx, "INSERT INTO `outbox` (`tenant_id`, `event`, `created_at`) VALUES (?, ?, ?)", tenantOf(ctx, m.Event.Tenant), event, m.CreatedAt)
+	if err != nil {
+		return
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+
+	m.Id = int(id)
+	m.Event.Id = strconv.Itoa(m.Id)
+	return
+}
+
+// FindPending returns up to limit messages not published yet, oldest first.
+func (r *OutboxMysql) FindPending(ctx context.Context, limit int) (m []internal.OutboxMessage, err error) {
+	t := internal.TenantFromContext(ctx)
+	rows, err := r.ex.QueryContext(ctx, "SELECT `id`, `event`, `created_at` FROM `outbox` WHERE `published_at` IS NULL AND (? = '*' OR `tenant_id` = ?) ORDER BY `id` LIMIT ?", t, t, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v internal.OutboxMessage
+		var event []byte
+		err = rows.Scan(&v.Id, &event, &v.CreatedAt)
+		if err != nil {
+			return
+		}
+		err = json.Unmarshal(event, &v.Event)
+		if err != nil {
+			return
+		}
+		v.Event.Id = strconv.Itoa(v.Id)
+		m = append(m, v)
+	}
+	err = rows.Err()
+	return
+}
+
+// MarkPublished marks a message as published.
+func (r *OutboxMysql) MarkPublished(ctx context.Context, id int, at time.Time) (err error) {
+	t := internal.TenantFromContext(ctx)
+	res, err := r.ex.ExecContext(ctx, "UPDATE `outbox` SET `published_at` = ? WHERE `id` = ? AND (? = '*' OR `tenant_id` = ?)", at, id, t, t)
+	if err != nil {
+		return
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if n == 0 {
+		err = internal.ErrRepositoryOutboxMessageNotFound
+		return
+	}
+
+	return
+}
+
+// Purge removes the messages published before a time, returning how many were removed.
+// - the last message is kept, so its id is never given to another one, even after a restart of the server
+func (r *OutboxMysql) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	// the derived table lets the table be read while deleting from it
+	t := internal.TenantFromContext(ctx)
+	res, err := r.ex.ExecContext(ctx, "DELETE FROM `outbox` WHERE `published_at` < ? AND (? = '*' OR `tenant_id` = ?) AND `id` < (SELECT `max_id` FROM (SELECT MAX(`id`) AS `max_id` FROM `outbox`) AS `o`)", before, t, t)
+	if err != nil {
+		return
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	n = int(rows)
+	return
+}
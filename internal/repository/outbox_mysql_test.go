@@ -0,0 +1,127 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxMysql_Append(t *testing.T) {
+
+	t.Run("success - message appended with the id of its event", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		rp := repository.NewRepositoryOutboxMysql(db)
+		m := internal.OutboxMessage{
+			Event:     internal.Event{Type: internal.EventProductCreated, EntityId: 1, Actor: "anonymous", Timestamp: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+			CreatedAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+
+		//act
+		err := rp.Append(context.Background(), &m)
+
+		//assert
+		require.NoError(t, err)
+		require.NotZero(t, m.Id)
+		require.Equal(t, strconv.Itoa(m.Id), m.Event.Id)
+		found, err := rp.FindPending(context.Background(), 10)
+		require.NoError(t, err)
+		require.Equal(t, []internal.OutboxMessage{m}, found)
+	})
+
+}
+
+func TestOutboxMysql_MarkPublished(t *testing.T) {
+
+	t.Run("success - published message no longer pending", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		rp := repository.NewRepositoryOutboxMysql(db)
+		m := internal.OutboxMessage{Event: internal.Event{Type: internal.EventWarehouseDeleted, EntityId: 1, Timestamp: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}}
+		err := rp.Append(context.Background(), &m)
+		require.NoError(t, err)
+
+		//act
+		err = rp.MarkPublished(context.Background(), m.Id, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+
+		//assert
+		require.NoError(t, err)
+		found, err := rp.FindPending(context.Background(), 10)
+		require.NoError(t, err)
+		require.Empty(t, found)
+	})
+
+	t.Run("fail - message not found", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		rp := repository.NewRepositoryOutboxMysql(db)
+
+		//act
+		err := rp.MarkPublished(context.Background(), 999, time.Now())
+
+		//assert
+		require.ErrorIs(t, err, internal.ErrRepositoryOutboxMessageNotFound)
+	})
+
+}
+
+func TestOutboxMysql_Purge(t *testing.T) {
+
+	t.Run("success - published messages purged but the last one", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		rp := repository.NewRepositoryOutboxMysql(db)
+		publishedAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		for i := 0; i < 3; i++ {
+			m := internal.OutboxMessage{Event: internal.Event{Type: internal.EventProductUpdated, EntityId: 1, Timestamp: publishedAt}}
+			require.NoError(t, rp.Append(context.Background(), &m))
+			require.NoError(t, rp.MarkPublished(context.Background(), m.Id, publishedAt))
+		}
+
+		//act
+		n, err := rp.Purge(context.Background(), publishedAt.Add(time.Hour))
+
+		//assert
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+	})
+
+}
+
+func TestUnitOfWorkOutboxed_Do(t *testing.T) {
+
+	t.Run("fail - events of a failed transaction discarded", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		uow := repository.NewUnitOfWorkOutboxed(repository.NewUnitOfWorkMysql(db))
+		errAbort := errors.New("abort")
+
+		//act
+		err := uow.Do(context.Background(), func(r internal.RepositoriesTx) (err error) {
+			w := internal.Warehouse{WarehouseAttributes: internal.WarehouseAttributes{Name: "warehouse 1", Address: "address 1", Telephone: "telephone 1", Capacity: 100}}
+			err = r.Warehouse.Save(context.Background(), &w)
+			if err != nil {
+				return
+			}
+			return errAbort
+		})
+
+		//assert
+		require.ErrorIs(t, err, errAbort)
+		found, err := repository.NewRepositoryOutboxMysql(db).FindPending(context.Background(), 10)
+		require.NoError(t, err)
+		require.Empty(t, found)
+	})
+
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// NewRepositoryOutboxStore creates a new outbox repository.
+func NewRepositoryOutboxStore(st internal.StoreOutbox) (r *RepositoryOutboxStore) {
+	r = &RepositoryOutboxStore{
+		st: st,
+	}
+	return
+}
+
+// RepositoryOutboxStore is an outbox repository.
+type RepositoryOutboxStore struct {
+	// st is the underlying store.
+	st internal.StoreOutbox
+}
+
+// Append appends a message to the outbox, setting its id and the one of its event.
+// - the message is of the tenant of its event, the one of the context unless appended across tenants
+func (r *RepositoryOutboxStore) Append(ctx context.Context, m *internal.OutboxMessage) (err error) {
+	// read all messages
+	ms, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// find max id
+	var maxId int
+	for k := range ms {
+		if k > maxId {
+			maxId = k
+		}
+	}
+
+	// add message
+	(*m).Id = maxId + 1
+	(*m).Event.Id = strconv.Itoa(m.Id)
+	(*m).Event.Tenant = tenantOf(ctx, m.Event.Tenant)
+	if m.CreatedAt.IsZero() {
+		(*m).CreatedAt = time.Now()
+	}
+	ms[m.Id] = *m
+
+	// write all messages
+	err = r.st.WriteAll(ms)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// FindPending returns up to limit messages not published yet, oldest first.
+func (r *RepositoryOutboxStore) FindPending(ctx context.Context, limit int) (m []internal.OutboxMessage, err error) {
+	// read all messages
+	ms, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// filter pending and sort by id
+	// - the messages of other tenants are left out
+	m = make([]internal.OutboxMessage, 0, len(ms))
+	for _, v := range ms {
+		if !v.PublishedAt.IsZero() || !internal.InTenant(ctx, v.Event.Tenant) {
+			continue
+		}
+		m = append(m, v)
+	}
+	sort.Slice(m, func(i, j int) bool {
+		return m[i].Id < m[j].Id
+	})
+
+	if limit > 0 && len(m) > limit {
+		m = m[:limit]
+	}
+	return
+}
+
+// MarkPublished marks a message as published.
+func (r *RepositoryOutboxStore) MarkPublished(ctx context.Context, id int, at time.Time) (err error) {
+	// read all messages
+	ms, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// check if message exists
+	// - the messages of other tenants are not found
+	m, ok := ms[id]
+	if !ok || !internal.InTenant(ctx, m.Event.Tenant) {
+		err = internal.ErrRepositoryOutboxMessageNotFound
+		return
+	}
+
+	// mark message
+	m.PublishedAt = at
+	ms[id] = m
+
+	// write all messages
+	err = r.st.WriteAll(ms)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Purge removes the messages published before a time, returning how many were removed.
+// - the last message is kept, so its id is never given to another one
+func (r *RepositoryOutboxStore) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	// read all messages
+	ms, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// find max id
+	var maxId int
+	for k := range ms {
+		if k > maxId {
+			maxId = k
+		}
+	}
+
+	// delete messages
+	for k, v := range ms {
+		if k == maxId || v.PublishedAt.IsZero() || !v.PublishedAt.Before(before) || !internal.InTenant(ctx, v.Event.Tenant) {
+			continue
+		}
+		delete(ms, k)
+		n++
+	}
+	if n == 0 {
+		return
+	}
+
+	// write all messages
+	err = r.st.WriteAll(ms)
+	if err != nil {
+		n = 0
+		return
+	}
+
+	return
+}
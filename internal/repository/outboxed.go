@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+)
+
+// NewUnitOfWorkOutboxed creates a new unit of work that appends the events of the mutations of its repositories to its outbox.
+func NewUnitOfWorkOutboxed(uow internal.UnitOfWork) *UnitOfWorkOutboxed {
+	return &UnitOfWorkOutboxed{
+		uow: uow,
+	}
+}
+
+// UnitOfWorkOutboxed is a unit of work that appends the events of the mutations of its repositories to its outbox.
+// - the events are appended within the unit of work, so they are committed or discarded along with their mutations
+type UnitOfWorkOutboxed struct {
+	// uow is the decorated unit of work.
+	uow internal.UnitOfWork
+}
+
+// Do runs fn with repositories appending their events to the outbox.
+func (u *UnitOfWorkOutboxed) Do(ctx context.Context, fn func(r internal.RepositoriesTx) (err error)) (err error) {
+	err = u.uow.Do(ctx, func(r internal.RepositoriesTx) (err error) {
+		ob := &auditOutbox{ro: r.Outbox}
+		err = fn(internal.RepositoriesTx{
+			Product:   NewRepositoryProductAudited(r.Product, ob),
+			Warehouse: NewRepositoryWarehouseAudited(r.Warehouse, ob),
+			Price:     r.Price,
+			Outbox:    r.Outbox,
+			Audit:     r.Audit,
+		})
+		return
+	})
+	return
+}
+
+// auditOutbox is an audit log appending the event of every entry to an outbox.
+// - it lets the audited repositories capture the mutations as events
+type auditOutbox struct {
+	ro internal.RepositoryOutbox
+}
+
+// Save appends the event of an entry to the outbox.
+func (a *auditOutbox) Save(ctx context.Context, e *internal.AuditEntry) (err error) {
+	err = a.ro.Append(ctx, &internal.OutboxMessage{
+		Event: internal.EventFromAudit(*e),
+	})
+	return
+}
+
+// FindByEntity returns no entries, the outbox keeps events only.
+func (a *auditOutbox) FindByEntity(ctx context.Context, entity string, id int) (e []internal.AuditEntry, err error) {
+	return
+}
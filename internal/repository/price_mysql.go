@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"database/sql"
+	"time"
+)
+
+// NewRepositoryPriceMysql creates a new repository for price changes backed by mysql.
+func NewRepositoryPriceMysql(db *sql.DB) *PriceMysql {
+	return &PriceMysql{
+		ex: traced(db),
+	}
+}
+
+// PriceMysql is a repository for price changes backed by mysql.
+type PriceMysql struct {
+	// ex runs the queries, it is the db itself or the transaction the repository is bound to
+	ex executor
+}
+
+// Save saves a price change.
+// - the change is of the tenant of its product
+func (r *PriceMysql) Save(ctx context.Context, c *internal.PriceChange) (err error) {
+	res, err := r.ex.ExecContext(ctx, "INSERT INTO `product_prices` (`id_product`, `tenant_id`, `price`, `currency`, `effective_at`, `status`) VALUES (?, COALESCE((SELECT `tenant_id` FROM `products` WHERE `id` = ?), ?), ?, ?, ?, ?)", c.ProductId, c.ProductId, tenantOf(ctx, ""), c.Price, c.Price.Currency, c.EffectiveAt, c.Status)
+	if err != nil {
+		return
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+
+	c.Id = int(id)
+	return
+}
+
+// UpdateStatus updates the status of a price change.
+func (r *PriceMysql) UpdateStatus(ctx context.Context, id int, status string) (err error) {
+	t := internal.TenantFromContext(ctx)
+	res, err := r.ex.ExecContext(ctx, "UPDATE `product_prices` SET `status` = ? WHERE `id` = ? AND (? = '*' OR `tenant_id` = ?)", status, id, t, t)
+	if err != nil {
+		return
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if n == 0 {
+		// rows affected is 0 as well when the status does not change
+		var exists bool
+		err = r.ex.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM `product_prices` WHERE `id` = ? AND (? = '*' OR `tenant_id` = ?))", id, t, t).Scan(&exists)
+		if err != nil {
+			return
+		}
+		if !exists {
+			err = internal.ErrRepositoryPriceNotFound
+		}
+	}
+
+	return
+}
+
+// FindByProduct returns the price changes of a product ordered by effective date.
+func (r *PriceMysql) FindByProduct(ctx context.Context, productId int) (c []internal.PriceChange, err error) {
+	t := internal.TenantFromContext(ctx)
+	return r.query(ctx, "SELECT `id`, `id_product`, `price`, `currency`, `effective_at`, `status` FROM `product_prices` WHERE `id_product` = ? AND (? = '*' OR `tenant_id` = ?) ORDER BY `effective_at`, `id`", productId, t, t)
+}
+
+// FindDue returns the scheduled price changes effective at or before a time ordered by effective date.
+func (r *PriceMysql) FindDue(ctx context.Context, at time.Time) (c []internal.PriceChange, err error) {
+	t := internal.TenantFromContext(ctx)
+	return r.query(ctx, "SELECT `id`, `id_product`, `price`, `currency`, `effective_at`, `status` FROM `product_prices` WHERE `status` = ? AND `effective_at` <= ? AND (? = '*' OR `tenant_id` = ?) ORDER BY `effective_at`, `id`", internal.PriceStatusScheduled, at, t, t)
+}
+
+// query runs a query returning price changes.
+func (r *PriceMysql) query(ctx context.Context, q string, args ...any) (c []internal.PriceChange, err error) {
+	rows, err := r.ex.QueryContext(ctx, q, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v internal.PriceChange
+		err = rows.Scan(&v.Id, &v.ProductId, &v.Price, &v.Price.Currency, &v.EffectiveAt, &v.Status)
+		if err != nil {
+			return
+		}
+		c = append(c, v)
+	}
+	err = rows.Err()
+	return
+}
@@ -0,0 +1,55 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceMysql_FindDue(t *testing.T) {
+
+	t.Run("success - scheduled and due", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `product_prices` (`id`, `id_product`, `price`, `effective_at`, `status`) VALUES (1, 1, 10, '2030-01-01 00:00:00', 'scheduled'), (2, 1, 20, '2030-02-01 00:00:00', 'scheduled'), (3, 1, 5, '2029-01-01 00:00:00', 'applied')")
+			require.NoError(t, err)
+		}(db)
+
+		rp := repository.NewRepositoryPriceMysql(db)
+
+		//act
+		cs, err := rp.FindDue(context.Background(), time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC))
+
+		//assert
+		require.NoError(t, err)
+		require.Len(t, cs, 1)
+		require.Equal(t, 1, cs[0].Id)
+		require.Equal(t, internal.NewMoney(1000, internal.CurrencyDefault), cs[0].Price)
+	})
+
+}
+
+func TestPriceMysql_UpdateStatus(t *testing.T) {
+
+	t.Run("fail - not found", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		rp := repository.NewRepositoryPriceMysql(db)
+
+		//act
+		err := rp.UpdateStatus(context.Background(), 1, internal.PriceStatusApplied)
+
+		//assert
+		require.ErrorIs(t, err, internal.ErrRepositoryPriceNotFound)
+	})
+
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"sort"
+	"time"
+)
+
+// NewRepositoryPriceStore creates a new repository for price changes.
+func NewRepositoryPriceStore(st internal.StorePrice) (r *RepositoryPriceStore) {
+	r = &RepositoryPriceStore{
+		st: st,
+	}
+	return
+}
+
+// RepositoryPriceStore is a repository for price changes.
+type RepositoryPriceStore struct {
+	// st is the underlying store.
+	st internal.StorePrice
+}
+
+// Save saves a price change.
+// - the change is of the tenant of its product, the one of the context unless saved across tenants
+func (r *RepositoryPriceStore) Save(ctx context.Context, c *internal.PriceChange) (err error) {
+	// read all price changes
+	cs, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// find max id
+	var maxId int
+	for k := range cs {
+		if k > maxId {
+			maxId = k
+		}
+	}
+
+	// add price change
+	(*c).Id = maxId + 1
+	(*c).Tenant = tenantOf(ctx, c.Tenant)
+	cs[c.Id] = *c
+
+	// write all price changes
+	err = r.st.WriteAll(cs)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// UpdateStatus updates the status of a price change.
+func (r *RepositoryPriceStore) UpdateStatus(ctx context.Context, id int, status string) (err error) {
+	// read all price changes
+	cs, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// update price change
+	// - the price changes of other tenants are not found
+	c, ok := cs[id]
+	if !ok || !internal.InTenant(ctx, c.Tenant) {
+		err = internal.ErrRepositoryPriceNotFound
+		return
+	}
+	c.Status = status
+	cs[id] = c
+
+	// write all price changes
+	err = r.st.WriteAll(cs)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// FindByProduct returns the price changes of a product ordered by effective date.
+func (r *RepositoryPriceStore) FindByProduct(ctx context.Context, productId int) (c []internal.PriceChange, err error) {
+	// read all price changes
+	cs, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	for _, v := range sortedPriceChanges(cs) {
+		if v.ProductId == productId && internal.InTenant(ctx, v.Tenant) {
+			c = append(c, v)
+		}
+	}
+	return
+}
+
+// FindDue returns the scheduled price changes effective at or before a time ordered by effective date.
+func (r *RepositoryPriceStore) FindDue(ctx context.Context, at time.Time) (c []internal.PriceChange, err error) {
+	// read all price changes
+	cs, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	for _, v := range sortedPriceChanges(cs) {
+		if v.Status == internal.PriceStatusScheduled && !v.EffectiveAt.After(at) && internal.InTenant(ctx, v.Tenant) {
+			c = append(c, v)
+		}
+	}
+	return
+}
+
+// sortedPriceChanges returns the price changes sorted by effective date, then by id.
+func sortedPriceChanges(cs map[int]internal.PriceChange) (c []internal.PriceChange) {
+	for _, v := range cs {
+		c = append(c, v)
+	}
+	sort.Slice(c, func(i, j int) bool {
+		if !c[i].EffectiveAt.Equal(c[j].EffectiveAt) {
+			return c[i].EffectiveAt.Before(c[j].EffectiveAt)
+		}
+		return c[i].Id < c[j].Id
+	})
+	return
+}
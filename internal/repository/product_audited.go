@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"time"
+)
+
+// NewRepositoryProductAudited creates a new repository for products that records its mutations to an audit log.
+func NewRepositoryProductAudited(rp internal.RepositoryProduct, ra internal.RepositoryAudit) *RepositoryProductAudited {
+	return &RepositoryProductAudited{
+		rp: rp,
+		ra: ra,
+	}
+}
+
+// RepositoryProductAudited is a repository for products that records its mutations to an audit log.
+type RepositoryProductAudited struct {
+	// rp is the decorated repository.
+	rp internal.RepositoryProduct
+	// ra is the audit log.
+	ra internal.RepositoryAudit
+}
+
+// FindById finds a product by id.
+func (r *RepositoryProductAudited) FindById(ctx context.Context, id int) (p internal.Product, err error) {
+	return r.rp.FindById(ctx, id)
+}
+
+// Save saves a product.
+func (r *RepositoryProductAudited) Save(ctx context.Context, p *internal.Product) (err error) {
+	err = r.rp.Save(ctx, p)
+	if err != nil {
+		return
+	}
+
+	err = audit(ctx, r.ra, internal.AuditEntityProduct, p.Id, internal.AuditOperationCreate, nil, p)
+	return
+}
+
+// UpdateOrSave updates or saves a product.
+func (r *RepositoryProductAudited) UpdateOrSave(ctx context.Context, p *internal.Product) (err error) {
+	before, err := r.current(ctx, p.Id)
+	if err != nil {
+		return
+	}
+
+	err = r.rp.UpdateOrSave(ctx, p)
+	if err != nil {
+		return
+	}
+
+	op := internal.AuditOperationUpdate
+	if before == nil || before.Id != p.Id {
+		before, op = nil, internal.AuditOperationCreate
+	}
+	err = audit(ctx, r.ra, internal.AuditEntityProduct, p.Id, op, before, p)
+	return
+}
+
+// Update updates a product.
+func (r *RepositoryProductAudited) Update(ctx context.Context, p *internal.Product) (err error) {
+	before, err := r.current(ctx, p.Id)
+	if err != nil {
+		return
+	}
+
+	err = r.rp.Update(ctx, p)
+	if err != nil {
+		return
+	}
+
+	err = audit(ctx, r.ra, internal.AuditEntityProduct, p.Id, internal.AuditOperationUpdate, before, p)
+	return
+}
+
+// Delete soft deletes a product.
+func (r *RepositoryProductAudited) Delete(ctx context.Context, id int, version int) (err error) {
+	before, err := r.current(ctx, id)
+	if err != nil {
+		return
+	}
+
+	err = r.rp.Delete(ctx, id, version)
+	if err != nil {
+		return
+	}
+
+	err = audit(ctx, r.ra, internal.AuditEntityProduct, id, internal.AuditOperationDelete, before, nil)
+	return
+}
+
+// Restore restores a soft deleted product.
+func (r *RepositoryProductAudited) Restore(ctx context.Context, id int) (err error) {
+	err = r.rp.Restore(ctx, id)
+	if err != nil {
+		return
+	}
+
+	after, err := r.current(ctx, id)
+	if err != nil {
+		return
+	}
+
+	err = audit(ctx, r.ra, internal.AuditEntityProduct, id, internal.AuditOperationRestore, nil, after)
+	return
+}
+
+// Purge permanently removes the products soft deleted before a time.
+// - a purge is recorded as a single entry with no entity id
+func (r *RepositoryProductAudited) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	n, err = r.rp.Purge(ctx, before)
+	if err != nil || n == 0 {
+		return
+	}
+
+	err = audit(ctx, r.ra, internal.AuditEntityProduct, 0, internal.AuditOperationPurge, nil, map[string]any{
+		"before": before,
+		"count":  n,
+	})
+	return
+}
+
+// GetAll returns all products.
+func (r *RepositoryProductAudited) GetAll(ctx context.Context) (p []internal.Product, err error) {
+	return r.rp.GetAll(ctx)
+}
+
+// GetAllIncludingDeleted returns all products, soft deleted ones included.
+func (r *RepositoryProductAudited) GetAllIncludingDeleted(ctx context.Context) (p []internal.Product, err error) {
+	return r.rp.GetAllIncludingDeleted(ctx)
+}
+
+// Batch applies all the operations or none of them.
+// - an entry is recorded for each operation once the batch succeeds
+func (r *RepositoryProductAudited) Batch(ctx context.Context, ops []internal.ProductOperation) (p []internal.Product, err error) {
+	befores := make([]*internal.Product, len(ops))
+	for i, op := range ops {
+		if op.Kind == internal.ProductOperationCreate {
+			continue
+		}
+		befores[i], err = r.current(ctx, op.Product.Id)
+		if err != nil {
+			return
+		}
+	}
+
+	p, err = r.rp.Batch(ctx, ops)
+	if err != nil {
+		return
+	}
+
+	for i, op := range ops {
+		switch op.Kind {
+		case internal.ProductOperationCreate:
+			err = audit(ctx, r.ra, internal.AuditEntityProduct, p[i].Id, internal.AuditOperationCreate, nil, p[i])
+		case internal.ProductOperationUpdate:
+			err = audit(ctx, r.ra, internal.AuditEntityProduct, p[i].Id, internal.AuditOperationUpdate, befores[i], p[i])
+		case internal.ProductOperationDelete:
+			err = audit(ctx, r.ra, internal.AuditEntityProduct, p[i].Id, internal.AuditOperationDelete, befores[i], nil)
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// FindExpiring returns the products expiring between two dates.
+func (r *RepositoryProductAudited) FindExpiring(ctx context.Context, from, to time.Time, warehouseId int) (p []internal.Product, err error) {
+	return r.rp.FindExpiring(ctx, from, to, warehouseId)
+}
+
+// FindExpired returns the products expired before a date.
+func (r *RepositoryProductAudited) FindExpired(ctx context.Context, before time.Time, warehouseId int) (p []internal.Product, err error) {
+	return r.rp.FindExpired(ctx, before, warehouseId)
+}
+
+// UnpublishExpired unpublishes the published products expired before a date.
+// - an update entry is recorded for each unpublished product
+func (r *RepositoryProductAudited) UnpublishExpired(ctx context.Context, before time.Time) (p []internal.Product, err error) {
+	p, err = r.rp.UnpublishExpired(ctx, before)
+	if err != nil {
+		return
+	}
+
+	for _, v := range p {
+		previous := v
+		previous.IsPublished = true
+		previous.Version--
+		err = audit(ctx, r.ra, internal.AuditEntityProduct, v.Id, internal.AuditOperationUpdate, previous, v)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// current returns the current product, nil if it does not exist.
+func (r *RepositoryProductAudited) current(ctx context.Context, id int) (p *internal.Product, err error) {
+	current, err := r.rp.FindById(ctx, id)
+	if err != nil {
+		if errors.Is(err, internal.ErrRepositoryProductNotFound) {
+			err = nil
+		}
+		return
+	}
+
+	p = &current
+	return
+}
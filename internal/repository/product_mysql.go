@@ -2,8 +2,10 @@ package repository
 
 import (
 	"app/internal"
+	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 )
@@ -11,18 +13,22 @@ import (
 func NewRepositoryProductMySql(db *sql.DB) *ProductMysql {
 	return &ProductMysql{
 		db: db,
+		ex: traced(db),
 	}
 }
 
 type ProductMysql struct {
 	db *sql.DB
+	// ex runs the queries, it is the db itself or the transaction the repository is bound to
+	ex executor
 }
 
-func (r *ProductMysql) FindById(id int) (p internal.Product, err error) {
+func (r *ProductMysql) FindById(ctx context.Context, id int) (p internal.Product, err error) {
 
-	row := r.db.QueryRow("SELECT p.`id`, p.`name`, p.`quantity`, p.`code_value`, p.`is_published`, p.`expiration`, p.`price` from `products` `p` where p.`id` = ? ", id)
+	t := internal.TenantFromContext(ctx)
+	row := r.ex.QueryRowContext(ctx, "SELECT p.`id`, p.`tenant_id`, p.`name`, p.`quantity`, p.`code_value`, p.`is_published`, p.`expiration`, p.`price`, p.`currency`, p.`id_warehouse`, p.`version`, p.`deleted_at` from `products` `p` where p.`id` = ? AND (? = '*' OR p.`tenant_id` = ?) AND p.`deleted_at` IS NULL", id, t, t)
 
-	err = row.Scan(&p.Id, &p.Name, &p.Quantity, &p.CodeValue, &p.IsPublished, &p.Expiration, &p.Price)
+	p, err = scanProduct(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			err = internal.ErrRepositoryProductNotFound
@@ -33,17 +39,11 @@ func (r *ProductMysql) FindById(id int) (p internal.Product, err error) {
 	return
 }
 
-func (r *ProductMysql) Save(p *internal.Product) (err error) {
+func (r *ProductMysql) Save(ctx context.Context, p *internal.Product) (err error) {
 
-	var id int
-	err = r.db.QueryRow("SELECT COUNT(*) FROM `products`").Scan(&id)
-	if err != nil {
-		return
-	}
-
-	id++
-
-	_, err = r.db.Exec("INSERT INTO `products` (`id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", id, p.Name, p.Quantity, p.CodeValue, p.IsPublished, p.Expiration, p.Price, p.WarehouseId)
+	// the id is assigned by the database, concurrent saves never get the same one
+	tenant := tenantOf(ctx, p.Tenant)
+	res, err := r.ex.ExecContext(ctx, "INSERT INTO `products` (`tenant_id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `currency`, `id_warehouse`, `version`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, 1)", tenant, p.Name, p.Quantity, p.CodeValue, p.IsPublished, p.Expiration, p.Price, p.Price.Currency, p.WarehouseId)
 	if err != nil {
 		var mySqlErr *mysql.MySQLError
 		if errors.As(err, &mySqlErr) {
@@ -52,17 +52,26 @@ func (r *ProductMysql) Save(p *internal.Product) (err error) {
 			case 1062:
 				err = internal.ErrRepositoryProductDuplicated
 			}
-			return
 		}
+		return
 	}
 
-	p.Id = id
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		return
+	}
+
+	p.Id = int(id)
+	p.Tenant = tenant
+	p.Version = 1
 
 	return
 }
 
-func (r *ProductMysql) UpdateOrSave(p *internal.Product) (err error) {
-	res, err := r.db.Exec("UPDATE `products` SET `name` = ?, `quantity` = ?, `code_value` = ?, `is_published` = ?, `expiration` = ?, `price` = ? WHERE `id` = ?", p.Name, p.Quantity, p.CodeValue, p.IsPublished, p.Expiration, p.Price, p.Id)
+func (r *ProductMysql) UpdateOrSave(ctx context.Context, p *internal.Product) (err error) {
+	t := internal.TenantFromContext(ctx)
+	res, err := r.ex.ExecContext(ctx, "UPDATE `products` SET `name` = ?, `quantity` = ?, `code_value` = ?, `is_published` = ?, `expiration` = ?, `price` = ?, `currency` = ?, `version` = `version` + 1 WHERE `id` = ? AND (? = '*' OR `tenant_id` = ?) AND (? = 0 OR `version` = ?) AND `deleted_at` IS NULL", p.Name, p.Quantity, p.CodeValue, p.IsPublished, p.Expiration, p.Price, p.Price.Currency, p.Id, t, t, p.Version, p.Version)
 	if err != nil {
 		var mysqlErr *mysql.MySQLError
 		if errors.As(err, &mysqlErr) {
@@ -71,8 +80,8 @@ func (r *ProductMysql) UpdateOrSave(p *internal.Product) (err error) {
 			case 1062:
 				err = internal.ErrRepositoryProductDuplicated
 			}
-			return
 		}
+		return
 	}
 
 	rowsAffected, err := res.RowsAffected()
@@ -82,17 +91,31 @@ func (r *ProductMysql) UpdateOrSave(p *internal.Product) (err error) {
 	}
 
 	if rowsAffected == 0 {
-		err = r.Save(p)
+		// the product exists with another version
+		_, err = r.version(ctx, p.Id)
+		if err == nil {
+			err = internal.ErrRepositoryProductVersionConflict
+			return
+		}
+		if !errors.Is(err, internal.ErrRepositoryProductNotFound) {
+			return
+		}
+
+		err = r.Save(ctx, p)
 		if err != nil {
 			return
 		}
+		return
 	}
 
+	p.Tenant = tenantOf(ctx, p.Tenant)
+	p.Version, err = r.version(ctx, p.Id)
 	return
 }
 
-func (r *ProductMysql) Update(p *internal.Product) (err error) {
-	_, err = r.db.Exec("UPDATE `products` SET `name` = ?, `quantity` = ?, `code_value` = ?, `is_published` = ?, `expiration` = ?, `price` = ? WHERE `id` = ?", p.Name, p.Quantity, p.CodeValue, p.IsPublished, p.Expiration, p.Price, p.Id)
+func (r *ProductMysql) Update(ctx context.Context, p *internal.Product) (err error) {
+	t := internal.TenantFromContext(ctx)
+	res, err := r.ex.ExecContext(ctx, "UPDATE `products` SET `name` = ?, `quantity` = ?, `code_value` = ?, `is_published` = ?, `expiration` = ?, `price` = ?, `currency` = ?, `id_warehouse` = ?, `version` = `version` + 1 WHERE `id` = ? AND (? = '*' OR `tenant_id` = ?) AND (? = 0 OR `version` = ?) AND `deleted_at` IS NULL", p.Name, p.Quantity, p.CodeValue, p.IsPublished, p.Expiration, p.Price, p.Price.Currency, p.WarehouseId, p.Id, t, t, p.Version, p.Version)
 	if err != nil {
 		var mysqlErr *mysql.MySQLError
 		if errors.As(err, &mysqlErr) {
@@ -101,15 +124,72 @@ func (r *ProductMysql) Update(p *internal.Product) (err error) {
 			case 1062:
 				err = internal.ErrRepositoryProductDuplicated
 			}
-			return
 		}
+		return
+	}
+
+	rowsAffected, err := res.RowsAffected()
+
+	if err != nil {
+		return
 	}
 
+	if rowsAffected == 0 {
+		// the product does not exist or exists with another version
+		_, err = r.version(ctx, p.Id)
+		if err == nil {
+			err = internal.ErrRepositoryProductVersionConflict
+		}
+		return
+	}
+
+	p.Tenant = tenantOf(ctx, p.Tenant)
+	p.Version, err = r.version(ctx, p.Id)
 	return
 }
 
-func (r *ProductMysql) Delete(id int) (err error) {
-	res, err := r.db.Exec("DELETE FROM `products` WHERE `id` = ?", id)
+// version returns the current version of a product.
+func (r *ProductMysql) version(ctx context.Context, id int) (v int, err error) {
+	t := internal.TenantFromContext(ctx)
+	err = r.ex.QueryRowContext(ctx, "SELECT `version` FROM `products` WHERE `id` = ? AND (? = '*' OR `tenant_id` = ?) AND `deleted_at` IS NULL", id, t, t).Scan(&v)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = internal.ErrRepositoryProductNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *ProductMysql) Delete(ctx context.Context, id int, version int) (err error) {
+	t := internal.TenantFromContext(ctx)
+	res, err := r.ex.ExecContext(ctx, "UPDATE `products` SET `deleted_at` = ?, `version` = `version` + 1 WHERE `id` = ? AND (? = 0 OR `version` = ?) AND (? = '*' OR `tenant_id` = ?) AND `deleted_at` IS NULL", time.Now(), id, version, version, t, t)
+	if err != nil {
+		return
+	}
+
+	rowsAffected, err := res.RowsAffected()
+
+	if err != nil {
+		return
+	}
+
+	if rowsAffected == 0 {
+		// the product does not exist or exists with another version
+		_, err = r.version(ctx, id)
+		if err == nil {
+			err = internal.ErrRepositoryProductVersionConflict
+		}
+		return
+	}
+
+	return
+}
+
+func (r *ProductMysql) Restore(ctx context.Context, id int) (err error) {
+	t := internal.TenantFromContext(ctx)
+	res, err := r.ex.ExecContext(ctx, "UPDATE `products` SET `deleted_at` = NULL, `version` = `version` + 1 WHERE `id` = ? AND (? = '*' OR `tenant_id` = ?) AND `deleted_at` IS NOT NULL", id, t, t)
 	if err != nil {
 		return
 	}
@@ -128,8 +208,37 @@ func (r *ProductMysql) Delete(id int) (err error) {
 	return
 }
 
-func (r *ProductMysql) GetAll() (p []internal.Product, err error) {
-	rows, err := r.db.Query("SELECT p.`id`, p.`name`, p.`quantity`, p.`code_value`, p.`is_published`, p.`expiration`, p.`price` from `products` `p`")
+func (r *ProductMysql) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	t := internal.TenantFromContext(ctx)
+	res, err := r.ex.ExecContext(ctx, "DELETE FROM `products` WHERE (? = '*' OR `tenant_id` = ?) AND `deleted_at` IS NOT NULL AND `deleted_at` < ?", t, t, before)
+	if err != nil {
+		return
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	n = int(rowsAffected)
+	return
+}
+
+func (r *ProductMysql) GetAll(ctx context.Context) (p []internal.Product, err error) {
+	t := internal.TenantFromContext(ctx)
+	p, err = r.query(ctx, "SELECT p.`id`, p.`tenant_id`, p.`name`, p.`quantity`, p.`code_value`, p.`is_published`, p.`expiration`, p.`price`, p.`currency`, p.`id_warehouse`, p.`version`, p.`deleted_at` from `products` `p` where (? = '*' OR p.`tenant_id` = ?) AND p.`deleted_at` IS NULL", t, t)
+	return
+}
+
+func (r *ProductMysql) GetAllIncludingDeleted(ctx context.Context) (p []internal.Product, err error) {
+	t := internal.TenantFromContext(ctx)
+	p, err = r.query(ctx, "SELECT p.`id`, p.`tenant_id`, p.`name`, p.`quantity`, p.`code_value`, p.`is_published`, p.`expiration`, p.`price`, p.`currency`, p.`id_warehouse`, p.`version`, p.`deleted_at` from `products` `p` where (? = '*' OR p.`tenant_id` = ?)", t, t)
+	return
+}
+
+// query returns the products selected by a query.
+func (r *ProductMysql) query(ctx context.Context, query string, args ...any) (p []internal.Product, err error) {
+	rows, err := r.ex.QueryContext(ctx, query, args...)
 	if err != nil {
 		return
 	}
@@ -138,7 +247,7 @@ func (r *ProductMysql) GetAll() (p []internal.Product, err error) {
 
 	for rows.Next() {
 		var product internal.Product
-		err = rows.Scan(&product.Id, &product.Name, &product.Quantity, &product.CodeValue, &product.IsPublished, &product.Expiration, &product.Price)
+		product, err = scanProduct(rows)
 		if err != nil {
 			return
 		}
@@ -153,3 +262,135 @@ func (r *ProductMysql) GetAll() (p []internal.Product, err error) {
 
 	return
 }
+
+// scanProduct scans a product selected with its deleted_at column last.
+func scanProduct(row interface{ Scan(dest ...any) error }) (p internal.Product, err error) {
+	var deletedAt sql.NullTime
+	err = row.Scan(&p.Id, &p.Tenant, &p.Name, &p.Quantity, &p.CodeValue, &p.IsPublished, &p.Expiration, &p.Price, &p.Price.Currency, &p.WarehouseId, &p.Version, &deletedAt)
+	if err != nil {
+		return
+	}
+
+	p.DeletedAt = deletedAt.Time
+	return
+}
+
+func (r *ProductMysql) Batch(ctx context.Context, ops []internal.ProductOperation) (p []internal.Product, err error) {
+	// already bound to a transaction
+	if r.db == nil {
+		p, err = r.batch(ctx, ops)
+		return
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	p, err = (&ProductMysql{ex: traced(tx)}).batch(ctx, ops)
+	if err != nil {
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		p = nil
+		return
+	}
+
+	return
+}
+
+func (r *ProductMysql) batch(ctx context.Context, ops []internal.ProductOperation) (p []internal.Product, err error) {
+	p = make([]internal.Product, len(ops))
+	for i, op := range ops {
+		p[i] = op.Product
+
+		switch op.Kind {
+		case internal.ProductOperationCreate:
+			err = r.Save(ctx, &p[i])
+		case internal.ProductOperationUpdate:
+			var current internal.Product
+			current, err = r.FindById(ctx, p[i].Id)
+			if err == nil {
+				p[i].WarehouseId = current.WarehouseId
+				err = r.Update(ctx, &p[i])
+			}
+		case internal.ProductOperationDelete:
+			err = r.Delete(ctx, p[i].Id, p[i].Version)
+		default:
+			err = internal.ErrRepositoryProductOperationInvalid
+		}
+		if err != nil {
+			p = nil
+			err = &internal.ProductOperationError{Index: i, Err: err}
+			return
+		}
+	}
+
+	return
+}
+
+// FindExpiring returns the products expiring between two dates, both included, sorted by expiration.
+func (r *ProductMysql) FindExpiring(ctx context.Context, from, to time.Time, warehouseId int) (p []internal.Product, err error) {
+	t := internal.TenantFromContext(ctx)
+	p, err = r.query(ctx, "SELECT p.`id`, p.`tenant_id`, p.`name`, p.`quantity`, p.`code_value`, p.`is_published`, p.`expiration`, p.`price`, p.`currency`, p.`id_warehouse`, p.`version`, p.`deleted_at` from `products` `p` where p.`expiration` BETWEEN ? AND ? AND (? = 0 OR p.`id_warehouse` = ?) AND (? = '*' OR p.`tenant_id` = ?) AND p.`deleted_at` IS NULL ORDER BY p.`expiration`, p.`id`", from, to, warehouseId, warehouseId, t, t)
+	return
+}
+
+// FindExpired returns the products expired before a date sorted by expiration.
+func (r *ProductMysql) FindExpired(ctx context.Context, before time.Time, warehouseId int) (p []internal.Product, err error) {
+	t := internal.TenantFromContext(ctx)
+	p, err = r.query(ctx, "SELECT p.`id`, p.`tenant_id`, p.`name`, p.`quantity`, p.`code_value`, p.`is_published`, p.`expiration`, p.`price`, p.`currency`, p.`id_warehouse`, p.`version`, p.`deleted_at` from `products` `p` where p.`expiration` < ? AND (? = 0 OR p.`id_warehouse` = ?) AND (? = '*' OR p.`tenant_id` = ?) AND p.`deleted_at` IS NULL ORDER BY p.`expiration`, p.`id`", before, warehouseId, warehouseId, t, t)
+	return
+}
+
+// UnpublishExpired unpublishes the published products expired before a date, returning them.
+func (r *ProductMysql) UnpublishExpired(ctx context.Context, before time.Time) (p []internal.Product, err error) {
+	// already bound to a transaction
+	if r.db == nil {
+		p, err = r.unpublishExpired(ctx, before)
+		return
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	p, err = (&ProductMysql{ex: traced(tx)}).unpublishExpired(ctx, before)
+	if err != nil {
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		p = nil
+		return
+	}
+
+	return
+}
+
+func (r *ProductMysql) unpublishExpired(ctx context.Context, before time.Time) (p []internal.Product, err error) {
+	// lock the products to unpublish
+	t := internal.TenantFromContext(ctx)
+	p, err = r.query(ctx, "SELECT p.`id`, p.`tenant_id`, p.`name`, p.`quantity`, p.`code_value`, p.`is_published`, p.`expiration`, p.`price`, p.`currency`, p.`id_warehouse`, p.`version`, p.`deleted_at` from `products` `p` where p.`expiration` < ? AND p.`is_published` AND (? = '*' OR p.`tenant_id` = ?) AND p.`deleted_at` IS NULL ORDER BY p.`expiration`, p.`id` FOR UPDATE", before, t, t)
+	if err != nil || len(p) == 0 {
+		return
+	}
+
+	_, err = r.ex.ExecContext(ctx, "UPDATE `products` SET `is_published` = false, `version` = `version` + 1 WHERE `expiration` < ? AND `is_published` AND (? = '*' OR `tenant_id` = ?) AND `deleted_at` IS NULL", before, t, t)
+	if err != nil {
+		p = nil
+		return
+	}
+
+	for i := range p {
+		p[i].IsPublished = false
+		p[i].Version++
+	}
+	return
+}
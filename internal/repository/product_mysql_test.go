@@ -3,33 +3,18 @@ package repository_test
 import (
 	"app/internal"
 	"app/internal/repository"
+	"context"
 	"database/sql"
 	"testing"
 	"time"
 
-	"github.com/DATA-DOG/go-txdb"
-	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/require"
 )
 
-func init() {
-	cfg := mysql.Config{
-		User:      "user1",
-		Passwd:    "secret_password",
-		Addr:      "localhost:3306",
-		Net:       "tcp",
-		DBName:    "test_db",
-		ParseTime: true,
-	}
-
-	txdb.Register("txdb", "mysql", cfg.FormatDSN())
-}
-
 func TestProduct_GetAll(t *testing.T) {
 
 	t.Run("success - return 1", func(t *testing.T) {
-		db, err := sql.Open("txdb", "test_db")
-		require.NoError(t, err)
+		db := openTxdb(t)
 		defer db.Close()
 
 		//set up
@@ -41,7 +26,7 @@ func TestProduct_GetAll(t *testing.T) {
 		rp := repository.NewRepositoryProductMySql(db)
 
 		//act
-		product, err := rp.GetAll()
+		product, err := rp.GetAll(context.Background())
 
 		date, err := time.Parse("2006-01-02", "2021-01-01")
 
@@ -51,7 +36,9 @@ func TestProduct_GetAll(t *testing.T) {
 		expectedProduct := []internal.Product{
 			{
 				Id:          1,
+				Tenant:      internal.TenantDefault,
 				WarehouseId: 0,
+				Version:     1,
 				ProductAttributes: internal.ProductAttributes{
 
 					Name:        "product 1",
@@ -59,7 +46,7 @@ func TestProduct_GetAll(t *testing.T) {
 					CodeValue:   "code_value 1",
 					IsPublished: true,
 					Expiration:  date,
-					Price:       1,
+					Price:       internal.NewMoney(100, internal.CurrencyDefault),
 				},
 			},
 		}
@@ -68,14 +55,13 @@ func TestProduct_GetAll(t *testing.T) {
 	})
 
 	t.Run("success - return 0", func(t *testing.T) {
-		db, err := sql.Open("txdb", "test_db")
-		require.NoError(t, err)
+		db := openTxdb(t)
 		defer db.Close()
 
 		rp := repository.NewRepositoryProductMySql(db)
 
 		//act
-		wh, err := rp.GetAll()
+		wh, err := rp.GetAll(context.Background())
 
 		//assert
 		expectedWh := []internal.Product(nil)
@@ -88,8 +74,7 @@ func TestProduct_GetAll(t *testing.T) {
 func TestProduct_Save(t *testing.T) {
 
 	t.Run("success - saved", func(t *testing.T) {
-		db, err := sql.Open("txdb", "test_db")
-		require.NoError(t, err)
+		db := openTxdb(t)
 		defer db.Close()
 
 		timeNow := time.Now()
@@ -104,25 +89,44 @@ func TestProduct_Save(t *testing.T) {
 				CodeValue:   "code_value 1",
 				IsPublished: true,
 				Expiration:  timeNow,
-				Price:       1,
+				Price:       internal.NewMoney(100, internal.CurrencyDefault),
 			},
 		}
 
 		rp := repository.NewRepositoryProductMySql(db)
 
 		//act
-		err = rp.Save(&prod)
+		err := rp.Save(context.Background(), &prod)
 
 		//assert
 		require.NoError(t, err)
+		require.NotZero(t, prod.Id)
+		require.Equal(t, 1, prod.Version)
+	})
+
+	t.Run("success - saved twice, distinct ids assigned by the database", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		rp := repository.NewRepositoryProductMySql(db)
+		prod1 := internal.Product{ProductAttributes: internal.ProductAttributes{Name: "product 1", CodeValue: "code_value 1", Expiration: time.Now(), Price: internal.NewMoney(100, internal.CurrencyDefault)}}
+		prod2 := internal.Product{ProductAttributes: internal.ProductAttributes{Name: "product 2", CodeValue: "code_value 2", Expiration: time.Now(), Price: internal.NewMoney(100, internal.CurrencyDefault)}}
+
+		//act
+		err1 := rp.Save(context.Background(), &prod1)
+		err2 := rp.Save(context.Background(), &prod2)
+
+		//assert
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.NotEqual(t, prod1.Id, prod2.Id)
 	})
 
 }
 
 func TestProduct_Delete(t *testing.T) {
 	t.Run("success - delete by id", func(t *testing.T) {
-		db, err := sql.Open("txdb", "test_db")
-		require.NoError(t, err)
+		db := openTxdb(t)
 		defer db.Close()
 
 		// defer func(db *sql.DB) {
@@ -144,15 +148,14 @@ func TestProduct_Delete(t *testing.T) {
 		rp := repository.NewRepositoryProductMySql(db)
 
 		//act
-		err = rp.Delete(1)
+		err := rp.Delete(context.Background(), 1, 0)
 
 		//assert
 		require.NoError(t, err)
 	})
 
 	t.Run("fail - not found by id", func(t *testing.T) {
-		db, err := sql.Open("txdb", "test_db")
-		require.NoError(t, err)
+		db := openTxdb(t)
 		defer db.Close()
 
 		// defer func(db *sql.DB) {
@@ -169,19 +172,39 @@ func TestProduct_Delete(t *testing.T) {
 		rp := repository.NewRepositoryProductMySql(db)
 
 		//act
-		err = rp.Delete(1)
+		err := rp.Delete(context.Background(), 1, 0)
 
 		//assert
 		require.Error(t, err)
 		require.ErrorIs(t, err, internal.ErrRepositoryProductNotFound)
 	})
+
+	t.Run("fail - version conflict", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `products` (`id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse`, `version`) VALUES (1, 'product 1', 1, 'code_value 1', true, '2021-01-01', 1, 0, 2)")
+			require.NoError(t, err)
+		}(db)
+
+		rp := repository.NewRepositoryProductMySql(db)
+
+		//act
+		err := rp.Delete(context.Background(), 1, 1)
+
+		//assert
+		require.ErrorIs(t, err, internal.ErrRepositoryProductVersionConflict)
+		_, err = rp.FindById(context.Background(), 1)
+		require.NoError(t, err)
+	})
 }
 
 func TestProduct_Update(t *testing.T) {
 
 	t.Run("success - updated", func(t *testing.T) {
-		db, err := sql.Open("txdb", "test_db")
-		require.NoError(t, err)
+		db := openTxdb(t)
 		defer db.Close()
 
 		timeNow := time.Now()
@@ -201,17 +224,323 @@ func TestProduct_Update(t *testing.T) {
 				CodeValue:   "code_value 1",
 				IsPublished: true,
 				Expiration:  timeNow,
-				Price:       1,
+				Price:       internal.NewMoney(100, internal.CurrencyDefault),
 			},
 		}
 
 		rp := repository.NewRepositoryProductMySql(db)
 
 		//act
-		err = rp.Update(&prod)
+		err := rp.Update(context.Background(), &prod)
 
 		//assert
 		require.NoError(t, err)
+		require.Equal(t, 2, prod.Version)
+	})
+
+	t.Run("fail - version conflict", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `products` (`id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse`, `version`) VALUES (1, 'product 1', 1, 'code_value 1', true, '2021-01-01', 1, 0, 2)")
+			require.NoError(t, err)
+		}(db)
+
+		prod := internal.Product{
+			Id:      1,
+			Version: 1,
+			ProductAttributes: internal.ProductAttributes{
+				Name:      "updated test",
+				CodeValue: "code_value 1",
+			},
+		}
+
+		rp := repository.NewRepositoryProductMySql(db)
+
+		//act
+		err := rp.Update(context.Background(), &prod)
+
+		//assert
+		require.ErrorIs(t, err, internal.ErrRepositoryProductVersionConflict)
+	})
+
+	t.Run("fail - not found", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		prod := internal.Product{Id: 1}
+
+		rp := repository.NewRepositoryProductMySql(db)
+
+		//act
+		err := rp.Update(context.Background(), &prod)
+
+		//assert
+		require.ErrorIs(t, err, internal.ErrRepositoryProductNotFound)
+	})
+
+}
+
+func TestProduct_Batch(t *testing.T) {
+
+	t.Run("success - all operations applied", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `products` (`id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse`) VALUES (1, 'product 1', 1, 'code_value 1', true, '2021-01-01', 1, 0)")
+			require.NoError(t, err)
+		}(db)
+
+		date, err := time.Parse("2006-01-02", "2021-01-01")
+		require.NoError(t, err)
+
+		ops := []internal.ProductOperation{
+			{
+				Kind: internal.ProductOperationCreate,
+				Product: internal.Product{
+					ProductAttributes: internal.ProductAttributes{Name: "product 2", Quantity: 2, CodeValue: "code_value 2", Expiration: date, Price: internal.NewMoney(200, internal.CurrencyDefault)},
+				},
+			},
+			{
+				Kind:    internal.ProductOperationDelete,
+				Product: internal.Product{Id: 1},
+			},
+		}
+
+		rp := repository.NewRepositoryProductMySql(db)
+
+		//act
+		products, err := rp.Batch(context.Background(), ops)
+
+		//assert
+		require.NoError(t, err)
+		require.Len(t, products, 2)
+		// - the id is assigned by the database, past the ids of the products inserted
+		require.Greater(t, products[0].Id, 1)
+		_, err = rp.FindById(context.Background(), 1)
+		require.ErrorIs(t, err, internal.ErrRepositoryProductNotFound)
+	})
+
+	t.Run("fail - rolled back on not found", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `products` (`id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse`) VALUES (1, 'product 1', 1, 'code_value 1', true, '2021-01-01', 1, 0)")
+			require.NoError(t, err)
+		}(db)
+
+		ops := []internal.ProductOperation{
+			{Kind: internal.ProductOperationDelete, Product: internal.Product{Id: 1}},
+			{Kind: internal.ProductOperationDelete, Product: internal.Product{Id: 2}},
+		}
+
+		rp := repository.NewRepositoryProductMySql(db)
+
+		//act
+		products, err := rp.Batch(context.Background(), ops)
+
+		//assert
+		var opErr *internal.ProductOperationError
+		require.ErrorAs(t, err, &opErr)
+		require.Equal(t, 1, opErr.Index)
+		require.ErrorIs(t, err, internal.ErrRepositoryProductNotFound)
+		require.Nil(t, products)
+		_, err = rp.FindById(context.Background(), 1)
+		require.NoError(t, err)
+	})
+
+}
+
+func TestProduct_Restore(t *testing.T) {
+
+	t.Run("success - restored", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `products` (`id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse`) VALUES (1, 'product 1', 1, 'code_value 1', true, '2021-01-01', 1, 0)")
+			require.NoError(t, err)
+		}(db)
+
+		rp := repository.NewRepositoryProductMySql(db)
+		err := rp.Delete(context.Background(), 1, 0)
+		require.NoError(t, err)
+		_, err = rp.FindById(context.Background(), 1)
+		require.ErrorIs(t, err, internal.ErrRepositoryProductNotFound)
+
+		//act
+		err = rp.Restore(context.Background(), 1)
+
+		//assert
+		require.NoError(t, err)
+		_, err = rp.FindById(context.Background(), 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("fail - not deleted", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `products` (`id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse`) VALUES (1, 'product 1', 1, 'code_value 1', true, '2021-01-01', 1, 0)")
+			require.NoError(t, err)
+		}(db)
+
+		rp := repository.NewRepositoryProductMySql(db)
+
+		//act
+		err := rp.Restore(context.Background(), 1)
+
+		//assert
+		require.ErrorIs(t, err, internal.ErrRepositoryProductNotFound)
+	})
+
+}
+
+func TestProduct_Purge(t *testing.T) {
+
+	t.Run("success - purged beyond retention", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `products` (`id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse`, `deleted_at`) VALUES (1, 'product 1', 1, 'code_value 1', true, '2021-01-01', 1, 0, '2021-01-01'), (2, 'product 2', 1, 'code_value 2', true, '2021-01-01', 1, 0, NOW()), (3, 'product 3', 1, 'code_value 3', true, '2021-01-01', 1, 0, NULL)")
+			require.NoError(t, err)
+		}(db)
+
+		rp := repository.NewRepositoryProductMySql(db)
+
+		//act
+		n, err := rp.Purge(context.Background(), time.Now().Add(-24*time.Hour))
+
+		//assert
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+		products, err := rp.GetAllIncludingDeleted(context.Background())
+		require.NoError(t, err)
+		require.Len(t, products, 2)
+	})
+
+}
+
+func TestProduct_FindExpiring(t *testing.T) {
+
+	t.Run("success - expiring within a warehouse", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `products` (`id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse`) VALUES (1, 'product 1', 1, 'code_value 1', true, '2030-01-10', 1, 1), (2, 'product 2', 1, 'code_value 2', true, '2030-01-05', 1, 1), (3, 'product 3', 1, 'code_value 3', true, '2030-01-05', 1, 2), (4, 'product 4', 1, 'code_value 4', true, '2030-03-01', 1, 1)")
+			require.NoError(t, err)
+		}(db)
+
+		rp := repository.NewRepositoryProductMySql(db)
+
+		//act
+		from := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		products, err := rp.FindExpiring(context.Background(), from, from.AddDate(0, 0, 30), 1)
+
+		//assert
+		require.NoError(t, err)
+		require.Len(t, products, 2)
+		require.Equal(t, 2, products[0].Id)
+		require.Equal(t, 1, products[1].Id)
+	})
+
+}
+
+func TestProduct_UnpublishExpired(t *testing.T) {
+
+	t.Run("success - expired published products unpublished", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `products` (`id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse`) VALUES (1, 'product 1', 1, 'code_value 1', true, '2021-01-01', 1, 0), (2, 'product 2', 1, 'code_value 2', false, '2021-01-01', 1, 0), (3, 'product 3', 1, 'code_value 3', true, '2099-01-01', 1, 0)")
+			require.NoError(t, err)
+		}(db)
+
+		rp := repository.NewRepositoryProductMySql(db)
+
+		//act
+		products, err := rp.UnpublishExpired(context.Background(), time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+
+		//assert
+		require.NoError(t, err)
+		require.Len(t, products, 1)
+		require.Equal(t, 1, products[0].Id)
+		require.False(t, products[0].IsPublished)
+		p, err := rp.FindById(context.Background(), 1)
+		require.NoError(t, err)
+		require.False(t, p.IsPublished)
+		require.Equal(t, 2, p.Version)
+	})
+
+}
+
+func TestProduct_Tenant(t *testing.T) {
+
+	t.Run("fail - products of another tenant not found", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `products` (`id`, `tenant_id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse`) VALUES (1, 'acme', 'product 1', 1, 'code_value 1', true, '2021-01-01', 1, 0), (2, 'globex', 'product 2', 1, 'code_value 2', true, '2021-01-01', 1, 0)")
+			require.NoError(t, err)
+		}(db)
+
+		rp := repository.NewRepositoryProductMySql(db)
+		ctx := internal.ContextWithTenant(context.Background(), "globex")
+
+		//act
+		_, errFind := rp.FindById(ctx, 1)
+		errDelete := rp.Delete(ctx, 1, 0)
+		products, errGetAll := rp.GetAll(ctx)
+
+		//assert
+		require.ErrorIs(t, errFind, internal.ErrRepositoryProductNotFound)
+		require.ErrorIs(t, errDelete, internal.ErrRepositoryProductNotFound)
+		require.NoError(t, errGetAll)
+		require.Len(t, products, 1)
+		require.Equal(t, 2, products[0].Id)
+		require.Equal(t, "globex", products[0].Tenant)
+	})
+
+	t.Run("success - product saved in the tenant of the context", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		rp := repository.NewRepositoryProductMySql(db)
+		ctx := internal.ContextWithTenant(context.Background(), "acme")
+		prod := internal.Product{
+			ProductAttributes: internal.ProductAttributes{
+				Name:       "product 1",
+				CodeValue:  "code_value 1",
+				Expiration: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+				Price:      internal.NewMoney(100, internal.CurrencyDefault),
+			},
+		}
+
+		//act
+		err := rp.Save(ctx, &prod)
+
+		//assert
+		require.NoError(t, err)
+		require.Equal(t, "acme", prod.Tenant)
+		_, err = rp.FindById(context.Background(), prod.Id)
+		require.ErrorIs(t, err, internal.ErrRepositoryProductNotFound)
 	})
 
 }
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"time"
+)
+
+// NewRepositoryProductObserved creates a new repository for products whose calls are observed, e.g. to measure their latency.
+func NewRepositoryProductObserved(rp internal.RepositoryProduct, o internal.Observer) *RepositoryProductObserved {
+	return &RepositoryProductObserved{
+		rp: rp,
+		o:  o,
+	}
+}
+
+// RepositoryProductObserved is a repository for products whose calls are observed, e.g. to measure their latency.
+// - the context of a call is the one of its observation, given to the decorated repository
+type RepositoryProductObserved struct {
+	// rp is the decorated repository.
+	rp internal.RepositoryProduct
+	// o is the observer of the calls.
+	o internal.Observer
+}
+
+// FindById finds a product by id.
+func (r *RepositoryProductObserved) FindById(ctx context.Context, id int) (p internal.Product, err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "product", "FindById")
+	p, err = r.rp.FindById(ctx, id)
+	done(err)
+	return
+}
+
+// Save saves a product.
+func (r *RepositoryProductObserved) Save(ctx context.Context, p *internal.Product) (err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "product", "Save")
+	err = r.rp.Save(ctx, p)
+	done(err)
+	return
+}
+
+// UpdateOrSave updates or saves a product.
+func (r *RepositoryProductObserved) UpdateOrSave(ctx context.Context, p *internal.Product) (err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "product", "UpdateOrSave")
+	err = r.rp.UpdateOrSave(ctx, p)
+	done(err)
+	return
+}
+
+// Update updates a product.
+func (r *RepositoryProductObserved) Update(ctx context.Context, p *internal.Product) (err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "product", "Update")
+	err = r.rp.Update(ctx, p)
+	done(err)
+	return
+}
+
+// Delete soft deletes a product.
+func (r *RepositoryProductObserved) Delete(ctx context.Context, id int, version int) (err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "product", "Delete")
+	err = r.rp.Delete(ctx, id, version)
+	done(err)
+	return
+}
+
+// Restore restores a soft deleted product.
+func (r *RepositoryProductObserved) Restore(ctx context.Context, id int) (err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "product", "Restore")
+	err = r.rp.Restore(ctx, id)
+	done(err)
+	return
+}
+
+// Purge permanently removes the products soft deleted before a time.
+func (r *RepositoryProductObserved) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "product", "Purge")
+	n, err = r.rp.Purge(ctx, before)
+	done(err)
+	return
+}
+
+// GetAll returns all products.
+func (r *RepositoryProductObserved) GetAll(ctx context.Context) (p []internal.Product, err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "product", "GetAll")
+	p, err = r.rp.GetAll(ctx)
+	done(err)
+	return
+}
+
+// GetAllIncludingDeleted returns all products, soft deleted ones included.
+func (r *RepositoryProductObserved) GetAllIncludingDeleted(ctx context.Context) (p []internal.Product, err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "product", "GetAllIncludingDeleted")
+	p, err = r.rp.GetAllIncludingDeleted(ctx)
+	done(err)
+	return
+}
+
+// Batch applies all the operations or none of them.
+func (r *RepositoryProductObserved) Batch(ctx context.Context, ops []internal.ProductOperation) (p []internal.Product, err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "product", "Batch")
+	p, err = r.rp.Batch(ctx, ops)
+	done(err)
+	return
+}
+
+// FindExpiring returns the products expiring between two dates.
+func (r *RepositoryProductObserved) FindExpiring(ctx context.Context, from, to time.Time, warehouseId int) (p []internal.Product, err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "product", "FindExpiring")
+	p, err = r.rp.FindExpiring(ctx, from, to, warehouseId)
+	done(err)
+	return
+}
+
+// FindExpired returns the products expired before a date.
+func (r *RepositoryProductObserved) FindExpired(ctx context.Context, before time.Time, warehouseId int) (p []internal.Product, err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "product", "FindExpired")
+	p, err = r.rp.FindExpired(ctx, before, warehouseId)
+	done(err)
+	return
+}
+
+// UnpublishExpired unpublishes the published products expired before a date.
+func (r *RepositoryProductObserved) UnpublishExpired(ctx context.Context, before time.Time) (p []internal.Product, err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "product", "UnpublishExpired")
+	p, err = r.rp.UnpublishExpired(ctx, before)
+	done(err)
+	return
+}
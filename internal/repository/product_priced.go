@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"time"
+)
+
+// NewRepositoryProductPriced creates a new repository for products that records the changes of their prices.
+func NewRepositoryProductPriced(rp internal.RepositoryProduct, uow internal.UnitOfWork) *RepositoryProductPriced {
+	return &RepositoryProductPriced{
+		rp:  rp,
+		uow: uow,
+	}
+}
+
+// RepositoryProductPriced is a repository for products that records the changes of their prices.
+// - a product saved, or updated with a different price, gets an applied price change effective now
+// - the change is recorded in the unit of work of the mutation, so both are committed or discarded together
+type RepositoryProductPriced struct {
+	// rp is the decorated repository, it makes the reads and the mutations leaving the prices as they are.
+	rp internal.RepositoryProduct
+	// uow is the unit of work the mutations changing the prices run in.
+	uow internal.UnitOfWork
+}
+
+// FindById finds a product by id.
+func (r *RepositoryProductPriced) FindById(ctx context.Context, id int) (p internal.Product, err error) {
+	return r.rp.FindById(ctx, id)
+}
+
+// Save saves a product.
+func (r *RepositoryProductPriced) Save(ctx context.Context, p *internal.Product) (err error) {
+	err = r.uow.Do(ctx, func(rt internal.RepositoriesTx) (err error) {
+		err = rt.Product.Save(ctx, p)
+		if err != nil {
+			return
+		}
+
+		err = recordPrice(ctx, rt.Price, *p)
+		return
+	})
+	return
+}
+
+// UpdateOrSave updates or saves a product.
+func (r *RepositoryProductPriced) UpdateOrSave(ctx context.Context, p *internal.Product) (err error) {
+	err = r.uow.Do(ctx, func(rt internal.RepositoriesTx) (err error) {
+		price, found, err := productPrice(ctx, rt.Product, p.Id)
+		if err != nil {
+			return
+		}
+
+		err = rt.Product.UpdateOrSave(ctx, p)
+		if err != nil {
+			return
+		}
+
+		// a product not found is saved with a new id
+		if found && price.Equal(p.Price) {
+			return
+		}
+		err = recordPrice(ctx, rt.Price, *p)
+		return
+	})
+	return
+}
+
+// Update updates a product.
+func (r *RepositoryProductPriced) Update(ctx context.Context, p *internal.Product) (err error) {
+	err = r.uow.Do(ctx, func(rt internal.RepositoriesTx) (err error) {
+		price, _, err := productPrice(ctx, rt.Product, p.Id)
+		if err != nil {
+			return
+		}
+
+		err = rt.Product.Update(ctx, p)
+		if err != nil {
+			return
+		}
+
+		if price.Equal(p.Price) {
+			return
+		}
+		err = recordPrice(ctx, rt.Price, *p)
+		return
+	})
+	return
+}
+
+// Delete soft deletes a product.
+func (r *RepositoryProductPriced) Delete(ctx context.Context, id int, version int) (err error) {
+	return r.rp.Delete(ctx, id, version)
+}
+
+// Restore restores a soft deleted product.
+func (r *RepositoryProductPriced) Restore(ctx context.Context, id int) (err error) {
+	return r.rp.Restore(ctx, id)
+}
+
+// Purge permanently removes the products soft deleted before a time.
+func (r *RepositoryProductPriced) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	return r.rp.Purge(ctx, before)
+}
+
+// GetAll returns all products.
+func (r *RepositoryProductPriced) GetAll(ctx context.Context) (p []internal.Product, err error) {
+	return r.rp.GetAll(ctx)
+}
+
+// GetAllIncludingDeleted returns all products, soft deleted ones included.
+func (r *RepositoryProductPriced) GetAllIncludingDeleted(ctx context.Context) (p []internal.Product, err error) {
+	return r.rp.GetAllIncludingDeleted(ctx)
+}
+
+// Batch applies all the operations or none of them.
+// - price changes are recorded along with the batch
+func (r *RepositoryProductPriced) Batch(ctx context.Context, ops []internal.ProductOperation) (p []internal.Product, err error) {
+	err = r.uow.Do(ctx, func(rt internal.RepositoriesTx) (err error) {
+		prices := make([]internal.Money, len(ops))
+		for i, op := range ops {
+			if op.Kind != internal.ProductOperationUpdate {
+				continue
+			}
+			prices[i], _, err = productPrice(ctx, rt.Product, op.Product.Id)
+			if err != nil {
+				return
+			}
+		}
+
+		p, err = rt.Product.Batch(ctx, ops)
+		if err != nil {
+			return
+		}
+
+		for i, op := range ops {
+			switch {
+			case op.Kind == internal.ProductOperationCreate:
+			case op.Kind == internal.ProductOperationUpdate && !prices[i].Equal(p[i].Price):
+			default:
+				continue
+			}
+			err = recordPrice(ctx, rt.Price, p[i])
+			if err != nil {
+				return
+			}
+		}
+		return
+	})
+	if err != nil {
+		p = nil
+	}
+	return
+}
+
+// FindExpiring returns the products expiring between two dates.
+func (r *RepositoryProductPriced) FindExpiring(ctx context.Context, from, to time.Time, warehouseId int) (p []internal.Product, err error) {
+	return r.rp.FindExpiring(ctx, from, to, warehouseId)
+}
+
+// FindExpired returns the products expired before a date.
+func (r *RepositoryProductPriced) FindExpired(ctx context.Context, before time.Time, warehouseId int) (p []internal.Product, err error) {
+	return r.rp.FindExpired(ctx, before, warehouseId)
+}
+
+// UnpublishExpired unpublishes the published products expired before a date.
+func (r *RepositoryProductPriced) UnpublishExpired(ctx context.Context, before time.Time) (p []internal.Product, err error) {
+	return r.rp.UnpublishExpired(ctx, before)
+}
+
+// productPrice returns the current price of a product, found is false if it does not exist.
+func productPrice(ctx context.Context, rp internal.RepositoryProduct, id int) (price internal.Money, found bool, err error) {
+	p, err := rp.FindById(ctx, id)
+	if err != nil {
+		if errors.Is(err, internal.ErrRepositoryProductNotFound) {
+			err = nil
+		}
+		return
+	}
+
+	price, found = p.Price, true
+	return
+}
+
+// recordPrice saves the price of a product as an applied price change effective now.
+func recordPrice(ctx context.Context, rpr internal.RepositoryPrice, p internal.Product) (err error) {
+	err = rpr.Save(ctx, &internal.PriceChange{
+		ProductId:   p.Id,
+		Price:       p.Price,
+		EffectiveAt: time.Now(),
+		Status:      internal.PriceStatusApplied,
+		Tenant:      p.Tenant,
+	})
+	return
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// NewRepositoryProductStocked creates a new repository for products that raises an alert when a product falls low on stock.
+func NewRepositoryProductStocked(rp internal.RepositoryProduct, rt internal.RepositoryReorderThreshold, sink internal.AlertSink) *RepositoryProductStocked {
+	return &RepositoryProductStocked{
+		rp:   rp,
+		rt:   rt,
+		sink: sink,
+	}
+}
+
+// RepositoryProductStocked is a repository for products that raises an alert when a product falls low on stock.
+// - an alert is raised when a product is saved, or updated, with a quantity below its threshold while it was not before
+// - alerts are best effort, a failing sink is logged but does not fail the mutation
+type RepositoryProductStocked struct {
+	// rp is the decorated repository.
+	rp internal.RepositoryProduct
+	// rt is the repository for reorder thresholds.
+	rt internal.RepositoryReorderThreshold
+	// sink is the destination of the alerts.
+	sink internal.AlertSink
+}
+
+// FindById finds a product by id.
+func (r *RepositoryProductStocked) FindById(ctx context.Context, id int) (p internal.Product, err error) {
+	return r.rp.FindById(ctx, id)
+}
+
+// Save saves a product.
+func (r *RepositoryProductStocked) Save(ctx context.Context, p *internal.Product) (err error) {
+	err = r.rp.Save(ctx, p)
+	if err != nil {
+		return
+	}
+
+	r.check(ctx, nil, *p)
+	return
+}
+
+// UpdateOrSave updates or saves a product.
+func (r *RepositoryProductStocked) UpdateOrSave(ctx context.Context, p *internal.Product) (err error) {
+	before, err := r.current(ctx, p.Id)
+	if err != nil {
+		return
+	}
+
+	err = r.rp.UpdateOrSave(ctx, p)
+	if err != nil {
+		return
+	}
+
+	r.check(ctx, before, *p)
+	return
+}
+
+// Update updates a product.
+func (r *RepositoryProductStocked) Update(ctx context.Context, p *internal.Product) (err error) {
+	before, err := r.current(ctx, p.Id)
+	if err != nil {
+		return
+	}
+
+	err = r.rp.Update(ctx, p)
+	if err != nil {
+		return
+	}
+
+	r.check(ctx, before, *p)
+	return
+}
+
+// Delete soft deletes a product.
+func (r *RepositoryProductStocked) Delete(ctx context.Context, id int, version int) (err error) {
+	return r.rp.Delete(ctx, id, version)
+}
+
+// Restore restores a soft deleted product.
+func (r *RepositoryProductStocked) Restore(ctx context.Context, id int) (err error) {
+	return r.rp.Restore(ctx, id)
+}
+
+// Purge permanently removes the products soft deleted before a time.
+func (r *RepositoryProductStocked) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	return r.rp.Purge(ctx, before)
+}
+
+// GetAll returns all products.
+func (r *RepositoryProductStocked) GetAll(ctx context.Context) (p []internal.Product, err error) {
+	return r.rp.GetAll(ctx)
+}
+
+// GetAllIncludingDeleted returns all products, soft deleted ones included.
+func (r *RepositoryProductStocked) GetAllIncludingDeleted(ctx context.Context) (p []internal.Product, err error) {
+	return r.rp.GetAllIncludingDeleted(ctx)
+}
+
+// Batch applies all the operations or none of them.
+// - alerts are raised once the batch succeeds
+func (r *RepositoryProductStocked) Batch(ctx context.Context, ops []internal.ProductOperation) (p []internal.Product, err error) {
+	befores := make([]*internal.Product, len(ops))
+	for i, op := range ops {
+		if op.Kind != internal.ProductOperationUpdate {
+			continue
+		}
+		befores[i], err = r.current(ctx, op.Product.Id)
+		if err != nil {
+			return
+		}
+	}
+
+	p, err = r.rp.Batch(ctx, ops)
+	if err != nil {
+		return
+	}
+
+	for i, op := range ops {
+		if op.Kind == internal.ProductOperationDelete {
+			continue
+		}
+		r.check(ctx, befores[i], p[i])
+	}
+
+	return
+}
+
+// FindExpiring returns the products expiring between two dates.
+func (r *RepositoryProductStocked) FindExpiring(ctx context.Context, from, to time.Time, warehouseId int) (p []internal.Product, err error) {
+	return r.rp.FindExpiring(ctx, from, to, warehouseId)
+}
+
+// FindExpired returns the products expired before a date.
+func (r *RepositoryProductStocked) FindExpired(ctx context.Context, before time.Time, warehouseId int) (p []internal.Product, err error) {
+	return r.rp.FindExpired(ctx, before, warehouseId)
+}
+
+// UnpublishExpired unpublishes the published products expired before a date.
+func (r *RepositoryProductStocked) UnpublishExpired(ctx context.Context, before time.Time) (p []internal.Product, err error) {
+	return r.rp.UnpublishExpired(ctx, before)
+}
+
+// current returns the product as it is before a mutation, nil if it does not exist.
+func (r *RepositoryProductStocked) current(ctx context.Context, id int) (p *internal.Product, err error) {
+	v, err := r.rp.FindById(ctx, id)
+	if err != nil {
+		if errors.Is(err, internal.ErrRepositoryProductNotFound) {
+			err = nil
+		}
+		return
+	}
+
+	p = &v
+	return
+}
+
+// check raises an alert if a product fell low on stock, before is nil for a new product.
+func (r *RepositoryProductStocked) check(ctx context.Context, before *internal.Product, after internal.Product) {
+	ts, err := r.rt.GetAll(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "low stock check failed", "product_id", after.Id, "error", err)
+		return
+	}
+	thresholds := internal.NewReorderThresholds(ts)
+
+	q, low := thresholds.IsLow(after)
+	if !low {
+		return
+	}
+	if before != nil {
+		if _, wasLow := thresholds.IsLow(*before); wasLow {
+			return
+		}
+	}
+
+	err = r.sink.Alert(ctx, internal.LowStockAlert{
+		Product:   after,
+		Threshold: q,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "low stock check failed", "product_id", after.Id, "error", err)
+	}
+}
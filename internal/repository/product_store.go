@@ -1,6 +1,11 @@
 package repository
 
-import "app/internal"
+import (
+	"app/internal"
+	"context"
+	"sort"
+	"time"
+)
 
 // NewRepositoryProductStore creates a new repository for products.
 func NewRepositoryProductStore(st internal.StoreProduct) (r *RepositoryProductStore) {
@@ -17,7 +22,7 @@ type RepositoryProductStore struct {
 }
 
 // FindById finds a product by id.
-func (r *RepositoryProductStore) FindById(id int) (p internal.Product, err error) {
+func (r *RepositoryProductStore) FindById(ctx context.Context, id int) (p internal.Product, err error) {
 	// read all products
 	ps, err := r.st.ReadAll()
 	if err != nil {
@@ -25,8 +30,10 @@ func (r *RepositoryProductStore) FindById(id int) (p internal.Product, err error
 	}
 
 	// find product
+	// - the products of other tenants are not found
 	p, ok := ps[id]
-	if !ok {
+	if !ok || !p.DeletedAt.IsZero() || !internal.InTenant(ctx, p.Tenant) {
+		p = internal.Product{}
 		err = internal.ErrRepositoryProductNotFound
 		return
 	}
@@ -35,7 +42,7 @@ func (r *RepositoryProductStore) FindById(id int) (p internal.Product, err error
 }
 
 // Save saves a product.
-func (r *RepositoryProductStore) Save(p *internal.Product) (err error) {
+func (r *RepositoryProductStore) Save(ctx context.Context, p *internal.Product) (err error) {
 	// read all products
 	ps, err := r.st.ReadAll()
 	if err != nil {
@@ -58,6 +65,8 @@ func (r *RepositoryProductStore) Save(p *internal.Product) (err error) {
 
 	// set id
 	(*p).Id = maxId + 1
+	(*p).Tenant = tenantOf(ctx, p.Tenant)
+	(*p).Version = 1
 
 	// add product
 	ps[p.Id] = *p
@@ -72,7 +81,7 @@ func (r *RepositoryProductStore) Save(p *internal.Product) (err error) {
 }
 
 // UpdateOrSave updates or saves a product.
-func (r *RepositoryProductStore) UpdateOrSave(p *internal.Product) (err error) {
+func (r *RepositoryProductStore) UpdateOrSave(ctx context.Context, p *internal.Product) (err error) {
 	// read all products
 	ps, err := r.st.ReadAll()
 	if err != nil {
@@ -80,9 +89,17 @@ func (r *RepositoryProductStore) UpdateOrSave(p *internal.Product) (err error) {
 	}
 
 	// update product
-	_, ok := ps[p.Id]
-	switch ok {
+	// - a soft deleted product is not updated, a new one is saved instead
+	// - nor is a product of another tenant
+	current, ok := ps[p.Id]
+	switch ok && current.DeletedAt.IsZero() && internal.InTenant(ctx, current.Tenant) {
 	case true:
+		if p.Version != 0 && p.Version != current.Version {
+			err = internal.ErrRepositoryProductVersionConflict
+			return
+		}
+		(*p).Tenant = current.Tenant
+		(*p).Version = current.Version + 1
 		ps[p.Id] = *p
 	default:
 		// find max id
@@ -101,6 +118,8 @@ func (r *RepositoryProductStore) UpdateOrSave(p *internal.Product) (err error) {
 
 		// set id
 		(*p).Id = maxId + 1
+		(*p).Tenant = tenantOf(ctx, p.Tenant)
+		(*p).Version = 1
 
 		// add product
 		ps[p.Id] = *p
@@ -116,7 +135,7 @@ func (r *RepositoryProductStore) UpdateOrSave(p *internal.Product) (err error) {
 }
 
 // Update updates a product.
-func (r *RepositoryProductStore) Update(p *internal.Product) (err error) {
+func (r *RepositoryProductStore) Update(ctx context.Context, p *internal.Product) (err error) {
 	// read all products
 	ps, err := r.st.ReadAll()
 	if err != nil {
@@ -124,13 +143,19 @@ func (r *RepositoryProductStore) Update(p *internal.Product) (err error) {
 	}
 
 	// update product
-	_, ok := ps[p.Id]
-	if !ok {
+	current, ok := ps[p.Id]
+	if !ok || !current.DeletedAt.IsZero() || !internal.InTenant(ctx, current.Tenant) {
 		err = internal.ErrRepositoryProductNotFound
 		return
 	}
+	if p.Version != 0 && p.Version != current.Version {
+		err = internal.ErrRepositoryProductVersionConflict
+		return
+	}
 
 	// update product
+	(*p).Tenant = current.Tenant
+	(*p).Version = current.Version + 1
 	ps[p.Id] = *p
 
 	// write all products
@@ -142,8 +167,8 @@ func (r *RepositoryProductStore) Update(p *internal.Product) (err error) {
 	return
 }
 
-// Delete deletes a product.
-func (r *RepositoryProductStore) Delete(id int) (err error) {
+// Delete soft deletes a product.
+func (r *RepositoryProductStore) Delete(ctx context.Context, id int, version int) (err error) {
 	// read all products
 	ps, err := r.st.ReadAll()
 	if err != nil {
@@ -151,24 +176,275 @@ func (r *RepositoryProductStore) Delete(id int) (err error) {
 	}
 
 	// delete product
-	_, ok := ps[id]
-	if !ok {
+	p, ok := ps[id]
+	if !ok || !p.DeletedAt.IsZero() || !internal.InTenant(ctx, p.Tenant) {
 		err = internal.ErrRepositoryProductNotFound
 		return
 	}
+	if version != 0 && version != p.Version {
+		err = internal.ErrRepositoryProductVersionConflict
+		return
+	}
 
 	// delete product
-	delete(ps, id)
+	p.DeletedAt = time.Now()
+	p.Version++
+	ps[id] = p
+
+	// write all products
+	err = r.st.WriteAll(ps)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Restore restores a soft deleted product.
+func (r *RepositoryProductStore) Restore(ctx context.Context, id int) (err error) {
+	// read all products
+	ps, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// find deleted product
+	p, ok := ps[id]
+	if !ok || p.DeletedAt.IsZero() || !internal.InTenant(ctx, p.Tenant) {
+		err = internal.ErrRepositoryProductNotFound
+		return
+	}
+
+	// restore product
+	p.DeletedAt = time.Time{}
+	p.Version++
+	ps[id] = p
+
+	// write all products
+	err = r.st.WriteAll(ps)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Purge permanently removes the products soft deleted before a time.
+func (r *RepositoryProductStore) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	// read all products
+	ps, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// remove products
+	for id, p := range ps {
+		if !p.DeletedAt.IsZero() && p.DeletedAt.Before(before) && internal.InTenant(ctx, p.Tenant) {
+			delete(ps, id)
+			n++
+		}
+	}
+	if n == 0 {
+		return
+	}
+
+	// write all products
+	err = r.st.WriteAll(ps)
+	if err != nil {
+		n = 0
+		return
+	}
+
+	return
+}
+
+// GetAll returns all products sorted by id.
+func (r *RepositoryProductStore) GetAll(ctx context.Context) (p []internal.Product, err error) {
+	// read all products
+	ps, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	for _, v := range sortedProducts(ps) {
+		if !v.DeletedAt.IsZero() || !internal.InTenant(ctx, v.Tenant) {
+			continue
+		}
+		p = append(p, v)
+	}
+	return
+}
+
+// GetAllIncludingDeleted returns all products sorted by id, soft deleted ones included.
+func (r *RepositoryProductStore) GetAllIncludingDeleted(ctx context.Context) (p []internal.Product, err error) {
+	// read all products
+	ps, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	for _, v := range sortedProducts(ps) {
+		if !internal.InTenant(ctx, v.Tenant) {
+			continue
+		}
+		p = append(p, v)
+	}
+	return
+}
+
+// sortedProducts returns the products sorted by id.
+func sortedProducts(ps map[int]internal.Product) (p []internal.Product) {
+	for _, v := range ps {
+		p = append(p, v)
+	}
+	sort.Slice(p, func(i, j int) bool {
+		return p[i].Id < p[j].Id
+	})
+	return
+}
+
+// Batch applies all the operations or none of them.
+// - operations are applied in memory and written to the store at once
+func (r *RepositoryProductStore) Batch(ctx context.Context, ops []internal.ProductOperation) (p []internal.Product, err error) {
+	// read all products
+	ps, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// find max id
+	var maxId int
+	for k := range ps {
+		if k > maxId {
+			maxId = k
+		}
+	}
+
+	// apply operations
+	p = make([]internal.Product, len(ops))
+	for i, op := range ops {
+		p[i] = op.Product
+
+		switch op.Kind {
+		case internal.ProductOperationCreate:
+			maxId++
+			p[i].Id = maxId
+			p[i].Tenant = tenantOf(ctx, p[i].Tenant)
+			p[i].Version = 1
+			ps[p[i].Id] = p[i]
+		case internal.ProductOperationUpdate:
+			current, ok := ps[p[i].Id]
+			if !ok || !current.DeletedAt.IsZero() || !internal.InTenant(ctx, current.Tenant) {
+				err = internal.ErrRepositoryProductNotFound
+				break
+			}
+			p[i].Tenant = current.Tenant
+			p[i].WarehouseId = current.WarehouseId
+			p[i].Version = current.Version + 1
+			ps[p[i].Id] = p[i]
+		case internal.ProductOperationDelete:
+			current, ok := ps[p[i].Id]
+			if !ok || !current.DeletedAt.IsZero() || !internal.InTenant(ctx, current.Tenant) {
+				err = internal.ErrRepositoryProductNotFound
+				break
+			}
+			if p[i].Version != 0 && p[i].Version != current.Version {
+				err = internal.ErrRepositoryProductVersionConflict
+				break
+			}
+			current.DeletedAt = time.Now()
+			current.Version++
+			ps[p[i].Id] = current
+		default:
+			err = internal.ErrRepositoryProductOperationInvalid
+		}
+		if err != nil {
+			p = nil
+			err = &internal.ProductOperationError{Index: i, Err: err}
+			return
+		}
+	}
 
 	// write all products
 	err = r.st.WriteAll(ps)
 	if err != nil {
+		p = nil
 		return
 	}
 
 	return
 }
 
-func (r *RepositoryProductStore) GetAll() (p []internal.Product, err error) {
-	return nil, nil
+// FindExpiring returns the products expiring between two dates, both included, sorted by expiration.
+func (r *RepositoryProductStore) FindExpiring(ctx context.Context, from, to time.Time, warehouseId int) (p []internal.Product, err error) {
+	p, err = r.findByExpiration(ctx, func(v internal.Product) bool {
+		return !v.Expiration.Before(from) && !v.Expiration.After(to) && (warehouseId == 0 || v.WarehouseId == warehouseId)
+	})
+	return
+}
+
+// FindExpired returns the products expired before a date sorted by expiration.
+func (r *RepositoryProductStore) FindExpired(ctx context.Context, before time.Time, warehouseId int) (p []internal.Product, err error) {
+	p, err = r.findByExpiration(ctx, func(v internal.Product) bool {
+		return v.Expiration.Before(before) && (warehouseId == 0 || v.WarehouseId == warehouseId)
+	})
+	return
+}
+
+// UnpublishExpired unpublishes the published products expired before a date, returning them.
+func (r *RepositoryProductStore) UnpublishExpired(ctx context.Context, before time.Time) (p []internal.Product, err error) {
+	// read all products
+	ps, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// unpublish products
+	for _, v := range sortedProductsByExpiration(ps) {
+		if !v.DeletedAt.IsZero() || !v.IsPublished || !v.Expiration.Before(before) || !internal.InTenant(ctx, v.Tenant) {
+			continue
+		}
+		v.IsPublished = false
+		v.Version++
+		ps[v.Id] = v
+		p = append(p, v)
+	}
+	if len(p) == 0 {
+		return
+	}
+
+	// write all products
+	err = r.st.WriteAll(ps)
+	if err != nil {
+		p = nil
+		return
+	}
+
+	return
+}
+
+// findByExpiration returns the products of the tenant of the context not soft deleted matching a filter, sorted by expiration.
+func (r *RepositoryProductStore) findByExpiration(ctx context.Context, filter func(v internal.Product) bool) (p []internal.Product, err error) {
+	// read all products
+	ps, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	for _, v := range sortedProductsByExpiration(ps) {
+		if !v.DeletedAt.IsZero() || !internal.InTenant(ctx, v.Tenant) || !filter(v) {
+			continue
+		}
+		p = append(p, v)
+	}
+	return
+}
+
+// sortedProductsByExpiration returns the products sorted by expiration, then by id.
+func sortedProductsByExpiration(ps map[int]internal.Product) (p []internal.Product) {
+	p = sortedProducts(ps)
+	sort.SliceStable(p, func(i, j int) bool {
+		return p[i].Expiration.Before(p[j].Expiration)
+	})
+	return
 }
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"time"
+)
+
+// NewRepositoryProductTransactional creates a new repository for products that runs every mutation in its own unit of work.
+func NewRepositoryProductTransactional(rp internal.RepositoryProduct, uow internal.UnitOfWork) *RepositoryProductTransactional {
+	return &RepositoryProductTransactional{
+		rp:  rp,
+		uow: uow,
+	}
+}
+
+// RepositoryProductTransactional is a repository for products that runs every mutation in its own unit of work.
+// - the mutations get the decorations of the unit of work, e.g. the audit log and the outbox
+// - the reads are made on the decorated repository
+type RepositoryProductTransactional struct {
+	// rp is the decorated repository.
+	rp internal.RepositoryProduct
+	// uow is the unit of work the mutations run in.
+	uow internal.UnitOfWork
+}
+
+// FindById finds a product by id.
+func (r *RepositoryProductTransactional) FindById(ctx context.Context, id int) (p internal.Product, err error) {
+	return r.rp.FindById(ctx, id)
+}
+
+// Save saves a product.
+func (r *RepositoryProductTransactional) Save(ctx context.Context, p *internal.Product) (err error) {
+	err = r.uow.Do(ctx, func(rt internal.RepositoriesTx) (err error) {
+		return rt.Product.Save(ctx, p)
+	})
+	return
+}
+
+// UpdateOrSave updates or saves a product.
+func (r *RepositoryProductTransactional) UpdateOrSave(ctx context.Context, p *internal.Product) (err error) {
+	err = r.uow.Do(ctx, func(rt internal.RepositoriesTx) (err error) {
+		return rt.Product.UpdateOrSave(ctx, p)
+	})
+	return
+}
+
+// Update updates a product.
+func (r *RepositoryProductTransactional) Update(ctx context.Context, p *internal.Product) (err error) {
+	err = r.uow.Do(ctx, func(rt internal.RepositoriesTx) (err error) {
+		return rt.Product.Update(ctx, p)
+	})
+	return
+}
+
+// Delete soft deletes a product.
+func (r *RepositoryProductTransactional) Delete(ctx context.Context, id int, version int) (err error) {
+	err = r.uow.Do(ctx, func(rt internal.RepositoriesTx) (err error) {
+		return rt.Product.Delete(ctx, id, version)
+	})
+	return
+}
+
+// Restore restores a soft deleted product.
+func (r *RepositoryProductTransactional) Restore(ctx context.Context, id int) (err error) {
+	err = r.uow.Do(ctx, func(rt internal.RepositoriesTx) (err error) {
+		return rt.Product.Restore(ctx, id)
+	})
+	return
+}
+
+// Purge permanently removes the products soft deleted before a time.
+func (r *RepositoryProductTransactional) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	err = r.uow.Do(ctx, func(rt internal.RepositoriesTx) (err error) {
+		n, err = rt.Product.Purge(ctx, before)
+		return
+	})
+	return
+}
+
+// GetAll returns all products.
+func (r *RepositoryProductTransactional) GetAll(ctx context.Context) (p []internal.Product, err error) {
+	return r.rp.GetAll(ctx)
+}
+
+// GetAllIncludingDeleted returns all products, soft deleted ones included.
+func (r *RepositoryProductTransactional) GetAllIncludingDeleted(ctx context.Context) (p []internal.Product, err error) {
+	return r.rp.GetAllIncludingDeleted(ctx)
+}
+
+// Batch applies all the operations or none of them.
+func (r *RepositoryProductTransactional) Batch(ctx context.Context, ops []internal.ProductOperation) (p []internal.Product, err error) {
+	err = r.uow.Do(ctx, func(rt internal.RepositoriesTx) (err error) {
+		p, err = rt.Product.Batch(ctx, ops)
+		return
+	})
+	if err != nil {
+		p = nil
+	}
+	return
+}
+
+// FindExpiring returns the products expiring between two dates.
+func (r *RepositoryProductTransactional) FindExpiring(ctx context.Context, from, to time.Time, warehouseId int) (p []internal.Product, err error) {
+	return r.rp.FindExpiring(ctx, from, to, warehouseId)
+}
+
+// FindExpired returns the products expired before a date.
+func (r *RepositoryProductTransactional) FindExpired(ctx context.Context, before time.Time, warehouseId int) (p []internal.Product, err error) {
+	return r.rp.FindExpired(ctx, before, warehouseId)
+}
+
+// UnpublishExpired unpublishes the published products expired before a date.
+func (r *RepositoryProductTransactional) UnpublishExpired(ctx context.Context, before time.Time) (p []internal.Product, err error) {
+	err = r.uow.Do(ctx, func(rt internal.RepositoriesTx) (err error) {
+		p, err = rt.Product.UnpublishExpired(ctx, before)
+		return
+	})
+	if err != nil {
+		p = nil
+	}
+	return
+}
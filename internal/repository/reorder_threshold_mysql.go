@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"database/sql"
+)
+
+// NewRepositoryReorderThresholdMysql creates a new repository for reorder thresholds backed by mysql.
+func NewRepositoryReorderThresholdMysql(db *sql.DB) *ReorderThresholdMysql {
+	return &ReorderThresholdMysql{
+		ex: traced(db),
+	}
+}
+
+// ReorderThresholdMysql is a repository for reorder thresholds backed by mysql.
+type ReorderThresholdMysql struct {
+	// ex runs the queries, it is the db itself or the transaction the repository is bound to
+	ex executor
+}
+
+// FindByScope returns the threshold of a scope.
+func (r *ReorderThresholdMysql) FindByScope(ctx context.Context, s internal.ReorderScope) (t internal.ReorderThreshold, err error) {
+	tn := internal.TenantFromContext(ctx)
+	err = r.ex.QueryRowContext(ctx, "SELECT `scope`, `scope_id`, `quantity` FROM `reorder_thresholds` WHERE `scope` = ? AND `scope_id` = ? AND (? = '*' OR `tenant_id` = ?)", s.Kind, s.Id, tn, tn).Scan(&t.Kind, &t.Id, &t.Quantity)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = internal.ErrRepositoryReorderThresholdNotFound
+		}
+		return
+	}
+
+	return
+}
+
+// Save saves the threshold of a scope, replacing the previous one.
+func (r *ReorderThresholdMysql) Save(ctx context.Context, t *internal.ReorderThreshold) (err error) {
+	err = t.Validate()
+	if err != nil {
+		return
+	}
+
+	_, err = r.ex.ExecContext(ctx, "INSERT INTO `reorder_thresholds` (`scope`, `scope_id`, `tenant_id`, `quantity`) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE `quantity` = VALUES(`quantity`)", t.Kind, t.Id, tenantOf(ctx, ""), t.Quantity)
+	return
+}
+
+// Delete deletes the threshold of a scope.
+func (r *ReorderThresholdMysql) Delete(ctx context.Context, s internal.ReorderScope) (err error) {
+	t := internal.TenantFromContext(ctx)
+	res, err := r.ex.ExecContext(ctx, "DELETE FROM `reorder_thresholds` WHERE `scope` = ? AND `scope_id` = ? AND (? = '*' OR `tenant_id` = ?)", s.Kind, s.Id, t, t)
+	if err != nil {
+		return
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if n == 0 {
+		err = internal.ErrRepositoryReorderThresholdNotFound
+		return
+	}
+
+	return
+}
+
+// GetAll returns all thresholds sorted by scope kind and id.
+func (r *ReorderThresholdMysql) GetAll(ctx context.Context) (t []internal.ReorderThreshold, err error) {
+	tn := internal.TenantFromContext(ctx)
+	rows, err := r.ex.QueryContext(ctx, "SELECT `scope`, `scope_id`, `quantity` FROM `reorder_thresholds` WHERE (? = '*' OR `tenant_id` = ?) ORDER BY `scope`, `scope_id`", tn, tn)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v internal.ReorderThreshold
+		err = rows.Scan(&v.Kind, &v.Id, &v.Quantity)
+		if err != nil {
+			return
+		}
+		t = append(t, v)
+	}
+	err = rows.Err()
+	return
+}
@@ -0,0 +1,52 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorderThresholdMysql_Save(t *testing.T) {
+
+	t.Run("success - replaces the previous threshold", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `reorder_thresholds` (`scope`, `scope_id`, `quantity`) VALUES ('product', 1, 5)")
+			require.NoError(t, err)
+		}(db)
+
+		rp := repository.NewRepositoryReorderThresholdMysql(db)
+		th := internal.ReorderThreshold{ReorderScope: internal.ReorderScope{Kind: internal.ReorderScopeProduct, Id: 1}, Quantity: 10}
+
+		//act
+		err := rp.Save(context.Background(), &th)
+
+		//assert
+		require.NoError(t, err)
+		found, err := rp.FindByScope(context.Background(), th.ReorderScope)
+		require.NoError(t, err)
+		require.Equal(t, th, found)
+	})
+
+	t.Run("fail - unknown scope", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		rp := repository.NewRepositoryReorderThresholdMysql(db)
+		th := internal.ReorderThreshold{ReorderScope: internal.ReorderScope{Kind: "shelf", Id: 1}, Quantity: 10}
+
+		//act
+		err := rp.Save(context.Background(), &th)
+
+		//assert
+		require.ErrorIs(t, err, internal.ErrReorderThresholdInvalid)
+	})
+
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"sort"
+	"sync"
+)
+
+// NewRepositoryReorderThresholdStore creates a new repository for reorder thresholds.
+func NewRepositoryReorderThresholdStore(st internal.StoreReorderThreshold) (r *RepositoryReorderThresholdStore) {
+	r = &RepositoryReorderThresholdStore{
+		st: st,
+	}
+	return
+}
+
+// RepositoryReorderThresholdStore is a repository for reorder thresholds.
+// - each tenant has thresholds of its own
+type RepositoryReorderThresholdStore struct {
+	// mu serializes the reads and writes of the store.
+	mu sync.Mutex
+	// st is the underlying store.
+	st internal.StoreReorderThreshold
+}
+
+// FindByScope returns the threshold of a scope.
+func (r *RepositoryReorderThresholdStore) FindByScope(ctx context.Context, s internal.ReorderScope) (t internal.ReorderThreshold, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// read all thresholds
+	ts, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// find threshold
+	// - the thresholds of other tenants are not found
+	for tenant, v := range ts {
+		if !internal.InTenant(ctx, tenant) {
+			continue
+		}
+		var ok bool
+		if t, ok = v[s]; ok {
+			return
+		}
+	}
+
+	err = internal.ErrRepositoryReorderThresholdNotFound
+	return
+}
+
+// Save saves the threshold of a scope, replacing the previous one.
+func (r *RepositoryReorderThresholdStore) Save(ctx context.Context, t *internal.ReorderThreshold) (err error) {
+	err = t.Validate()
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// read all thresholds
+	ts, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// save threshold
+	tenant := tenantOf(ctx, "")
+	if ts[tenant] == nil {
+		ts[tenant] = make(map[internal.ReorderScope]internal.ReorderThreshold)
+	}
+	ts[tenant][t.ReorderScope] = *t
+
+	// write all thresholds
+	err = r.st.WriteAll(ts)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Delete deletes the threshold of a scope.
+func (r *RepositoryReorderThresholdStore) Delete(ctx context.Context, s internal.ReorderScope) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// read all thresholds
+	ts, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// delete threshold
+	// - the thresholds of other tenants are not found
+	var found bool
+	for tenant, v := range ts {
+		if _, ok := v[s]; !ok || !internal.InTenant(ctx, tenant) {
+			continue
+		}
+		delete(v, s)
+		found = true
+	}
+	if !found {
+		err = internal.ErrRepositoryReorderThresholdNotFound
+		return
+	}
+
+	// write all thresholds
+	err = r.st.WriteAll(ts)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// GetAll returns all thresholds sorted by scope kind and id.
+func (r *RepositoryReorderThresholdStore) GetAll(ctx context.Context) (t []internal.ReorderThreshold, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// read all thresholds
+	ts, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	for tenant, v := range ts {
+		if !internal.InTenant(ctx, tenant) {
+			continue
+		}
+		for _, w := range v {
+			t = append(t, w)
+		}
+	}
+	sort.Slice(t, func(i, j int) bool {
+		if t[i].Kind != t[j].Kind {
+			return t[i].Kind < t[j].Kind
+		}
+		return t[i].Id < t[j].Id
+	})
+	return
+}
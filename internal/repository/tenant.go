@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+)
+
+// tenantOf returns the tenant an entity is saved to: the one of the context, or its own across tenants.
+// - an entity without tenant saved across tenants is of the default one
+func tenantOf(ctx context.Context, tenant string) string {
+	t := internal.TenantFromContext(ctx)
+	if t != internal.TenantAll {
+		return t
+	}
+	if tenant == "" {
+		return internal.TenantDefault
+	}
+	return tenant
+}
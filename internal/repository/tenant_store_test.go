@@ -0,0 +1,120 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/store"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for the isolation of the tenants of the store repositories
+func TestRepositoryStore_Tenant(t *testing.T) {
+	acme := internal.ContextWithTenant(context.Background(), "acme")
+	globex := internal.ContextWithTenant(context.Background(), "globex")
+
+	t.Run("success - products saved in the tenant of the context", func(t *testing.T) {
+		// arrange
+		st := store.NewStoreProductMemory(map[int]internal.Product{})
+		rp := repository.NewRepositoryProductStore(st)
+		p := internal.Product{ProductAttributes: internal.ProductAttributes{Name: "product 1"}}
+
+		// act
+		err := rp.Save(acme, &p)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "acme", p.Tenant)
+		found, err := rp.FindById(acme, p.Id)
+		require.NoError(t, err)
+		require.Equal(t, p, found)
+	})
+
+	t.Run("fail - products of another tenant not found", func(t *testing.T) {
+		// arrange
+		st := store.NewStoreProductMemory(map[int]internal.Product{
+			1: {Id: 1, Tenant: "acme", Version: 1},
+			2: {Id: 2, Tenant: "globex", Version: 1},
+			3: {Id: 3, Version: 1},
+		})
+		rp := repository.NewRepositoryProductStore(st)
+
+		// act
+		_, errFind := rp.FindById(globex, 1)
+		errUpdate := rp.Update(globex, &internal.Product{Id: 1})
+		errDelete := rp.Delete(globex, 1, 0)
+		ps, errGetAll := rp.GetAll(globex)
+
+		// assert
+		require.ErrorIs(t, errFind, internal.ErrRepositoryProductNotFound)
+		require.ErrorIs(t, errUpdate, internal.ErrRepositoryProductNotFound)
+		require.ErrorIs(t, errDelete, internal.ErrRepositoryProductNotFound)
+		require.NoError(t, errGetAll)
+		require.Len(t, ps, 1)
+		require.Equal(t, 2, ps[0].Id)
+		stored, err := st.ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, internal.Product{Id: 1, Tenant: "acme", Version: 1}, stored[1])
+	})
+
+	t.Run("success - products without tenant of the default tenant", func(t *testing.T) {
+		// arrange
+		rp := repository.NewRepositoryProductStore(store.NewStoreProductMemory(map[int]internal.Product{
+			1: {Id: 1, Tenant: "acme", Version: 1},
+			2: {Id: 2, Version: 1},
+		}))
+
+		// act
+		ps, err := rp.GetAll(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, ps, 1)
+		require.Equal(t, 2, ps[0].Id)
+	})
+
+	t.Run("success - every tenant purged with all tenants", func(t *testing.T) {
+		// arrange
+		now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		st := store.NewStoreProductMemory(map[int]internal.Product{
+			1: {Id: 1, Tenant: "acme", Version: 1, DeletedAt: now.Add(-time.Hour)},
+			2: {Id: 2, Tenant: "globex", Version: 1, DeletedAt: now.Add(-time.Hour)},
+		})
+		rp := repository.NewRepositoryProductStore(st)
+
+		// act
+		nTenant, errTenant := rp.Purge(acme, now)
+		nAll, errAll := rp.Purge(internal.ContextWithTenant(context.Background(), internal.TenantAll), now)
+
+		// assert
+		require.NoError(t, errTenant)
+		require.Equal(t, 1, nTenant)
+		require.NoError(t, errAll)
+		require.Equal(t, 1, nAll)
+	})
+
+	t.Run("fail - warehouses of another tenant not found", func(t *testing.T) {
+		// arrange
+		stProduct := store.NewStoreProductMemory(map[int]internal.Product{
+			1: {Id: 1, Tenant: "acme", Version: 1, WarehouseId: 1},
+		})
+		rw := repository.NewRepositoryWarehouseStore(store.NewStoreWarehouseMemory(map[int]internal.Warehouse{
+			1: {Id: 1, Tenant: "acme", Version: 1},
+		}), stProduct)
+
+		// act
+		_, errFind := rw.FindById(globex, 1)
+		ws, errGetAll := rw.GetAll(globex)
+		report, errReport := rw.ReportProducts(globex, 0)
+
+		// assert
+		require.ErrorIs(t, errFind, internal.ErrRepositoryWarehouseNotFound)
+		require.NoError(t, errGetAll)
+		require.Empty(t, ws)
+		require.NoError(t, errReport)
+		require.Empty(t, report)
+	})
+}
@@ -0,0 +1,218 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/store"
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for the isolation of the tenants of the exchange rates, against the json store and mysql
+func TestRepositoryExchangeRate_Tenant(t *testing.T) {
+	acme := internal.ContextWithTenant(context.Background(), "acme")
+	globex := internal.ContextWithTenant(context.Background(), "globex")
+
+	backends := []struct {
+		name string
+		new  func(t *testing.T) internal.RepositoryExchangeRate
+	}{
+		{name: "store", new: func(t *testing.T) internal.RepositoryExchangeRate {
+			return repository.NewRepositoryExchangeRateStore(store.NewStoreExchangeRateJSON(filepath.Join(t.TempDir(), "exchange_rates.json")))
+		}},
+		{name: "mysql", new: func(t *testing.T) internal.RepositoryExchangeRate {
+			db := openTxdb(t)
+			t.Cleanup(func() { db.Close() })
+			return repository.NewRepositoryExchangeRateMysql(db)
+		}},
+	}
+
+	for _, b := range backends {
+		t.Run(b.name+" - rates of a tenant neither read nor changed by another", func(t *testing.T) {
+			// arrange
+			rr := b.new(t)
+			eurAcme, err := internal.ParseExchangeRate("EUR", "0.92")
+			require.NoError(t, err)
+			require.NoError(t, rr.Save(acme, &eurAcme))
+			eurGlobex, err := internal.ParseExchangeRate("EUR", "0.5")
+			require.NoError(t, err)
+
+			// act
+			errSave := rr.Save(globex, &eurGlobex)
+			errDelete := rr.Delete(globex, "EUR")
+			_, errFind := rr.FindByCurrency(globex, "EUR")
+			all, errGetAll := rr.GetAll(context.Background())
+
+			// assert
+			require.NoError(t, errSave)
+			require.NoError(t, errDelete)
+			require.ErrorIs(t, errFind, internal.ErrRepositoryExchangeRateNotFound)
+			require.NoError(t, errGetAll)
+			require.Empty(t, all)
+			found, err := rr.FindByCurrency(acme, "EUR")
+			require.NoError(t, err)
+			require.Equal(t, "0.92", found.String())
+		})
+	}
+
+	t.Run("store - concurrent rates all saved", func(t *testing.T) {
+		// arrange
+		rr := repository.NewRepositoryExchangeRateStore(store.NewStoreExchangeRateJSON(filepath.Join(t.TempDir(), "exchange_rates.json")))
+		currencies := []string{"AUD", "BRL", "CAD", "CHF", "CNY", "EUR", "GBP", "INR", "JPY", "MXN"}
+
+		// act
+		var wg sync.WaitGroup
+		for _, c := range currencies {
+			wg.Add(1)
+			go func(c string) {
+				defer wg.Done()
+				e, _ := internal.ParseExchangeRate(c, "2")
+				_ = rr.Save(acme, &e)
+			}(c)
+		}
+		wg.Wait()
+
+		// assert
+		all, err := rr.GetAll(acme)
+		require.NoError(t, err)
+		require.Len(t, all, len(currencies))
+	})
+}
+
+// Tests for the isolation of the tenants of the price changes, against the json store and mysql
+func TestRepositoryPrice_Tenant(t *testing.T) {
+	acme := internal.ContextWithTenant(context.Background(), "acme")
+	globex := internal.ContextWithTenant(context.Background(), "globex")
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	backends := []struct {
+		name string
+		new  func(t *testing.T) internal.RepositoryPrice
+	}{
+		{name: "store", new: func(t *testing.T) internal.RepositoryPrice {
+			return repository.NewRepositoryPriceStore(store.NewStorePriceJSON(filepath.Join(t.TempDir(), "prices.json")))
+		}},
+		{name: "mysql", new: func(t *testing.T) internal.RepositoryPrice {
+			db := openTxdb(t)
+			t.Cleanup(func() { db.Close() })
+			return repository.NewRepositoryPriceMysql(db)
+		}},
+	}
+
+	for _, b := range backends {
+		t.Run(b.name+" - price changes of a tenant neither read nor changed by another", func(t *testing.T) {
+			// arrange
+			rpr := b.new(t)
+			c := internal.PriceChange{ProductId: 1, Price: internal.NewMoney(100, internal.CurrencyDefault), EffectiveAt: now, Status: internal.PriceStatusScheduled}
+			require.NoError(t, rpr.Save(acme, &c))
+
+			// act
+			errUpdate := rpr.UpdateStatus(globex, c.Id, internal.PriceStatusCanceled)
+			byProduct, errByProduct := rpr.FindByProduct(globex, 1)
+			due, errDue := rpr.FindDue(globex, now)
+
+			// assert
+			require.ErrorIs(t, errUpdate, internal.ErrRepositoryPriceNotFound)
+			require.NoError(t, errByProduct)
+			require.Empty(t, byProduct)
+			require.NoError(t, errDue)
+			require.Empty(t, due)
+			found, err := rpr.FindByProduct(acme, 1)
+			require.NoError(t, err)
+			require.Len(t, found, 1)
+			require.Equal(t, internal.PriceStatusScheduled, found[0].Status)
+		})
+	}
+}
+
+// Tests for the isolation of the tenants of the outbox messages, against the json store and mysql
+func TestRepositoryOutbox_Tenant(t *testing.T) {
+	acme := internal.ContextWithTenant(context.Background(), "acme")
+	globex := internal.ContextWithTenant(context.Background(), "globex")
+
+	backends := []struct {
+		name string
+		new  func(t *testing.T) internal.RepositoryOutbox
+	}{
+		{name: "store", new: func(t *testing.T) internal.RepositoryOutbox {
+			return repository.NewRepositoryOutboxStore(store.NewStoreOutboxJSON(filepath.Join(t.TempDir(), "outbox.json")))
+		}},
+		{name: "mysql", new: func(t *testing.T) internal.RepositoryOutbox {
+			db := openTxdb(t)
+			t.Cleanup(func() { db.Close() })
+			return repository.NewRepositoryOutboxMysql(db)
+		}},
+	}
+
+	for _, b := range backends {
+		t.Run(b.name+" - messages of a tenant neither read nor published by another", func(t *testing.T) {
+			// arrange
+			ro := b.new(t)
+			m := internal.OutboxMessage{Event: internal.Event{Type: internal.EventProductCreated, EntityId: 1, Tenant: "acme"}}
+			require.NoError(t, ro.Append(acme, &m))
+
+			// act
+			pending, errPending := ro.FindPending(globex, 10)
+			errMark := ro.MarkPublished(globex, m.Id, time.Now())
+
+			// assert
+			require.NoError(t, errPending)
+			require.Empty(t, pending)
+			require.ErrorIs(t, errMark, internal.ErrRepositoryOutboxMessageNotFound)
+			found, err := ro.FindPending(acme, 10)
+			require.NoError(t, err)
+			require.Len(t, found, 1)
+			require.Equal(t, "acme", found[0].Event.Tenant)
+		})
+	}
+}
+
+// Tests for the isolation of the tenants of the reorder thresholds, against the json store and mysql
+func TestRepositoryReorderThreshold_Tenant(t *testing.T) {
+	acme := internal.ContextWithTenant(context.Background(), "acme")
+	globex := internal.ContextWithTenant(context.Background(), "globex")
+	scope := internal.ReorderScope{Kind: internal.ReorderScopeWarehouse, Id: 1}
+
+	backends := []struct {
+		name string
+		new  func(t *testing.T) internal.RepositoryReorderThreshold
+	}{
+		{name: "store", new: func(t *testing.T) internal.RepositoryReorderThreshold {
+			return repository.NewRepositoryReorderThresholdStore(store.NewStoreReorderThresholdJSON(filepath.Join(t.TempDir(), "reorder_thresholds.json")))
+		}},
+		{name: "mysql", new: func(t *testing.T) internal.RepositoryReorderThreshold {
+			db := openTxdb(t)
+			t.Cleanup(func() { db.Close() })
+			return repository.NewRepositoryReorderThresholdMysql(db)
+		}},
+	}
+
+	for _, b := range backends {
+		t.Run(b.name+" - thresholds of a tenant neither read nor changed by another", func(t *testing.T) {
+			// arrange
+			rt := b.new(t)
+			require.NoError(t, rt.Save(acme, &internal.ReorderThreshold{ReorderScope: scope, Quantity: 5}))
+
+			// act
+			errSave := rt.Save(globex, &internal.ReorderThreshold{ReorderScope: scope, Quantity: 7})
+			errDelete := rt.Delete(globex, scope)
+			_, errFind := rt.FindByScope(globex, scope)
+			all, errGetAll := rt.GetAll(context.Background())
+
+			// assert
+			require.NoError(t, errSave)
+			require.NoError(t, errDelete)
+			require.ErrorIs(t, errFind, internal.ErrRepositoryReorderThresholdNotFound)
+			require.NoError(t, errGetAll)
+			require.Empty(t, all)
+			found, err := rt.FindByScope(acme, scope)
+			require.NoError(t, err)
+			require.Equal(t, 5, found.Quantity)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"database/sql"
+)
+
+// NewUnitOfWorkMysql creates a new unit of work backed by mysql transactions.
+func NewUnitOfWorkMysql(db *sql.DB) *UnitOfWorkMysql {
+	return &UnitOfWorkMysql{
+		db: db,
+	}
+}
+
+// UnitOfWorkMysql is a unit of work backed by mysql transactions.
+type UnitOfWorkMysql struct {
+	db *sql.DB
+}
+
+// Do runs fn with repositories bound to a transaction.
+func (u *UnitOfWorkMysql) Do(ctx context.Context, fn func(r internal.RepositoriesTx) (err error)) (err error) {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	err = fn(internal.RepositoriesTx{
+		Product:   &ProductMysql{ex: traced(tx)},
+		Warehouse: &Warehouse{ex: traced(tx)},
+		Price:     &PriceMysql{ex: traced(tx)},
+		Outbox:    &OutboxMysql{ex: traced(tx)},
+		Audit:     &AuditMysql{ex: traced(tx)},
+	})
+	if err != nil {
+		return
+	}
+
+	err = tx.Commit()
+	return
+}
@@ -0,0 +1,80 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitOfWorkMysql_Do(t *testing.T) {
+
+	t.Run("success - committed", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `warehouses` (`id`, `name`, `adress`, `telephone`, `capacity`) VALUES (1, 'warehouse 1', 'address 1', 'telephone 1', 100)")
+			require.NoError(t, err)
+			_, err = db.Exec("INSERT INTO `products` (`id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse`) VALUES (1, 'product 1', 1, 'code_value 1', true, '2021-01-01', 1, 0)")
+			require.NoError(t, err)
+		}(db)
+
+		uow := repository.NewUnitOfWorkMysql(db)
+
+		//act
+		err := uow.Do(context.Background(), func(r internal.RepositoriesTx) (err error) {
+			w, err := r.Warehouse.FindById(context.Background(), 1)
+			if err != nil {
+				return
+			}
+			p, err := r.Product.FindById(context.Background(), 1)
+			if err != nil {
+				return
+			}
+			p.WarehouseId = w.Id
+			err = r.Product.Update(context.Background(), &p)
+			return
+		})
+
+		//assert
+		require.NoError(t, err)
+		p, err := repository.NewRepositoryProductMySql(db).FindById(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, 1, p.WarehouseId)
+	})
+
+	t.Run("fail - rolled back", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `products` (`id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse`) VALUES (1, 'product 1', 1, 'code_value 1', true, '2021-01-01', 1, 0)")
+			require.NoError(t, err)
+		}(db)
+
+		uow := repository.NewUnitOfWorkMysql(db)
+		expectedErr := errors.New("fail")
+
+		//act
+		err := uow.Do(context.Background(), func(r internal.RepositoriesTx) (err error) {
+			err = r.Product.Delete(context.Background(), 1, 0)
+			if err != nil {
+				return
+			}
+			return expectedErr
+		})
+
+		//assert
+		require.ErrorIs(t, err, expectedErr)
+		_, err = repository.NewRepositoryProductMySql(db).FindById(context.Background(), 1)
+		require.NoError(t, err)
+	})
+
+}
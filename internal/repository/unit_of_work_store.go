@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"app/internal"
+	"app/internal/store"
+	"context"
+	"sync"
+	"time"
+)
+
+// NewUnitOfWorkStore creates a new unit of work backed by stores.
+func NewUnitOfWorkStore(stProduct internal.StoreProduct, stWarehouse internal.StoreWarehouse, stPrice internal.StorePrice, stOutbox internal.StoreOutbox) (u *UnitOfWorkStore) {
+	u = &UnitOfWorkStore{
+		stProduct:   stProduct,
+		stWarehouse: stWarehouse,
+		stPrice:     stPrice,
+		stOutbox:    stOutbox,
+	}
+	return
+}
+
+// UnitOfWorkStore is a unit of work backed by stores.
+// - changes are made on in-memory copies and written to the stores only if fn succeeds
+type UnitOfWorkStore struct {
+	// mu serializes the units of work.
+	mu sync.Mutex
+	// stProduct is the store for products.
+	stProduct internal.StoreProduct
+	// stWarehouse is the store for warehouses.
+	stWarehouse internal.StoreWarehouse
+	// stPrice is the store for price changes.
+	stPrice internal.StorePrice
+	// stOutbox is the store for outbox messages.
+	stOutbox internal.StoreOutbox
+}
+
+// Do runs fn with repositories bound to in-memory copies of the stores.
+func (u *UnitOfWorkStore) Do(ctx context.Context, fn func(r internal.RepositoriesTx) (err error)) (err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	// read all
+	ps, err := u.stProduct.ReadAll()
+	if err != nil {
+		return
+	}
+	ws, err := u.stWarehouse.ReadAll()
+	if err != nil {
+		return
+	}
+	cs, err := u.stPrice.ReadAll()
+	if err != nil {
+		return
+	}
+	ms, err := u.stOutbox.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// run
+	stProduct := store.NewStoreProductMemory(ps)
+	stWarehouse := store.NewStoreWarehouseMemory(ws)
+	stPrice := store.NewStorePriceMemory(cs)
+	stOutbox := store.NewStoreOutboxMemory(ms)
+	err = fn(internal.RepositoriesTx{
+		Product:   NewRepositoryProductStore(stProduct),
+		Warehouse: NewRepositoryWarehouseStore(stWarehouse, stProduct),
+		Price:     NewRepositoryPriceStore(stPrice),
+		Outbox:    NewRepositoryOutboxStore(stOutbox),
+	})
+	if err != nil {
+		return
+	}
+
+	// write all
+	// - the stores are independent files, so a failure writing the second one leaves the first one written
+	// - the outbox is written first, so a failure may publish the event of a lost change but never lose the event of a written one
+	ps, err = stProduct.ReadAll()
+	if err != nil {
+		return
+	}
+	ws, err = stWarehouse.ReadAll()
+	if err != nil {
+		return
+	}
+	cs, err = stPrice.ReadAll()
+	if err != nil {
+		return
+	}
+	ms, err = stOutbox.ReadAll()
+	if err != nil {
+		return
+	}
+	err = u.stOutbox.WriteAll(ms)
+	if err != nil {
+		return
+	}
+	err = u.stWarehouse.WriteAll(ws)
+	if err != nil {
+		return
+	}
+	err = u.stProduct.WriteAll(ps)
+	if err != nil {
+		return
+	}
+	err = u.stPrice.WriteAll(cs)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Outbox returns the outbox of the units of work.
+// - its reads and writes are serialized with the units of work, so none of them overwrites the other
+func (u *UnitOfWorkStore) Outbox() internal.RepositoryOutbox {
+	return &outboxStoreLocked{
+		mu: &u.mu,
+		ro: NewRepositoryOutboxStore(u.stOutbox),
+	}
+}
+
+// Compact drops the records the stores no longer need, returning how many were dropped.
+// - the price changes of the products no longer stored, e.g. purged
+// - the published outbox messages, but the last one, which keeps the ids increasing
+// - it runs under the lock of the units of work, the stores write the products and warehouses to the files of their tenants
+func (u *UnitOfWorkStore) Compact(ctx context.Context) (n int, err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	// read all
+	ps, err := u.stProduct.ReadAll()
+	if err != nil {
+		return
+	}
+	cs, err := u.stPrice.ReadAll()
+	if err != nil {
+		return
+	}
+	ms, err := u.stOutbox.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// price changes
+	var dropped int
+	for id, c := range cs {
+		if _, ok := ps[c.ProductId]; !ok {
+			delete(cs, id)
+			dropped++
+		}
+	}
+	if dropped > 0 {
+		err = u.stPrice.WriteAll(cs)
+		if err != nil {
+			return
+		}
+		n += dropped
+	}
+
+	// outbox messages
+	var maxId int
+	for id := range ms {
+		maxId = max(maxId, id)
+	}
+	dropped = 0
+	for id, m := range ms {
+		if id != maxId && !m.PublishedAt.IsZero() {
+			delete(ms, id)
+			dropped++
+		}
+	}
+	if dropped > 0 {
+		err = u.stOutbox.WriteAll(ms)
+		if err != nil {
+			return
+		}
+		n += dropped
+	}
+
+	return
+}
+
+// Price returns the price history of the units of work.
+// - its reads and writes are serialized with the units of work, so none of them overwrites the other
+func (u *UnitOfWorkStore) Price() internal.RepositoryPrice {
+	return &priceStoreLocked{
+		mu: &u.mu,
+		rp: NewRepositoryPriceStore(u.stPrice),
+	}
+}
+
+// priceStoreLocked is a price repository holding a lock on every call.
+type priceStoreLocked struct {
+	mu *sync.Mutex
+	rp internal.RepositoryPrice
+}
+
+// Save saves a price change.
+func (r *priceStoreLocked) Save(ctx context.Context, c *internal.PriceChange) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rp.Save(ctx, c)
+}
+
+// UpdateStatus updates the status of a price change.
+func (r *priceStoreLocked) UpdateStatus(ctx context.Context, id int, status string) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rp.UpdateStatus(ctx, id, status)
+}
+
+// FindByProduct returns the price changes of a product.
+func (r *priceStoreLocked) FindByProduct(ctx context.Context, productId int) (c []internal.PriceChange, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rp.FindByProduct(ctx, productId)
+}
+
+// FindDue returns the scheduled price changes due at a time.
+func (r *priceStoreLocked) FindDue(ctx context.Context, at time.Time) (c []internal.PriceChange, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rp.FindDue(ctx, at)
+}
+
+// outboxStoreLocked is an outbox repository holding a lock on every call.
+type outboxStoreLocked struct {
+	mu *sync.Mutex
+	ro internal.RepositoryOutbox
+}
+
+// Append appends a message to the outbox.
+func (r *outboxStoreLocked) Append(ctx context.Context, m *internal.OutboxMessage) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ro.Append(ctx, m)
+}
+
+// FindPending returns up to limit messages, oldest first.
+func (r *outboxStoreLocked) FindPending(ctx context.Context, limit int) (m []internal.OutboxMessage, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ro.FindPending(ctx, limit)
+}
+
+// MarkPublished marks a message as published.
+func (r *outboxStoreLocked) MarkPublished(ctx context.Context, id int, at time.Time) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ro.MarkPublished(ctx, id, at)
+}
+
+// Purge removes the messages published before a time.
+func (r *outboxStoreLocked) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ro.Purge(ctx, before)
+}
@@ -0,0 +1,60 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/store"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for UnitOfWorkStore.Compact
+func TestUnitOfWorkStore_Compact(t *testing.T) {
+	t.Run("success - the price changes of missing products and the published messages are dropped", func(t *testing.T) {
+		// arrange
+		now := time.Now()
+		stProduct := store.NewStoreProductMemory(map[int]internal.Product{
+			1: {Id: 1},
+		})
+		stPrice := store.NewStorePriceMemory(map[int]internal.PriceChange{
+			1: {Id: 1, ProductId: 1},
+			2: {Id: 2, ProductId: 2},
+		})
+		stOutbox := store.NewStoreOutboxMemory(map[int]internal.OutboxMessage{
+			1: {Id: 1, PublishedAt: now},
+			2: {Id: 2},
+			3: {Id: 3, PublishedAt: now},
+		})
+		uow := repository.NewUnitOfWorkStore(stProduct, store.NewStoreWarehouseMemory(nil), stPrice, stOutbox)
+
+		// act
+		n, err := uow.Compact(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+		cs, err := stPrice.ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, map[int]internal.PriceChange{1: {Id: 1, ProductId: 1}}, cs)
+		ms, err := stOutbox.ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, map[int]internal.OutboxMessage{2: {Id: 2}, 3: {Id: 3, PublishedAt: now}}, ms)
+	})
+
+	t.Run("success - nothing to drop", func(t *testing.T) {
+		// arrange
+		stPrice := store.NewStorePriceMemory(map[int]internal.PriceChange{})
+		stOutbox := store.NewStoreOutboxMemory(map[int]internal.OutboxMessage{})
+		uow := repository.NewUnitOfWorkStore(store.NewStoreProductMemory(nil), store.NewStoreWarehouseMemory(nil), stPrice, stOutbox)
+
+		// act
+		n, err := uow.Compact(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		require.Zero(t, n)
+	})
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"time"
+)
+
+// NewRepositoryWarehouseAudited creates a new repository for warehouses that records its mutations to an audit log.
+func NewRepositoryWarehouseAudited(rp internal.RepositoryWarehouse, ra internal.RepositoryAudit) *RepositoryWarehouseAudited {
+	return &RepositoryWarehouseAudited{
+		rp: rp,
+		ra: ra,
+	}
+}
+
+// RepositoryWarehouseAudited is a repository for warehouses that records its mutations to an audit log.
+type RepositoryWarehouseAudited struct {
+	// rp is the decorated repository.
+	rp internal.RepositoryWarehouse
+	// ra is the audit log.
+	ra internal.RepositoryAudit
+}
+
+// FindById finds a warehouse by id.
+func (r *RepositoryWarehouseAudited) FindById(ctx context.Context, id int) (w internal.Warehouse, err error) {
+	return r.rp.FindById(ctx, id)
+}
+
+// Save saves a warehouse.
+func (r *RepositoryWarehouseAudited) Save(ctx context.Context, w *internal.Warehouse) (err error) {
+	err = r.rp.Save(ctx, w)
+	if err != nil {
+		return
+	}
+
+	err = audit(ctx, r.ra, internal.AuditEntityWarehouse, w.Id, internal.AuditOperationCreate, nil, w)
+	return
+}
+
+// Update updates a warehouse.
+func (r *RepositoryWarehouseAudited) Update(ctx context.Context, w *internal.Warehouse) (err error) {
+	before, err := r.current(ctx, w.Id)
+	if err != nil {
+		return
+	}
+
+	err = r.rp.Update(ctx, w)
+	if err != nil {
+		return
+	}
+
+	err = audit(ctx, r.ra, internal.AuditEntityWarehouse, w.Id, internal.AuditOperationUpdate, before, w)
+	return
+}
+
+// Delete soft deletes a warehouse.
+func (r *RepositoryWarehouseAudited) Delete(ctx context.Context, id int, version int) (err error) {
+	before, err := r.current(ctx, id)
+	if err != nil {
+		return
+	}
+
+	err = r.rp.Delete(ctx, id, version)
+	if err != nil {
+		return
+	}
+
+	err = audit(ctx, r.ra, internal.AuditEntityWarehouse, id, internal.AuditOperationDelete, before, nil)
+	return
+}
+
+// Restore restores a soft deleted warehouse.
+func (r *RepositoryWarehouseAudited) Restore(ctx context.Context, id int) (err error) {
+	err = r.rp.Restore(ctx, id)
+	if err != nil {
+		return
+	}
+
+	after, err := r.current(ctx, id)
+	if err != nil {
+		return
+	}
+
+	err = audit(ctx, r.ra, internal.AuditEntityWarehouse, id, internal.AuditOperationRestore, nil, after)
+	return
+}
+
+// Purge permanently removes the warehouses soft deleted before a time.
+// - a purge is recorded as a single entry with no entity id
+func (r *RepositoryWarehouseAudited) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	n, err = r.rp.Purge(ctx, before)
+	if err != nil || n == 0 {
+		return
+	}
+
+	err = audit(ctx, r.ra, internal.AuditEntityWarehouse, 0, internal.AuditOperationPurge, nil, map[string]any{
+		"before": before,
+		"count":  n,
+	})
+	return
+}
+
+// ReportProducts counts the products of a warehouse, or of every warehouse when id is 0.
+func (r *RepositoryWarehouseAudited) ReportProducts(ctx context.Context, id int) (w []internal.WarehouseProductsCount, err error) {
+	return r.rp.ReportProducts(ctx, id)
+}
+
+// GetAll returns all warehouses.
+func (r *RepositoryWarehouseAudited) GetAll(ctx context.Context) (w []internal.Warehouse, err error) {
+	return r.rp.GetAll(ctx)
+}
+
+// GetAllIncludingDeleted returns all warehouses, soft deleted ones included.
+func (r *RepositoryWarehouseAudited) GetAllIncludingDeleted(ctx context.Context) (w []internal.Warehouse, err error) {
+	return r.rp.GetAllIncludingDeleted(ctx)
+}
+
+// current returns the current warehouse, nil if it does not exist.
+func (r *RepositoryWarehouseAudited) current(ctx context.Context, id int) (w *internal.Warehouse, err error) {
+	found, err := r.rp.FindById(ctx, id)
+	if err != nil {
+		if errors.Is(err, internal.ErrRepositoryWarehouseNotFound) {
+			err = nil
+		}
+		return
+	}
+
+	w = &found
+	return
+}
@@ -2,27 +2,31 @@ package repository
 
 import (
 	"app/internal"
+	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 )
 
 func NewRepositoryWarehouseMySql(db *sql.DB) *Warehouse {
 	return &Warehouse{
-		db: db,
+		ex: traced(db),
 	}
 }
 
 type Warehouse struct {
-	db *sql.DB
+	// ex runs the queries, it is the db itself or the transaction the repository is bound to
+	ex executor
 }
 
-func (r *Warehouse) FindById(id int) (w internal.Warehouse, err error) {
+func (r *Warehouse) FindById(ctx context.Context, id int) (w internal.Warehouse, err error) {
 
-	row := r.db.QueryRow("SELECT w.`id`, w.`name`, w.`adress`, w.`telephone`, w.`capacity` from `warehouses` `w` where w.`id` = ? ", id)
+	t := internal.TenantFromContext(ctx)
+	row := r.ex.QueryRowContext(ctx, "SELECT w.`id`, w.`tenant_id`, w.`name`, w.`adress`, w.`telephone`, w.`capacity`, w.`version`, w.`deleted_at` from `warehouses` `w` where w.`id` = ? AND (? = '*' OR w.`tenant_id` = ?) AND w.`deleted_at` IS NULL", id, t, t)
 
-	err = row.Scan(&w.Id, &w.Name, &w.Address, &w.Telephone, &w.Capacity)
+	w, err = scanWarehouse(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			err = internal.ErrRepositoryWarehouseNotFound
@@ -33,9 +37,10 @@ func (r *Warehouse) FindById(id int) (w internal.Warehouse, err error) {
 	return
 }
 
-func (r *Warehouse) Save(w *internal.Warehouse) (err error) {
+func (r *Warehouse) Save(ctx context.Context, w *internal.Warehouse) (err error) {
 
-	res, err := r.db.Exec("INSERT INTO `warehouses` (`id`, `name`, `adress`, `telephone`, `capacity`) VALUES (?, ?, ?, ?, ?)", w.Id, w.Name, w.Address, w.Telephone, w.Capacity)
+	tenant := tenantOf(ctx, w.Tenant)
+	res, err := r.ex.ExecContext(ctx, "INSERT INTO `warehouses` (`id`, `tenant_id`, `name`, `adress`, `telephone`, `capacity`, `version`) VALUES (?, ?, ?, ?, ?, ?, 1)", w.Id, tenant, w.Name, w.Address, w.Telephone, w.Capacity)
 	if err != nil {
 		var mySqlErr *mysql.MySQLError
 		if errors.As(err, &mySqlErr) {
@@ -44,8 +49,8 @@ func (r *Warehouse) Save(w *internal.Warehouse) (err error) {
 			case 1062:
 				err = internal.ErrRepositoryProductDuplicated
 			}
-			return
 		}
+		return
 	}
 
 	id, err := res.LastInsertId()
@@ -55,17 +60,67 @@ func (r *Warehouse) Save(w *internal.Warehouse) (err error) {
 	}
 
 	w.Id = int(id)
+	w.Tenant = tenant
+	w.Version = 1
 
 	return
 }
 
-func (r *Warehouse) ReportProducts(id int) (w []internal.WarehouseProductsCount, err error) {
+func (r *Warehouse) Update(ctx context.Context, w *internal.Warehouse) (err error) {
+	t := internal.TenantFromContext(ctx)
+	res, err := r.ex.ExecContext(ctx, "UPDATE `warehouses` SET `name` = ?, `adress` = ?, `telephone` = ?, `capacity` = ?, `version` = `version` + 1 WHERE `id` = ? AND (? = '*' OR `tenant_id` = ?) AND (? = 0 OR `version` = ?) AND `deleted_at` IS NULL", w.Name, w.Address, w.Telephone, w.Capacity, w.Id, t, t, w.Version, w.Version)
+	if err != nil {
+		var mySqlErr *mysql.MySQLError
+		if errors.As(err, &mySqlErr) {
+			switch mySqlErr.Number {
+			case 1062:
+				err = internal.ErrRepositoryWarehouseDuplicated
+			}
+		}
+		return
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAffected == 0 {
+		// the warehouse does not exist or exists with another version
+		_, err = r.version(ctx, w.Id)
+		if err == nil {
+			err = internal.ErrRepositoryWarehouseVersionConflict
+		}
+		return
+	}
+
+	w.Tenant = tenantOf(ctx, w.Tenant)
+	w.Version, err = r.version(ctx, w.Id)
+	return
+}
+
+// version returns the current version of a warehouse.
+func (r *Warehouse) version(ctx context.Context, id int) (v int, err error) {
+	t := internal.TenantFromContext(ctx)
+	err = r.ex.QueryRowContext(ctx, "SELECT `version` FROM `warehouses` WHERE `id` = ? AND (? = '*' OR `tenant_id` = ?) AND `deleted_at` IS NULL", id, t, t).Scan(&v)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = internal.ErrRepositoryWarehouseNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *Warehouse) ReportProducts(ctx context.Context, id int) (w []internal.WarehouseProductsCount, err error) {
 	var rows *sql.Rows
 
+	t := internal.TenantFromContext(ctx)
 	if id == 0 {
-		rows, err = r.db.Query("SELECT w.`id`, count(p.`id`) from `warehouses` `w` left join `products` `p` on w.`id` = p.`id_warehouse` group by w.`id`, w.`name`")
+		rows, err = r.ex.QueryContext(ctx, "SELECT w.`id`, count(p.`id`) from `warehouses` `w` left join `products` `p` on w.`id` = p.`id_warehouse` and p.`tenant_id` = w.`tenant_id` and p.`deleted_at` IS NULL where (? = '*' OR w.`tenant_id` = ?) and w.`deleted_at` IS NULL group by w.`id`, w.`name`", t, t)
 	} else {
-		rows, err = r.db.Query("SELECT w.`id`, count(p.`id`) from `warehouses` `w` left join `products` `p` on w.`id` = p.`id_warehouse` where w.`id` = ? group by w.`id`, w.`name`", id)
+		rows, err = r.ex.QueryContext(ctx, "SELECT w.`id`, count(p.`id`) from `warehouses` `w` left join `products` `p` on w.`id` = p.`id_warehouse` and p.`tenant_id` = w.`tenant_id` and p.`deleted_at` IS NULL where w.`id` = ? and (? = '*' OR w.`tenant_id` = ?) and w.`deleted_at` IS NULL group by w.`id`, w.`name`", id, t, t)
 	}
 
 	if err != nil {
@@ -87,8 +142,81 @@ func (r *Warehouse) ReportProducts(id int) (w []internal.WarehouseProductsCount,
 	return
 }
 
-func (r *Warehouse) GetAll() (w []internal.Warehouse, err error) {
-	rows, err := r.db.Query("SELECT w.`id`, w.`name`, w.`adress`, w.`telephone`, w.`capacity` from `warehouses` `w`")
+func (r *Warehouse) Delete(ctx context.Context, id int, version int) (err error) {
+	t := internal.TenantFromContext(ctx)
+	res, err := r.ex.ExecContext(ctx, "UPDATE `warehouses` SET `deleted_at` = ?, `version` = `version` + 1 WHERE `id` = ? AND (? = 0 OR `version` = ?) AND (? = '*' OR `tenant_id` = ?) AND `deleted_at` IS NULL", time.Now(), id, version, version, t, t)
+	if err != nil {
+		return
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAffected == 0 {
+		// the warehouse does not exist or exists with another version
+		_, err = r.version(ctx, id)
+		if err == nil {
+			err = internal.ErrRepositoryWarehouseVersionConflict
+		}
+		return
+	}
+
+	return
+}
+
+func (r *Warehouse) Restore(ctx context.Context, id int) (err error) {
+	t := internal.TenantFromContext(ctx)
+	res, err := r.ex.ExecContext(ctx, "UPDATE `warehouses` SET `deleted_at` = NULL, `version` = `version` + 1 WHERE `id` = ? AND (? = '*' OR `tenant_id` = ?) AND `deleted_at` IS NOT NULL", id, t, t)
+	if err != nil {
+		return
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAffected == 0 {
+		err = internal.ErrRepositoryWarehouseNotFound
+		return
+	}
+
+	return
+}
+
+func (r *Warehouse) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	t := internal.TenantFromContext(ctx)
+	res, err := r.ex.ExecContext(ctx, "DELETE FROM `warehouses` WHERE (? = '*' OR `tenant_id` = ?) AND `deleted_at` IS NOT NULL AND `deleted_at` < ?", t, t, before)
+	if err != nil {
+		return
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	n = int(rowsAffected)
+	return
+}
+
+func (r *Warehouse) GetAll(ctx context.Context) (w []internal.Warehouse, err error) {
+	t := internal.TenantFromContext(ctx)
+	w, err = r.query(ctx, "SELECT w.`id`, w.`tenant_id`, w.`name`, w.`adress`, w.`telephone`, w.`capacity`, w.`version`, w.`deleted_at` from `warehouses` `w` where (? = '*' OR w.`tenant_id` = ?) AND w.`deleted_at` IS NULL", t, t)
+	return
+}
+
+func (r *Warehouse) GetAllIncludingDeleted(ctx context.Context) (w []internal.Warehouse, err error) {
+	t := internal.TenantFromContext(ctx)
+	w, err = r.query(ctx, "SELECT w.`id`, w.`tenant_id`, w.`name`, w.`adress`, w.`telephone`, w.`capacity`, w.`version`, w.`deleted_at` from `warehouses` `w` where (? = '*' OR w.`tenant_id` = ?)", t, t)
+	return
+}
+
+// query returns the warehouses selected by a query.
+func (r *Warehouse) query(ctx context.Context, query string, args ...any) (w []internal.Warehouse, err error) {
+	rows, err := r.ex.QueryContext(ctx, query, args...)
 	if err != nil {
 		return
 	}
@@ -97,7 +225,7 @@ func (r *Warehouse) GetAll() (w []internal.Warehouse, err error) {
 
 	for rows.Next() {
 		var wh internal.Warehouse
-		err = rows.Scan(&wh.Id, &wh.Name, &wh.Address, &wh.Telephone, &wh.Capacity)
+		wh, err = scanWarehouse(rows)
 		if err != nil {
 			return
 		}
@@ -112,3 +240,15 @@ func (r *Warehouse) GetAll() (w []internal.Warehouse, err error) {
 
 	return
 }
+
+// scanWarehouse scans a warehouse selected with its deleted_at column last.
+func scanWarehouse(row interface{ Scan(dest ...any) error }) (w internal.Warehouse, err error) {
+	var deletedAt sql.NullTime
+	err = row.Scan(&w.Id, &w.Tenant, &w.Name, &w.Address, &w.Telephone, &w.Capacity, &w.Version, &deletedAt)
+	if err != nil {
+		return
+	}
+
+	w.DeletedAt = deletedAt.Time
+	return
+}
@@ -3,32 +3,17 @@ package repository_test
 import (
 	"app/internal"
 	"app/internal/repository"
+	"context"
 	"database/sql"
 	"testing"
 
-	"github.com/DATA-DOG/go-txdb"
-	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/require"
 )
 
-func init() {
-	cfg := mysql.Config{
-		User:      "user1",
-		Passwd:    "secret_password",
-		Addr:      "localhost:3306",
-		Net:       "tcp",
-		DBName:    "test_db",
-		ParseTime: true,
-	}
-
-	txdb.Register("txdb", "mysql", cfg.FormatDSN())
-}
-
 func TestWarehouse_FindById(t *testing.T) {
 
 	t.Run("success - found by id", func(t *testing.T) {
-		db, err := sql.Open("txdb", "test_db")
-		require.NoError(t, err)
+		db := openTxdb(t)
 		defer db.Close()
 
 		defer func(db *sql.DB) {
@@ -50,31 +35,32 @@ func TestWarehouse_FindById(t *testing.T) {
 		rp := repository.NewRepositoryWarehouseMySql(db)
 
 		//act
-		wh, err := rp.FindById(1)
+		wh, err := rp.FindById(context.Background(), 1)
 
 		//assert
 		expectedWh := internal.Warehouse{
-			Id: 1,
+			Id:     1,
+			Tenant: internal.TenantDefault,
 			WarehouseAttributes: internal.WarehouseAttributes{
 				Name:      "warehouse 1",
 				Address:   "address 1",
 				Telephone: "telephone 1",
 				Capacity:  100,
 			},
+			Version: 1,
 		}
 		require.NoError(t, err)
 		require.Equal(t, expectedWh, wh)
 	})
 
 	t.Run("failure - warehouse not found", func(t *testing.T) {
-		db, err := sql.Open("txdb", "test_db")
-		require.NoError(t, err)
+		db := openTxdb(t)
 		defer db.Close()
 
 		rp := repository.NewRepositoryWarehouseMySql(db)
 
 		//act
-		wh, err := rp.FindById(1)
+		wh, err := rp.FindById(context.Background(), 1)
 
 		//assert
 		expectedWh := internal.Warehouse{}
@@ -89,8 +75,7 @@ func TestWarehouse_FindById(t *testing.T) {
 func TestWarehouse_GetAll(t *testing.T) {
 
 	t.Run("success - return 1", func(t *testing.T) {
-		db, err := sql.Open("txdb", "test_db")
-		require.NoError(t, err)
+		db := openTxdb(t)
 		defer db.Close()
 
 		//set up
@@ -102,18 +87,20 @@ func TestWarehouse_GetAll(t *testing.T) {
 		rp := repository.NewRepositoryWarehouseMySql(db)
 
 		//act
-		wh, err := rp.GetAll()
+		wh, err := rp.GetAll(context.Background())
 
 		//assert
 		expectedWh := []internal.Warehouse{
 			{
-				Id: 1,
+				Id:     1,
+				Tenant: internal.TenantDefault,
 				WarehouseAttributes: internal.WarehouseAttributes{
 					Name:      "warehouse 1",
 					Address:   "address 1",
 					Telephone: "telephone 1",
 					Capacity:  100,
 				},
+				Version: 1,
 			},
 		}
 		require.NoError(t, err)
@@ -121,14 +108,13 @@ func TestWarehouse_GetAll(t *testing.T) {
 	})
 
 	t.Run("success - return 0", func(t *testing.T) {
-		db, err := sql.Open("txdb", "test_db")
-		require.NoError(t, err)
+		db := openTxdb(t)
 		defer db.Close()
 
 		rp := repository.NewRepositoryWarehouseMySql(db)
 
 		//act
-		wh, err := rp.GetAll()
+		wh, err := rp.GetAll(context.Background())
 
 		//assert
 		expectedWh := []internal.Warehouse(nil)
@@ -141,8 +127,7 @@ func TestWarehouse_GetAll(t *testing.T) {
 func TestWarehouse_Save(t *testing.T) {
 
 	t.Run("success - saved", func(t *testing.T) {
-		db, err := sql.Open("txdb", "test_db")
-		require.NoError(t, err)
+		db := openTxdb(t)
 		defer db.Close()
 
 		//set up
@@ -159,9 +144,128 @@ func TestWarehouse_Save(t *testing.T) {
 		rp := repository.NewRepositoryWarehouseMySql(db)
 
 		//act
-		err = rp.Save(&wh)
+		err := rp.Save(context.Background(), &wh)
+
+		//assert
+		require.NoError(t, err)
+	})
+
+}
+
+func TestWarehouse_Update(t *testing.T) {
+
+	t.Run("success - updated", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `warehouses` (`id`, `name`, `adress`, `telephone`, `capacity`) VALUES (1, 'warehouse 1', 'address 1', 'telephone 1', 100)")
+			require.NoError(t, err)
+		}(db)
+
+		wh := internal.Warehouse{
+			Id:      1,
+			Version: 1,
+			WarehouseAttributes: internal.WarehouseAttributes{
+				Name:      "warehouse 1",
+				Address:   "address 2",
+				Telephone: "telephone 1",
+				Capacity:  50,
+			},
+		}
+
+		rp := repository.NewRepositoryWarehouseMySql(db)
+
+		//act
+		err := rp.Update(context.Background(), &wh)
+
+		//assert
+		require.NoError(t, err)
+		require.Equal(t, 2, wh.Version)
+	})
+
+	t.Run("fail - version conflict", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `warehouses` (`id`, `name`, `adress`, `telephone`, `capacity`, `version`) VALUES (1, 'warehouse 1', 'address 1', 'telephone 1', 100, 3)")
+			require.NoError(t, err)
+		}(db)
+
+		wh := internal.Warehouse{Id: 1, Version: 1}
+
+		rp := repository.NewRepositoryWarehouseMySql(db)
+
+		//act
+		err := rp.Update(context.Background(), &wh)
+
+		//assert
+		require.ErrorIs(t, err, internal.ErrRepositoryWarehouseVersionConflict)
+	})
+
+}
+
+func TestWarehouse_Delete(t *testing.T) {
+
+	t.Run("success - soft deleted", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `warehouses` (`id`, `name`, `adress`, `telephone`, `capacity`) VALUES (1, 'warehouse 1', 'address 1', 'telephone 1', 100)")
+			require.NoError(t, err)
+		}(db)
+
+		rp := repository.NewRepositoryWarehouseMySql(db)
+
+		//act
+		err := rp.Delete(context.Background(), 1, 0)
+
+		//assert
+		require.NoError(t, err)
+		_, err = rp.FindById(context.Background(), 1)
+		require.ErrorIs(t, err, internal.ErrRepositoryWarehouseNotFound)
+		wh, err := rp.GetAllIncludingDeleted(context.Background())
+		require.NoError(t, err)
+		require.Len(t, wh, 1)
+		require.False(t, wh[0].DeletedAt.IsZero())
+	})
+
+	t.Run("fail - not found", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		rp := repository.NewRepositoryWarehouseMySql(db)
+
+		//act
+		err := rp.Delete(context.Background(), 1, 0)
+
+		//assert
+		require.ErrorIs(t, err, internal.ErrRepositoryWarehouseNotFound)
+	})
+
+	t.Run("fail - version conflict", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		//set up
+		func(db *sql.DB) {
+			_, err := db.Exec("INSERT INTO `warehouses` (`id`, `name`, `adress`, `telephone`, `capacity`, `version`) VALUES (1, 'warehouse 1', 'address 1', 'telephone 1', 100, 2)")
+			require.NoError(t, err)
+		}(db)
+
+		rp := repository.NewRepositoryWarehouseMySql(db)
+
+		//act
+		err := rp.Delete(context.Background(), 1, 1)
 
 		//assert
+		require.ErrorIs(t, err, internal.ErrRepositoryWarehouseVersionConflict)
+		_, err = rp.FindById(context.Background(), 1)
 		require.NoError(t, err)
 	})
 
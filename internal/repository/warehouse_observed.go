@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"time"
+)
+
+// NewRepositoryWarehouseObserved creates a new repository for warehouses whose calls are observed, e.g. to measure their latency.
+func NewRepositoryWarehouseObserved(rp internal.RepositoryWarehouse, o internal.Observer) *RepositoryWarehouseObserved {
+	return &RepositoryWarehouseObserved{
+		rp: rp,
+		o:  o,
+	}
+}
+
+// RepositoryWarehouseObserved is a repository for warehouses whose calls are observed, e.g. to measure their latency.
+// - the context of a call is the one of its observation, given to the decorated repository
+type RepositoryWarehouseObserved struct {
+	// rp is the decorated repository.
+	rp internal.RepositoryWarehouse
+	// o is the observer of the calls.
+	o internal.Observer
+}
+
+// FindById finds a warehouse by id.
+func (r *RepositoryWarehouseObserved) FindById(ctx context.Context, id int) (w internal.Warehouse, err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "warehouse", "FindById")
+	w, err = r.rp.FindById(ctx, id)
+	done(err)
+	return
+}
+
+// Save saves a warehouse.
+func (r *RepositoryWarehouseObserved) Save(ctx context.Context, w *internal.Warehouse) (err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "warehouse", "Save")
+	err = r.rp.Save(ctx, w)
+	done(err)
+	return
+}
+
+// Update updates a warehouse.
+func (r *RepositoryWarehouseObserved) Update(ctx context.Context, w *internal.Warehouse) (err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "warehouse", "Update")
+	err = r.rp.Update(ctx, w)
+	done(err)
+	return
+}
+
+// Delete soft deletes a warehouse.
+func (r *RepositoryWarehouseObserved) Delete(ctx context.Context, id int, version int) (err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "warehouse", "Delete")
+	err = r.rp.Delete(ctx, id, version)
+	done(err)
+	return
+}
+
+// Restore restores a soft deleted warehouse.
+func (r *RepositoryWarehouseObserved) Restore(ctx context.Context, id int) (err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "warehouse", "Restore")
+	err = r.rp.Restore(ctx, id)
+	done(err)
+	return
+}
+
+// Purge permanently removes the warehouses soft deleted before a time.
+func (r *RepositoryWarehouseObserved) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "warehouse", "Purge")
+	n, err = r.rp.Purge(ctx, before)
+	done(err)
+	return
+}
+
+// ReportProducts counts the products of a warehouse, or of every warehouse when id is 0.
+func (r *RepositoryWarehouseObserved) ReportProducts(ctx context.Context, id int) (w []internal.WarehouseProductsCount, err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "warehouse", "ReportProducts")
+	w, err = r.rp.ReportProducts(ctx, id)
+	done(err)
+	return
+}
+
+// GetAll returns all warehouses.
+func (r *RepositoryWarehouseObserved) GetAll(ctx context.Context) (w []internal.Warehouse, err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "warehouse", "GetAll")
+	w, err = r.rp.GetAll(ctx)
+	done(err)
+	return
+}
+
+// GetAllIncludingDeleted returns all warehouses, soft deleted ones included.
+func (r *RepositoryWarehouseObserved) GetAllIncludingDeleted(ctx context.Context) (w []internal.Warehouse, err error) {
+	ctx, done := r.o.ObserveRepository(ctx, "warehouse", "GetAllIncludingDeleted")
+	w, err = r.rp.GetAllIncludingDeleted(ctx)
+	done(err)
+	return
+}
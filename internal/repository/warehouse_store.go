@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"sort"
+	"time"
+)
+
+// NewRepositoryWarehouseStore creates a new repository for warehouses.
+func NewRepositoryWarehouseStore(st internal.StoreWarehouse, stProduct internal.StoreProduct) (r *RepositoryWarehouseStore) {
+	r = &RepositoryWarehouseStore{
+		st:        st,
+		stProduct: stProduct,
+	}
+	return
+}
+
+// RepositoryWarehouseStore is a repository for warehouses.
+type RepositoryWarehouseStore struct {
+	// st is the underlying store.
+	st internal.StoreWarehouse
+	// stProduct is the store of the products kept in the warehouses.
+	stProduct internal.StoreProduct
+}
+
+// FindById finds a warehouse by id.
+func (r *RepositoryWarehouseStore) FindById(ctx context.Context, id int) (w internal.Warehouse, err error) {
+	// read all warehouses
+	ws, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// find warehouse
+	// - the warehouses of other tenants are not found
+	w, ok := ws[id]
+	if !ok || !w.DeletedAt.IsZero() || !internal.InTenant(ctx, w.Tenant) {
+		w = internal.Warehouse{}
+		err = internal.ErrRepositoryWarehouseNotFound
+		return
+	}
+
+	return
+}
+
+// Save saves a warehouse.
+func (r *RepositoryWarehouseStore) Save(ctx context.Context, w *internal.Warehouse) (err error) {
+	// read all warehouses
+	ws, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// find max id
+	var maxId int
+	for k := range ws {
+		if k > maxId {
+			maxId = k
+		}
+	}
+
+	// set id
+	(*w).Id = maxId + 1
+	(*w).Tenant = tenantOf(ctx, w.Tenant)
+	(*w).Version = 1
+
+	// add warehouse
+	ws[w.Id] = *w
+
+	// write all warehouses
+	err = r.st.WriteAll(ws)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Update updates a warehouse.
+func (r *RepositoryWarehouseStore) Update(ctx context.Context, w *internal.Warehouse) (err error) {
+	// read all warehouses
+	ws, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// update warehouse
+	current, ok := ws[w.Id]
+	if !ok || !current.DeletedAt.IsZero() || !internal.InTenant(ctx, current.Tenant) {
+		err = internal.ErrRepositoryWarehouseNotFound
+		return
+	}
+	if w.Version != 0 && w.Version != current.Version {
+		err = internal.ErrRepositoryWarehouseVersionConflict
+		return
+	}
+
+	// update warehouse
+	(*w).Tenant = current.Tenant
+	(*w).Version = current.Version + 1
+	ws[w.Id] = *w
+
+	// write all warehouses
+	err = r.st.WriteAll(ws)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// ReportProducts counts the products of a warehouse, or of every warehouse when id is 0.
+func (r *RepositoryWarehouseStore) ReportProducts(ctx context.Context, id int) (w []internal.WarehouseProductsCount, err error) {
+	// read all warehouses and products
+	ws, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+	ps, err := r.stProduct.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// count products
+	counts := make(map[int]int)
+	for _, p := range ps {
+		if !p.DeletedAt.IsZero() {
+			continue
+		}
+		counts[p.WarehouseId]++
+	}
+
+	// report
+	for _, wh := range sortedWarehouses(ws) {
+		if (id != 0 && wh.Id != id) || !wh.DeletedAt.IsZero() || !internal.InTenant(ctx, wh.Tenant) {
+			continue
+		}
+		w = append(w, internal.WarehouseProductsCount{
+			Name:  wh.Name,
+			Count: counts[wh.Id],
+		})
+	}
+
+	return
+}
+
+// Delete soft deletes a warehouse.
+func (r *RepositoryWarehouseStore) Delete(ctx context.Context, id int, version int) (err error) {
+	// read all warehouses
+	ws, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// delete warehouse
+	w, ok := ws[id]
+	if !ok || !w.DeletedAt.IsZero() || !internal.InTenant(ctx, w.Tenant) {
+		err = internal.ErrRepositoryWarehouseNotFound
+		return
+	}
+	if version != 0 && version != w.Version {
+		err = internal.ErrRepositoryWarehouseVersionConflict
+		return
+	}
+	w.DeletedAt = time.Now()
+	w.Version++
+	ws[id] = w
+
+	// write all warehouses
+	err = r.st.WriteAll(ws)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Restore restores a soft deleted warehouse.
+func (r *RepositoryWarehouseStore) Restore(ctx context.Context, id int) (err error) {
+	// read all warehouses
+	ws, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// restore warehouse
+	w, ok := ws[id]
+	if !ok || w.DeletedAt.IsZero() || !internal.InTenant(ctx, w.Tenant) {
+		err = internal.ErrRepositoryWarehouseNotFound
+		return
+	}
+	w.DeletedAt = time.Time{}
+	w.Version++
+	ws[id] = w
+
+	// write all warehouses
+	err = r.st.WriteAll(ws)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Purge permanently removes the warehouses soft deleted before a time.
+func (r *RepositoryWarehouseStore) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	// read all warehouses
+	ws, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// remove warehouses
+	for id, w := range ws {
+		if !w.DeletedAt.IsZero() && w.DeletedAt.Before(before) && internal.InTenant(ctx, w.Tenant) {
+			delete(ws, id)
+			n++
+		}
+	}
+	if n == 0 {
+		return
+	}
+
+	// write all warehouses
+	err = r.st.WriteAll(ws)
+	if err != nil {
+		n = 0
+		return
+	}
+
+	return
+}
+
+// GetAll returns all warehouses sorted by id.
+func (r *RepositoryWarehouseStore) GetAll(ctx context.Context) (w []internal.Warehouse, err error) {
+	// read all warehouses
+	ws, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	for _, v := range sortedWarehouses(ws) {
+		if !v.DeletedAt.IsZero() || !internal.InTenant(ctx, v.Tenant) {
+			continue
+		}
+		w = append(w, v)
+	}
+	return
+}
+
+// GetAllIncludingDeleted returns all warehouses sorted by id, soft deleted ones included.
+func (r *RepositoryWarehouseStore) GetAllIncludingDeleted(ctx context.Context) (w []internal.Warehouse, err error) {
+	// read all warehouses
+	ws, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	for _, v := range sortedWarehouses(ws) {
+		if !internal.InTenant(ctx, v.Tenant) {
+			continue
+		}
+		w = append(w, v)
+	}
+	return
+}
+
+// sortedWarehouses returns the warehouses sorted by id.
+func sortedWarehouses(ws map[int]internal.Warehouse) (w []internal.Warehouse) {
+	for _, v := range ws {
+		w = append(w, v)
+	}
+	sort.Slice(w, func(i, j int) bool {
+		return w[i].Id < w[j].Id
+	})
+	return
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"time"
+)
+
+// NewRepositoryWarehouseTransactional creates a new repository for warehouses that runs every mutation in its own unit of work.
+func NewRepositoryWarehouseTransactional(rp internal.RepositoryWarehouse, uow internal.UnitOfWork) *RepositoryWarehouseTransactional {
+	return &RepositoryWarehouseTransactional{
+		rp:  rp,
+		uow: uow,
+	}
+}
+
+// RepositoryWarehouseTransactional is a repository for warehouses that runs every mutation in its own unit of work.
+// - the mutations get the decorations of the unit of work, e.g. the audit log and the outbox
+// - the reads are made on the decorated repository
+type RepositoryWarehouseTransactional struct {
+	// rp is the decorated repository.
+	rp internal.RepositoryWarehouse
+	// uow is the unit of work the mutations run in.
+	uow internal.UnitOfWork
+}
+
+// FindById finds a warehouse by id.
+func (r *RepositoryWarehouseTransactional) FindById(ctx context.Context, id int) (w internal.Warehouse, err error) {
+	return r.rp.FindById(ctx, id)
+}
+
+// Save saves a warehouse.
+func (r *RepositoryWarehouseTransactional) Save(ctx context.Context, w *internal.Warehouse) (err error) {
+	err = r.uow.Do(ctx, func(rt internal.RepositoriesTx) (err error) {
+		return rt.Warehouse.Save(ctx, w)
+	})
+	return
+}
+
+// Update updates a warehouse.
+func (r *RepositoryWarehouseTransactional) Update(ctx context.Context, w *internal.Warehouse) (err error) {
+	err = r.uow.Do(ctx, func(rt internal.RepositoriesTx) (err error) {
+		return rt.Warehouse.Update(ctx, w)
+	})
+	return
+}
+
+// Delete soft deletes a warehouse.
+func (r *RepositoryWarehouseTransactional) Delete(ctx context.Context, id int, version int) (err error) {
+	err = r.uow.Do(ctx, func(rt internal.RepositoriesTx) (err error) {
+		return rt.Warehouse.Delete(ctx, id, version)
+	})
+	return
+}
+
+// Restore restores a soft deleted warehouse.
+func (r *RepositoryWarehouseTransactional) Restore(ctx context.Context, id int) (err error) {
+	err = r.uow.Do(ctx, func(rt internal.RepositoriesTx) (err error) {
+		return rt.Warehouse.Restore(ctx, id)
+	})
+	return
+}
+
+// Purge permanently removes the warehouses soft deleted before a time.
+func (r *RepositoryWarehouseTransactional) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	err = r.uow.Do(ctx, func(rt internal.RepositoriesTx) (err error) {
+		n, err = rt.Warehouse.Purge(ctx, before)
+		return
+	})
+	return
+}
+
+// ReportProducts counts the products of a warehouse, or of every warehouse when id is 0.
+func (r *RepositoryWarehouseTransactional) ReportProducts(ctx context.Context, id int) (w []internal.WarehouseProductsCount, err error) {
+	return r.rp.ReportProducts(ctx, id)
+}
+
+// GetAll returns all warehouses.
+func (r *RepositoryWarehouseTransactional) GetAll(ctx context.Context) (w []internal.Warehouse, err error) {
+	return r.rp.GetAll(ctx)
+}
+
+// GetAllIncludingDeleted returns all warehouses, soft deleted ones included.
+func (r *RepositoryWarehouseTransactional) GetAllIncludingDeleted(ctx context.Context) (w []internal.Warehouse, err error) {
+	return r.rp.GetAllIncludingDeleted(ctx)
+}
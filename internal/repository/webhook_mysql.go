@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"database/sql"
+	"encoding/json"
+)
+
+// NewRepositoryWebhookMysql creates a new repository for webhooks backed by mysql.
+func NewRepositoryWebhookMysql(db *sql.DB) *WebhookMysql {
+	return &WebhookMysql{
+		ex: traced(db),
+	}
+}
+
+// WebhookMysql is a repository for webhooks backed by mysql.
+// - the event patterns are stored as a JSON array
+type WebhookMysql struct {
+	// ex runs the queries, it is the db itself or the transaction the repository is bound to
+	ex executor
+}
+
+// FindById returns a webhook by its id.
+func (r *WebhookMysql) FindById(ctx context.Context, id int) (w internal.Webhook, err error) {
+	var events []byte
+	t := internal.TenantFromContext(ctx)
+	err = r.ex.QueryRowContext(ctx, "SELECT `id`, `tenant_id`, `url`, `secret`, `events`, `created_at` FROM `webhooks` WHERE `id` = ? AND (? = '*' OR `tenant_id` = ?)", id, t, t).Scan(&w.Id, &w.Tenant, &w.URL, &w.Secret, &events, &w.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = internal.ErrRepositoryWebhookNotFound
+		}
+		return
+	}
+
+	err = json.Unmarshal(events, &w.Events)
+	return
+}
+
+// Save saves a webhook, setting its id.
+func (r *WebhookMysql) Save(ctx context.Context, w *internal.Webhook) (err error) {
+	err = w.Validate()
+	if err != nil {
+		return
+	}
+
+	events, err := json.Marshal(w.Events)
+	if err != nil {
+		return
+	}
+
+	tenant := tenantOf(ctx, w.Tenant)
+	res, err := r.ex.ExecContext(ctx, "INSERT INTO `webhooks` (`tenant_id`, `url`, `secret`, `events`, `created_at`) VALUES (?, ?, ?, ?, ?)", tenant, w.URL, w.Secret, events, w.CreatedAt)
+	if err != nil {
+		return
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+
+	w.Id = int(id)
+	w.Tenant = tenant
+	return
+}
+
+// Delete deletes a webhook.
+func (r *WebhookMysql) Delete(ctx context.Context, id int) (err error) {
+	t := internal.TenantFromContext(ctx)
+	res, err := r.ex.ExecContext(ctx, "DELETE FROM `webhooks` WHERE `id` = ? AND (? = '*' OR `tenant_id` = ?)", id, t, t)
+	if err != nil {
+		return
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if n == 0 {
+		err = internal.ErrRepositoryWebhookNotFound
+		return
+	}
+
+	return
+}
+
+// GetAll returns all webhooks sorted by id.
+func (r *WebhookMysql) GetAll(ctx context.Context) (w []internal.Webhook, err error) {
+	t := internal.TenantFromContext(ctx)
+	rows, err := r.ex.QueryContext(ctx, "SELECT `id`, `tenant_id`, `url`, `secret`, `events`, `created_at` FROM `webhooks` WHERE (? = '*' OR `tenant_id` = ?) ORDER BY `id`", t, t)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v internal.Webhook
+		var events []byte
+		err = rows.Scan(&v.Id, &v.Tenant, &v.URL, &v.Secret, &events, &v.CreatedAt)
+		if err != nil {
+			return
+		}
+		err = json.Unmarshal(events, &v.Events)
+		if err != nil {
+			return
+		}
+		w = append(w, v)
+	}
+	err = rows.Err()
+	return
+}
+
+// NewRepositoryDeadLetterMysql creates a new repository for dead letters backed by mysql.
+func NewRepositoryDeadLetterMysql(db *sql.DB) *DeadLetterMysql {
+	return &DeadLetterMysql{
+		ex: traced(db),
+	}
+}
+
+// DeadLetterMysql is a repository for dead letters backed by mysql.
+// - the event is stored as JSON, its tenant in a column of its own so the dead letters are filtered by it
+type DeadLetterMysql struct {
+	// ex runs the queries, it is the db itself or the transaction the repository is bound to
+	ex executor
+}
+
+// FindById returns a dead letter by its id.
+func (r *DeadLetterMysql) FindById(ctx context.Context, id int) (d internal.DeadLetter, err error) {
+	var event []byte
+	t := internal.TenantFromContext(ctx)
+	err = r.ex.QueryRowContext(ctx, "SELECT `id`, `id_webhook`, `event`, `attempts`, `last_error`, `failed_at` FROM `webhook_dead_letters` WHERE `id` = ? AND (? = '*' OR `tenant_id` = ?)", id, t, t).Scan(&d.Id, &d.WebhookId, &event, &d.Attempts, &d.LastError, &d.FailedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = internal.ErrRepositoryDeadLetterNotFound
+		}
+		return
+	}
+
+	err = json.Unmarshal(event, &d.Event)
+	return
+}
+
+// Save saves a dead letter, setting its id.
+func (r *DeadLetterMysql) Save(ctx context.Context, d *internal.DeadLetter) (err error) {
+	event, err := json.Marshal(d.Event)
+	if err != nil {
+		return
+	}
+
+	tenant := d.Event.Tenant
+	if tenant == "" {
+		tenant = internal.TenantDefault
+	}
+	res, err := r.ex.ExecContext(ctx, "INSERT INTO `webhook_dead_letters` (`id_webhook`, `tenant_id`, `event`, `attempts`, `last_error`, `failed_at`) VALUES (?, ?, ?, ?, ?, ?)", d.WebhookId, tenant, event, d.Attempts, d.LastError, d.FailedAt)
+	if err != nil {
+		return
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+
+	d.Id = int(id)
+	return
+}
+
+// Delete deletes a dead letter.
+func (r *DeadLetterMysql) Delete(ctx context.Context, id int) (err error) {
+	t := internal.TenantFromContext(ctx)
+	res, err := r.ex.ExecContext(ctx, "DELETE FROM `webhook_dead_letters` WHERE `id` = ? AND (? = '*' OR `tenant_id` = ?)", id, t, t)
+	if err != nil {
+		return
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if n == 0 {
+		err = internal.ErrRepositoryDeadLetterNotFound
+		return
+	}
+
+	return
+}
+
+// GetAll returns all dead letters sorted by id.
+func (r *DeadLetterMysql) GetAll(ctx context.Context) (d []internal.DeadLetter, err error) {
+	t := internal.TenantFromContext(ctx)
+	rows, err := r.ex.QueryContext(ctx, "SELECT `id`, `id_webhook`, `event`, `attempts`, `last_error`, `failed_at` FROM `webhook_dead_letters` WHERE (? = '*' OR `tenant_id` = ?) ORDER BY `id`", t, t)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v internal.DeadLetter
+		var event []byte
+		err = rows.Scan(&v.Id, &v.WebhookId, &event, &v.Attempts, &v.LastError, &v.FailedAt)
+		if err != nil {
+			return
+		}
+		err = json.Unmarshal(event, &v.Event)
+		if err != nil {
+			return
+		}
+		d = append(d, v)
+	}
+	err = rows.Err()
+	return
+}
@@ -0,0 +1,101 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookMysql_Save(t *testing.T) {
+
+	t.Run("success - webhook saved with its events", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		rp := repository.NewRepositoryWebhookMysql(db)
+		w := internal.Webhook{URL: "https://example.com/hook", Secret: "secret", Events: []string{"product.*"}, CreatedAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+		//act
+		err := rp.Save(context.Background(), &w)
+
+		//assert
+		require.NoError(t, err)
+		found, err := rp.FindById(context.Background(), w.Id)
+		require.NoError(t, err)
+		require.Equal(t, w, found)
+	})
+
+	t.Run("fail - invalid url", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		rp := repository.NewRepositoryWebhookMysql(db)
+		w := internal.Webhook{URL: "not a url"}
+
+		//act
+		err := rp.Save(context.Background(), &w)
+
+		//assert
+		require.ErrorIs(t, err, internal.ErrWebhookInvalid)
+	})
+
+}
+
+func TestDeadLetterMysql_Save(t *testing.T) {
+
+	t.Run("success - dead letter saved with its event", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		rp := repository.NewRepositoryDeadLetterMysql(db)
+		d := internal.DeadLetter{
+			WebhookId: 1,
+			Event:     internal.Event{Id: "1", Type: internal.EventProductCreated, EntityId: 1, Actor: "anonymous", Timestamp: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+			Attempts:  5,
+			LastError: "webhook: receiver responded 500",
+			FailedAt:  time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+
+		//act
+		err := rp.Save(context.Background(), &d)
+
+		//assert
+		require.NoError(t, err)
+		found, err := rp.FindById(context.Background(), d.Id)
+		require.NoError(t, err)
+		require.Equal(t, d, found)
+	})
+
+	t.Run("success - dead letter across tenants not found by a tenant", func(t *testing.T) {
+		db := openTxdb(t)
+		defer db.Close()
+
+		rp := repository.NewRepositoryDeadLetterMysql(db)
+		d := internal.DeadLetter{
+			WebhookId: 1,
+			Event:     internal.Event{Id: "1", Type: internal.EventProductPurged, Actor: "job:purge", Tenant: internal.TenantAll, Timestamp: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+			Attempts:  5,
+			LastError: "webhook: receiver responded 500",
+			FailedAt:  time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+		err := rp.Save(context.Background(), &d)
+		require.NoError(t, err)
+		acme := internal.ContextWithTenant(context.Background(), "acme")
+
+		//act
+		_, errFind := rp.FindById(acme, d.Id)
+		all, errAll := rp.GetAll(acme)
+		errDelete := rp.Delete(acme, d.Id)
+
+		//assert
+		require.ErrorIs(t, errFind, internal.ErrRepositoryDeadLetterNotFound)
+		require.NoError(t, errAll)
+		require.Empty(t, all)
+		require.ErrorIs(t, errDelete, internal.ErrRepositoryDeadLetterNotFound)
+	})
+
+}
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"sort"
+	"sync"
+)
+
+// NewRepositoryWebhookStore creates a new repository for webhooks.
+func NewRepositoryWebhookStore(st internal.StoreWebhook) (r *RepositoryWebhookStore) {
+	r = &RepositoryWebhookStore{
+		st: st,
+	}
+	return
+}
+
+// RepositoryWebhookStore is a repository for webhooks.
+type RepositoryWebhookStore struct {
+	// st is the underlying store.
+	st internal.StoreWebhook
+}
+
+// FindById returns a webhook by its id.
+func (r *RepositoryWebhookStore) FindById(ctx context.Context, id int) (w internal.Webhook, err error) {
+	// read all webhooks
+	ws, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// find webhook
+	// - the webhooks of other tenants are not found
+	w, ok := ws[id]
+	if !ok || !internal.InTenant(ctx, w.Tenant) {
+		err = internal.ErrRepositoryWebhookNotFound
+		return
+	}
+
+	return
+}
+
+// Save saves a webhook, setting its id.
+func (r *RepositoryWebhookStore) Save(ctx context.Context, w *internal.Webhook) (err error) {
+	err = w.Validate()
+	if err != nil {
+		return
+	}
+
+	// read all webhooks
+	ws, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// find max id
+	var maxId int
+	for k := range ws {
+		if k > maxId {
+			maxId = k
+		}
+	}
+
+	// add webhook
+	(*w).Id = maxId + 1
+	(*w).Tenant = tenantOf(ctx, w.Tenant)
+	ws[w.Id] = *w
+
+	// write all webhooks
+	err = r.st.WriteAll(ws)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Delete deletes a webhook.
+func (r *RepositoryWebhookStore) Delete(ctx context.Context, id int) (err error) {
+	// read all webhooks
+	ws, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// delete webhook
+	if v, ok := ws[id]; !ok || !internal.InTenant(ctx, v.Tenant) {
+		err = internal.ErrRepositoryWebhookNotFound
+		return
+	}
+	delete(ws, id)
+
+	// write all webhooks
+	err = r.st.WriteAll(ws)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// GetAll returns all webhooks sorted by id.
+func (r *RepositoryWebhookStore) GetAll(ctx context.Context) (w []internal.Webhook, err error) {
+	// read all webhooks
+	ws, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	for _, v := range ws {
+		if !internal.InTenant(ctx, v.Tenant) {
+			continue
+		}
+		w = append(w, v)
+	}
+	sort.Slice(w, func(i, j int) bool {
+		return w[i].Id < w[j].Id
+	})
+	return
+}
+
+// NewRepositoryDeadLetterStore creates a new repository for dead letters.
+func NewRepositoryDeadLetterStore(st internal.StoreDeadLetter) (r *RepositoryDeadLetterStore) {
+	r = &RepositoryDeadLetterStore{
+		st: st,
+	}
+	return
+}
+
+// RepositoryDeadLetterStore is a repository for dead letters.
+// - it is safe for concurrent use, as deliveries fail from many goroutines
+type RepositoryDeadLetterStore struct {
+	// mu serializes the reads and writes of the store.
+	mu sync.Mutex
+	// st is the underlying store.
+	st internal.StoreDeadLetter
+}
+
+// FindById returns a dead letter by its id.
+func (r *RepositoryDeadLetterStore) FindById(ctx context.Context, id int) (d internal.DeadLetter, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// read all dead letters
+	ds, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// find dead letter
+	// - the dead letters of the events of other tenants are not found
+	d, ok := ds[id]
+	if !ok || !deadLetterInTenant(ctx, d) {
+		err = internal.ErrRepositoryDeadLetterNotFound
+		return
+	}
+
+	return
+}
+
+// Save saves a dead letter, setting its id.
+func (r *RepositoryDeadLetterStore) Save(ctx context.Context, d *internal.DeadLetter) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// read all dead letters
+	ds, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// find max id
+	var maxId int
+	for k := range ds {
+		if k > maxId {
+			maxId = k
+		}
+	}
+
+	// add dead letter
+	(*d).Id = maxId + 1
+	ds[d.Id] = *d
+
+	// write all dead letters
+	err = r.st.WriteAll(ds)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Delete deletes a dead letter.
+func (r *RepositoryDeadLetterStore) Delete(ctx context.Context, id int) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// read all dead letters
+	ds, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	// delete dead letter
+	if v, ok := ds[id]; !ok || !deadLetterInTenant(ctx, v) {
+		err = internal.ErrRepositoryDeadLetterNotFound
+		return
+	}
+	delete(ds, id)
+
+	// write all dead letters
+	err = r.st.WriteAll(ds)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// GetAll returns all dead letters sorted by id.
+func (r *RepositoryDeadLetterStore) GetAll(ctx context.Context) (d []internal.DeadLetter, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// read all dead letters
+	ds, err := r.st.ReadAll()
+	if err != nil {
+		return
+	}
+
+	for _, v := range ds {
+		if !deadLetterInTenant(ctx, v) {
+			continue
+		}
+		d = append(d, v)
+	}
+	sort.Slice(d, func(i, j int) bool {
+		return d[i].Id < d[j].Id
+	})
+	return
+}
+
+// deadLetterInTenant tells whether a dead letter is visible to ctx, by the tenant of its event.
+func deadLetterInTenant(ctx context.Context, d internal.DeadLetter) bool {
+	t := internal.TenantFromContext(ctx)
+	return t == internal.TenantAll || d.Event.InTenant(t)
+}
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"app/internal"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+)
+
+// NewStoreExchangeRateJSON creates a new JSON file store for exchange rates.
+func NewStoreExchangeRateJSON(path string) (s *StoreExchangeRateJSON) {
+	s = &StoreExchangeRateJSON{
+		Path: path,
+	}
+	return
+}
+
+// StoreExchangeRateJSON is a JSON file store for exchange rates.
+type StoreExchangeRateJSON struct {
+	// Path is the path to the JSON file.
+	Path string
+}
+
+// ExchangeRateJSON is a JSON representation of an exchange rate.
+type ExchangeRateJSON struct {
+	Currency string      `json:"currency"`
+	Rate     json.Number `json:"rate"`
+}
+
+// ReadAll reads all rates from the store, the ones of every tenant.
+// - the rates of each tenant are kept in a file of their own, see tenantPath
+func (s *StoreExchangeRateJSON) ReadAll() (e map[string]map[string]internal.ExchangeRate, err error) {
+	paths, err := tenantPaths(s.Path)
+	if err != nil {
+		return
+	}
+
+	e = make(map[string]map[string]internal.ExchangeRate, len(paths))
+	for tenant, path := range paths {
+		e[tenant], err = s.readFile(path)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// readFile reads the rates of a tenant from its file, by currency.
+// - a missing file is read as no rates
+func (s *StoreExchangeRateJSON) readFile(path string) (e map[string]internal.ExchangeRate, err error) {
+	e = make(map[string]internal.ExchangeRate)
+
+	// open file
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		return
+	}
+	defer f.Close()
+
+	// decode JSON
+	var er []ExchangeRateJSON
+	dec := json.NewDecoder(f)
+	dec.UseNumber()
+	err = dec.Decode(&er)
+	if err != nil {
+		return
+	}
+
+	// serialize
+	for _, v := range er {
+		var rate internal.ExchangeRate
+		rate, err = internal.ParseExchangeRate(v.Currency, v.Rate.String())
+		if err != nil {
+			return
+		}
+		e[rate.Currency] = rate
+	}
+
+	return
+}
+
+// WriteAll writes all rates to the store, the ones of each tenant to its file.
+// - the file of a tenant left without rates is emptied, the one of the default tenant is always written
+func (s *StoreExchangeRateJSON) WriteAll(e map[string]map[string]internal.ExchangeRate) (err error) {
+	paths, err := tenantPaths(s.Path)
+	if err != nil {
+		return
+	}
+
+	// serialize
+	// - sorted by currency
+	er := make(map[string][]ExchangeRateJSON)
+	for tenant := range paths {
+		er[tenant] = []ExchangeRateJSON{}
+	}
+	for tenant, es := range e {
+		if tenant == "" {
+			tenant = internal.TenantDefault
+		}
+		for _, v := range es {
+			er[tenant] = append(er[tenant], ExchangeRateJSON{
+				Currency: v.Currency,
+				Rate:     json.Number(v.String()),
+			})
+		}
+	}
+
+	for tenant, v := range er {
+		sort.Slice(v, func(i, j int) bool {
+			return v[i].Currency < v[j].Currency
+		})
+		err = writeJSON(tenantPath(s.Path, tenant), v)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
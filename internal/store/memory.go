@@ -0,0 +1,289 @@
+package store
+
+import "app/internal"
+
+// NewStoreProductMemory creates a new in-memory store for products.
+func NewStoreProductMemory(p map[int]internal.Product) (s *StoreProductMemory) {
+	if p == nil {
+		p = make(map[int]internal.Product)
+	}
+	s = &StoreProductMemory{
+		p: p,
+	}
+	return
+}
+
+// StoreProductMemory is an in-memory store for products.
+type StoreProductMemory struct {
+	// p is the stored products.
+	p map[int]internal.Product
+}
+
+// ReadAll reads a copy of all products from the store.
+func (s *StoreProductMemory) ReadAll() (p map[int]internal.Product, err error) {
+	p = make(map[int]internal.Product, len(s.p))
+	for k, v := range s.p {
+		p[k] = v
+	}
+	return
+}
+
+// WriteAll writes all products to the store.
+func (s *StoreProductMemory) WriteAll(p map[int]internal.Product) (err error) {
+	s.p = make(map[int]internal.Product, len(p))
+	for k, v := range p {
+		s.p[k] = v
+	}
+	return
+}
+
+// NewStoreWarehouseMemory creates a new in-memory store for warehouses.
+func NewStoreWarehouseMemory(w map[int]internal.Warehouse) (s *StoreWarehouseMemory) {
+	if w == nil {
+		w = make(map[int]internal.Warehouse)
+	}
+	s = &StoreWarehouseMemory{
+		w: w,
+	}
+	return
+}
+
+// StoreWarehouseMemory is an in-memory store for warehouses.
+type StoreWarehouseMemory struct {
+	// w is the stored warehouses.
+	w map[int]internal.Warehouse
+}
+
+// ReadAll reads a copy of all warehouses from the store.
+func (s *StoreWarehouseMemory) ReadAll() (w map[int]internal.Warehouse, err error) {
+	w = make(map[int]internal.Warehouse, len(s.w))
+	for k, v := range s.w {
+		w[k] = v
+	}
+	return
+}
+
+// WriteAll writes all warehouses to the store.
+func (s *StoreWarehouseMemory) WriteAll(w map[int]internal.Warehouse) (err error) {
+	s.w = make(map[int]internal.Warehouse, len(w))
+	for k, v := range w {
+		s.w[k] = v
+	}
+	return
+}
+
+// NewStorePriceMemory creates a new in-memory store for price changes.
+func NewStorePriceMemory(c map[int]internal.PriceChange) (s *StorePriceMemory) {
+	if c == nil {
+		c = make(map[int]internal.PriceChange)
+	}
+	s = &StorePriceMemory{
+		c: c,
+	}
+	return
+}
+
+// StorePriceMemory is an in-memory store for price changes.
+type StorePriceMemory struct {
+	// c is the stored price changes.
+	c map[int]internal.PriceChange
+}
+
+// ReadAll reads a copy of all price changes from the store.
+func (s *StorePriceMemory) ReadAll() (c map[int]internal.PriceChange, err error) {
+	c = make(map[int]internal.PriceChange, len(s.c))
+	for k, v := range s.c {
+		c[k] = v
+	}
+	return
+}
+
+// WriteAll writes all price changes to the store.
+func (s *StorePriceMemory) WriteAll(c map[int]internal.PriceChange) (err error) {
+	s.c = make(map[int]internal.PriceChange, len(c))
+	for k, v := range c {
+		s.c[k] = v
+	}
+	return
+}
+
+// NewStoreReorderThresholdMemory creates a new in-memory store for reorder thresholds, by tenant.
+func NewStoreReorderThresholdMemory(t map[string]map[internal.ReorderScope]internal.ReorderThreshold) (s *StoreReorderThresholdMemory) {
+	if t == nil {
+		t = make(map[string]map[internal.ReorderScope]internal.ReorderThreshold)
+	}
+	s = &StoreReorderThresholdMemory{
+		t: t,
+	}
+	return
+}
+
+// StoreReorderThresholdMemory is an in-memory store for reorder thresholds.
+type StoreReorderThresholdMemory struct {
+	// t is the stored thresholds, by tenant.
+	t map[string]map[internal.ReorderScope]internal.ReorderThreshold
+}
+
+// ReadAll reads a copy of all thresholds from the store.
+func (s *StoreReorderThresholdMemory) ReadAll() (t map[string]map[internal.ReorderScope]internal.ReorderThreshold, err error) {
+	t = make(map[string]map[internal.ReorderScope]internal.ReorderThreshold, len(s.t))
+	for tenant, ts := range s.t {
+		t[tenant] = make(map[internal.ReorderScope]internal.ReorderThreshold, len(ts))
+		for k, v := range ts {
+			t[tenant][k] = v
+		}
+	}
+	return
+}
+
+// WriteAll writes all thresholds to the store.
+func (s *StoreReorderThresholdMemory) WriteAll(t map[string]map[internal.ReorderScope]internal.ReorderThreshold) (err error) {
+	s.t = make(map[string]map[internal.ReorderScope]internal.ReorderThreshold, len(t))
+	for tenant, ts := range t {
+		s.t[tenant] = make(map[internal.ReorderScope]internal.ReorderThreshold, len(ts))
+		for k, v := range ts {
+			s.t[tenant][k] = v
+		}
+	}
+	return
+}
+
+// NewStoreWebhookMemory creates a new in-memory store for webhooks.
+func NewStoreWebhookMemory(w map[int]internal.Webhook) (s *StoreWebhookMemory) {
+	if w == nil {
+		w = make(map[int]internal.Webhook)
+	}
+	s = &StoreWebhookMemory{
+		w: w,
+	}
+	return
+}
+
+// StoreWebhookMemory is an in-memory store for webhooks.
+type StoreWebhookMemory struct {
+	// w is the stored webhooks.
+	w map[int]internal.Webhook
+}
+
+// ReadAll reads a copy of all webhooks from the store.
+func (s *StoreWebhookMemory) ReadAll() (w map[int]internal.Webhook, err error) {
+	w = make(map[int]internal.Webhook, len(s.w))
+	for k, v := range s.w {
+		w[k] = v
+	}
+	return
+}
+
+// WriteAll writes all webhooks to the store.
+func (s *StoreWebhookMemory) WriteAll(w map[int]internal.Webhook) (err error) {
+	s.w = make(map[int]internal.Webhook, len(w))
+	for k, v := range w {
+		s.w[k] = v
+	}
+	return
+}
+
+// NewStoreDeadLetterMemory creates a new in-memory store for dead letters.
+func NewStoreDeadLetterMemory(d map[int]internal.DeadLetter) (s *StoreDeadLetterMemory) {
+	if d == nil {
+		d = make(map[int]internal.DeadLetter)
+	}
+	s = &StoreDeadLetterMemory{
+		d: d,
+	}
+	return
+}
+
+// StoreDeadLetterMemory is an in-memory store for dead letters.
+type StoreDeadLetterMemory struct {
+	// d is the stored dead letters.
+	d map[int]internal.DeadLetter
+}
+
+// ReadAll reads a copy of all dead letters from the store.
+func (s *StoreDeadLetterMemory) ReadAll() (d map[int]internal.DeadLetter, err error) {
+	d = make(map[int]internal.DeadLetter, len(s.d))
+	for k, v := range s.d {
+		d[k] = v
+	}
+	return
+}
+
+// WriteAll writes all dead letters to the store.
+func (s *StoreDeadLetterMemory) WriteAll(d map[int]internal.DeadLetter) (err error) {
+	s.d = make(map[int]internal.DeadLetter, len(d))
+	for k, v := range d {
+		s.d[k] = v
+	}
+	return
+}
+
+// NewStoreOutboxMemory creates a new in-memory store for outbox messages.
+func NewStoreOutboxMemory(m map[int]internal.OutboxMessage) (s *StoreOutboxMemory) {
+	if m == nil {
+		m = make(map[int]internal.OutboxMessage)
+	}
+	s = &StoreOutboxMemory{
+		m: m,
+	}
+	return
+}
+
+// StoreOutboxMemory is an in-memory store for outbox messages.
+type StoreOutboxMemory struct {
+	// m is the stored outbox messages.
+	m map[int]internal.OutboxMessage
+}
+
+// ReadAll reads a copy of all outbox messages from the store.
+func (s *StoreOutboxMemory) ReadAll() (m map[int]internal.OutboxMessage, err error) {
+	m = make(map[int]internal.OutboxMessage, len(s.m))
+	for k, v := range s.m {
+		m[k] = v
+	}
+	return
+}
+
+// WriteAll writes all outbox messages to the store.
+func (s *StoreOutboxMemory) WriteAll(m map[int]internal.OutboxMessage) (err error) {
+	s.m = make(map[int]internal.OutboxMessage, len(m))
+	for k, v := range m {
+		s.m[k] = v
+	}
+	return
+}
+
+// NewStoreIdempotencyMemory creates a new in-memory store for idempotency records.
+func NewStoreIdempotencyMemory(r map[string]internal.IdempotencyRecord) (s *StoreIdempotencyMemory) {
+	if r == nil {
+		r = make(map[string]internal.IdempotencyRecord)
+	}
+	s = &StoreIdempotencyMemory{
+		r: r,
+	}
+	return
+}
+
+// StoreIdempotencyMemory is an in-memory store for idempotency records.
+type StoreIdempotencyMemory struct {
+	// r is the stored idempotency records.
+	r map[string]internal.IdempotencyRecord
+}
+
+// ReadAll reads a copy of all idempotency records from the store.
+func (s *StoreIdempotencyMemory) ReadAll() (r map[string]internal.IdempotencyRecord, err error) {
+	r = make(map[string]internal.IdempotencyRecord, len(s.r))
+	for k, v := range s.r {
+		r[k] = v
+	}
+	return
+}
+
+// WriteAll writes all idempotency records to the store.
+func (s *StoreIdempotencyMemory) WriteAll(r map[string]internal.IdempotencyRecord) (err error) {
+	s.r = make(map[string]internal.IdempotencyRecord, len(r))
+	for k, v := range r {
+		s.r[k] = v
+	}
+	return
+}
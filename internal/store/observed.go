@@ -0,0 +1,267 @@
+package store
+
+import "app/internal"
+
+// NewStoreProductObserved creates a new store for products whose reads and writes are observed, e.g. to measure their duration.
+func NewStoreProductObserved(st internal.StoreProduct, o internal.Observer) (s *StoreProductObserved) {
+	s = &StoreProductObserved{
+		st: st,
+		o:  o,
+	}
+	return
+}
+
+// StoreProductObserved is a store for products whose reads and writes are observed.
+type StoreProductObserved struct {
+	// st is the decorated store.
+	st internal.StoreProduct
+	// o is the observer of the reads and writes.
+	o internal.Observer
+}
+
+// ReadAll reads all products from the store.
+func (s *StoreProductObserved) ReadAll() (p map[int]internal.Product, err error) {
+	done := s.o.ObserveStore("product", "ReadAll")
+	p, err = s.st.ReadAll()
+	done(err)
+	return
+}
+
+// WriteAll writes all products to the store.
+func (s *StoreProductObserved) WriteAll(p map[int]internal.Product) (err error) {
+	done := s.o.ObserveStore("product", "WriteAll")
+	err = s.st.WriteAll(p)
+	done(err)
+	return
+}
+
+// NewStoreWarehouseObserved creates a new store for warehouses whose reads and writes are observed, e.g. to measure their duration.
+func NewStoreWarehouseObserved(st internal.StoreWarehouse, o internal.Observer) (s *StoreWarehouseObserved) {
+	s = &StoreWarehouseObserved{
+		st: st,
+		o:  o,
+	}
+	return
+}
+
+// StoreWarehouseObserved is a store for warehouses whose reads and writes are observed.
+type StoreWarehouseObserved struct {
+	// st is the decorated store.
+	st internal.StoreWarehouse
+	// o is the observer of the reads and writes.
+	o internal.Observer
+}
+
+// ReadAll reads all warehouses from the store.
+func (s *StoreWarehouseObserved) ReadAll() (w map[int]internal.Warehouse, err error) {
+	done := s.o.ObserveStore("warehouse", "ReadAll")
+	w, err = s.st.ReadAll()
+	done(err)
+	return
+}
+
+// WriteAll writes all warehouses to the store.
+func (s *StoreWarehouseObserved) WriteAll(w map[int]internal.Warehouse) (err error) {
+	done := s.o.ObserveStore("warehouse", "WriteAll")
+	err = s.st.WriteAll(w)
+	done(err)
+	return
+}
+
+// NewStorePriceObserved creates a new store for price changes whose reads and writes are observed, e.g. to measure their duration.
+func NewStorePriceObserved(st internal.StorePrice, o internal.Observer) (s *StorePriceObserved) {
+	s = &StorePriceObserved{
+		st: st,
+		o:  o,
+	}
+	return
+}
+
+// StorePriceObserved is a store for price changes whose reads and writes are observed.
+type StorePriceObserved struct {
+	// st is the decorated store.
+	st internal.StorePrice
+	// o is the observer of the reads and writes.
+	o internal.Observer
+}
+
+// ReadAll reads all price changes from the store.
+func (s *StorePriceObserved) ReadAll() (c map[int]internal.PriceChange, err error) {
+	done := s.o.ObserveStore("price", "ReadAll")
+	c, err = s.st.ReadAll()
+	done(err)
+	return
+}
+
+// WriteAll writes all price changes to the store.
+func (s *StorePriceObserved) WriteAll(c map[int]internal.PriceChange) (err error) {
+	done := s.o.ObserveStore("price", "WriteAll")
+	err = s.st.WriteAll(c)
+	done(err)
+	return
+}
+
+// NewStoreExchangeRateObserved creates a new store for exchange rates whose reads and writes are observed, e.g. to measure their duration.
+func NewStoreExchangeRateObserved(st internal.StoreExchangeRate, o internal.Observer) (s *StoreExchangeRateObserved) {
+	s = &StoreExchangeRateObserved{
+		st: st,
+		o:  o,
+	}
+	return
+}
+
+// StoreExchangeRateObserved is a store for exchange rates whose reads and writes are observed.
+type StoreExchangeRateObserved struct {
+	// st is the decorated store.
+	st internal.StoreExchangeRate
+	// o is the observer of the reads and writes.
+	o internal.Observer
+}
+
+// ReadAll reads all exchange rates from the store.
+func (s *StoreExchangeRateObserved) ReadAll() (e map[string]map[string]internal.ExchangeRate, err error) {
+	done := s.o.ObserveStore("exchange_rate", "ReadAll")
+	e, err = s.st.ReadAll()
+	done(err)
+	return
+}
+
+// WriteAll writes all exchange rates to the store.
+func (s *StoreExchangeRateObserved) WriteAll(e map[string]map[string]internal.ExchangeRate) (err error) {
+	done := s.o.ObserveStore("exchange_rate", "WriteAll")
+	err = s.st.WriteAll(e)
+	done(err)
+	return
+}
+
+// NewStoreReorderThresholdObserved creates a new store for reorder thresholds whose reads and writes are observed, e.g. to measure their duration.
+func NewStoreReorderThresholdObserved(st internal.StoreReorderThreshold, o internal.Observer) (s *StoreReorderThresholdObserved) {
+	s = &StoreReorderThresholdObserved{
+		st: st,
+		o:  o,
+	}
+	return
+}
+
+// StoreReorderThresholdObserved is a store for reorder thresholds whose reads and writes are observed.
+type StoreReorderThresholdObserved struct {
+	// st is the decorated store.
+	st internal.StoreReorderThreshold
+	// o is the observer of the reads and writes.
+	o internal.Observer
+}
+
+// ReadAll reads all reorder thresholds from the store.
+func (s *StoreReorderThresholdObserved) ReadAll() (t map[string]map[internal.ReorderScope]internal.ReorderThreshold, err error) {
+	done := s.o.ObserveStore("reorder_threshold", "ReadAll")
+	t, err = s.st.ReadAll()
+	done(err)
+	return
+}
+
+// WriteAll writes all reorder thresholds to the store.
+func (s *StoreReorderThresholdObserved) WriteAll(t map[string]map[internal.ReorderScope]internal.ReorderThreshold) (err error) {
+	done := s.o.ObserveStore("reorder_threshold", "WriteAll")
+	err = s.st.WriteAll(t)
+	done(err)
+	return
+}
+
+// NewStoreWebhookObserved creates a new store for webhooks whose reads and writes are observed, e.g. to measure their duration.
+func NewStoreWebhookObserved(st internal.StoreWebhook, o internal.Observer) (s *StoreWebhookObserved) {
+	s = &StoreWebhookObserved{
+		st: st,
+		o:  o,
+	}
+	return
+}
+
+// StoreWebhookObserved is a store for webhooks whose reads and writes are observed.
+type StoreWebhookObserved struct {
+	// st is the decorated store.
+	st internal.StoreWebhook
+	// o is the observer of the reads and writes.
+	o internal.Observer
+}
+
+// ReadAll reads all webhooks from the store.
+func (s *StoreWebhookObserved) ReadAll() (w map[int]internal.Webhook, err error) {
+	done := s.o.ObserveStore("webhook", "ReadAll")
+	w, err = s.st.ReadAll()
+	done(err)
+	return
+}
+
+// WriteAll writes all webhooks to the store.
+func (s *StoreWebhookObserved) WriteAll(w map[int]internal.Webhook) (err error) {
+	done := s.o.ObserveStore("webhook", "WriteAll")
+	err = s.st.WriteAll(w)
+	done(err)
+	return
+}
+
+// NewStoreDeadLetterObserved creates a new store for dead letters whose reads and writes are observed, e.g. to measure their duration.
+func NewStoreDeadLetterObserved(st internal.StoreDeadLetter, o internal.Observer) (s *StoreDeadLetterObserved) {
+	s = &StoreDeadLetterObserved{
+		st: st,
+		o:  o,
+	}
+	return
+}
+
+// StoreDeadLetterObserved is a store for dead letters whose reads and writes are observed.
+type StoreDeadLetterObserved struct {
+	// st is the decorated store.
+	st internal.StoreDeadLetter
+	// o is the observer of the reads and writes.
+	o internal.Observer
+}
+
+// ReadAll reads all dead letters from the store.
+func (s *StoreDeadLetterObserved) ReadAll() (d map[int]internal.DeadLetter, err error) {
+	done := s.o.ObserveStore("dead_letter", "ReadAll")
+	d, err = s.st.ReadAll()
+	done(err)
+	return
+}
+
+// WriteAll writes all dead letters to the store.
+func (s *StoreDeadLetterObserved) WriteAll(d map[int]internal.DeadLetter) (err error) {
+	done := s.o.ObserveStore("dead_letter", "WriteAll")
+	err = s.st.WriteAll(d)
+	done(err)
+	return
+}
+
+// NewStoreOutboxObserved creates a new store for outbox messages whose reads and writes are observed, e.g. to measure their duration.
+func NewStoreOutboxObserved(st internal.StoreOutbox, o internal.Observer) (s *StoreOutboxObserved) {
+	s = &StoreOutboxObserved{
+		st: st,
+		o:  o,
+	}
+	return
+}
+
+// StoreOutboxObserved is a store for outbox messages whose reads and writes are observed.
+type StoreOutboxObserved struct {
+	// st is the decorated store.
+	st internal.StoreOutbox
+	// o is the observer of the reads and writes.
+	o internal.Observer
+}
+
+// ReadAll reads all outbox messages from the store.
+func (s *StoreOutboxObserved) ReadAll() (m map[int]internal.OutboxMessage, err error) {
+	done := s.o.ObserveStore("outbox", "ReadAll")
+	m, err = s.st.ReadAll()
+	done(err)
+	return
+}
+
+// WriteAll writes all outbox messages to the store.
+func (s *StoreOutboxObserved) WriteAll(m map[int]internal.OutboxMessage) (err error) {
+	done := s.o.ObserveStore("outbox", "WriteAll")
+	err = s.st.WriteAll(m)
+	done(err)
+	return
+}
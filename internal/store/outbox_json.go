@@ -0,0 +1,128 @@
+package store
+
+import (
+	"app/internal"
+	"sort"
+	"time"
+)
+
+// NewStoreOutboxJSON creates a new JSON file store for outbox messages.
+func NewStoreOutboxJSON(path string) (s *StoreOutboxJSON) {
+	s = &StoreOutboxJSON{
+		Path: path,
+	}
+	return
+}
+
+// StoreOutboxJSON is a JSON file store for outbox messages.
+type StoreOutboxJSON struct {
+	// Path is the path to the JSON file.
+	Path string
+}
+
+// OutboxMessageJSON is a JSON representation of an outbox message.
+type OutboxMessageJSON struct {
+	Id          int            `json:"id"`
+	Event       internal.Event `json:"event"`
+	CreatedAt   string         `json:"created_at"`
+	PublishedAt string         `json:"published_at,omitempty"`
+}
+
+// ReadAll reads all outbox messages from the store, the ones of every tenant.
+// - the messages of each tenant are kept in a file of their own, see tenantPath
+func (s *StoreOutboxJSON) ReadAll() (m map[int]internal.OutboxMessage, err error) {
+	paths, err := tenantPaths(s.Path)
+	if err != nil {
+		return
+	}
+
+	m = make(map[int]internal.OutboxMessage)
+	for tenant, path := range paths {
+		err = s.readFile(path, tenant, m)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// readFile reads the outbox messages of a tenant from its file into m.
+// - a missing file is read as no messages
+func (s *StoreOutboxJSON) readFile(path, tenant string, m map[int]internal.OutboxMessage) (err error) {
+	// decode JSON
+	var ms []OutboxMessageJSON
+	err = readJSON(path, &ms)
+	if err != nil {
+		return
+	}
+
+	// serialize
+	for _, v := range ms {
+		var createdAt time.Time
+		createdAt, err = time.Parse(time.RFC3339Nano, v.CreatedAt)
+		if err != nil {
+			return
+		}
+		var publishedAt time.Time
+		if v.PublishedAt != "" {
+			publishedAt, err = time.Parse(time.RFC3339Nano, v.PublishedAt)
+			if err != nil {
+				return
+			}
+		}
+		v.Event.Tenant = tenant
+		m[v.Id] = internal.OutboxMessage{
+			Id:          v.Id,
+			Event:       v.Event,
+			CreatedAt:   createdAt,
+			PublishedAt: publishedAt,
+		}
+	}
+
+	return
+}
+
+// WriteAll writes all outbox messages to the store, each to the file of the tenant of its event.
+// - the file of a tenant left without messages is emptied, the one of the default tenant is always written
+func (s *StoreOutboxJSON) WriteAll(m map[int]internal.OutboxMessage) (err error) {
+	paths, err := tenantPaths(s.Path)
+	if err != nil {
+		return
+	}
+
+	// serialize
+	// - sorted by id
+	ms := make(map[string][]OutboxMessageJSON)
+	for tenant := range paths {
+		ms[tenant] = []OutboxMessageJSON{}
+	}
+	for _, v := range m {
+		var publishedAt string
+		if !v.PublishedAt.IsZero() {
+			publishedAt = v.PublishedAt.Format(time.RFC3339Nano)
+		}
+		tenant := v.Event.Tenant
+		if tenant == "" {
+			tenant = internal.TenantDefault
+		}
+		ms[tenant] = append(ms[tenant], OutboxMessageJSON{
+			Id:          v.Id,
+			Event:       v.Event,
+			CreatedAt:   v.CreatedAt.Format(time.RFC3339Nano),
+			PublishedAt: publishedAt,
+		})
+	}
+
+	for tenant, v := range ms {
+		sort.Slice(v, func(i, j int) bool {
+			return v[i].Id < v[j].Id
+		})
+		err = writeJSON(tenantPath(s.Path, tenant), v)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,135 @@
+package store
+
+import (
+	"app/internal"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"time"
+)
+
+// NewStorePriceJSON creates a new JSON file store for price changes.
+func NewStorePriceJSON(path string) (s *StorePriceJSON) {
+	s = &StorePriceJSON{
+		Path: path,
+	}
+	return
+}
+
+// StorePriceJSON is a JSON file store for price changes.
+type StorePriceJSON struct {
+	// Path is the path to the JSON file.
+	Path string
+}
+
+// PriceChangeJSON is a JSON representation of a price change.
+type PriceChangeJSON struct {
+	Id          int            `json:"id"`
+	ProductId   int            `json:"product_id"`
+	Price       internal.Money `json:"price"`
+	Currency    string         `json:"currency,omitempty"`
+	EffectiveAt string         `json:"effective_at"`
+	Status      string         `json:"status"`
+}
+
+// ReadAll reads all price changes from the store, the ones of every tenant.
+// - the price changes of each tenant are kept in a file of their own, see tenantPath
+func (s *StorePriceJSON) ReadAll() (c map[int]internal.PriceChange, err error) {
+	paths, err := tenantPaths(s.Path)
+	if err != nil {
+		return
+	}
+
+	c = make(map[int]internal.PriceChange)
+	for tenant, path := range paths {
+		err = s.readFile(path, tenant, c)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// readFile reads the price changes of a tenant from its file into c.
+// - a missing file is read as no price changes
+func (s *StorePriceJSON) readFile(path, tenant string, c map[int]internal.PriceChange) (err error) {
+	// open file
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		return
+	}
+	defer f.Close()
+
+	// decode JSON
+	var pc []PriceChangeJSON
+	err = json.NewDecoder(f).Decode(&pc)
+	if err != nil {
+		return
+	}
+
+	// serialize
+	for _, v := range pc {
+		var effectiveAt time.Time
+		effectiveAt, err = time.Parse(time.RFC3339, v.EffectiveAt)
+		if err != nil {
+			return
+		}
+		c[v.Id] = internal.PriceChange{
+			Id:          v.Id,
+			ProductId:   v.ProductId,
+			Price:       internal.NewMoney(v.Price.Amount, v.Currency),
+			EffectiveAt: effectiveAt,
+			Status:      v.Status,
+			Tenant:      tenant,
+		}
+	}
+
+	return
+}
+
+// WriteAll writes all price changes to the store, each to the file of its tenant.
+// - the file of a tenant left without price changes is emptied, the one of the default tenant is always written
+func (s *StorePriceJSON) WriteAll(c map[int]internal.PriceChange) (err error) {
+	paths, err := tenantPaths(s.Path)
+	if err != nil {
+		return
+	}
+
+	// serialize
+	// - sorted by id, so the file reads as a timeline
+	pc := make(map[string][]PriceChangeJSON)
+	for tenant := range paths {
+		pc[tenant] = []PriceChangeJSON{}
+	}
+	for _, v := range c {
+		tenant := v.Tenant
+		if tenant == "" {
+			tenant = internal.TenantDefault
+		}
+		pc[tenant] = append(pc[tenant], PriceChangeJSON{
+			Id:          v.Id,
+			ProductId:   v.ProductId,
+			Price:       v.Price,
+			Currency:    v.Price.Currency,
+			EffectiveAt: v.EffectiveAt.Format(time.RFC3339),
+			Status:      v.Status,
+		})
+	}
+
+	for tenant, v := range pc {
+		sort.Slice(v, func(i, j int) bool {
+			return v[i].Id < v[j].Id
+		})
+		err = writeJSON(tenantPath(s.Path, tenant), v)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -23,19 +23,43 @@ type StoreProductJSON struct {
 
 // ProductJSON is a JSON representation of a product.
 type ProductJSON struct {
-	Id          int     `json:"id"`
-	Name        string  `json:"name"`
-	Quantity    int     `json:"quantity"`
-	CodeValue   string  `json:"code_value"`
-	IsPublished bool    `json:"is_published"`
-	Expiration  string  `json:"expiration"`
-	Price       float64 `json:"price"`
+	Id          int            `json:"id"`
+	Name        string         `json:"name"`
+	Quantity    int            `json:"quantity"`
+	CodeValue   string         `json:"code_value"`
+	IsPublished bool           `json:"is_published"`
+	Expiration  string         `json:"expiration"`
+	Price       internal.Money `json:"price"`
+	Currency    string         `json:"currency,omitempty"`
+	WarehouseId int            `json:"warehouse_id"`
+	Version     int            `json:"version"`
+	DeletedAt   string         `json:"deleted_at,omitempty"`
 }
 
-// ReadAll reads all products from the store.
+// ReadAll reads all products from the store, the ones of every tenant.
+// - the products of each tenant are kept in a file of their own, see tenantPath
 func (s *StoreProductJSON) ReadAll() (p map[int]internal.Product, err error) {
+	paths, err := tenantPaths(s.Path)
+	if err != nil {
+		return
+	}
+
+	p = make(map[int]internal.Product)
+	for tenant, path := range paths {
+		err = s.readFile(path, tenant, p)
+		if err != nil {
+			p = nil
+			return
+		}
+	}
+
+	return
+}
+
+// readFile reads the products of a tenant from its file into p.
+func (s *StoreProductJSON) readFile(path, tenant string, p map[int]internal.Product) (err error) {
 	// open file
-	f, err := os.Open(s.Path)
+	f, err := os.Open(path)
 	if err != nil {
 		return
 	}
@@ -49,36 +73,63 @@ func (s *StoreProductJSON) ReadAll() (p map[int]internal.Product, err error) {
 	}
 
 	// serialize
-	p = make(map[int]internal.Product)
 	for _, v := range pr {
 		var exp time.Time
 		exp, err = time.Parse(time.DateOnly, v.Expiration)
 		if err != nil {
 			return
 		}
+		var deletedAt time.Time
+		if v.DeletedAt != "" {
+			deletedAt, err = time.Parse(time.RFC3339, v.DeletedAt)
+			if err != nil {
+				return
+			}
+		}
 
 		p[v.Id] = internal.Product{
-			Id:          v.Id,
+			Id:     v.Id,
+			Tenant: tenant,
 			ProductAttributes: internal.ProductAttributes{
 				Name:        v.Name,
 				Quantity:    v.Quantity,
 				CodeValue:   v.CodeValue,
 				IsPublished: v.IsPublished,
 				Expiration:  exp,
-				Price:       v.Price,
+				Price:       internal.NewMoney(v.Price.Amount, v.Currency),
 			},
+			WarehouseId: v.WarehouseId,
+			Version:     max(v.Version, 1),
+			DeletedAt:   deletedAt,
 		}
 	}
 
 	return
 }
 
-// WriteAll writes all products to the store.
+// WriteAll writes all products to the store, each to the file of its tenant.
+// - the file of a tenant left without products is emptied, the one of the default tenant is always written
 func (s *StoreProductJSON) WriteAll(p map[int]internal.Product) (err error) {
+	paths, err := tenantPaths(s.Path)
+	if err != nil {
+		return
+	}
+
 	// serialize
-	var pr []ProductJSON
+	pr := make(map[string][]ProductJSON)
+	for tenant := range paths {
+		pr[tenant] = nil
+	}
 	for _, v := range p {
-		pr = append(pr, ProductJSON{
+		var deletedAt string
+		if !v.DeletedAt.IsZero() {
+			deletedAt = v.DeletedAt.Format(time.RFC3339)
+		}
+		tenant := v.Tenant
+		if tenant == "" {
+			tenant = internal.TenantDefault
+		}
+		pr[tenant] = append(pr[tenant], ProductJSON{
 			Id:          v.Id,
 			Name:        v.Name,
 			Quantity:    v.Quantity,
@@ -86,12 +137,28 @@ func (s *StoreProductJSON) WriteAll(p map[int]internal.Product) (err error) {
 			IsPublished: v.IsPublished,
 			Expiration:  v.Expiration.Format(time.DateOnly),
 			Price:       v.Price,
+			Currency:    v.Price.Currency,
+			WarehouseId: v.WarehouseId,
+			Version:     v.Version,
+			DeletedAt:   deletedAt,
 		})
 	}
 
+	for tenant, v := range pr {
+		err = s.writeFile(tenantPath(s.Path, tenant), v)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// writeFile writes the products of a tenant to its file.
+func (s *StoreProductJSON) writeFile(path string, pr []ProductJSON) (err error) {
 	// open file
 	// - create if not exists / write only / truncate
-	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 	if err != nil {
 		return
 	}
@@ -104,4 +171,4 @@ func (s *StoreProductJSON) WriteAll(p map[int]internal.Product) (err error) {
 	}
 
 	return
-}
\ No newline at end of file
+}
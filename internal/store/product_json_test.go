@@ -0,0 +1,69 @@
+package store_test
+
+import (
+	"app/internal"
+	"app/internal/store"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for StoreProductJSON tenants
+func TestStoreProductJSON_Tenants(t *testing.T) {
+	t.Run("success - products of each tenant written to a file of their own", func(t *testing.T) {
+		// arrange
+		dir := t.TempDir()
+		st := store.NewStoreProductJSON(filepath.Join(dir, "products.json"))
+		exp := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		p := map[int]internal.Product{
+			1: {Id: 1, Tenant: internal.TenantDefault, Version: 1, ProductAttributes: internal.ProductAttributes{Name: "product 1", Expiration: exp, Price: internal.NewMoney(100, internal.CurrencyDefault)}},
+			2: {Id: 2, Tenant: "acme", Version: 1, ProductAttributes: internal.ProductAttributes{Name: "product 2", Expiration: exp, Price: internal.NewMoney(200, internal.CurrencyDefault)}},
+		}
+
+		// act
+		err := st.WriteAll(p)
+
+		// assert
+		require.NoError(t, err)
+		require.FileExists(t, filepath.Join(dir, "products.json"))
+		require.FileExists(t, filepath.Join(dir, "products.acme.json"))
+		read, err := st.ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, p, read)
+	})
+
+	t.Run("success - file of a tenant without products emptied", func(t *testing.T) {
+		// arrange
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "products.json"), []byte("[]"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "products.acme.json"), []byte(`[{"id":2,"name":"product 2","expiration":"2030-01-01","price":200,"version":1}]`), 0644))
+		st := store.NewStoreProductJSON(filepath.Join(dir, "products.json"))
+
+		// act
+		err := st.WriteAll(map[int]internal.Product{})
+
+		// assert
+		require.NoError(t, err)
+		read, err := st.ReadAll()
+		require.NoError(t, err)
+		require.Empty(t, read)
+	})
+
+	t.Run("success - files of invalid tenants ignored", func(t *testing.T) {
+		// arrange
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "products.json"), []byte("[]"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "products.Acme.json"), []byte(`[{"id":2,"name":"product 2","expiration":"2030-01-01","price":200,"version":1}]`), 0644))
+		st := store.NewStoreProductJSON(filepath.Join(dir, "products.json"))
+
+		// act
+		read, err := st.ReadAll()
+
+		// assert
+		require.NoError(t, err)
+		require.Empty(t, read)
+	})
+}
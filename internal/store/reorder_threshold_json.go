@@ -0,0 +1,126 @@
+package store
+
+import (
+	"app/internal"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+)
+
+// NewStoreReorderThresholdJSON creates a new JSON file store for reorder thresholds.
+func NewStoreReorderThresholdJSON(path string) (s *StoreReorderThresholdJSON) {
+	s = &StoreReorderThresholdJSON{
+		Path: path,
+	}
+	return
+}
+
+// StoreReorderThresholdJSON is a JSON file store for reorder thresholds.
+type StoreReorderThresholdJSON struct {
+	// Path is the path to the JSON file.
+	Path string
+}
+
+// ReorderThresholdJSON is a JSON representation of a reorder threshold.
+type ReorderThresholdJSON struct {
+	Scope    string `json:"scope"`
+	ScopeId  int    `json:"scope_id"`
+	Quantity int    `json:"quantity"`
+}
+
+// ReadAll reads all thresholds from the store, the ones of every tenant.
+// - the thresholds of each tenant are kept in a file of their own, see tenantPath
+func (s *StoreReorderThresholdJSON) ReadAll() (t map[string]map[internal.ReorderScope]internal.ReorderThreshold, err error) {
+	paths, err := tenantPaths(s.Path)
+	if err != nil {
+		return
+	}
+
+	t = make(map[string]map[internal.ReorderScope]internal.ReorderThreshold, len(paths))
+	for tenant, path := range paths {
+		t[tenant], err = s.readFile(path)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// readFile reads the thresholds of a tenant from its file, by scope.
+// - a missing file is read as no thresholds
+func (s *StoreReorderThresholdJSON) readFile(path string) (t map[internal.ReorderScope]internal.ReorderThreshold, err error) {
+	t = make(map[internal.ReorderScope]internal.ReorderThreshold)
+
+	// open file
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		return
+	}
+	defer f.Close()
+
+	// decode JSON
+	var tr []ReorderThresholdJSON
+	err = json.NewDecoder(f).Decode(&tr)
+	if err != nil {
+		return
+	}
+
+	// serialize
+	for _, v := range tr {
+		scope := internal.ReorderScope{Kind: v.Scope, Id: v.ScopeId}
+		t[scope] = internal.ReorderThreshold{
+			ReorderScope: scope,
+			Quantity:     v.Quantity,
+		}
+	}
+
+	return
+}
+
+// WriteAll writes all thresholds to the store, the ones of each tenant to its file.
+// - the file of a tenant left without thresholds is emptied, the one of the default tenant is always written
+func (s *StoreReorderThresholdJSON) WriteAll(t map[string]map[internal.ReorderScope]internal.ReorderThreshold) (err error) {
+	paths, err := tenantPaths(s.Path)
+	if err != nil {
+		return
+	}
+
+	// serialize
+	// - sorted by scope
+	tr := make(map[string][]ReorderThresholdJSON)
+	for tenant := range paths {
+		tr[tenant] = []ReorderThresholdJSON{}
+	}
+	for tenant, ts := range t {
+		if tenant == "" {
+			tenant = internal.TenantDefault
+		}
+		for _, v := range ts {
+			tr[tenant] = append(tr[tenant], ReorderThresholdJSON{
+				Scope:    v.Kind,
+				ScopeId:  v.Id,
+				Quantity: v.Quantity,
+			})
+		}
+	}
+
+	for tenant, v := range tr {
+		sort.Slice(v, func(i, j int) bool {
+			if v[i].Scope != v[j].Scope {
+				return v[i].Scope < v[j].Scope
+			}
+			return v[i].ScopeId < v[j].ScopeId
+		})
+		err = writeJSON(tenantPath(s.Path, tenant), v)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
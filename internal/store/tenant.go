@@ -0,0 +1,44 @@
+package store
+
+import (
+	"app/internal"
+	"path/filepath"
+	"strings"
+)
+
+// tenantPath returns the path of the file of a tenant of a store.
+// - the default tenant keeps the path of the store, another one gets its name before the extension, e.g. products.acme.json
+func tenantPath(path, tenant string) string {
+	if tenant == "" || tenant == internal.TenantDefault {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + tenant + ext
+}
+
+// tenantPaths returns the paths of the files of the tenants of a store, by tenant.
+// - the default tenant is always part of them, its file may not exist yet
+func tenantPaths(path string) (paths map[string]string, err error) {
+	paths = map[string]string{internal.TenantDefault: path}
+
+	ext := filepath.Ext(path)
+	prefix := strings.TrimSuffix(path, ext) + "."
+	matches, err := filepath.Glob(escapeGlob(prefix) + "*" + escapeGlob(ext))
+	if err != nil {
+		return
+	}
+	for _, m := range matches {
+		tenant := strings.TrimSuffix(strings.TrimPrefix(m, prefix), ext)
+		if !internal.TenantValid(tenant) || tenant == internal.TenantDefault {
+			continue
+		}
+		paths[tenant] = m
+	}
+	return
+}
+
+// escapeGlob escapes the meta characters of a path, so it is matched literally by a glob.
+func escapeGlob(path string) string {
+	r := strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`)
+	return r.Replace(path)
+}
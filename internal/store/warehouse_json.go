@@ -0,0 +1,162 @@
+package store
+
+import (
+	"app/internal"
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+)
+
+// NewStoreWarehouseJSON creates a new JSON file store for warehouses.
+func NewStoreWarehouseJSON(path string) (s *StoreWarehouseJSON) {
+	s = &StoreWarehouseJSON{
+		Path: path,
+	}
+	return
+}
+
+// StoreWarehouseJSON is a JSON file store for warehouses.
+type StoreWarehouseJSON struct {
+	// Path is the path to the JSON file.
+	Path string
+}
+
+// WarehouseJSON is a JSON representation of a warehouse.
+type WarehouseJSON struct {
+	Id        int    `json:"id"`
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	Telephone string `json:"telephone"`
+	Capacity  int    `json:"capacity"`
+	Version   int    `json:"version"`
+	DeletedAt string `json:"deleted_at,omitempty"`
+}
+
+// ReadAll reads all warehouses from the store, the ones of every tenant.
+// - the warehouses of each tenant are kept in a file of their own, see tenantPath
+// - a missing file is read as an empty store
+func (s *StoreWarehouseJSON) ReadAll() (w map[int]internal.Warehouse, err error) {
+	paths, err := tenantPaths(s.Path)
+	if err != nil {
+		return
+	}
+
+	w = make(map[int]internal.Warehouse)
+	for tenant, path := range paths {
+		err = s.readFile(path, tenant, w)
+		if err != nil {
+			w = nil
+			return
+		}
+	}
+
+	return
+}
+
+// readFile reads the warehouses of a tenant from its file into w.
+func (s *StoreWarehouseJSON) readFile(path, tenant string, w map[int]internal.Warehouse) (err error) {
+	// open file
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		return
+	}
+	defer f.Close()
+
+	// decode JSON
+	var wh []WarehouseJSON
+	err = json.NewDecoder(f).Decode(&wh)
+	if err != nil {
+		return
+	}
+
+	// serialize
+	for _, v := range wh {
+		var deletedAt time.Time
+		if v.DeletedAt != "" {
+			deletedAt, err = time.Parse(time.RFC3339, v.DeletedAt)
+			if err != nil {
+				return
+			}
+		}
+		w[v.Id] = internal.Warehouse{
+			Id:     v.Id,
+			Tenant: tenant,
+			WarehouseAttributes: internal.WarehouseAttributes{
+				Name:      v.Name,
+				Address:   v.Address,
+				Telephone: v.Telephone,
+				Capacity:  v.Capacity,
+			},
+			Version:   max(v.Version, 1),
+			DeletedAt: deletedAt,
+		}
+	}
+
+	return
+}
+
+// WriteAll writes all warehouses to the store, each to the file of its tenant.
+// - the file of a tenant left without warehouses is emptied, the one of the default tenant is always written
+func (s *StoreWarehouseJSON) WriteAll(w map[int]internal.Warehouse) (err error) {
+	paths, err := tenantPaths(s.Path)
+	if err != nil {
+		return
+	}
+
+	// serialize
+	wh := make(map[string][]WarehouseJSON)
+	for tenant := range paths {
+		wh[tenant] = nil
+	}
+	for _, v := range w {
+		var deletedAt string
+		if !v.DeletedAt.IsZero() {
+			deletedAt = v.DeletedAt.Format(time.RFC3339)
+		}
+		tenant := v.Tenant
+		if tenant == "" {
+			tenant = internal.TenantDefault
+		}
+		wh[tenant] = append(wh[tenant], WarehouseJSON{
+			Id:        v.Id,
+			Name:      v.Name,
+			Address:   v.Address,
+			Telephone: v.Telephone,
+			Capacity:  v.Capacity,
+			Version:   v.Version,
+			DeletedAt: deletedAt,
+		})
+	}
+
+	for tenant, v := range wh {
+		err = s.writeFile(tenantPath(s.Path, tenant), v)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// writeFile writes the warehouses of a tenant to its file.
+func (s *StoreWarehouseJSON) writeFile(path string, wh []WarehouseJSON) (err error) {
+	// open file
+	// - create if not exists / write only / truncate
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	// encode JSON
+	err = json.NewEncoder(f).Encode(wh)
+	if err != nil {
+		return
+	}
+
+	return
+}
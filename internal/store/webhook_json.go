@@ -0,0 +1,204 @@
+package store
+
+import (
+	"app/internal"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"time"
+)
+
+// NewStoreWebhookJSON creates a new JSON file store for webhooks.
+func NewStoreWebhookJSON(path string) (s *StoreWebhookJSON) {
+	s = &StoreWebhookJSON{
+		Path: path,
+	}
+	return
+}
+
+// StoreWebhookJSON is a JSON file store for webhooks.
+type StoreWebhookJSON struct {
+	// Path is the path to the JSON file.
+	Path string
+}
+
+// WebhookJSON is a JSON representation of a webhook.
+type WebhookJSON struct {
+	Id        int      `json:"id"`
+	Tenant    string   `json:"tenant,omitempty"`
+	URL       string   `json:"url"`
+	Secret    string   `json:"secret"`
+	Events    []string `json:"events,omitempty"`
+	CreatedAt string   `json:"created_at"`
+}
+
+// ReadAll reads all webhooks from the store.
+// - a missing file is read as an empty store
+func (s *StoreWebhookJSON) ReadAll() (w map[int]internal.Webhook, err error) {
+	w = make(map[int]internal.Webhook)
+
+	// decode JSON
+	var wh []WebhookJSON
+	err = readJSON(s.Path, &wh)
+	if err != nil {
+		return
+	}
+
+	// serialize
+	for _, v := range wh {
+		var createdAt time.Time
+		createdAt, err = time.Parse(time.RFC3339, v.CreatedAt)
+		if err != nil {
+			return
+		}
+		// - webhooks predating tenants are of the default one
+		if v.Tenant == "" {
+			v.Tenant = internal.TenantDefault
+		}
+		w[v.Id] = internal.Webhook{
+			Id:        v.Id,
+			Tenant:    v.Tenant,
+			URL:       v.URL,
+			Secret:    v.Secret,
+			Events:    v.Events,
+			CreatedAt: createdAt,
+		}
+	}
+
+	return
+}
+
+// WriteAll writes all webhooks to the store.
+func (s *StoreWebhookJSON) WriteAll(w map[int]internal.Webhook) (err error) {
+	// serialize
+	// - sorted by id
+	wh := make([]WebhookJSON, 0, len(w))
+	for _, v := range w {
+		wh = append(wh, WebhookJSON{
+			Id:        v.Id,
+			Tenant:    v.Tenant,
+			URL:       v.URL,
+			Secret:    v.Secret,
+			Events:    v.Events,
+			CreatedAt: v.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	sort.Slice(wh, func(i, j int) bool {
+		return wh[i].Id < wh[j].Id
+	})
+
+	err = writeJSON(s.Path, wh)
+	return
+}
+
+// NewStoreDeadLetterJSON creates a new JSON file store for dead letters.
+func NewStoreDeadLetterJSON(path string) (s *StoreDeadLetterJSON) {
+	s = &StoreDeadLetterJSON{
+		Path: path,
+	}
+	return
+}
+
+// StoreDeadLetterJSON is a JSON file store for dead letters.
+type StoreDeadLetterJSON struct {
+	// Path is the path to the JSON file.
+	Path string
+}
+
+// DeadLetterJSON is a JSON representation of a dead letter.
+type DeadLetterJSON struct {
+	Id        int            `json:"id"`
+	WebhookId int            `json:"webhook_id"`
+	Event     internal.Event `json:"event"`
+	Attempts  int            `json:"attempts"`
+	LastError string         `json:"last_error"`
+	FailedAt  string         `json:"failed_at"`
+}
+
+// ReadAll reads all dead letters from the store.
+// - a missing file is read as an empty store
+func (s *StoreDeadLetterJSON) ReadAll() (d map[int]internal.DeadLetter, err error) {
+	d = make(map[int]internal.DeadLetter)
+
+	// decode JSON
+	var dl []DeadLetterJSON
+	err = readJSON(s.Path, &dl)
+	if err != nil {
+		return
+	}
+
+	// serialize
+	for _, v := range dl {
+		var failedAt time.Time
+		failedAt, err = time.Parse(time.RFC3339, v.FailedAt)
+		if err != nil {
+			return
+		}
+		d[v.Id] = internal.DeadLetter{
+			Id:        v.Id,
+			WebhookId: v.WebhookId,
+			Event:     v.Event,
+			Attempts:  v.Attempts,
+			LastError: v.LastError,
+			FailedAt:  failedAt,
+		}
+	}
+
+	return
+}
+
+// WriteAll writes all dead letters to the store.
+func (s *StoreDeadLetterJSON) WriteAll(d map[int]internal.DeadLetter) (err error) {
+	// serialize
+	// - sorted by id
+	dl := make([]DeadLetterJSON, 0, len(d))
+	for _, v := range d {
+		dl = append(dl, DeadLetterJSON{
+			Id:        v.Id,
+			WebhookId: v.WebhookId,
+			Event:     v.Event,
+			Attempts:  v.Attempts,
+			LastError: v.LastError,
+			FailedAt:  v.FailedAt.Format(time.RFC3339),
+		})
+	}
+	sort.Slice(dl, func(i, j int) bool {
+		return dl[i].Id < dl[j].Id
+	})
+
+	err = writeJSON(s.Path, dl)
+	return
+}
+
+// readJSON decodes a JSON file into v, a missing file leaves v untouched.
+func readJSON(path string, v any) (err error) {
+	// open file
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		return
+	}
+	defer f.Close()
+
+	// decode JSON
+	err = json.NewDecoder(f).Decode(v)
+	return
+}
+
+// writeJSON encodes v into a JSON file.
+func writeJSON(path string, v any) (err error) {
+	// open file
+	// - create if not exists / write only / truncate
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	// encode JSON
+	err = json.NewEncoder(f).Encode(v)
+	return
+}
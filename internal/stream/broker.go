@@ -0,0 +1,196 @@
+package stream
+
+import (
+	"app/internal"
+	"context"
+	"sync"
+	"time"
+)
+
+// ConfigBroker is the configuration of a broker.
+type ConfigBroker struct {
+	// BufferSize is how many of the last events are kept to resume a stream.
+	BufferSize int
+	// SubscriberBuffer is how many events can wait for a subscriber before it is dropped.
+	SubscriberBuffer int
+	// Heartbeat is the time between two keep-alives sent on an idle stream.
+	Heartbeat time.Duration
+}
+
+// NewBroker creates a new broker streaming the events to its subscribers.
+func NewBroker(cfg *ConfigBroker) (b *Broker) {
+	// default config
+	defaultConfig := &ConfigBroker{
+		BufferSize:       1024,
+		SubscriberBuffer: 64,
+		Heartbeat:        15 * time.Second,
+	}
+	if cfg != nil {
+		if cfg.BufferSize > 0 {
+			defaultConfig.BufferSize = cfg.BufferSize
+		}
+		if cfg.SubscriberBuffer > 0 {
+			defaultConfig.SubscriberBuffer = cfg.SubscriberBuffer
+		}
+		if cfg.Heartbeat > 0 {
+			defaultConfig.Heartbeat = cfg.Heartbeat
+		}
+	}
+
+	b = &Broker{
+		cfg:  defaultConfig,
+		subs: make(map[*Subscription]struct{}),
+	}
+	return
+}
+
+// Broker streams the events to its subscribers.
+// - the last events are kept in a bounded buffer, so a subscriber can resume after the last event it got
+// - a subscriber too slow to keep up is dropped, its channel closed, and is expected to resume
+type Broker struct {
+	// cfg is the configuration of the broker.
+	cfg *ConfigBroker
+
+	// mu guards buf, subs and closed.
+	mu sync.Mutex
+	// buf holds the last events, oldest first.
+	buf []internal.Event
+	// subs are the current subscriptions.
+	subs map[*Subscription]struct{}
+	// closed tells whether the broker is closed.
+	closed bool
+}
+
+// Filter selects the events of a subscription.
+type Filter struct {
+	// WarehouseId selects the events of a warehouse and of its products, 0 selects every event.
+	WarehouseId int
+	// Types are the patterns of the selected event types, e.g. product.*, none selects every type.
+	Types []string
+	// Tenant selects the events of a tenant, empty selects the events of every tenant.
+	Tenant string
+}
+
+// Match tells whether an event is selected by the filter.
+func (f Filter) Match(e internal.Event) bool {
+	if f.Tenant != "" && !e.InTenant(f.Tenant) {
+		return false
+	}
+	if f.WarehouseId != 0 && !e.InWarehouse(f.WarehouseId) {
+		return false
+	}
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, pattern := range f.Types {
+		if internal.EventMatches(pattern, e.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription is a stream of the events selected by a filter.
+type Subscription struct {
+	// C receives the events, it is closed once the subscription ends.
+	C <-chan internal.Event
+	// ch is the sending side of C.
+	ch chan internal.Event
+	// filter selects the events.
+	filter Filter
+}
+
+// Heartbeat returns the time between two keep-alives sent on an idle stream.
+func (b *Broker) Heartbeat() time.Duration {
+	return b.cfg.Heartbeat
+}
+
+// Publish sends an event to the matching subscribers and keeps it in the buffer.
+func (b *Broker) Publish(ctx context.Context, e internal.Event) (err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	// buffer
+	b.buf = append(b.buf, e)
+	if len(b.buf) > b.cfg.BufferSize {
+		b.buf = b.buf[len(b.buf)-b.cfg.BufferSize:]
+	}
+
+	// send
+	for s := range b.subs {
+		if !s.filter.Match(e) {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			b.drop(s)
+		}
+	}
+	return
+}
+
+// Subscribe subscribes to the events selected by a filter, returning the buffered ones after lastEventId to replay first.
+// - an empty lastEventId replays nothing, an id no longer in the buffer replays the whole buffer
+func (b *Broker) Subscribe(filter Filter, lastEventId string) (s *Subscription, replay []internal.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan internal.Event, b.cfg.SubscriberBuffer)
+	s = &Subscription{C: ch, ch: ch, filter: filter}
+	if b.closed {
+		close(ch)
+		return
+	}
+	b.subs[s] = struct{}{}
+
+	// replay
+	if lastEventId == "" {
+		return
+	}
+	from := 0
+	for i := len(b.buf) - 1; i >= 0; i-- {
+		if b.buf[i].Id == lastEventId {
+			from = i + 1
+			break
+		}
+	}
+	for _, e := range b.buf[from:] {
+		if filter.Match(e) {
+			replay = append(replay, e)
+		}
+	}
+	return
+}
+
+// Unsubscribe ends a subscription.
+func (b *Broker) Unsubscribe(s *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.drop(s)
+}
+
+// Close ends every subscription, events published afterwards are discarded.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.subs {
+		b.drop(s)
+	}
+	b.closed = true
+}
+
+// drop removes a subscription and closes its channel, b.mu must be held.
+func (b *Broker) drop(s *Subscription) {
+	if _, ok := b.subs[s]; !ok {
+		return
+	}
+	delete(b.subs, s)
+	close(s.ch)
+}
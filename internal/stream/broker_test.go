@@ -0,0 +1,135 @@
+package stream_test
+
+import (
+	"app/internal"
+	"app/internal/stream"
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// event creates an event of a product of a warehouse.
+func event(id int, typ string, warehouseId int) internal.Event {
+	after, _ := json.Marshal(internal.Product{Id: 1, WarehouseId: warehouseId})
+	return internal.Event{Id: strconv.Itoa(id), Type: typ, EntityId: 1, After: after}
+}
+
+// Tests for Broker.Publish
+func TestBroker_Publish(t *testing.T) {
+	t.Run("success - matching events sent to the subscriber", func(t *testing.T) {
+		// arrange
+		br := stream.NewBroker(nil)
+		s, _ := br.Subscribe(stream.Filter{WarehouseId: 1, Types: []string{"product.*"}}, "")
+
+		// act
+		require.NoError(t, br.Publish(context.Background(), event(1, internal.EventProductCreated, 1)))
+		require.NoError(t, br.Publish(context.Background(), event(2, internal.EventProductCreated, 2)))
+		require.NoError(t, br.Publish(context.Background(), internal.Event{Id: "3", Type: internal.EventWarehouseUpdated, EntityId: 1}))
+		require.NoError(t, br.Publish(context.Background(), event(4, internal.EventProductUpdated, 1)))
+
+		// assert
+		require.Equal(t, "1", (<-s.C).Id)
+		require.Equal(t, "4", (<-s.C).Id)
+		require.Empty(t, s.C)
+	})
+
+	t.Run("success - only the events of the tenant of the subscriber sent", func(t *testing.T) {
+		// arrange
+		br := stream.NewBroker(nil)
+		s, _ := br.Subscribe(stream.Filter{Tenant: "acme"}, "")
+		other := event(2, internal.EventProductCreated, 1)
+		other.Tenant = "globex"
+		own := event(3, internal.EventProductCreated, 1)
+		own.Tenant = "acme"
+
+		// act
+		require.NoError(t, br.Publish(context.Background(), event(1, internal.EventProductCreated, 1)))
+		require.NoError(t, br.Publish(context.Background(), other))
+		require.NoError(t, br.Publish(context.Background(), own))
+
+		// assert
+		require.Equal(t, "3", (<-s.C).Id)
+		require.Empty(t, s.C)
+	})
+
+	t.Run("fail - slow subscriber dropped", func(t *testing.T) {
+		// arrange
+		br := stream.NewBroker(&stream.ConfigBroker{SubscriberBuffer: 1})
+		s, _ := br.Subscribe(stream.Filter{}, "")
+
+		// act
+		require.NoError(t, br.Publish(context.Background(), event(1, internal.EventProductCreated, 1)))
+		require.NoError(t, br.Publish(context.Background(), event(2, internal.EventProductCreated, 1)))
+
+		// assert
+		e, ok := <-s.C
+		require.True(t, ok)
+		require.Equal(t, "1", e.Id)
+		_, ok = <-s.C
+		require.False(t, ok)
+	})
+}
+
+// Tests for Broker.Subscribe
+func TestBroker_Subscribe(t *testing.T) {
+	t.Run("success - events after the last event id replayed", func(t *testing.T) {
+		// arrange
+		br := stream.NewBroker(nil)
+		for i := 1; i <= 4; i++ {
+			require.NoError(t, br.Publish(context.Background(), event(i, internal.EventProductCreated, i%2)))
+		}
+
+		// act
+		_, replay := br.Subscribe(stream.Filter{WarehouseId: 1}, "1")
+
+		// assert
+		require.Len(t, replay, 1)
+		require.Equal(t, "3", replay[0].Id)
+	})
+
+	t.Run("success - last event id out of the buffer replays the whole buffer", func(t *testing.T) {
+		// arrange
+		br := stream.NewBroker(&stream.ConfigBroker{BufferSize: 2})
+		for i := 1; i <= 4; i++ {
+			require.NoError(t, br.Publish(context.Background(), event(i, internal.EventProductCreated, 1)))
+		}
+
+		// act
+		_, replay := br.Subscribe(stream.Filter{}, "1")
+
+		// assert
+		require.Len(t, replay, 2)
+		require.Equal(t, "3", replay[0].Id)
+		require.Equal(t, "4", replay[1].Id)
+	})
+
+	t.Run("success - no last event id replays nothing", func(t *testing.T) {
+		// arrange
+		br := stream.NewBroker(nil)
+		require.NoError(t, br.Publish(context.Background(), event(1, internal.EventProductCreated, 1)))
+
+		// act
+		_, replay := br.Subscribe(stream.Filter{}, "")
+
+		// assert
+		require.Empty(t, replay)
+	})
+}
+
+// Tests for Broker.Close
+func TestBroker_Close(t *testing.T) {
+	// arrange
+	br := stream.NewBroker(nil)
+	s, _ := br.Subscribe(stream.Filter{}, "")
+
+	// act
+	br.Close()
+
+	// assert
+	_, ok := <-s.C
+	require.False(t, ok)
+	require.NoError(t, br.Publish(context.Background(), event(1, internal.EventProductCreated, 1)))
+}
@@ -0,0 +1,77 @@
+package stream
+
+import (
+	"app/internal"
+	"encoding/json"
+)
+
+// QuantityChange is a change of the quantity of a product in a warehouse.
+type QuantityChange struct {
+	// EventId is the id of the event of the change.
+	EventId string
+	// ProductId is the id of the product.
+	ProductId int
+	// WarehouseId is the id of the warehouse.
+	WarehouseId int
+	// Quantity is the quantity in the warehouse after the change, 0 once removed.
+	Quantity int
+	// PreviousQuantity is the quantity in the warehouse before the change, 0 if it was not in it.
+	PreviousQuantity int
+	// Removed tells whether the product left the warehouse, deleted or moved to another one.
+	Removed bool
+}
+
+// QuantityChangeOf returns the change of the quantity of a product in a warehouse carried by an event.
+// - ok is false if the event is not of a product of the warehouse, or leaves its quantity unchanged
+func QuantityChangeOf(e internal.Event, warehouseId int) (c QuantityChange, ok bool) {
+	if e.Entity() != internal.AuditEntityProduct {
+		return
+	}
+
+	before, inBefore := snapshotIn(e.Before, warehouseId)
+	after, inAfter := snapshotIn(e.After, warehouseId)
+	// a soft deleted product is back in its warehouse once restored
+	if e.Type == internal.EventProductRestored {
+		inBefore = false
+	}
+	if !inBefore && !inAfter {
+		return
+	}
+
+	c = QuantityChange{
+		EventId:     e.Id,
+		ProductId:   e.EntityId,
+		WarehouseId: warehouseId,
+	}
+	if inBefore {
+		c.PreviousQuantity = before.Quantity
+	}
+	switch {
+	case !inAfter:
+		c.Removed = true
+	case e.Type == internal.EventProductDeleted:
+		// a soft deleted product keeps its snapshot
+		c.Removed = true
+	default:
+		c.Quantity = after.Quantity
+	}
+
+	ok = c.Removed || !inBefore || c.Quantity != c.PreviousQuantity
+	return
+}
+
+// productSnapshot is the part of the snapshot of a product a quantity change is read from.
+type productSnapshot struct {
+	Id          int
+	Quantity    int
+	WarehouseId int
+}
+
+// snapshotIn decodes the snapshot of a product, in is false if it is empty or of another warehouse.
+func snapshotIn(raw json.RawMessage, warehouseId int) (p productSnapshot, in bool) {
+	if len(raw) == 0 || json.Unmarshal(raw, &p) != nil {
+		return
+	}
+	in = p.Id != 0 && p.WarehouseId == warehouseId
+	return
+}
@@ -0,0 +1,78 @@
+package stream_test
+
+import (
+	"app/internal"
+	"app/internal/stream"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for QuantityChangeOf
+func TestQuantityChangeOf(t *testing.T) {
+	cases := []struct {
+		name   string
+		event  internal.Event
+		change stream.QuantityChange
+		ok     bool
+	}{
+		{
+			name:   "created",
+			event:  internal.Event{Id: "1", Type: internal.EventProductCreated, EntityId: 3, After: []byte(`{"Id":3,"Quantity":5,"WarehouseId":1}`)},
+			change: stream.QuantityChange{EventId: "1", ProductId: 3, WarehouseId: 1, Quantity: 5},
+			ok:     true,
+		},
+		{
+			name:   "quantity updated",
+			event:  internal.Event{Id: "2", Type: internal.EventProductUpdated, EntityId: 3, Before: []byte(`{"Id":3,"Quantity":5,"WarehouseId":1}`), After: []byte(`{"Id":3,"Quantity":2,"WarehouseId":1}`)},
+			change: stream.QuantityChange{EventId: "2", ProductId: 3, WarehouseId: 1, Quantity: 2, PreviousQuantity: 5},
+			ok:     true,
+		},
+		{
+			name:  "quantity unchanged",
+			event: internal.Event{Id: "3", Type: internal.EventProductUpdated, EntityId: 3, Before: []byte(`{"Id":3,"Quantity":5,"WarehouseId":1}`), After: []byte(`{"Id":3,"Quantity":5,"WarehouseId":1}`)},
+			ok:    false,
+		},
+		{
+			name:   "moved out",
+			event:  internal.Event{Id: "4", Type: internal.EventProductUpdated, EntityId: 3, Before: []byte(`{"Id":3,"Quantity":5,"WarehouseId":1}`), After: []byte(`{"Id":3,"Quantity":5,"WarehouseId":2}`)},
+			change: stream.QuantityChange{EventId: "4", ProductId: 3, WarehouseId: 1, PreviousQuantity: 5, Removed: true},
+			ok:     true,
+		},
+		{
+			name:   "deleted",
+			event:  internal.Event{Id: "5", Type: internal.EventProductDeleted, EntityId: 3, Before: []byte(`{"Id":3,"Quantity":5,"WarehouseId":1}`), After: []byte(`{"Id":3,"Quantity":5,"WarehouseId":1}`)},
+			change: stream.QuantityChange{EventId: "5", ProductId: 3, WarehouseId: 1, PreviousQuantity: 5, Removed: true},
+			ok:     true,
+		},
+		{
+			name:   "restored",
+			event:  internal.Event{Id: "8", Type: internal.EventProductRestored, EntityId: 3, Before: []byte(`{"Id":3,"Quantity":5,"WarehouseId":1}`), After: []byte(`{"Id":3,"Quantity":5,"WarehouseId":1}`)},
+			change: stream.QuantityChange{EventId: "8", ProductId: 3, WarehouseId: 1, Quantity: 5},
+			ok:     true,
+		},
+		{
+			name:  "other warehouse",
+			event: internal.Event{Id: "6", Type: internal.EventProductCreated, EntityId: 3, After: []byte(`{"Id":3,"Quantity":5,"WarehouseId":2}`)},
+			ok:    false,
+		},
+		{
+			name:  "warehouse",
+			event: internal.Event{Id: "7", Type: internal.EventWarehouseUpdated, EntityId: 1},
+			ok:    false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			change, ok := stream.QuantityChangeOf(c.event, 1)
+
+			// assert
+			require.Equal(t, c.ok, ok)
+			if c.ok {
+				require.Equal(t, c.change, change)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"context"
+	"regexp"
+)
+
+const (
+	// TenantDefault is the tenant of the requests that do not identify one, and of the data predating tenants.
+	TenantDefault = "default"
+	// TenantAll is the tenant of the background jobs, which work on the data of every tenant.
+	// - it is never a valid tenant of a request
+	TenantAll = "*"
+)
+
+// tenantPattern is the pattern of a valid tenant, it is also part of file names.
+var tenantPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// TenantValid tells whether a tenant is a valid tenant of a request.
+func TenantValid(tenant string) bool {
+	return tenantPattern.MatchString(tenant)
+}
+
+// tenantKey is the context key of the tenant.
+type tenantKey struct{}
+
+// ContextWithTenant returns a copy of ctx carrying the tenant whose data the request works on.
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant carried by ctx, TenantDefault if there is none.
+func TenantFromContext(ctx context.Context) (tenant string) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	if !ok || tenant == "" {
+		tenant = TenantDefault
+	}
+	return
+}
+
+// InTenant tells whether the data of a tenant is visible to ctx, the one of its tenant or any with TenantAll.
+// - data without tenant predates tenants, it is of the default one
+func InTenant(ctx context.Context, tenant string) bool {
+	t := TenantFromContext(ctx)
+	return t == TenantAll || t == tenantOrDefault(tenant)
+}
+
+// tenantOrDefault returns a tenant, TenantDefault if it is empty.
+func tenantOrDefault(tenant string) string {
+	if tenant == "" {
+		return TenantDefault
+	}
+	return tenant
+}
@@ -0,0 +1,64 @@
+package internal_test
+
+import (
+	"app/internal"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for TenantValid
+func TestTenantValid(t *testing.T) {
+	cases := []struct {
+		tenant string
+		valid  bool
+	}{
+		{tenant: "acme", valid: true},
+		{tenant: "acme-2_eu", valid: true},
+		{tenant: "", valid: false},
+		{tenant: internal.TenantAll, valid: false},
+		{tenant: "Acme", valid: false},
+		{tenant: "../acme", valid: false},
+		{tenant: "-acme", valid: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.tenant, func(t *testing.T) {
+			// act
+			valid := internal.TenantValid(c.tenant)
+
+			// assert
+			require.Equal(t, c.valid, valid)
+		})
+	}
+}
+
+// Tests for InTenant
+func TestInTenant(t *testing.T) {
+	cases := []struct {
+		name   string
+		ctx    string
+		tenant string
+		in     bool
+	}{
+		{name: "same tenant", ctx: "acme", tenant: "acme", in: true},
+		{name: "other tenant", ctx: "acme", tenant: "globex", in: false},
+		{name: "data without tenant is of the default tenant", ctx: internal.TenantDefault, tenant: "", in: true},
+		{name: "data without tenant is not of another tenant", ctx: "acme", tenant: "", in: false},
+		{name: "every tenant", ctx: internal.TenantAll, tenant: "globex", in: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			ctx := internal.ContextWithTenant(context.Background(), c.ctx)
+
+			// act
+			in := internal.InTenant(ctx, c.tenant)
+
+			// assert
+			require.Equal(t, c.in, in)
+		})
+	}
+}
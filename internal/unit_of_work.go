@@ -0,0 +1,24 @@
+package internal
+
+import "context"
+
+// RepositoriesTx are the repositories bound to a unit of work.
+type RepositoriesTx struct {
+	// Product is the repository for products.
+	Product RepositoryProduct
+	// Warehouse is the repository for warehouses.
+	Warehouse RepositoryWarehouse
+	// Price is the repository for price changes.
+	Price RepositoryPrice
+	// Outbox is the outbox of the events.
+	Outbox RepositoryOutbox
+	// Audit is the audit log bound to the unit of work, nil if the audit log is not part of it.
+	Audit RepositoryAudit
+}
+
+// UnitOfWork is an interface to run operations across repositories atomically.
+type UnitOfWork interface {
+	// Do runs fn with repositories bound to the same unit of work,
+	// committing the changes if fn succeeds and discarding them otherwise
+	Do(ctx context.Context, fn func(r RepositoriesTx) (err error)) (err error)
+}
@@ -1,5 +1,7 @@
 package internal
 
+import "time"
+
 // WarehouseAttributes is a struct that contains the attributes of a warehouse
 type WarehouseAttributes struct {
 	// Name is the name of the Warehouse
@@ -14,10 +16,19 @@ type WarehouseAttributes struct {
 
 // Warehouse is a struct that contains the attributes of a Warehouse
 type Warehouse struct {
-	// Id is the unique identifier of the Warehouse
+	// Id is the unique identifier of the Warehouse, across tenants
 	Id int
+	// Tenant is the tenant owning the Warehouse
+	Tenant string
 	// WarehouseAttributes is the attributes of the Warehouse
 	WarehouseAttributes
+
+	// Version is the version of the Warehouse, incremented on every update
+	// - updating with version 0 skips the version check
+	Version int
+
+	// DeletedAt is the time the Warehouse was soft deleted, zero if it is not deleted
+	DeletedAt time.Time
 }
 
 type WarehouseProductsCount struct {
@@ -1,19 +1,36 @@
 package internal
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"time"
+)
 
 var (
 	// ErrRepositoryProductNotFound is returned when a product is not found.
 	ErrRepositoryWarehouseNotFound   = errors.New("repository: Warehouse not found")
 	ErrRepositoryWarehouseDuplicated = errors.New("repository: Warehouse duplicated")
+	// ErrRepositoryWarehouseVersionConflict is returned when a Warehouse was modified since the version being updated.
+	ErrRepositoryWarehouseVersionConflict = errors.New("repository: Warehouse version conflict")
 )
 
 // RepositoryWarehouse is an interface that contains the methods for a Warehouse repository
 type RepositoryWarehouse interface {
 	// FindById returns a warehouse by its id
-	FindById(id int) (w Warehouse, err error)
+	FindById(ctx context.Context, id int) (w Warehouse, err error)
 	// Save saves a warehouse
-	Save(w *Warehouse) (err error)
-	ReportProducts(id int) (w []WarehouseProductsCount, err error)
-	GetAll() (w []Warehouse, err error)
+	Save(ctx context.Context, w *Warehouse) (err error)
+	// Update updates a warehouse
+	Update(ctx context.Context, w *Warehouse) (err error)
+	// Delete soft deletes a warehouse, hiding it from every finder but GetAllIncludingDeleted
+	// - a version other than 0 must be the current one, otherwise ErrRepositoryWarehouseVersionConflict is returned
+	Delete(ctx context.Context, id int, version int) (err error)
+	// Restore restores a soft deleted warehouse
+	Restore(ctx context.Context, id int) (err error)
+	// Purge permanently removes the warehouses soft deleted before a time, returning how many were removed
+	Purge(ctx context.Context, before time.Time) (n int, err error)
+	ReportProducts(ctx context.Context, id int) (w []WarehouseProductsCount, err error)
+	GetAll(ctx context.Context) (w []Warehouse, err error)
+	// GetAllIncludingDeleted returns all warehouses, soft deleted ones included
+	GetAllIncludingDeleted(ctx context.Context) (w []Warehouse, err error)
 }
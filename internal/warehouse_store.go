@@ -0,0 +1,9 @@
+package internal
+
+// StoreWarehouse is an interface for a warehouse store.
+type StoreWarehouse interface {
+	// ReadAll reads all warehouses from the store.
+	ReadAll() (w map[int]Warehouse, err error)
+	// WriteAll writes all warehouses to the store.
+	WriteAll(w map[int]Warehouse) (err error)
+}
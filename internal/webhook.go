@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"time"
+)
+
+var (
+	// ErrWebhookInvalid is returned when a webhook has no absolute http url.
+	ErrWebhookInvalid = errors.New("webhook: invalid url")
+	// ErrWebhookEventInvalid is returned when an event pattern of a webhook matches no event type.
+	ErrWebhookEventInvalid = errors.New("webhook: invalid event")
+)
+
+// Webhook is a url the events are delivered to
+type Webhook struct {
+	// Id is the unique identifier of the webhook
+	Id int
+	// Tenant is the tenant the webhook is delivered the events of
+	Tenant string
+	// URL is the http url the events are posted to
+	URL string
+	// Secret is the key the events are signed with
+	Secret string
+	// Events are the patterns of the event types delivered, e.g. product.* (every type when empty)
+	Events []string
+	// CreatedAt is when the webhook was registered
+	CreatedAt time.Time
+}
+
+// Validate checks the url and the event patterns of a webhook.
+func (w Webhook) Validate() (err error) {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		err = ErrWebhookInvalid
+		return
+	}
+
+	for _, pattern := range w.Events {
+		if !slices.ContainsFunc(EventTypes, func(typ string) bool { return EventMatches(pattern, typ) }) {
+			err = ErrWebhookEventInvalid
+			return
+		}
+	}
+	return
+}
+
+// Subscribes tells whether a webhook is delivered the events of a type.
+func (w Webhook) Subscribes(typ string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, pattern := range w.Events {
+		if EventMatches(pattern, typ) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeadLetter is an event that could not be delivered to a webhook
+type DeadLetter struct {
+	// Id is the unique identifier of the dead letter
+	Id int
+	// WebhookId is the id of the webhook the event was not delivered to
+	WebhookId int
+	// Event is the undelivered event
+	Event Event
+	// Attempts is how many times the delivery was attempted
+	Attempts int
+	// LastError is the error of the last attempt
+	LastError string
+	// FailedAt is when the last attempt failed
+	FailedAt time.Time
+}
@@ -0,0 +1,291 @@
+package webhook
+
+import (
+	"app/internal"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrDeliveryRejected is returned when a receiver rejects a delivery for good, it is not retried.
+	ErrDeliveryRejected = errors.New("webhook: delivery rejected")
+	// ErrQueueFull is returned when a delivery can not wait for a worker.
+	ErrQueueFull = errors.New("webhook: queue full")
+	// ErrDispatcherStopped is returned when a delivery was still waiting when the dispatcher stopped.
+	ErrDispatcherStopped = errors.New("webhook: dispatcher stopped")
+)
+
+// ConfigDispatcher is the configuration of a dispatcher.
+type ConfigDispatcher struct {
+	// Workers is how many deliveries run at once.
+	Workers int
+	// QueueSize is how many deliveries can wait for a worker.
+	QueueSize int
+	// MaxAttempts is how many times a delivery is attempted before it is dead lettered.
+	MaxAttempts int
+	// BackoffBase is the wait before the second attempt, doubled on every further attempt.
+	BackoffBase time.Duration
+	// BackoffMax is the longest wait between two attempts.
+	BackoffMax time.Duration
+	// Client is the http client posting the deliveries.
+	Client *http.Client
+}
+
+// NewDispatcher creates a new dispatcher delivering the events to the webhooks subscribed to them.
+func NewDispatcher(rw internal.RepositoryWebhook, rd internal.RepositoryDeadLetter, cfg *ConfigDispatcher) (d *Dispatcher) {
+	// default config
+	defaultConfig := &ConfigDispatcher{
+		Workers:     4,
+		QueueSize:   1024,
+		MaxAttempts: 5,
+		BackoffBase: time.Second,
+		BackoffMax:  time.Minute,
+		Client:      &http.Client{Timeout: 10 * time.Second},
+	}
+	if cfg != nil {
+		if cfg.Workers > 0 {
+			defaultConfig.Workers = cfg.Workers
+		}
+		if cfg.QueueSize > 0 {
+			defaultConfig.QueueSize = cfg.QueueSize
+		}
+		if cfg.MaxAttempts > 0 {
+			defaultConfig.MaxAttempts = cfg.MaxAttempts
+		}
+		if cfg.BackoffBase > 0 {
+			defaultConfig.BackoffBase = cfg.BackoffBase
+		}
+		if cfg.BackoffMax > 0 {
+			defaultConfig.BackoffMax = cfg.BackoffMax
+		}
+		if cfg.Client != nil {
+			defaultConfig.Client = cfg.Client
+		}
+	}
+
+	d = &Dispatcher{
+		rw:    rw,
+		rd:    rd,
+		cfg:   defaultConfig,
+		queue: make(chan delivery, defaultConfig.QueueSize),
+	}
+	return
+}
+
+// Dispatcher delivers the events to the webhooks subscribed to them.
+// - events are queued on publish and posted in the background by a pool of workers
+// - a failing delivery is retried with an exponential backoff, then saved as a dead letter
+// - a receiver responding 4xx, other than 408 and 429, rejects the delivery for good
+type Dispatcher struct {
+	// rw is the repository for webhooks.
+	rw internal.RepositoryWebhook
+	// rd is the repository for dead letters.
+	rd internal.RepositoryDeadLetter
+	// cfg is the configuration of the dispatcher.
+	cfg *ConfigDispatcher
+	// queue holds the deliveries waiting for a worker.
+	queue chan delivery
+
+	// mu guards cancel.
+	mu sync.Mutex
+	// cancel stops the workers, nil if the dispatcher is stopped.
+	cancel context.CancelFunc
+	// wg waits for the workers to stop.
+	wg sync.WaitGroup
+}
+
+// delivery is an event to deliver to a webhook.
+type delivery struct {
+	webhook internal.Webhook
+	event   internal.Event
+}
+
+// Publish queues the event for every webhook of its tenant subscribed to its type.
+func (d *Dispatcher) Publish(ctx context.Context, e internal.Event) (err error) {
+	ws, err := d.rw.GetAll(internal.ContextWithTenant(ctx, internal.TenantAll))
+	if err != nil {
+		return
+	}
+
+	for _, w := range ws {
+		if !w.Subscribes(e.Type) || !e.InTenant(w.Tenant) {
+			continue
+		}
+		d.enqueue(ctx, delivery{webhook: w, event: e})
+	}
+	return
+}
+
+// Redeliver queues again the event of a dead letter, removing it from the dead letters.
+func (d *Dispatcher) Redeliver(ctx context.Context, id int) (err error) {
+	dl, err := d.rd.FindById(ctx, id)
+	if err != nil {
+		return
+	}
+	w, err := d.rw.FindById(ctx, dl.WebhookId)
+	if err != nil {
+		return
+	}
+
+	err = d.rd.Delete(ctx, id)
+	if err != nil {
+		return
+	}
+
+	d.enqueue(ctx, delivery{webhook: w, event: dl.Event})
+	return
+}
+
+// Start starts the workers.
+func (d *Dispatcher) Start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.cancel != nil {
+		return
+	}
+
+	var ctx context.Context
+	ctx, d.cancel = context.WithCancel(context.Background())
+	for i := 0; i < d.cfg.Workers; i++ {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.work(ctx)
+		}()
+	}
+}
+
+// Stop stops the workers, the deliveries still waiting are dead lettered.
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	cancel := d.cancel
+	d.cancel = nil
+	d.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	d.wg.Wait()
+
+	for {
+		select {
+		case dl := <-d.queue:
+			d.deadLetter(context.Background(), dl, 0, ErrDispatcherStopped)
+		default:
+			return
+		}
+	}
+}
+
+// Deliver posts an event to a webhook, retrying with an exponential backoff until it succeeds or the attempts run out.
+func (d *Dispatcher) Deliver(ctx context.Context, w internal.Webhook, e internal.Event) (attempts int, err error) {
+	for attempts = 1; ; attempts++ {
+		err = d.post(ctx, w, e)
+		if err == nil || errors.Is(err, ErrDeliveryRejected) || attempts >= d.cfg.MaxAttempts {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			err = errors.Join(err, ctx.Err())
+			return
+		case <-time.After(d.backoff(attempts)):
+		}
+	}
+}
+
+// work delivers the queued deliveries until ctx is done.
+func (d *Dispatcher) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case dl := <-d.queue:
+			attempts, err := d.Deliver(ctx, dl.webhook, dl.event)
+			if err != nil {
+				d.deadLetter(context.WithoutCancel(ctx), dl, attempts, err)
+			}
+		}
+	}
+}
+
+// enqueue queues a delivery, it is dead lettered if the queue is full.
+func (d *Dispatcher) enqueue(ctx context.Context, dl delivery) {
+	select {
+	case d.queue <- dl:
+	default:
+		d.deadLetter(ctx, dl, 0, ErrQueueFull)
+	}
+}
+
+// deadLetter saves a failed delivery as a dead letter.
+func (d *Dispatcher) deadLetter(ctx context.Context, dl delivery, attempts int, cause error) {
+	err := d.rd.Save(ctx, &internal.DeadLetter{
+		WebhookId: dl.webhook.Id,
+		Event:     dl.event,
+		Attempts:  attempts,
+		LastError: cause.Error(),
+		FailedAt:  time.Now(),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "webhook event lost", "webhook_id", dl.webhook.Id, "event_id", dl.event.Id, "error", err)
+	}
+}
+
+// backoff returns the wait after an attempt.
+func (d *Dispatcher) backoff(attempt int) (wait time.Duration) {
+	wait = d.cfg.BackoffBase
+	for i := 1; i < attempt && wait < d.cfg.BackoffMax; i++ {
+		wait *= 2
+	}
+	wait = min(wait, d.cfg.BackoffMax)
+	return
+}
+
+// post posts an event to a webhook once.
+func (d *Dispatcher) post(ctx context.Context, w internal.Webhook, e internal.Event) (err error) {
+	// serialize
+	body, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	// request
+	// - signed with the secret of the webhook
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderId, e.Id)
+	req.Header.Set(HeaderEvent, e.Type)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(w.Secret, timestamp, body))
+
+	// response
+	res, err := d.cfg.Client.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode <= 299:
+	case res.StatusCode >= 400 && res.StatusCode <= 499 && res.StatusCode != http.StatusRequestTimeout && res.StatusCode != http.StatusTooManyRequests:
+		err = fmt.Errorf("%w: receiver responded %d", ErrDeliveryRejected, res.StatusCode)
+	default:
+		err = fmt.Errorf("webhook: receiver responded %d", res.StatusCode)
+	}
+	return
+}
@@ -0,0 +1,216 @@
+package webhook_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/store"
+	"app/internal/webhook"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newDispatcher creates a dispatcher with a webhook to a receiver and fast retries.
+func newDispatcher(t *testing.T, url string, events ...string) (d *webhook.Dispatcher, rd internal.RepositoryDeadLetter) {
+	rw := repository.NewRepositoryWebhookStore(store.NewStoreWebhookMemory(nil))
+	require.NoError(t, rw.Save(context.Background(), &internal.Webhook{URL: url, Secret: "secret", Events: events}))
+	rd = repository.NewRepositoryDeadLetterStore(store.NewStoreDeadLetterMemory(nil))
+	d = webhook.NewDispatcher(rw, rd, &webhook.ConfigDispatcher{
+		MaxAttempts: 3,
+		BackoffBase: time.Millisecond,
+		BackoffMax:  2 * time.Millisecond,
+	})
+	return
+}
+
+// Tests for Dispatcher.Deliver
+func TestDispatcher_Deliver(t *testing.T) {
+	e := internal.Event{Id: "product-1", Type: internal.EventProductCreated, EntityId: 1, Actor: "anonymous", Timestamp: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+	w := internal.Webhook{Id: 1, Secret: "secret"}
+
+	t.Run("success - signed event delivered", func(t *testing.T) {
+		// arrange
+		var received internal.Event
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			timestamp, err := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+			require.NoError(t, err)
+			require.True(t, webhook.Verify("secret", timestamp, body, r.Header.Get(webhook.HeaderSignature)))
+			require.Equal(t, e.Id, r.Header.Get(webhook.HeaderId))
+			require.Equal(t, e.Type, r.Header.Get(webhook.HeaderEvent))
+			require.NoError(t, json.Unmarshal(body, &received))
+		}))
+		defer srv.Close()
+		d, _ := newDispatcher(t, srv.URL)
+		w.URL = srv.URL
+
+		// act
+		attempts, err := d.Deliver(context.Background(), w, e)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 1, attempts)
+		require.Equal(t, e, received)
+	})
+
+	t.Run("success - retried until the receiver recovers", func(t *testing.T) {
+		// arrange
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				rw.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer srv.Close()
+		d, _ := newDispatcher(t, srv.URL)
+		w.URL = srv.URL
+
+		// act
+		attempts, err := d.Deliver(context.Background(), w, e)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 3, attempts)
+	})
+
+	t.Run("fail - attempts run out", func(t *testing.T) {
+		// arrange
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+		d, _ := newDispatcher(t, srv.URL)
+		w.URL = srv.URL
+
+		// act
+		attempts, err := d.Deliver(context.Background(), w, e)
+
+		// assert
+		require.EqualError(t, err, "webhook: receiver responded 500")
+		require.Equal(t, 3, attempts)
+	})
+
+	t.Run("fail - rejected deliveries are not retried", func(t *testing.T) {
+		// arrange
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusGone)
+		}))
+		defer srv.Close()
+		d, _ := newDispatcher(t, srv.URL)
+		w.URL = srv.URL
+
+		// act
+		attempts, err := d.Deliver(context.Background(), w, e)
+
+		// assert
+		require.ErrorIs(t, err, webhook.ErrDeliveryRejected)
+		require.Equal(t, 1, attempts)
+	})
+}
+
+// Tests for Dispatcher.Publish
+func TestDispatcher_Publish(t *testing.T) {
+	t.Run("success - subscribed events delivered in the background", func(t *testing.T) {
+		// arrange
+		received := make(chan string, 10)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			received <- r.Header.Get(webhook.HeaderEvent)
+		}))
+		defer srv.Close()
+		d, _ := newDispatcher(t, srv.URL, "warehouse.*")
+		d.Start()
+		defer d.Stop()
+
+		// act
+		require.NoError(t, d.Publish(context.Background(), internal.Event{Id: "product-1", Type: internal.EventProductCreated}))
+		require.NoError(t, d.Publish(context.Background(), internal.Event{Id: "warehouse-2", Type: internal.EventWarehouseUpdated}))
+
+		// assert
+		select {
+		case typ := <-received:
+			require.Equal(t, internal.EventWarehouseUpdated, typ)
+		case <-time.After(5 * time.Second):
+			t.Fatal("event not delivered")
+		}
+	})
+
+	t.Run("success - events delivered to the webhooks of their tenant only", func(t *testing.T) {
+		// arrange
+		received := make(chan string, 10)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			received <- r.Header.Get(webhook.HeaderId)
+		}))
+		defer srv.Close()
+		rw := repository.NewRepositoryWebhookStore(store.NewStoreWebhookMemory(nil))
+		ctx := internal.ContextWithTenant(context.Background(), "acme")
+		require.NoError(t, rw.Save(ctx, &internal.Webhook{URL: srv.URL, Secret: "secret"}))
+		d := webhook.NewDispatcher(rw, repository.NewRepositoryDeadLetterStore(store.NewStoreDeadLetterMemory(nil)), nil)
+		d.Start()
+		defer d.Stop()
+
+		// act
+		require.NoError(t, d.Publish(context.Background(), internal.Event{Id: "product-1", Type: internal.EventProductCreated, Tenant: "globex"}))
+		require.NoError(t, d.Publish(context.Background(), internal.Event{Id: "product-2", Type: internal.EventProductCreated}))
+		require.NoError(t, d.Publish(context.Background(), internal.Event{Id: "product-3", Type: internal.EventProductCreated, Tenant: "acme"}))
+
+		// assert
+		select {
+		case id := <-received:
+			require.Equal(t, "product-3", id)
+		case <-time.After(5 * time.Second):
+			t.Fatal("event not delivered")
+		}
+	})
+
+	t.Run("success - undelivered events dead lettered and redelivered", func(t *testing.T) {
+		// arrange
+		var down atomic.Bool
+		down.Store(true)
+		received := make(chan string, 10)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if down.Load() {
+				rw.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			received <- r.Header.Get(webhook.HeaderId)
+		}))
+		defer srv.Close()
+		d, rd := newDispatcher(t, srv.URL)
+		d.Start()
+		defer d.Stop()
+
+		// act
+		require.NoError(t, d.Publish(context.Background(), internal.Event{Id: "product-1", Type: internal.EventProductDeleted}))
+		var dls []internal.DeadLetter
+		require.Eventually(t, func() bool {
+			dls, _ = rd.GetAll(context.Background())
+			return len(dls) == 1
+		}, 5*time.Second, time.Millisecond)
+		down.Store(false)
+		err := d.Redeliver(context.Background(), dls[0].Id)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 3, dls[0].Attempts)
+		require.Equal(t, "webhook: receiver responded 502", dls[0].LastError)
+		require.Equal(t, "product-1", dls[0].Event.Id)
+		select {
+		case id := <-received:
+			require.Equal(t, "product-1", id)
+		case <-time.After(5 * time.Second):
+			t.Fatal("event not redelivered")
+		}
+		dls, err = rd.GetAll(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, dls)
+	})
+}
@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	// HeaderId is the header of the id of the delivered event.
+	HeaderId = "X-Webhook-Id"
+	// HeaderEvent is the header of the type of the delivered event.
+	HeaderEvent = "X-Webhook-Event"
+	// HeaderTimestamp is the header of the unix time the delivery was signed at.
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	// HeaderSignature is the header of the signature of the delivery.
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// signaturePrefix is the prefix of the signatures, naming their algorithm.
+const signaturePrefix = "sha256="
+
+// Sign returns the signature of a delivery: the hex HMAC-SHA256, keyed by the secret, of the timestamp and the body joined by a dot.
+// - signing the timestamp lets receivers reject replayed deliveries
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify tells whether a signature is the one of a delivery, in constant time.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrRepositoryWebhookNotFound is returned when a webhook is not found.
+	ErrRepositoryWebhookNotFound = errors.New("repository: webhook not found")
+	// ErrRepositoryDeadLetterNotFound is returned when a dead letter is not found.
+	ErrRepositoryDeadLetterNotFound = errors.New("repository: dead letter not found")
+)
+
+// RepositoryWebhook is an interface that contains the methods for a webhook repository
+type RepositoryWebhook interface {
+	// FindById returns a webhook by its id
+	FindById(ctx context.Context, id int) (w Webhook, err error)
+	// Save saves a webhook, setting its id
+	Save(ctx context.Context, w *Webhook) (err error)
+	// Delete deletes a webhook
+	Delete(ctx context.Context, id int) (err error)
+	// GetAll returns all webhooks sorted by id
+	GetAll(ctx context.Context) (w []Webhook, err error)
+}
+
+// RepositoryDeadLetter is an interface that contains the methods for a dead letter repository
+type RepositoryDeadLetter interface {
+	// FindById returns a dead letter by its id
+	FindById(ctx context.Context, id int) (d DeadLetter, err error)
+	// Save saves a dead letter, setting its id
+	Save(ctx context.Context, d *DeadLetter) (err error)
+	// Delete deletes a dead letter
+	Delete(ctx context.Context, id int) (err error)
+	// GetAll returns all dead letters sorted by id
+	GetAll(ctx context.Context) (d []DeadLetter, err error)
+}
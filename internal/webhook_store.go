@@ -0,0 +1,17 @@
+package internal
+
+// StoreWebhook is an interface for a webhook store.
+type StoreWebhook interface {
+	// ReadAll reads all webhooks from the store.
+	ReadAll() (w map[int]Webhook, err error)
+	// WriteAll writes all webhooks to the store.
+	WriteAll(w map[int]Webhook) (err error)
+}
+
+// StoreDeadLetter is an interface for a dead letter store.
+type StoreDeadLetter interface {
+	// ReadAll reads all dead letters from the store.
+	ReadAll() (d map[int]DeadLetter, err error)
+	// WriteAll writes all dead letters to the store.
+	WriteAll(d map[int]DeadLetter) (err error)
+}
@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"app/platform/web/response"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderAPIKey is the header carrying an api key.
+	HeaderAPIKey = "X-API-Key"
+	// MethodAPIKey is the method of a principal authenticated by an api key.
+	MethodAPIKey = "api_key"
+	// MethodJWT is the method of a principal authenticated by a jwt bearer token.
+	MethodJWT = "jwt"
+)
+
+var (
+	// ErrCredentialsMissing is returned when a request carries neither an api key nor a bearer token.
+	ErrCredentialsMissing = errors.New("auth: credentials missing")
+	// ErrAPIKeyInvalid is returned when an api key is not one of the configured keys.
+	ErrAPIKeyInvalid = errors.New("auth: api key invalid")
+	// ErrTokenInvalid is returned when a token is malformed, its signature does not verify or a claim does not match.
+	ErrTokenInvalid = errors.New("auth: token invalid")
+	// ErrTokenExpired is returned when a token is expired or not valid yet.
+	ErrTokenExpired = errors.New("auth: token expired")
+)
+
+// APIKey is a static api key.
+type APIKey struct {
+	// Key is the secret sent by the client.
+	Key string
+	// Subject is who the key identifies.
+	Subject string
+	// Role is the role of the subject: viewer, operator or admin.
+	Role string
+	// Warehouses are the ids of the warehouses an operator is assigned to.
+	Warehouses []int
+	// Tenant is the tenant whose data the subject works on.
+	Tenant string
+}
+
+// Config is the configuration of an authenticator.
+// - a configuration without api keys nor jwt keys disables the authentication
+type Config struct {
+	// APIKeys are the accepted api keys.
+	APIKeys []APIKey
+	// JWTKeys are the keys the bearer tokens are verified with.
+	JWTKeys []JWTKey
+	// Issuer is the required iss claim of the tokens, not checked if empty.
+	Issuer string
+	// Audience is the required aud claim of the tokens, not checked if empty.
+	Audience string
+	// Leeway is the clock skew tolerated when checking the time claims of the tokens.
+	Leeway time.Duration
+}
+
+// Principal is who performs an authenticated request.
+type Principal struct {
+	// Subject identifies the principal, the subject of an api key or the sub claim of a token.
+	Subject string
+	// Method is how the principal was authenticated: api_key or jwt.
+	Method string
+	// Role is the role of the principal, the one of its api key or the role claim of its token.
+	// - a principal without role is denied every route
+	Role string
+	// Warehouses are the ids of the warehouses an operator is assigned to, the warehouses claim of a token.
+	Warehouses []int
+	// Tenant is the tenant whose data the principal works on, the one of its api key or the tenant claim of its token.
+	Tenant string
+	// Claims are the claims of the token, nil for an api key.
+	Claims map[string]any
+}
+
+// principalKey is the context key of the principal.
+type principalKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx carrying the principal performing the request.
+func ContextWithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal carried by ctx, ok is false if there is none.
+func PrincipalFromContext(ctx context.Context) (p Principal, ok bool) {
+	p, ok = ctx.Value(principalKey{}).(Principal)
+	return
+}
+
+// NewAuthenticator creates a new authenticator of requests.
+func NewAuthenticator(cfg *Config) (a *Authenticator) {
+	// default config
+	defaultConfig := &Config{}
+	if cfg != nil {
+		defaultConfig.APIKeys = cfg.APIKeys
+		defaultConfig.JWTKeys = cfg.JWTKeys
+		defaultConfig.Issuer = cfg.Issuer
+		defaultConfig.Audience = cfg.Audience
+		if cfg.Leeway > 0 {
+			defaultConfig.Leeway = cfg.Leeway
+		}
+	}
+
+	a = &Authenticator{
+		cfg: defaultConfig,
+		now: time.Now,
+	}
+	return
+}
+
+// Authenticator authenticates requests with api keys or jwt bearer tokens.
+// - an api key is sent in the X-API-Key header, a token in the Authorization header as Bearer
+type Authenticator struct {
+	// cfg is the configuration of the authenticator.
+	cfg *Config
+	// now returns the current time, the time claims of the tokens are checked against it.
+	now func() time.Time
+}
+
+// Enabled tells whether the authenticator has any key to authenticate requests with.
+func (a *Authenticator) Enabled() bool {
+	return len(a.cfg.APIKeys) > 0 || len(a.cfg.JWTKeys) > 0
+}
+
+// Authenticate returns the principal of a request.
+func (a *Authenticator) Authenticate(r *http.Request) (p Principal, err error) {
+	if key := r.Header.Get(HeaderAPIKey); key != "" {
+		return a.apiKey(key)
+	}
+
+	scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if strings.EqualFold(scheme, "Bearer") && token != "" {
+		return a.token(token)
+	}
+
+	err = ErrCredentialsMissing
+	return
+}
+
+// Middleware is a middleware rejecting the requests that can not be authenticated, with 401.
+// - the principal of an authenticated request is attached to its context
+// - a disabled authenticator lets every request through without principal
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		p, err := a.Authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+			response.JSON(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ContextWithPrincipal(r.Context(), p)))
+	})
+}
+
+// apiKey returns the principal of an api key.
+// - the keys are compared by their hashes in constant time, so the time taken tells nothing about them
+func (a *Authenticator) apiKey(key string) (p Principal, err error) {
+	sum := sha256.Sum256([]byte(key))
+	found := -1
+	for i, k := range a.cfg.APIKeys {
+		s := sha256.Sum256([]byte(k.Key))
+		if subtle.ConstantTimeCompare(sum[:], s[:]) == 1 && found < 0 {
+			found = i
+		}
+	}
+	if found < 0 {
+		err = ErrAPIKeyInvalid
+		return
+	}
+
+	k := a.cfg.APIKeys[found]
+	p = Principal{
+		Subject:    k.Subject,
+		Method:     MethodAPIKey,
+		Role:       k.Role,
+		Warehouses: k.Warehouses,
+		Tenant:     k.Tenant,
+	}
+	return
+}
@@ -0,0 +1,138 @@
+package auth_test
+
+import (
+	"app/platform/web/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Authenticator.Middleware
+func TestAuthenticator_Middleware(t *testing.T) {
+	// handler responding with the subject of the principal
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, ok := auth.PrincipalFromContext(r.Context())
+		if !ok {
+			w.Write([]byte("none"))
+			return
+		}
+		w.Write([]byte(p.Method + ":" + p.Subject + ":" + p.Role))
+	})
+
+	t.Run("success - api key", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			APIKeys: []auth.APIKey{{Key: "key-1", Subject: "ci", Role: auth.RoleViewer}, {Key: "key-2", Subject: "ops", Role: auth.RoleOperator}},
+		})
+
+		// act
+		req := httptest.NewRequest(http.MethodGet, "/products", nil)
+		req.Header.Set(auth.HeaderAPIKey, "key-2")
+		res := httptest.NewRecorder()
+		a.Middleware(next).ServeHTTP(res, req)
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, "api_key:ops:operator", res.Body.String())
+	})
+
+	t.Run("success - bearer token", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			JWTKeys: []auth.JWTKey{{Algorithm: auth.AlgorithmHS256, Secret: []byte("secret")}},
+		})
+
+		// act
+		req := httptest.NewRequest(http.MethodGet, "/products", nil)
+		req.Header.Set("Authorization", "Bearer "+signHS256(t, []byte("secret"), "", validClaims()))
+		res := httptest.NewRecorder()
+		a.Middleware(next).ServeHTTP(res, req)
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, "jwt:alice:", res.Body.String())
+	})
+
+	t.Run("success - disabled", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(nil)
+
+		// act
+		req := httptest.NewRequest(http.MethodGet, "/products", nil)
+		res := httptest.NewRecorder()
+		a.Middleware(next).ServeHTTP(res, req)
+
+		// assert
+		require.False(t, a.Enabled())
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, "none", res.Body.String())
+	})
+
+	t.Run("error - credentials missing", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			APIKeys: []auth.APIKey{{Key: "key-1", Subject: "ci"}},
+		})
+
+		// act
+		req := httptest.NewRequest(http.MethodGet, "/products", nil)
+		res := httptest.NewRecorder()
+		a.Middleware(next).ServeHTTP(res, req)
+
+		// assert
+		require.Equal(t, http.StatusUnauthorized, res.Code)
+		require.Equal(t, `Bearer realm="api"`, res.Header().Get("WWW-Authenticate"))
+		require.JSONEq(t, `"unauthorized"`, res.Body.String())
+	})
+
+	t.Run("error - api key invalid", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			APIKeys: []auth.APIKey{{Key: "key-1", Subject: "ci"}},
+		})
+
+		// act
+		req := httptest.NewRequest(http.MethodGet, "/products", nil)
+		req.Header.Set(auth.HeaderAPIKey, "key-3")
+		res := httptest.NewRecorder()
+		a.Middleware(next).ServeHTTP(res, req)
+
+		// assert
+		require.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+}
+
+// Tests for Authenticator.Authenticate
+func TestAuthenticator_Authenticate(t *testing.T) {
+	t.Run("error - credentials missing", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			APIKeys: []auth.APIKey{{Key: "key-1", Subject: "ci"}},
+		})
+
+		// act
+		req := httptest.NewRequest(http.MethodGet, "/products", nil)
+		req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+		_, err := a.Authenticate(req)
+
+		// assert
+		require.ErrorIs(t, err, auth.ErrCredentialsMissing)
+	})
+
+	t.Run("error - api key invalid", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			APIKeys: []auth.APIKey{{Key: "key-1", Subject: "ci"}},
+		})
+
+		// act
+		req := httptest.NewRequest(http.MethodGet, "/products", nil)
+		req.Header.Set(auth.HeaderAPIKey, "key-10")
+		_, err := a.Authenticate(req)
+
+		// assert
+		require.ErrorIs(t, err, auth.ErrAPIKeyInvalid)
+	})
+}
@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"app/platform/web/logging"
+	"app/platform/web/response"
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// RoleViewer is the role of the principals reading the resources.
+	RoleViewer = "viewer"
+	// RoleOperator is the role of the principals adjusting the stock of their warehouses, on top of reading.
+	RoleOperator = "operator"
+	// RoleAdmin is the role of the principals allowed everything.
+	RoleAdmin = "admin"
+)
+
+// roleRanks are the ranks of the roles, a role is granted the permissions of the lower ranked ones.
+var roleRanks = map[string]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// Roles are the valid roles.
+var Roles = []string{RoleViewer, RoleOperator, RoleAdmin}
+
+// RoleAllows tells whether a role is granted the permissions of another one.
+// - an unknown role is granted nothing
+func RoleAllows(role, required string) bool {
+	rank, ok := roleRanks[role]
+	return ok && rank >= roleRanks[required]
+}
+
+// ErrScopeNone is returned by a WarehouseFunc when the request touches no warehouse, e.g. its resource is not found.
+// - the request is let through, so its handler responds as it would to anyone
+var ErrScopeNone = errors.New("auth: request touches no warehouse")
+
+// WarehouseFunc returns the warehouse a request touches, the route parameters are available with chi.URLParam.
+type WarehouseFunc func(r *http.Request) (warehouseId int, err error)
+
+// Rule is a rule of a policy.
+type Rule struct {
+	// Method is the method of the requests, every method if empty.
+	Method string
+	// Pattern is the chi route pattern of the requests, e.g. /products/{id}.
+	// - a pattern ending with /* matches its root and every route under it
+	Pattern string
+	// Role is the role required.
+	Role string
+	// Warehouse returns the warehouse the request touches, an operator must be assigned to it.
+	// - nil if the rule is not scoped to a warehouse
+	Warehouse WarehouseFunc
+}
+
+// matches tells whether the rule applies to a request of a method on a route pattern.
+func (r Rule) matches(method, pattern string) bool {
+	if r.Method != "" && r.Method != method {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(r.Pattern, "*"); ok {
+		return strings.HasPrefix(pattern, prefix) || pattern == strings.TrimSuffix(prefix, "/")
+	}
+	return r.Pattern == pattern
+}
+
+// Policy is a table of the roles required by the routes.
+type Policy struct {
+	// Rules are the rules of the policy, the first matching a request applies.
+	Rules []Rule
+	// Default is the role required by the requests matching no rule, admin if empty.
+	Default string
+}
+
+// NewAuthorizer creates a new authorizer of requests.
+func NewAuthorizer(policy Policy) (a *Authorizer) {
+	if policy.Default == "" {
+		policy.Default = RoleAdmin
+	}
+
+	a = &Authorizer{
+		policy: policy,
+	}
+	return
+}
+
+// Authorizer authorizes the requests of the principals by their role.
+type Authorizer struct {
+	// policy is the table of the roles required by the routes.
+	policy Policy
+}
+
+// rule returns the rule applying to a request, and the routing context of its route.
+// - the route is matched ahead of the router, so the middleware knows its pattern and parameters
+func (a *Authorizer) rule(r *http.Request) (rule Rule, rctx *chi.Context) {
+	rctx = chi.NewRouteContext()
+	rule = Rule{Role: a.policy.Default}
+
+	routes := chi.RouteContext(r.Context())
+	if routes == nil || routes.Routes == nil {
+		return
+	}
+	path := r.URL.RawPath
+	if path == "" {
+		path = r.URL.Path
+	}
+	// - unknown routes are left to the router, which responds not found
+	if !routes.Routes.Match(rctx, r.Method, path) {
+		rule = Rule{}
+		return
+	}
+
+	pattern := rctx.RoutePattern()
+	for _, ru := range a.policy.Rules {
+		if ru.matches(r.Method, pattern) {
+			rule = ru
+			break
+		}
+	}
+	return
+}
+
+// Middleware is a middleware rejecting with 403 the requests whose principal lacks the role required by their route.
+// - an operator must also be assigned to the warehouse of a request scoped to one, admins are not scoped
+// - requests without principal are let through, authentication being disabled
+func (a *Authorizer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, ok := PrincipalFromContext(r.Context())
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rule, rctx := a.rule(r)
+		if rule.Role == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !RoleAllows(p.Role, rule.Role) {
+			response.JSON(w, http.StatusForbidden, "forbidden")
+			return
+		}
+
+		if rule.Warehouse != nil && !RoleAllows(p.Role, RoleAdmin) {
+			warehouseId, err := rule.Warehouse(r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx)))
+			switch {
+			case errors.Is(err, ErrScopeNone):
+			case err != nil:
+				logging.Error(r.Context(), err)
+				response.JSON(w, http.StatusInternalServerError, "internal server error")
+				return
+			case !slices.Contains(p.Warehouses, warehouseId):
+				response.JSON(w, http.StatusForbidden, "forbidden")
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,124 @@
+package auth_test
+
+import (
+	"app/platform/web/auth"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// newAuthorizedRouter returns a router of products whose requests are performed by a principal.
+// - the warehouse of a product is its id divided by 10, 0 is not found
+func newAuthorizedRouter(p *auth.Principal) *chi.Mux {
+	warehouseOfProduct := func(r *http.Request) (int, error) {
+		id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+		if id == 0 {
+			return 0, auth.ErrScopeNone
+		}
+		if id < 0 {
+			return 0, errors.New("repository down")
+		}
+		return id / 10, nil
+	}
+	az := auth.NewAuthorizer(auth.Policy{
+		Rules: []auth.Rule{
+			{Method: http.MethodGet, Pattern: "/admin/*", Role: auth.RoleAdmin},
+			{Method: http.MethodGet, Pattern: "/*", Role: auth.RoleViewer},
+			{Method: http.MethodPatch, Pattern: "/products/{id}", Role: auth.RoleOperator, Warehouse: warehouseOfProduct},
+		},
+	})
+
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	rt := chi.NewRouter()
+	rt.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if p != nil {
+				r = r.WithContext(auth.ContextWithPrincipal(r.Context(), *p))
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	rt.Use(az.Middleware)
+	rt.Route("/products", func(r chi.Router) {
+		r.Get("/", ok)
+		r.Get("/{id}", ok)
+		r.Patch("/{id}", ok)
+		r.Delete("/{id}", ok)
+	})
+	rt.Route("/admin", func(r chi.Router) {
+		r.Get("/", ok)
+		r.Get("/jobs", ok)
+	})
+	return rt
+}
+
+// Tests for Authorizer.Middleware
+func TestAuthorizer_Middleware(t *testing.T) {
+	viewer := &auth.Principal{Subject: "v", Role: auth.RoleViewer}
+	operator := &auth.Principal{Subject: "o", Role: auth.RoleOperator, Warehouses: []int{1, 3}}
+	admin := &auth.Principal{Subject: "a", Role: auth.RoleAdmin}
+	norole := &auth.Principal{Subject: "n"}
+
+	cases := []struct {
+		name         string
+		principal    *auth.Principal
+		method       string
+		path         string
+		expectedCode int
+	}{
+		{"success - viewer reads", viewer, http.MethodGet, "/products/12", http.StatusOK},
+		{"success - viewer lists", viewer, http.MethodGet, "/products", http.StatusOK},
+		{"success - operator adjusts its warehouse", operator, http.MethodPatch, "/products/12", http.StatusOK},
+		{"success - operator adjusts a product not found", operator, http.MethodPatch, "/products/0", http.StatusOK},
+		{"success - admin adjusts any warehouse", admin, http.MethodPatch, "/products/25", http.StatusOK},
+		{"success - admin deletes", admin, http.MethodDelete, "/products/12", http.StatusOK},
+		{"success - admin route", admin, http.MethodGet, "/admin/jobs", http.StatusOK},
+		{"success - no principal", nil, http.MethodDelete, "/products/12", http.StatusOK},
+		{"success - unknown route left to the router", viewer, http.MethodGet, "/unknown", http.StatusNotFound},
+		{"error - viewer adjusts", viewer, http.MethodPatch, "/products/12", http.StatusForbidden},
+		{"error - operator adjusts another warehouse", operator, http.MethodPatch, "/products/25", http.StatusForbidden},
+		{"error - operator deletes", operator, http.MethodDelete, "/products/12", http.StatusForbidden},
+		{"error - operator admin route", operator, http.MethodGet, "/admin/jobs", http.StatusForbidden},
+		{"error - operator admin root", operator, http.MethodGet, "/admin", http.StatusForbidden},
+		{"error - principal without role", norole, http.MethodGet, "/products", http.StatusForbidden},
+		{"error - warehouse not resolved", operator, http.MethodPatch, "/products/-1", http.StatusInternalServerError},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			rt := newAuthorizedRouter(c.principal)
+
+			// act
+			req := httptest.NewRequest(c.method, c.path, nil)
+			res := httptest.NewRecorder()
+			rt.ServeHTTP(res, req)
+
+			// assert
+			require.Equal(t, c.expectedCode, res.Code)
+		})
+	}
+}
+
+// Tests for RoleAllows
+func TestRoleAllows(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		// ...
+
+		// assert
+		require.True(t, auth.RoleAllows(auth.RoleAdmin, auth.RoleOperator))
+		require.True(t, auth.RoleAllows(auth.RoleOperator, auth.RoleOperator))
+		require.False(t, auth.RoleAllows(auth.RoleViewer, auth.RoleOperator))
+		require.False(t, auth.RoleAllows("", auth.RoleViewer))
+		require.False(t, auth.RoleAllows("root", auth.RoleViewer))
+	})
+}
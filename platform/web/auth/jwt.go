@@ -0,0 +1,251 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// AlgorithmHS256 is the algorithm of the tokens signed with a shared secret, HMAC with SHA-256.
+	AlgorithmHS256 = "HS256"
+	// AlgorithmRS256 is the algorithm of the tokens signed with a private key, RSASSA-PKCS1-v1_5 with SHA-256.
+	AlgorithmRS256 = "RS256"
+)
+
+// JWTKey is a local key the tokens are verified with.
+type JWTKey struct {
+	// Id is the kid of the tokens signed with the key, a token without kid is tried with every key of its algorithm.
+	Id string
+	// Algorithm is the algorithm of the key: HS256 or RS256.
+	Algorithm string
+	// Secret is the shared secret of a HS256 key.
+	Secret []byte
+	// PublicKey is the public key of a RS256 key.
+	PublicKey *rsa.PublicKey
+}
+
+// jwtHeader is the header of a token.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// token returns the principal of a jwt.
+// - the algorithm of the token must be the one of the key, so a public key is never used as a shared secret
+// - the sub and exp claims are required
+func (a *Authenticator) token(token string) (p Principal, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		err = ErrTokenInvalid
+		return
+	}
+
+	// header
+	var h jwtHeader
+	err = decodeSegment(parts[0], &h)
+	if err != nil {
+		return
+	}
+
+	// signature
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		err = ErrTokenInvalid
+		return
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, k := range a.cfg.JWTKeys {
+		if k.Algorithm != h.Alg || (h.Kid != "" && k.Id != h.Kid) {
+			continue
+		}
+		if verify(k, signed, sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		err = ErrTokenInvalid
+		return
+	}
+
+	// claims
+	var claims map[string]any
+	err = decodeSegment(parts[1], &claims)
+	if err != nil {
+		return
+	}
+	err = a.checkClaims(claims)
+	if err != nil {
+		return
+	}
+
+	p = Principal{
+		Subject: claims["sub"].(string),
+		Method:  MethodJWT,
+		Claims:  claims,
+	}
+	p.Role, p.Warehouses, err = roleClaims(claims)
+	if err != nil {
+		p = Principal{}
+		return
+	}
+	if v, present := claims["tenant"]; present {
+		var ok bool
+		p.Tenant, ok = v.(string)
+		if !ok {
+			p = Principal{}
+			err = ErrTokenInvalid
+		}
+	}
+	return
+}
+
+// roleClaims returns the role and warehouses claims of a token, both optional.
+func roleClaims(claims map[string]any) (role string, warehouses []int, err error) {
+	if v, present := claims["role"]; present {
+		var ok bool
+		role, ok = v.(string)
+		if !ok {
+			err = ErrTokenInvalid
+			return
+		}
+	}
+
+	if v, present := claims["warehouses"]; present {
+		ids, ok := v.([]any)
+		if !ok {
+			err = ErrTokenInvalid
+			return
+		}
+		for _, id := range ids {
+			n, ok := id.(float64)
+			if !ok || n != float64(int(n)) {
+				err = ErrTokenInvalid
+				return
+			}
+			warehouses = append(warehouses, int(n))
+		}
+	}
+	return
+}
+
+// checkClaims checks the registered claims of a token.
+func (a *Authenticator) checkClaims(claims map[string]any) (err error) {
+	if sub, ok := claims["sub"].(string); !ok || sub == "" {
+		return ErrTokenInvalid
+	}
+
+	// time claims
+	now := a.now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return ErrTokenInvalid
+	}
+	if !now.Before(time.Unix(int64(exp), 0).Add(a.cfg.Leeway)) {
+		return ErrTokenExpired
+	}
+	if v, present := claims["nbf"]; present {
+		nbf, ok := v.(float64)
+		if !ok {
+			return ErrTokenInvalid
+		}
+		if now.Add(a.cfg.Leeway).Before(time.Unix(int64(nbf), 0)) {
+			return ErrTokenExpired
+		}
+	}
+
+	// issuer and audience
+	if a.cfg.Issuer != "" && claims["iss"] != a.cfg.Issuer {
+		return ErrTokenInvalid
+	}
+	if a.cfg.Audience != "" && !hasAudience(claims["aud"], a.cfg.Audience) {
+		return ErrTokenInvalid
+	}
+	return
+}
+
+// hasAudience tells whether an aud claim, a string or an array of them, contains an audience.
+func hasAudience(aud any, audience string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == audience
+	case []any:
+		for _, s := range v {
+			if s == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verify tells whether a signature of a token verifies with a key.
+func verify(k JWTKey, signed, sig []byte) bool {
+	switch k.Algorithm {
+	case AlgorithmHS256:
+		if len(k.Secret) == 0 {
+			return false
+		}
+		mac := hmac.New(sha256.New, k.Secret)
+		mac.Write(signed)
+		return hmac.Equal(sig, mac.Sum(nil))
+	case AlgorithmRS256:
+		if k.PublicKey == nil {
+			return false
+		}
+		sum := sha256.Sum256(signed)
+		return rsa.VerifyPKCS1v15(k.PublicKey, crypto.SHA256, sum[:], sig) == nil
+	}
+	return false
+}
+
+// decodeSegment decodes a base64url segment of a token into v.
+func decodeSegment(seg string, v any) (err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return ErrTokenInvalid
+	}
+	if json.Unmarshal(raw, v) != nil {
+		return ErrTokenInvalid
+	}
+	return
+}
+
+// ParseRSAPublicKeyPEM parses a PEM encoded RSA public key, in PKIX or PKCS #1 form.
+func ParseRSAPublicKeyPEM(data []byte) (k *rsa.PublicKey, err error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		err = errors.New("auth: no PEM block found")
+		return
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		var pub any
+		pub, err = x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return
+		}
+		var ok bool
+		k, ok = pub.(*rsa.PublicKey)
+		if !ok {
+			err = errors.New("auth: not an RSA public key")
+		}
+	case "RSA PUBLIC KEY":
+		k, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		err = fmt.Errorf("auth: unexpected PEM block %q", block.Type)
+	}
+	return
+}
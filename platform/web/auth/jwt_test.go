@@ -0,0 +1,347 @@
+package auth_test
+
+import (
+	"app/platform/web/auth"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// validClaims returns the claims of a token valid for an hour.
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub": "alice",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+// signingInput returns the encoded header and claims of a token.
+func signingInput(t *testing.T, alg, kid string, claims map[string]any) string {
+	t.Helper()
+	header := map[string]any{"alg": alg, "typ": "JWT"}
+	if kid != "" {
+		header["kid"] = kid
+	}
+	h, err := json.Marshal(header)
+	require.NoError(t, err)
+	c, err := json.Marshal(claims)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+}
+
+// signHS256 returns a token signed with a shared secret.
+func signHS256(t *testing.T, secret []byte, kid string, claims map[string]any) string {
+	t.Helper()
+	input := signingInput(t, auth.AlgorithmHS256, kid, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signRS256 returns a token signed with a private key.
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+	input := signingInput(t, auth.AlgorithmRS256, kid, claims)
+	sum := sha256.Sum256([]byte(input))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	require.NoError(t, err)
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// bearer returns a request carrying a bearer token.
+func bearer(token string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+// Tests for Authenticator.Authenticate with jwt bearer tokens
+func TestAuthenticator_Authenticate_JWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	t.Run("success - HS256", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			JWTKeys: []auth.JWTKey{{Algorithm: auth.AlgorithmHS256, Secret: []byte("secret")}},
+		})
+
+		// act
+		p, err := a.Authenticate(bearer(signHS256(t, []byte("secret"), "", validClaims())))
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "alice", p.Subject)
+		require.Equal(t, auth.MethodJWT, p.Method)
+		require.Equal(t, "alice", p.Claims["sub"])
+	})
+
+	t.Run("success - RS256 with kid", func(t *testing.T) {
+		// arrange
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		a := auth.NewAuthenticator(&auth.Config{
+			JWTKeys: []auth.JWTKey{
+				{Id: "old", Algorithm: auth.AlgorithmRS256, PublicKey: &other.PublicKey},
+				{Id: "new", Algorithm: auth.AlgorithmRS256, PublicKey: &rsaKey.PublicKey},
+			},
+		})
+
+		// act
+		p, err := a.Authenticate(bearer(signRS256(t, rsaKey, "new", validClaims())))
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "alice", p.Subject)
+	})
+
+	t.Run("success - issuer, audience and leeway", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			JWTKeys:  []auth.JWTKey{{Algorithm: auth.AlgorithmHS256, Secret: []byte("secret")}},
+			Issuer:   "https://issuer.example",
+			Audience: "inventory",
+			Leeway:   time.Minute,
+		})
+		claims := validClaims()
+		claims["iss"] = "https://issuer.example"
+		claims["aud"] = []string{"billing", "inventory"}
+		claims["exp"] = time.Now().Add(-30 * time.Second).Unix()
+
+		// act
+		p, err := a.Authenticate(bearer(signHS256(t, []byte("secret"), "", claims)))
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "alice", p.Subject)
+	})
+
+	t.Run("success - role claims", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			JWTKeys: []auth.JWTKey{{Algorithm: auth.AlgorithmHS256, Secret: []byte("secret")}},
+		})
+		claims := validClaims()
+		claims["role"] = auth.RoleOperator
+		claims["warehouses"] = []int{1, 3}
+		claims["tenant"] = "acme"
+
+		// act
+		p, err := a.Authenticate(bearer(signHS256(t, []byte("secret"), "", claims)))
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, auth.RoleOperator, p.Role)
+		require.Equal(t, []int{1, 3}, p.Warehouses)
+		require.Equal(t, "acme", p.Tenant)
+	})
+
+	t.Run("error - role claims", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			JWTKeys: []auth.JWTKey{{Algorithm: auth.AlgorithmHS256, Secret: []byte("secret")}},
+		})
+		claims := validClaims()
+		claims["role"] = auth.RoleOperator
+		claims["warehouses"] = []any{1, "3"}
+
+		// act
+		_, err := a.Authenticate(bearer(signHS256(t, []byte("secret"), "", claims)))
+
+		// assert
+		require.ErrorIs(t, err, auth.ErrTokenInvalid)
+	})
+
+	t.Run("error - tenant claim", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			JWTKeys: []auth.JWTKey{{Algorithm: auth.AlgorithmHS256, Secret: []byte("secret")}},
+		})
+		claims := validClaims()
+		claims["tenant"] = 7
+
+		// act
+		_, err := a.Authenticate(bearer(signHS256(t, []byte("secret"), "", claims)))
+
+		// assert
+		require.ErrorIs(t, err, auth.ErrTokenInvalid)
+	})
+
+	t.Run("error - signature", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			JWTKeys: []auth.JWTKey{{Algorithm: auth.AlgorithmHS256, Secret: []byte("secret")}},
+		})
+
+		// act
+		_, err := a.Authenticate(bearer(signHS256(t, []byte("other"), "", validClaims())))
+
+		// assert
+		require.ErrorIs(t, err, auth.ErrTokenInvalid)
+	})
+
+	t.Run("error - algorithm of another key", func(t *testing.T) {
+		// arrange
+		// - a token signed with HS256 using the public key as secret must not verify with a RS256 key
+		pub, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+		require.NoError(t, err)
+		pemKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})
+		a := auth.NewAuthenticator(&auth.Config{
+			JWTKeys: []auth.JWTKey{{Algorithm: auth.AlgorithmRS256, PublicKey: &rsaKey.PublicKey}},
+		})
+
+		// act
+		_, err = a.Authenticate(bearer(signHS256(t, pemKey, "", validClaims())))
+
+		// assert
+		require.ErrorIs(t, err, auth.ErrTokenInvalid)
+	})
+
+	t.Run("error - unknown kid", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			JWTKeys: []auth.JWTKey{{Id: "k1", Algorithm: auth.AlgorithmHS256, Secret: []byte("secret")}},
+		})
+
+		// act
+		_, err := a.Authenticate(bearer(signHS256(t, []byte("secret"), "k2", validClaims())))
+
+		// assert
+		require.ErrorIs(t, err, auth.ErrTokenInvalid)
+	})
+
+	t.Run("error - expired", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			JWTKeys: []auth.JWTKey{{Algorithm: auth.AlgorithmHS256, Secret: []byte("secret")}},
+		})
+		claims := validClaims()
+		claims["exp"] = time.Now().Add(-time.Minute).Unix()
+
+		// act
+		_, err := a.Authenticate(bearer(signHS256(t, []byte("secret"), "", claims)))
+
+		// assert
+		require.ErrorIs(t, err, auth.ErrTokenExpired)
+	})
+
+	t.Run("error - not valid yet", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			JWTKeys: []auth.JWTKey{{Algorithm: auth.AlgorithmHS256, Secret: []byte("secret")}},
+		})
+		claims := validClaims()
+		claims["nbf"] = time.Now().Add(time.Minute).Unix()
+
+		// act
+		_, err := a.Authenticate(bearer(signHS256(t, []byte("secret"), "", claims)))
+
+		// assert
+		require.ErrorIs(t, err, auth.ErrTokenExpired)
+	})
+
+	t.Run("error - claims missing", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			JWTKeys: []auth.JWTKey{{Algorithm: auth.AlgorithmHS256, Secret: []byte("secret")}},
+		})
+
+		// act
+		_, errSub := a.Authenticate(bearer(signHS256(t, []byte("secret"), "", map[string]any{"exp": time.Now().Add(time.Hour).Unix()})))
+		_, errExp := a.Authenticate(bearer(signHS256(t, []byte("secret"), "", map[string]any{"sub": "alice"})))
+
+		// assert
+		require.ErrorIs(t, errSub, auth.ErrTokenInvalid)
+		require.ErrorIs(t, errExp, auth.ErrTokenInvalid)
+	})
+
+	t.Run("error - issuer and audience", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			JWTKeys:  []auth.JWTKey{{Algorithm: auth.AlgorithmHS256, Secret: []byte("secret")}},
+			Issuer:   "https://issuer.example",
+			Audience: "inventory",
+		})
+		wrongIssuer := validClaims()
+		wrongIssuer["iss"] = "https://other.example"
+		wrongIssuer["aud"] = "inventory"
+		wrongAudience := validClaims()
+		wrongAudience["iss"] = "https://issuer.example"
+		wrongAudience["aud"] = "billing"
+
+		// act
+		_, errIss := a.Authenticate(bearer(signHS256(t, []byte("secret"), "", wrongIssuer)))
+		_, errAud := a.Authenticate(bearer(signHS256(t, []byte("secret"), "", wrongAudience)))
+
+		// assert
+		require.ErrorIs(t, errIss, auth.ErrTokenInvalid)
+		require.ErrorIs(t, errAud, auth.ErrTokenInvalid)
+	})
+
+	t.Run("error - malformed", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(&auth.Config{
+			JWTKeys: []auth.JWTKey{{Algorithm: auth.AlgorithmHS256, Secret: []byte("secret")}},
+		})
+
+		// act
+		_, err := a.Authenticate(bearer("not.a-token"))
+
+		// assert
+		require.ErrorIs(t, err, auth.ErrTokenInvalid)
+	})
+}
+
+// Tests for ParseRSAPublicKeyPEM
+func TestParseRSAPublicKeyPEM(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	t.Run("success - PKIX", func(t *testing.T) {
+		// arrange
+		der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		require.NoError(t, err)
+
+		// act
+		pub, err := auth.ParseRSAPublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+		// assert
+		require.NoError(t, err)
+		require.True(t, key.PublicKey.Equal(pub))
+	})
+
+	t.Run("success - PKCS1", func(t *testing.T) {
+		// arrange
+		der := x509.MarshalPKCS1PublicKey(&key.PublicKey)
+
+		// act
+		pub, err := auth.ParseRSAPublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: der}))
+
+		// assert
+		require.NoError(t, err)
+		require.True(t, key.PublicKey.Equal(pub))
+	})
+
+	t.Run("error - no PEM block", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		_, err := auth.ParseRSAPublicKeyPEM([]byte("not a key"))
+
+		// assert
+		require.Error(t, err)
+	})
+}
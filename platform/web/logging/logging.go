@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+)
+
+const (
+	// FormatText is the format of the logs as key=value pairs.
+	FormatText = "text"
+	// FormatJSON is the format of the logs as JSON objects, one per line.
+	FormatJSON = "json"
+)
+
+// Config is the configuration of a logger.
+type Config struct {
+	// Format is the format of the logs: text or json, text if empty.
+	Format string
+	// Level is the minimum level of the logs, info by default.
+	Level slog.Level
+	// Output is where the logs are written, the standard error if nil.
+	Output io.Writer
+}
+
+// NewLogger creates a new structured logger.
+// - the records logged with a context carry the request id of the context
+func NewLogger(cfg *Config) (l *slog.Logger) {
+	// default config
+	defaultConfig := &Config{
+		Format: FormatText,
+		Level:  slog.LevelInfo,
+		Output: os.Stderr,
+	}
+	if cfg != nil {
+		if cfg.Format != "" {
+			defaultConfig.Format = cfg.Format
+		}
+		defaultConfig.Level = cfg.Level
+		if cfg.Output != nil {
+			defaultConfig.Output = cfg.Output
+		}
+	}
+
+	opts := &slog.HandlerOptions{Level: defaultConfig.Level}
+	var h slog.Handler
+	switch defaultConfig.Format {
+	case FormatJSON:
+		h = slog.NewJSONHandler(defaultConfig.Output, opts)
+	default:
+		h = slog.NewTextHandler(defaultConfig.Output, opts)
+	}
+
+	l = slog.New(contextHandler{Handler: h})
+	return
+}
+
+// contextHandler is a handler adding the request id of the context to the records.
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle adds the request id of ctx to a record, then handles it.
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler whose records carry attrs.
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a handler whose attributes are in a group.
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,58 @@
+package logging_test
+
+import (
+	"app/platform/web/logging"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for NewLogger
+func TestNewLogger(t *testing.T) {
+	t.Run("case 1: json records carry the request id of the context", func(t *testing.T) {
+		// arrange
+		var buf bytes.Buffer
+		l := logging.NewLogger(&logging.Config{Format: logging.FormatJSON, Output: &buf})
+		ctx := logging.ContextWithRequestID(context.Background(), "abc")
+
+		// act
+		l.InfoContext(ctx, "hello", "key", "value")
+
+		// assert
+		var rec map[string]any
+		err := json.Unmarshal(buf.Bytes(), &rec)
+		require.NoError(t, err)
+		require.Equal(t, "hello", rec["msg"])
+		require.Equal(t, "value", rec["key"])
+		require.Equal(t, "abc", rec["request_id"])
+	})
+
+	t.Run("case 2: text records without request id", func(t *testing.T) {
+		// arrange
+		var buf bytes.Buffer
+		l := logging.NewLogger(&logging.Config{Output: &buf})
+
+		// act
+		l.Info("hello", "key", "value")
+
+		// assert
+		require.Contains(t, buf.String(), "msg=hello key=value")
+		require.NotContains(t, buf.String(), "request_id")
+	})
+
+	t.Run("case 3: records under the level are dropped", func(t *testing.T) {
+		// arrange
+		var buf bytes.Buffer
+		l := logging.NewLogger(&logging.Config{Level: slog.LevelWarn, Output: &buf})
+
+		// act
+		l.Info("hello")
+
+		// assert
+		require.Empty(t, buf.String())
+	})
+}
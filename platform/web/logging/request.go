@@ -0,0 +1,122 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// HeaderRequestID is the header carrying the id of a request.
+const HeaderRequestID = "X-Request-Id"
+
+// requestIDPattern is the pattern of the request ids accepted from the clients.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// requestIDKey is the context key of the request id.
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the id of the request it serves.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request id carried by ctx, ok is false if there is none.
+func RequestIDFromContext(ctx context.Context) (id string, ok bool) {
+	id, ok = ctx.Value(requestIDKey{}).(string)
+	return
+}
+
+// RequestID is a middleware that attaches an id to each request, and sends it back in the X-Request-Id header.
+// - the id sent by a client is kept, so a request can be traced across services, a new one is generated otherwise
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(HeaderRequestID)
+		if !requestIDPattern.MatchString(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(HeaderRequestID, id)
+		next.ServeHTTP(w, r.WithContext(ContextWithRequestID(r.Context(), id)))
+	})
+}
+
+// newRequestID returns a new random request id.
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// causeKey is the context key of the cause of the failure of a request.
+type causeKey struct{}
+
+// cause is the cause of the failure of a request, set by its handler.
+type cause struct {
+	err error
+}
+
+// Error records the cause of the failure of the request of ctx, it is logged with the access log of the request.
+// - without access log, the cause is logged right away
+// - the causes recorded by a request failing only in part, e.g. some rows of an import, are all logged
+func Error(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	if c, ok := ctx.Value(causeKey{}).(*cause); ok {
+		c.err = errors.Join(c.err, err)
+		return
+	}
+	slog.ErrorContext(ctx, "request failed", "error", err)
+}
+
+// AccessLog is a middleware logging each request once served, with its status, size and duration.
+// - a request failing with a 5xx status is logged as an error, with the cause recorded by its handler
+// - a request with a cause recorded but no 5xx status failed in part, it is logged as a warning with the cause
+func AccessLog(l *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			c := &cause{}
+			ctx := context.WithValue(r.Context(), causeKey{}, c)
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			defer func() {
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+				attrs := []slog.Attr{
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.Int("status", status),
+					slog.Int("bytes", ww.BytesWritten()),
+					slog.Duration("duration", time.Since(start)),
+					slog.String("remote", r.RemoteAddr),
+				}
+				if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+					attrs = append(attrs, slog.String("route", rctx.RoutePattern()))
+				}
+
+				level := slog.LevelInfo
+				if c.err != nil {
+					level = slog.LevelWarn
+					attrs = append(attrs, slog.String("error", c.err.Error()))
+				}
+				if status >= http.StatusInternalServerError {
+					level = slog.LevelError
+				}
+				l.LogAttrs(ctx, level, "request", attrs...)
+			}()
+
+			next.ServeHTTP(ww, r.WithContext(ctx))
+		})
+	}
+}
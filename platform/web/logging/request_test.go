@@ -0,0 +1,130 @@
+package logging_test
+
+import (
+	"app/platform/web/logging"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for RequestID
+func TestRequestID(t *testing.T) {
+	// handler returning the request id of the context
+	hd := logging.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := logging.RequestIDFromContext(r.Context())
+		w.Write([]byte(id))
+	}))
+
+	t.Run("case 1: the id of the client is kept", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(logging.HeaderRequestID, "client-id.1")
+		res := httptest.NewRecorder()
+
+		// act
+		hd.ServeHTTP(res, req)
+
+		// assert
+		require.Equal(t, "client-id.1", res.Header().Get(logging.HeaderRequestID))
+		require.Equal(t, "client-id.1", res.Body.String())
+	})
+
+	t.Run("case 2: an id is generated without a valid one", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(logging.HeaderRequestID, "invalid id")
+		res := httptest.NewRecorder()
+
+		// act
+		hd.ServeHTTP(res, req)
+
+		// assert
+		id := res.Header().Get(logging.HeaderRequestID)
+		require.Len(t, id, 32)
+		require.Equal(t, id, res.Body.String())
+	})
+}
+
+// Tests for AccessLog
+func TestAccessLog(t *testing.T) {
+	t.Run("case 1: a request is logged with its status and id", func(t *testing.T) {
+		// arrange
+		var buf bytes.Buffer
+		l := logging.NewLogger(&logging.Config{Format: logging.FormatJSON, Output: &buf})
+		hd := logging.RequestID(logging.AccessLog(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("ok"))
+		})))
+		req := httptest.NewRequest(http.MethodPost, "/products", nil)
+		req.Header.Set(logging.HeaderRequestID, "abc")
+		res := httptest.NewRecorder()
+
+		// act
+		hd.ServeHTTP(res, req)
+
+		// assert
+		var rec map[string]any
+		err := json.Unmarshal(buf.Bytes(), &rec)
+		require.NoError(t, err)
+		require.Equal(t, "INFO", rec["level"])
+		require.Equal(t, "request", rec["msg"])
+		require.Equal(t, "POST", rec["method"])
+		require.Equal(t, "/products", rec["path"])
+		require.Equal(t, float64(http.StatusCreated), rec["status"])
+		require.Equal(t, float64(2), rec["bytes"])
+		require.Equal(t, "abc", rec["request_id"])
+		require.NotContains(t, rec, "error")
+	})
+
+	t.Run("case 2: a failed request is logged as an error with its cause", func(t *testing.T) {
+		// arrange
+		var buf bytes.Buffer
+		l := logging.NewLogger(&logging.Config{Format: logging.FormatJSON, Output: &buf})
+		hd := logging.AccessLog(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logging.Error(r.Context(), errors.New("connection refused"))
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/products", nil)
+		res := httptest.NewRecorder()
+
+		// act
+		hd.ServeHTTP(res, req)
+
+		// assert
+		var rec map[string]any
+		err := json.Unmarshal(buf.Bytes(), &rec)
+		require.NoError(t, err)
+		require.Equal(t, "ERROR", rec["level"])
+		require.Equal(t, float64(http.StatusInternalServerError), rec["status"])
+		require.Equal(t, "connection refused", rec["error"])
+	})
+
+	t.Run("case 3: a request failing in part is logged as a warning with its causes", func(t *testing.T) {
+		// arrange
+		var buf bytes.Buffer
+		l := logging.NewLogger(&logging.Config{Format: logging.FormatJSON, Output: &buf})
+		hd := logging.AccessLog(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logging.Error(r.Context(), errors.New("row 1: connection refused"))
+			logging.Error(r.Context(), errors.New("row 3: deadlock"))
+			w.WriteHeader(http.StatusOK)
+		}))
+		req := httptest.NewRequest(http.MethodPost, "/products/import", nil)
+		res := httptest.NewRecorder()
+
+		// act
+		hd.ServeHTTP(res, req)
+
+		// assert
+		var rec map[string]any
+		err := json.Unmarshal(buf.Bytes(), &rec)
+		require.NoError(t, err)
+		require.Equal(t, "WARN", rec["level"])
+		require.Equal(t, float64(http.StatusOK), rec["status"])
+		require.Equal(t, "row 1: connection refused\nrow 3: deadlock", rec["error"])
+	})
+}
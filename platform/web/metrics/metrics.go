@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// RouteOther is the route of the requests matching no route.
+	RouteOther = "other"
+	// OutcomeOk is the outcome of a call returning no error.
+	OutcomeOk = "ok"
+	// OutcomeError is the outcome of a call returning an error.
+	OutcomeError = "error"
+)
+
+// NewMetrics creates new metrics, on a registry of their own with the go runtime and process collectors.
+func NewMetrics() (m *Metrics) {
+	m = &Metrics{
+		reg: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Number of http requests served, by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Duration of the http requests, by method and route.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		repositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "repository_operation_duration_seconds",
+			Help:    "Duration of the calls to the repositories, by repository, operation and outcome.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"repository", "operation", "outcome"}),
+		storeDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "store_operation_duration_seconds",
+			Help:    "Duration of the reads and writes of the stores, by store, operation and outcome.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"store", "operation", "outcome"}),
+	}
+	m.reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.repositoryDuration,
+		m.storeDuration,
+	)
+	return
+}
+
+// Metrics are the metrics of the application, exposed in the prometheus text format.
+type Metrics struct {
+	// reg is the registry of the metrics.
+	reg *prometheus.Registry
+	// requests counts the http requests.
+	requests *prometheus.CounterVec
+	// requestDuration measures the duration of the http requests.
+	requestDuration *prometheus.HistogramVec
+	// repositoryDuration measures the duration of the calls to the repositories.
+	repositoryDuration *prometheus.HistogramVec
+	// storeDuration measures the duration of the reads and writes of the stores.
+	storeDuration *prometheus.HistogramVec
+}
+
+// RegisterDB registers the statistics of the connection pool of a database, e.g. the open and in use connections.
+func (m *Metrics) RegisterDB(name string, db *sql.DB) (err error) {
+	err = m.reg.Register(collectors.NewDBStatsCollector(db, name))
+	return
+}
+
+// Handler returns the handler exposing the metrics in the prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.reg, promhttp.HandlerOpts{Registry: m.reg})
+}
+
+// Middleware is a middleware counting the requests and measuring their duration.
+// - a request is labelled with its chi route pattern, e.g. /products/{id}, to keep the number of series bounded
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		defer func() {
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			route := RouteOther
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+
+			m.requests.WithLabelValues(r.Method, route, strconv.Itoa(status)).Inc()
+			m.requestDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+		}()
+
+		next.ServeHTTP(ww, r)
+	})
+}
+
+// ObserveRepository starts measuring the duration of the call to a method of a repository.
+func (m *Metrics) ObserveRepository(ctx context.Context, repository, method string) (c context.Context, done func(err error)) {
+	start := time.Now()
+	c = ctx
+	done = func(err error) {
+		m.repositoryDuration.WithLabelValues(repository, method, outcome(err)).Observe(time.Since(start).Seconds())
+	}
+	return
+}
+
+// ObserveStore starts measuring the duration of the call to a method of a store.
+func (m *Metrics) ObserveStore(store, method string) (done func(err error)) {
+	start := time.Now()
+	done = func(err error) {
+		m.storeDuration.WithLabelValues(store, method, outcome(err)).Observe(time.Since(start).Seconds())
+	}
+	return
+}
+
+// outcome returns the outcome of a call returning err.
+func outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+	return OutcomeOk
+}
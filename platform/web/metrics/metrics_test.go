@@ -0,0 +1,68 @@
+package metrics_test
+
+import (
+	"app/platform/web/metrics"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// scrape returns the metrics exposed by m in the prometheus text format.
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	res := httptest.NewRecorder()
+	m.Handler().ServeHTTP(res, req)
+	require.Equal(t, http.StatusOK, res.Code)
+	return res.Body.String()
+}
+
+// Tests for Metrics.Middleware
+func TestMetrics_Middleware(t *testing.T) {
+	t.Run("case 1: the requests are counted by route pattern", func(t *testing.T) {
+		// arrange
+		m := metrics.NewMetrics()
+		rt := chi.NewRouter()
+		rt.Use(m.Middleware)
+		rt.Get("/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		// act
+		for _, path := range []string{"/products/1", "/products/2", "/unknown"} {
+			rt.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+
+		// assert
+		out := scrape(t, m)
+		require.Contains(t, out, `http_requests_total{method="GET",route="/products/{id}",status="404"} 2`)
+		require.Contains(t, out, `http_requests_total{method="GET",route="other",status="404"} 1`)
+		require.Contains(t, out, `http_request_duration_seconds_count{method="GET",route="/products/{id}"} 2`)
+	})
+}
+
+// Tests for Metrics.ObserveRepository and Metrics.ObserveStore
+func TestMetrics_Observe(t *testing.T) {
+	t.Run("case 1: the calls are measured by outcome", func(t *testing.T) {
+		// arrange
+		m := metrics.NewMetrics()
+
+		// act
+		_, done := m.ObserveRepository(context.Background(), "product", "FindById")
+		done(nil)
+		_, done = m.ObserveRepository(context.Background(), "product", "FindById")
+		done(errors.New("not found"))
+		m.ObserveStore("product", "ReadAll")(nil)
+
+		// assert
+		out := scrape(t, m)
+		require.Contains(t, out, `repository_operation_duration_seconds_count{operation="FindById",outcome="ok",repository="product"} 1`)
+		require.Contains(t, out, `repository_operation_duration_seconds_count{operation="FindById",outcome="error",repository="product"} 1`)
+		require.Contains(t, out, `store_operation_duration_seconds_count{operation="ReadAll",outcome="ok",store="product"} 1`)
+	})
+}
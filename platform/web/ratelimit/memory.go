@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the full buckets are dropped from a memory store.
+const sweepInterval = time.Minute
+
+// NewStoreMemory creates a new in-memory store of token buckets.
+func NewStoreMemory() (s *StoreMemory) {
+	s = &StoreMemory{
+		buckets: make(map[string]*bucket),
+	}
+	return
+}
+
+// StoreMemory is an in-memory store of token buckets, the buckets of a single instance.
+// - a full bucket is the same as no bucket, so the full ones are dropped now and then to bound the memory
+type StoreMemory struct {
+	// mu guards the buckets.
+	mu sync.Mutex
+	// buckets are the buckets by key.
+	buckets map[string]*bucket
+	// swept is when the full buckets were last dropped.
+	swept time.Time
+}
+
+// bucket is a token bucket.
+type bucket struct {
+	// tokens is the number of tokens of the bucket at last.
+	tokens float64
+	// last is when the bucket was last taken from.
+	last time.Time
+	// full is when the bucket is full again.
+	full time.Time
+}
+
+// Take takes a token from the bucket of a key.
+func (s *StoreMemory) Take(ctx context.Context, key string, l Limit, now time.Time) (r Result, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	burst := float64(l.burst())
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		s.buckets[key] = b
+	}
+
+	// refill
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed*l.Rate)
+		b.last = now
+	}
+
+	// take
+	if b.tokens >= 1 {
+		b.tokens--
+		r.Allowed = true
+	} else {
+		r.RetryAfter = duration((1 - b.tokens) / l.Rate)
+	}
+	r.Remaining = int(b.tokens)
+	r.Reset = duration((burst - b.tokens) / l.Rate)
+	b.full = now.Add(r.Reset)
+	return
+}
+
+// sweep drops the full buckets, at most once per sweepInterval.
+func (s *StoreMemory) sweep(now time.Time) {
+	if now.Sub(s.swept) < sweepInterval {
+		return
+	}
+	s.swept = now
+
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// duration converts seconds to a duration.
+func duration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,67 @@
+package ratelimit_test
+
+import (
+	"app/platform/web/ratelimit"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for StoreMemory.Take
+func TestStoreMemory_Take(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := ratelimit.Limit{Rate: 1, Burst: 2}
+
+	t.Run("success - burst allowed then refilled at the rate", func(t *testing.T) {
+		// arrange
+		st := ratelimit.NewStoreMemory()
+
+		// act
+		r1, err1 := st.Take(context.Background(), "a", l, now)
+		r2, err2 := st.Take(context.Background(), "a", l, now)
+		r3, err3 := st.Take(context.Background(), "a", l, now.Add(500*time.Millisecond))
+		r4, err4 := st.Take(context.Background(), "a", l, now.Add(time.Second))
+
+		// assert
+		require.NoError(t, err1)
+		require.Equal(t, ratelimit.Result{Allowed: true, Remaining: 1, Reset: time.Second}, r1)
+		require.NoError(t, err2)
+		require.Equal(t, ratelimit.Result{Allowed: true, Remaining: 0, Reset: 2 * time.Second}, r2)
+		require.NoError(t, err3)
+		require.Equal(t, ratelimit.Result{Allowed: false, Remaining: 0, RetryAfter: 500 * time.Millisecond, Reset: 1500 * time.Millisecond}, r3)
+		require.NoError(t, err4)
+		require.Equal(t, ratelimit.Result{Allowed: true, Remaining: 0, Reset: 2 * time.Second}, r4)
+	})
+
+	t.Run("success - keys have buckets of their own", func(t *testing.T) {
+		// arrange
+		st := ratelimit.NewStoreMemory()
+		l := ratelimit.Limit{Rate: 1}
+
+		// act
+		ra, errA := st.Take(context.Background(), "a", l, now)
+		rb, errB := st.Take(context.Background(), "b", l, now)
+
+		// assert
+		require.NoError(t, errA)
+		require.True(t, ra.Allowed)
+		require.NoError(t, errB)
+		require.True(t, rb.Allowed)
+	})
+
+	t.Run("success - bucket never over its burst", func(t *testing.T) {
+		// arrange
+		st := ratelimit.NewStoreMemory()
+		_, err := st.Take(context.Background(), "a", l, now)
+		require.NoError(t, err)
+
+		// act
+		r, err := st.Take(context.Background(), "a", l, now.Add(time.Hour))
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, ratelimit.Result{Allowed: true, Remaining: 1, Reset: time.Second}, r)
+	})
+}
@@ -0,0 +1,235 @@
+package ratelimit
+
+import (
+	"app/platform/web/auth"
+	"app/platform/web/response"
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// HeaderLimit is the header carrying the number of requests a client is allowed at once.
+	HeaderLimit = "X-RateLimit-Limit"
+	// HeaderRemaining is the header carrying the number of requests a client has left.
+	HeaderRemaining = "X-RateLimit-Remaining"
+	// HeaderReset is the header carrying the seconds until a client is allowed its whole limit again.
+	HeaderReset = "X-RateLimit-Reset"
+)
+
+// Limit is the limit of the requests of a client, a token bucket.
+type Limit struct {
+	// Rate is the number of requests a client is allowed per second, the rate the bucket is refilled at.
+	// - a limit without rate is unlimited
+	Rate float64
+	// Burst is the number of requests a client is allowed at once, the capacity of the bucket, 1 if lower.
+	Burst int
+}
+
+// unlimited tells whether the limit lets every request through.
+func (l Limit) unlimited() bool {
+	return l.Rate <= 0
+}
+
+// burst returns the capacity of the bucket of the limit.
+func (l Limit) burst() int {
+	if l.Burst < 1 {
+		return 1
+	}
+	return l.Burst
+}
+
+// Group is a group of routes sharing a limit, each client has a bucket per group.
+type Group struct {
+	// Name identifies the buckets of the group, its pattern if empty.
+	Name string
+	// Method is the method of the requests, every method if empty.
+	Method string
+	// Pattern is the chi route pattern of the requests, e.g. /products/{id}.
+	// - a pattern ending with /* matches its root and every route under it
+	Pattern string
+	// Limit is the limit of the requests of a client to the routes of the group.
+	Limit Limit
+}
+
+// matches tells whether the group contains a request of a method on a route pattern.
+func (g Group) matches(method, pattern string) bool {
+	if g.Method != "" && g.Method != method {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(g.Pattern, "*"); ok {
+		return strings.HasPrefix(pattern, prefix) || pattern == strings.TrimSuffix(prefix, "/")
+	}
+	return g.Pattern == pattern
+}
+
+// name returns the name of the buckets of the group.
+func (g Group) name() string {
+	if g.Name != "" {
+		return g.Name
+	}
+	return g.Method + " " + g.Pattern
+}
+
+// Config is the configuration of a limiter.
+// - a configuration without limits disables the rate limiting
+type Config struct {
+	// Default is the limit of the requests matching no group, unlimited if empty.
+	Default Limit
+	// Groups are the groups of routes with a limit of their own, the first matching a request applies.
+	Groups []Group
+	// IP is the limit of the requests of an ip whoever performs them, checked by MiddlewareIP, unlimited if empty.
+	// - it bounds the requests an authentication is attempted for, the failing ones included
+	IP Limit
+	// Store keeps the buckets of the clients, in memory if nil.
+	Store Store
+}
+
+// NewLimiter creates a new limiter of requests.
+func NewLimiter(cfg *Config) (l *Limiter) {
+	// default config
+	defaultConfig := &Config{}
+	if cfg != nil {
+		defaultConfig.Default = cfg.Default
+		defaultConfig.Groups = cfg.Groups
+		defaultConfig.IP = cfg.IP
+		if cfg.Store != nil {
+			defaultConfig.Store = cfg.Store
+		}
+	}
+	if defaultConfig.Store == nil {
+		defaultConfig.Store = NewStoreMemory()
+	}
+
+	l = &Limiter{
+		cfg: defaultConfig,
+		now: time.Now,
+	}
+	return
+}
+
+// Limiter limits the rate of the requests of each client with token buckets.
+// - a client is the principal of an authenticated request, e.g. its api key, otherwise its ip
+type Limiter struct {
+	// cfg is the configuration of the limiter.
+	cfg *Config
+	// now returns the current time, the buckets are refilled up to it.
+	now func() time.Time
+}
+
+// group returns the group of a request, ok is false if it is of none.
+func (l *Limiter) group(r *http.Request) (g Group, ok bool) {
+	routes := chi.RouteContext(r.Context())
+	if routes == nil || routes.Routes == nil || len(l.cfg.Groups) == 0 {
+		return
+	}
+	path := r.URL.RawPath
+	if path == "" {
+		path = r.URL.Path
+	}
+	rctx := chi.NewRouteContext()
+	if !routes.Routes.Match(rctx, r.Method, path) {
+		return
+	}
+
+	pattern := rctx.RoutePattern()
+	for _, gr := range l.cfg.Groups {
+		if gr.matches(r.Method, pattern) {
+			return gr, true
+		}
+	}
+	return
+}
+
+// Middleware is a middleware rejecting with 429 the requests of the clients over their limit.
+// - the limit of the client is reported in the X-RateLimit-* headers, and when to retry in Retry-After
+// - a store failing lets the requests through, the api stays up without rate limiting
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, bucket := l.cfg.Default, "default"
+		if g, ok := l.group(r); ok {
+			limit, bucket = g.Limit, g.name()
+		}
+		l.serve(w, r, next, limit, bucket+"|"+Client(r))
+	})
+}
+
+// MiddlewareIP is a middleware rejecting with 429 the requests of the ips over the ip limit, whoever performs them.
+// - it runs ahead of the authentication, so a client is limited before its credentials are checked
+func (l *Limiter) MiddlewareIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l.serve(w, r, next, l.cfg.IP, "ip|"+ip(r))
+	})
+}
+
+// serve serves a request if the bucket of its key has a token left for a limit, otherwise rejects it with 429.
+func (l *Limiter) serve(w http.ResponseWriter, r *http.Request, next http.Handler, limit Limit, key string) {
+	if limit.unlimited() {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	res, err := l.cfg.Store.Take(r.Context(), key, limit, l.now())
+	if err != nil {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Set(HeaderLimit, strconv.Itoa(limit.burst()))
+	w.Header().Set(HeaderRemaining, strconv.Itoa(res.Remaining))
+	w.Header().Set(HeaderReset, seconds(res.Reset))
+	if !res.Allowed {
+		w.Header().Set("Retry-After", seconds(res.RetryAfter))
+		response.JSON(w, http.StatusTooManyRequests, "too many requests")
+		return
+	}
+
+	next.ServeHTTP(w, r)
+}
+
+// Client returns the client of a request: the principal performing it, otherwise its ip.
+func Client(r *http.Request) string {
+	if p, ok := auth.PrincipalFromContext(r.Context()); ok {
+		return p.Method + ":" + p.Subject
+	}
+	return "ip:" + ip(r)
+}
+
+// ip returns the ip of the client of a request.
+func ip(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// seconds formats a duration as whole seconds, rounded up.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// Result is the result of a client taking a token from its bucket.
+type Result struct {
+	// Allowed tells whether the request is allowed, a token was left.
+	Allowed bool
+	// Remaining is the number of tokens left.
+	Remaining int
+	// RetryAfter is the time until a token is refilled, 0 if the request is allowed.
+	RetryAfter time.Duration
+	// Reset is the time until the bucket is full.
+	Reset time.Duration
+}
+
+// Store keeps the token buckets of the clients.
+type Store interface {
+	// Take takes a token from the bucket of a key, refilled at the rate of the limit up to now.
+	// - a key without bucket starts with a full one
+	Take(ctx context.Context, key string, l Limit, now time.Time) (r Result, err error)
+}
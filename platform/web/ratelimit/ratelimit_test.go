@@ -0,0 +1,162 @@
+package ratelimit_test
+
+import (
+	"app/platform/web/auth"
+	"app/platform/web/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// newLimitedRouter returns a router of products and warehouses limited by a limiter.
+func newLimitedRouter(cfg *ratelimit.Config) *chi.Mux {
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	rt := chi.NewRouter()
+	rt.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if subject := r.Header.Get("X-Subject"); subject != "" {
+				r = r.WithContext(auth.ContextWithPrincipal(r.Context(), auth.Principal{Subject: subject, Method: auth.MethodAPIKey}))
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	rt.Use(ratelimit.NewLimiter(cfg).Middleware)
+	rt.Route("/products", func(r chi.Router) {
+		r.Get("/", ok)
+		r.Get("/{id}", ok)
+	})
+	rt.Get("/warehouses", ok)
+	return rt
+}
+
+// get performs a GET request on a router, from an ip and optionally by a principal.
+func get(rt http.Handler, path, ip, subject string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = ip + ":1234"
+	if subject != "" {
+		req.Header.Set("X-Subject", subject)
+	}
+	res := httptest.NewRecorder()
+	rt.ServeHTTP(res, req)
+	return res
+}
+
+// Tests for Limiter.Middleware
+func TestLimiter_Middleware(t *testing.T) {
+	t.Run("success - requests over the limit rejected", func(t *testing.T) {
+		// arrange
+		rt := newLimitedRouter(&ratelimit.Config{Default: ratelimit.Limit{Rate: 0.001, Burst: 2}})
+
+		// act
+		res1 := get(rt, "/warehouses", "192.0.2.1", "")
+		res2 := get(rt, "/warehouses", "192.0.2.1", "")
+		res3 := get(rt, "/warehouses", "192.0.2.1", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res1.Code)
+		require.Equal(t, "2", res1.Header().Get(ratelimit.HeaderLimit))
+		require.Equal(t, "1", res1.Header().Get(ratelimit.HeaderRemaining))
+		require.Equal(t, "1000", res1.Header().Get(ratelimit.HeaderReset))
+		require.Equal(t, http.StatusOK, res2.Code)
+		require.Equal(t, "0", res2.Header().Get(ratelimit.HeaderRemaining))
+		require.Equal(t, http.StatusTooManyRequests, res3.Code)
+		require.Equal(t, "1000", res3.Header().Get("Retry-After"))
+		require.JSONEq(t, `"too many requests"`, res3.Body.String())
+	})
+
+	t.Run("success - clients limited on their own", func(t *testing.T) {
+		// arrange
+		rt := newLimitedRouter(&ratelimit.Config{Default: ratelimit.Limit{Rate: 0.001, Burst: 1}})
+
+		// act
+		resIp1 := get(rt, "/warehouses", "192.0.2.1", "")
+		resIp2 := get(rt, "/warehouses", "192.0.2.2", "")
+		resKey1 := get(rt, "/warehouses", "192.0.2.1", "alice")
+		resKey2 := get(rt, "/warehouses", "192.0.2.1", "bob")
+		resKey3 := get(rt, "/warehouses", "192.0.2.2", "alice")
+
+		// assert
+		require.Equal(t, http.StatusOK, resIp1.Code)
+		require.Equal(t, http.StatusOK, resIp2.Code)
+		require.Equal(t, http.StatusOK, resKey1.Code)
+		require.Equal(t, http.StatusOK, resKey2.Code)
+		require.Equal(t, http.StatusTooManyRequests, resKey3.Code)
+	})
+
+	t.Run("success - route groups limited on their own", func(t *testing.T) {
+		// arrange
+		rt := newLimitedRouter(&ratelimit.Config{
+			Default: ratelimit.Limit{Rate: 0.001, Burst: 1},
+			Groups: []ratelimit.Group{
+				{Method: http.MethodGet, Pattern: "/products/*", Limit: ratelimit.Limit{Rate: 0.001, Burst: 2}},
+			},
+		})
+
+		// act
+		resProducts := get(rt, "/products", "192.0.2.1", "")
+		resProduct := get(rt, "/products/1", "192.0.2.1", "")
+		resOver := get(rt, "/products/2", "192.0.2.1", "")
+		resWarehouses := get(rt, "/warehouses", "192.0.2.1", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, resProducts.Code)
+		require.Equal(t, http.StatusOK, resProduct.Code)
+		require.Equal(t, http.StatusTooManyRequests, resOver.Code)
+		require.Equal(t, http.StatusOK, resWarehouses.Code)
+	})
+
+	t.Run("success - unlimited without limits", func(t *testing.T) {
+		// arrange
+		rt := newLimitedRouter(nil)
+
+		// act
+		var res *httptest.ResponseRecorder
+		for i := 0; i < 10; i++ {
+			res = get(rt, "/warehouses", "192.0.2.1", "")
+		}
+
+		// assert
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Empty(t, res.Header().Get(ratelimit.HeaderLimit))
+	})
+}
+
+// Tests for Limiter.MiddlewareIP
+func TestLimiter_MiddlewareIP(t *testing.T) {
+	t.Run("success - ips limited whoever performs their requests", func(t *testing.T) {
+		// arrange
+		lm := ratelimit.NewLimiter(&ratelimit.Config{IP: ratelimit.Limit{Rate: 0.001, Burst: 2}})
+		rt := lm.MiddlewareIP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }))
+
+		// act
+		resKey1 := get(rt, "/warehouses", "192.0.2.1", "alice")
+		resKey2 := get(rt, "/warehouses", "192.0.2.1", "bob")
+		resOver := get(rt, "/warehouses", "192.0.2.1", "carol")
+		resIp2 := get(rt, "/warehouses", "192.0.2.2", "alice")
+
+		// assert
+		require.Equal(t, http.StatusOK, resKey1.Code)
+		require.Equal(t, http.StatusOK, resKey2.Code)
+		require.Equal(t, http.StatusTooManyRequests, resOver.Code)
+		require.Equal(t, "1000", resOver.Header().Get("Retry-After"))
+		require.Equal(t, http.StatusOK, resIp2.Code)
+	})
+
+	t.Run("success - unlimited without ip limit", func(t *testing.T) {
+		// arrange
+		lm := ratelimit.NewLimiter(&ratelimit.Config{Default: ratelimit.Limit{Rate: 0.001, Burst: 1}})
+		rt := lm.MiddlewareIP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }))
+
+		// act
+		res1 := get(rt, "/warehouses", "192.0.2.1", "")
+		res2 := get(rt, "/warehouses", "192.0.2.1", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, res1.Code)
+		require.Equal(t, http.StatusOK, res2.Code)
+		require.Empty(t, res2.Header().Get(ratelimit.HeaderLimit))
+	})
+}
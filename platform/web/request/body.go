@@ -0,0 +1,38 @@
+package request
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// MaxBodySize is the size in bytes the body of a request is read up to, e.g. an import of tens of thousands of rows.
+// - a larger body is rejected with ErrRequestBodyTooLarge rather than read into memory
+const MaxBodySize = 8 << 20
+
+var (
+	// ErrRequestBodyTooLarge is used when the request body is larger than MaxBodySize.
+	ErrRequestBodyTooLarge = errors.New("request body too large")
+)
+
+// Body reads the body of a request, up to MaxBodySize
+func Body(r *http.Request) (body []byte, err error) {
+	body, err = io.ReadAll(limited(r))
+	if tooLarge(err) {
+		err = ErrRequestBodyTooLarge
+		return
+	}
+
+	return
+}
+
+// limited returns the body of a request failing once read past MaxBodySize
+func limited(r *http.Request) io.Reader {
+	return http.MaxBytesReader(nil, r.Body, MaxBodySize)
+}
+
+// tooLarge tells whether reading a body failed with err because it is larger than MaxBodySize
+func tooLarge(err error) bool {
+	var e *http.MaxBytesError
+	return errors.As(err, &e)
+}
@@ -0,0 +1,58 @@
+package request_test
+
+import (
+	"app/platform/web/request"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Body function
+func TestRequestBody(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		inputRequest := http.Request{
+			Body: io.NopCloser(strings.NewReader(`{"name":"test"}`)),
+		}
+		body, err := request.Body(&inputRequest)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, `{"name":"test"}`, string(body))
+	})
+
+	t.Run("success - body of the maximum size", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		inputRequest := http.Request{
+			Body: io.NopCloser(strings.NewReader(strings.Repeat("a", request.MaxBodySize))),
+		}
+		body, err := request.Body(&inputRequest)
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, body, request.MaxBodySize)
+	})
+
+	t.Run("error - too large", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		inputRequest := http.Request{
+			Body: io.NopCloser(strings.NewReader(strings.Repeat("a", request.MaxBodySize+1))),
+		}
+		_, err := request.Body(&inputRequest)
+
+		// assert
+		require.ErrorIs(t, err, request.ErrRequestBodyTooLarge)
+	})
+}
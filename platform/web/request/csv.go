@@ -0,0 +1,58 @@
+package request
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrRequestContentTypeNotCSV is used when the request content type is not text/csv.
+	ErrRequestContentTypeNotCSV = errors.New("request content type is not text/csv")
+	// ErrRequestCSVInvalid is used when the request csv is invalid.
+	ErrRequestCSVInvalid = errors.New("request csv invalid")
+)
+
+// CSV decodes a csv from request body into a list of records keyed by the header row
+func CSV(r *http.Request) (records []map[string]string, err error) {
+	// check content type
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "text/csv" {
+		err = ErrRequestContentTypeNotCSV
+		return
+	}
+
+	// get body
+	rows, err := csv.NewReader(limited(r)).ReadAll()
+	if tooLarge(err) {
+		err = ErrRequestBodyTooLarge
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("%w. %v", ErrRequestCSVInvalid, err)
+		return
+	}
+	if len(rows) == 0 {
+		err = fmt.Errorf("%w. missing header", ErrRequestCSVInvalid)
+		return
+	}
+
+	// serialize
+	header := rows[0]
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	records = make([]map[string]string, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		record := make(map[string]string, len(header))
+		for i, column := range header {
+			record[column] = strings.TrimSpace(row[i])
+		}
+		records = append(records, record)
+	}
+
+	return
+}
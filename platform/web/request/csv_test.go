@@ -0,0 +1,117 @@
+package request_test
+
+import (
+	"app/platform/web/request"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for CSV function
+func TestRequestCSV(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"text/csv; charset=utf-8"}},
+			Body:   io.NopCloser(strings.NewReader("name, quantity\ntest, 1\nother,2\n")),
+		}
+		records, err := request.CSV(&inputRequest)
+
+		// assert
+		expectedRecords := []map[string]string{
+			{"name": "test", "quantity": "1"},
+			{"name": "other", "quantity": "2"},
+		}
+		require.NoError(t, err)
+		require.Equal(t, expectedRecords, records)
+	})
+
+	t.Run("success - header only", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"text/csv"}},
+			Body:   io.NopCloser(strings.NewReader("name,quantity\n")),
+		}
+		records, err := request.CSV(&inputRequest)
+
+		// assert
+		expectedRecords := []map[string]string{}
+		require.NoError(t, err)
+		require.Equal(t, expectedRecords, records)
+	})
+
+	t.Run("error - content-type", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body:   io.NopCloser(strings.NewReader("name\ntest\n")),
+		}
+		records, err := request.CSV(&inputRequest)
+
+		// assert
+		require.ErrorIs(t, err, request.ErrRequestContentTypeNotCSV)
+		require.EqualError(t, err, "request content type is not text/csv")
+		require.Nil(t, records)
+	})
+
+	t.Run("error - empty body", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"text/csv"}},
+			Body:   io.NopCloser(strings.NewReader("")),
+		}
+		records, err := request.CSV(&inputRequest)
+
+		// assert
+		require.ErrorIs(t, err, request.ErrRequestCSVInvalid)
+		require.EqualError(t, err, "request csv invalid. missing header")
+		require.Nil(t, records)
+	})
+
+	t.Run("error - wrong number of fields", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"text/csv"}},
+			Body:   io.NopCloser(strings.NewReader("name,quantity\ntest\n")),
+		}
+		records, err := request.CSV(&inputRequest)
+
+		// assert
+		require.ErrorIs(t, err, request.ErrRequestCSVInvalid)
+		require.Nil(t, records)
+	})
+
+	t.Run("error - too large", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"text/csv"}},
+			Body:   io.NopCloser(strings.NewReader("name\n" + strings.Repeat("test\n", request.MaxBodySize/5+1))),
+		}
+		records, err := request.CSV(&inputRequest)
+
+		// assert
+		require.ErrorIs(t, err, request.ErrRequestBodyTooLarge)
+		require.Nil(t, records)
+	})
+}
@@ -0,0 +1,32 @@
+package request
+
+import (
+	"net/http"
+	"strings"
+)
+
+// IfMatch returns the entity tags of the If-Match header, unquoted.
+// - present is false when the header is not sent
+// - weak tags are skipped, If-Match compares the tags strongly so they never match
+// - an asterisk is returned as the tag "*"
+func IfMatch(r *http.Request) (tags []string, present bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return
+	}
+	present = true
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+		tag = strings.Trim(tag, `"`)
+		if tag == "" {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	return
+}
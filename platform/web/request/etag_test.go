@@ -0,0 +1,74 @@
+package request_test
+
+import (
+	"app/platform/web/request"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for IfMatch function
+func TestRequestIfMatch(t *testing.T) {
+	t.Run("success - single tag", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"If-Match": []string{`"3"`}},
+		}
+		tags, present := request.IfMatch(&inputRequest)
+
+		// assert
+		expectedTags := []string{"3"}
+		require.True(t, present)
+		require.Equal(t, expectedTags, tags)
+	})
+
+	t.Run("success - list with weak tag skipped and asterisk", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"If-Match": []string{`"1", W/"2", *`}},
+		}
+		tags, present := request.IfMatch(&inputRequest)
+
+		// assert
+		expectedTags := []string{"1", "*"}
+		require.True(t, present)
+		require.Equal(t, expectedTags, tags)
+	})
+
+	t.Run("success - only weak tags, present without tags", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"If-Match": []string{`W/"3"`}},
+		}
+		tags, present := request.IfMatch(&inputRequest)
+
+		// assert
+		require.True(t, present)
+		require.Nil(t, tags)
+	})
+
+	t.Run("success - header not sent", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{},
+		}
+		tags, present := request.IfMatch(&inputRequest)
+
+		// assert
+		require.False(t, present)
+		require.Nil(t, tags)
+	})
+}
@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // JSON decodes json from request body to ptr
@@ -15,8 +18,16 @@ var (
 	ErrRequestJSONInvalid = errors.New("request json invalid")
 )
 
+// tracer returns the tracer of the decoding of the request bodies, from the global provider.
+func tracer() trace.Tracer {
+	return otel.Tracer("app/platform/web/request")
+}
+
 // JSON decodes json from request body to ptr
 func JSON(r *http.Request, ptr any) (err error) {
+	_, span := tracer().Start(r.Context(), "request.JSON")
+	defer span.End()
+
 	// check content type
 	if r.Header.Get("Content-Type") != "application/json" {
 		err = ErrRequestContentTypeNotJSON
@@ -24,7 +35,11 @@ func JSON(r *http.Request, ptr any) (err error) {
 	}
 
 	// get body
-	err = json.NewDecoder(r.Body).Decode(ptr)
+	err = json.NewDecoder(limited(r)).Decode(ptr)
+	if tooLarge(err) {
+		err = ErrRequestBodyTooLarge
+		return
+	}
 	if err != nil {
 		err = fmt.Errorf("%w. %v", ErrRequestJSONInvalid, err)
 		return
@@ -73,4 +73,24 @@ func TestRequestJSON(t *testing.T) {
 		require.EqualError(t, err, "request json invalid. unexpected EOF")
 		require.Equal(t, expectedSchema, inputSchema)
 	})
+
+	t.Run("error - too large", func(t *testing.T) {
+		// arrange
+		type schema struct {
+			Name string `json:"name"`
+		}
+
+		// act
+		inputSchema := schema{}
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body: io.NopCloser(strings.NewReader(`{"name":"` + strings.Repeat("a", request.MaxBodySize) + `"}`)),
+		}
+		err := request.JSON(&inputRequest, &inputSchema)
+
+		// assert
+		expectedSchema := schema{}
+		require.ErrorIs(t, err, request.ErrRequestBodyTooLarge)
+		require.Equal(t, expectedSchema, inputSchema)
+	})
 }
\ No newline at end of file
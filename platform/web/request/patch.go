@@ -0,0 +1,451 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrRequestContentTypeNotPatch is used when the request content type is not a supported patch format.
+	ErrRequestContentTypeNotPatch = errors.New("request content type is not a supported patch format")
+	// ErrRequestPatchInvalid is used when the request patch is invalid or can not be applied.
+	ErrRequestPatchInvalid = errors.New("request patch invalid")
+)
+
+const (
+	// ContentTypeMergePatch is the content type of a json merge patch (RFC 7396).
+	ContentTypeMergePatch = "application/merge-patch+json"
+	// ContentTypeJSONPatch is the content type of a json patch (RFC 6902).
+	ContentTypeJSONPatch = "application/json-patch+json"
+)
+
+// Patch applies the patch of the request body to the document pointed by ptr.
+// - application/merge-patch+json and application/json are applied as a json merge patch (RFC 7396)
+// - application/json-patch+json is applied as a json patch (RFC 6902)
+// - a patch leaving fields unknown to the document is rejected
+func Patch(r *http.Request, ptr any) (err error) {
+	_, span := tracer().Start(r.Context(), "request.Patch")
+	defer span.End()
+
+	// check content type
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		err = ErrRequestContentTypeNotPatch
+		return
+	}
+	switch mediaType {
+	case ContentTypeMergePatch, ContentTypeJSONPatch, "application/json":
+	default:
+		err = ErrRequestContentTypeNotPatch
+		return
+	}
+
+	// get body
+	body, err := Body(r)
+	if errors.Is(err, ErrRequestBodyTooLarge) {
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("%w. %v", ErrRequestPatchInvalid, err)
+		return
+	}
+
+	// document
+	doc, err := json.Marshal(ptr)
+	if err != nil {
+		err = fmt.Errorf("%w. %v", ErrRequestPatchInvalid, err)
+		return
+	}
+	var target any
+	err = unmarshalNumber(doc, &target)
+	if err != nil {
+		err = fmt.Errorf("%w. %v", ErrRequestPatchInvalid, err)
+		return
+	}
+
+	// apply patch
+	switch mediaType {
+	case ContentTypeJSONPatch:
+		var ops []patchOperation
+		err = json.Unmarshal(body, &ops)
+		if err != nil {
+			err = fmt.Errorf("%w. %v", ErrRequestPatchInvalid, err)
+			return
+		}
+		target, err = jsonPatch(target, ops)
+	default:
+		var patch any
+		err = unmarshalNumber(body, &patch)
+		if err != nil {
+			err = fmt.Errorf("%w. %v", ErrRequestPatchInvalid, err)
+			return
+		}
+		target = mergePatch(target, patch)
+	}
+	if err != nil {
+		err = fmt.Errorf("%w. %v", ErrRequestPatchInvalid, err)
+		return
+	}
+
+	// decode patched document into ptr
+	doc, err = json.Marshal(target)
+	if err != nil {
+		err = fmt.Errorf("%w. %v", ErrRequestPatchInvalid, err)
+		return
+	}
+	v := reflect.ValueOf(ptr).Elem()
+	original := reflect.New(v.Type()).Elem()
+	original.Set(v)
+	v.SetZero()
+
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.DisallowUnknownFields()
+	err = dec.Decode(ptr)
+	if err != nil {
+		v.Set(original)
+		err = fmt.Errorf("%w. %v", ErrRequestPatchInvalid, err)
+		return
+	}
+
+	return
+}
+
+// mergePatch applies a json merge patch to a target, returning the patched target.
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any)
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+
+	return t
+}
+
+// patchOperation is an operation of a json patch.
+type patchOperation struct {
+	Op    string           `json:"op"`
+	Path  string           `json:"path"`
+	From  string           `json:"from"`
+	Value *json.RawMessage `json:"value"`
+}
+
+// jsonPatch applies the operations of a json patch to a target, returning the patched target.
+func jsonPatch(target any, ops []patchOperation) (doc any, err error) {
+	doc = target
+	for i, op := range ops {
+		doc, err = jsonPatchOperation(doc, op)
+		if err != nil {
+			err = fmt.Errorf("operation %d: %v", i, err)
+			return
+		}
+	}
+	return
+}
+
+// jsonPatchOperation applies a single operation of a json patch to a document.
+func jsonPatchOperation(doc any, op patchOperation) (res any, err error) {
+	path, err := jsonPointer(op.Path)
+	if err != nil {
+		return
+	}
+
+	// value
+	var value any
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			err = fmt.Errorf("missing value for %q", op.Op)
+			return
+		}
+		err = unmarshalNumber(*op.Value, &value)
+		if err != nil {
+			return
+		}
+	}
+
+	switch op.Op {
+	case "add":
+		res, err = pointerAdd(doc, path, value)
+	case "remove":
+		res, _, err = pointerRemove(doc, path)
+	case "replace":
+		// - the whole document is replaced by the value, there is nothing to remove it from
+		if len(path) == 0 {
+			res = value
+			return
+		}
+		res, _, err = pointerRemove(doc, path)
+		if err != nil {
+			return
+		}
+		res, err = pointerAdd(res, path, value)
+	case "move", "copy":
+		var from []string
+		from, err = jsonPointer(op.From)
+		if err != nil {
+			return
+		}
+		if op.Op == "move" {
+			if strings.HasPrefix(op.Path+"/", op.From+"/") && op.Path != op.From {
+				err = fmt.Errorf("can not move %q into one of its children", op.From)
+				return
+			}
+			res, value, err = pointerRemove(doc, from)
+		} else {
+			res = doc
+			value, err = pointerGet(doc, from)
+			if err == nil {
+				value, err = deepCopy(value)
+			}
+		}
+		if err != nil {
+			return
+		}
+		res, err = pointerAdd(res, path, value)
+	case "test":
+		var current any
+		current, err = pointerGet(doc, path)
+		if err != nil {
+			return
+		}
+		if !jsonEqual(current, value) {
+			err = fmt.Errorf("test failed for %q", op.Path)
+			return
+		}
+		res = doc
+	default:
+		err = fmt.Errorf("unknown op %q", op.Op)
+	}
+
+	return
+}
+
+// jsonPointer parses a json pointer (RFC 6901) into its reference tokens.
+func jsonPointer(pointer string) (tokens []string, err error) {
+	if pointer == "" {
+		return
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		err = fmt.Errorf("invalid pointer %q", pointer)
+		return
+	}
+
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(token, "~1", "/")
+		token = strings.ReplaceAll(token, "~0", "~")
+		tokens = append(tokens, token)
+	}
+	return
+}
+
+// pointerIndex parses an array index of a json pointer, which must be lower than length.
+func pointerIndex(token string, length int) (i int, err error) {
+	i, err = strconv.Atoi(token)
+	if err != nil || i < 0 || i >= length || (len(token) > 1 && token[0] == '0') {
+		err = fmt.Errorf("invalid index %q", token)
+		return
+	}
+	return
+}
+
+// pointerGet returns the value at the location of the tokens.
+func pointerGet(node any, tokens []string) (value any, err error) {
+	if len(tokens) == 0 {
+		value = node
+		return
+	}
+
+	switch n := node.(type) {
+	case map[string]any:
+		child, ok := n[tokens[0]]
+		if !ok {
+			err = fmt.Errorf("path %q not found", tokens[0])
+			return
+		}
+		value, err = pointerGet(child, tokens[1:])
+	case []any:
+		var i int
+		i, err = pointerIndex(tokens[0], len(n))
+		if err != nil {
+			return
+		}
+		value, err = pointerGet(n[i], tokens[1:])
+	default:
+		err = fmt.Errorf("path %q not found", tokens[0])
+	}
+	return
+}
+
+// pointerAdd adds a value at the location of the tokens, returning the updated node.
+func pointerAdd(node any, tokens []string, value any) (res any, err error) {
+	if len(tokens) == 0 {
+		res = value
+		return
+	}
+
+	switch n := node.(type) {
+	case map[string]any:
+		if len(tokens) == 1 {
+			n[tokens[0]] = value
+			res = n
+			return
+		}
+		child, ok := n[tokens[0]]
+		if !ok {
+			err = fmt.Errorf("path %q not found", tokens[0])
+			return
+		}
+		n[tokens[0]], err = pointerAdd(child, tokens[1:], value)
+		res = n
+	case []any:
+		if len(tokens) == 1 {
+			if tokens[0] == "-" {
+				res = append(n, value)
+				return
+			}
+			var i int
+			i, err = pointerIndex(tokens[0], len(n)+1)
+			if err != nil {
+				return
+			}
+			n = append(n, nil)
+			copy(n[i+1:], n[i:])
+			n[i] = value
+			res = n
+			return
+		}
+		var i int
+		i, err = pointerIndex(tokens[0], len(n))
+		if err != nil {
+			return
+		}
+		n[i], err = pointerAdd(n[i], tokens[1:], value)
+		res = n
+	default:
+		err = fmt.Errorf("path %q not found", tokens[0])
+	}
+	return
+}
+
+// pointerRemove removes the value at the location of the tokens, returning the updated node and the removed value.
+func pointerRemove(node any, tokens []string) (res any, removed any, err error) {
+	if len(tokens) == 0 {
+		err = errors.New("can not remove the whole document")
+		return
+	}
+
+	switch n := node.(type) {
+	case map[string]any:
+		child, ok := n[tokens[0]]
+		if !ok {
+			err = fmt.Errorf("path %q not found", tokens[0])
+			return
+		}
+		if len(tokens) == 1 {
+			delete(n, tokens[0])
+			res, removed = n, child
+			return
+		}
+		n[tokens[0]], removed, err = pointerRemove(child, tokens[1:])
+		res = n
+	case []any:
+		var i int
+		i, err = pointerIndex(tokens[0], len(n))
+		if err != nil {
+			return
+		}
+		if len(tokens) == 1 {
+			removed = n[i]
+			res = append(n[:i], n[i+1:]...)
+			return
+		}
+		n[i], removed, err = pointerRemove(n[i], tokens[1:])
+		res = n
+	default:
+		err = fmt.Errorf("path %q not found", tokens[0])
+	}
+	return
+}
+
+// deepCopy returns a copy of a json value that shares no maps or slices with it.
+func deepCopy(value any) (c any, err error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	err = unmarshalNumber(b, &c)
+	return
+}
+
+// unmarshalNumber decodes a json value, keeping its numbers as json.Number so none is rounded to a float64.
+func unmarshalNumber(b []byte, v *any) (err error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	err = dec.Decode(v)
+	if err != nil {
+		return
+	}
+	if _, e := dec.Token(); e != io.EOF {
+		err = errors.New("invalid data after top-level value")
+	}
+	return
+}
+
+// jsonEqual tells whether two json values are equal, numbers being compared by value, e.g. 1 and 1.0.
+func jsonEqual(a, b any) bool {
+	switch x := a.(type) {
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		fx, okx := new(big.Float).SetString(x.String())
+		fy, oky := new(big.Float).SetString(y.String())
+		return okx && oky && fx.Cmp(fy) == 0
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !jsonEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
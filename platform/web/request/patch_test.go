@@ -0,0 +1,230 @@
+package request_test
+
+import (
+	"app/platform/web/request"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Patch function
+func TestRequestPatch(t *testing.T) {
+	type nested struct {
+		Code string `json:"code"`
+	}
+	type schema struct {
+		Name   string   `json:"name"`
+		Price  float64  `json:"price"`
+		Tags   []string `json:"tags"`
+		Nested nested   `json:"nested"`
+	}
+
+	t.Run("success - merge patch", func(t *testing.T) {
+		// arrange
+		inputSchema := schema{Name: "test", Price: 1.5, Tags: []string{"a"}, Nested: nested{Code: "x"}}
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/merge-patch+json"}},
+			Body:   io.NopCloser(strings.NewReader(`{"price":2.25,"tags":null,"nested":{"code":"y"}}`)),
+		}
+		err := request.Patch(&inputRequest, &inputSchema)
+
+		// assert
+		expectedSchema := schema{Name: "test", Price: 2.25, Nested: nested{Code: "y"}}
+		require.NoError(t, err)
+		require.Equal(t, expectedSchema, inputSchema)
+	})
+
+	t.Run("success - application/json is a merge patch", func(t *testing.T) {
+		// arrange
+		inputSchema := schema{Name: "test", Price: 1.5}
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body:   io.NopCloser(strings.NewReader(`{"name":"other"}`)),
+		}
+		err := request.Patch(&inputRequest, &inputSchema)
+
+		// assert
+		expectedSchema := schema{Name: "other", Price: 1.5}
+		require.NoError(t, err)
+		require.Equal(t, expectedSchema, inputSchema)
+	})
+
+	t.Run("success - json patch", func(t *testing.T) {
+		// arrange
+		inputSchema := schema{Name: "test", Price: 1.5, Tags: []string{"a", "b"}, Nested: nested{Code: "x"}}
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/json-patch+json"}},
+			Body: io.NopCloser(strings.NewReader(`[
+				{"op":"test","path":"/name","value":"test"},
+				{"op":"replace","path":"/price","value":3},
+				{"op":"add","path":"/tags/1","value":"c"},
+				{"op":"remove","path":"/tags/0"},
+				{"op":"add","path":"/tags/-","value":"d"},
+				{"op":"copy","from":"/nested/code","path":"/name"}
+			]`)),
+		}
+		err := request.Patch(&inputRequest, &inputSchema)
+
+		// assert
+		expectedSchema := schema{Name: "x", Price: 3, Tags: []string{"c", "b", "d"}, Nested: nested{Code: "x"}}
+		require.NoError(t, err)
+		require.Equal(t, expectedSchema, inputSchema)
+	})
+
+	t.Run("success - large integers keep their precision", func(t *testing.T) {
+		// arrange
+		type counter struct {
+			Count int64 `json:"count"`
+			Total int64 `json:"total"`
+		}
+		inputSchema := counter{Count: 9007199254740993}
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/merge-patch+json"}},
+			Body:   io.NopCloser(strings.NewReader(`{"total":9007199254740995}`)),
+		}
+		err := request.Patch(&inputRequest, &inputSchema)
+
+		// assert
+		expectedSchema := counter{Count: 9007199254740993, Total: 9007199254740995}
+		require.NoError(t, err)
+		require.Equal(t, expectedSchema, inputSchema)
+	})
+
+	t.Run("success - json patch test compares numbers by value", func(t *testing.T) {
+		// arrange
+		inputSchema := schema{Name: "test", Price: 1.5}
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/json-patch+json"}},
+			Body: io.NopCloser(strings.NewReader(`[
+				{"op":"test","path":"/price","value":1.50},
+				{"op":"replace","path":"/name","value":"other"}
+			]`)),
+		}
+		err := request.Patch(&inputRequest, &inputSchema)
+
+		// assert
+		expectedSchema := schema{Name: "other", Price: 1.5}
+		require.NoError(t, err)
+		require.Equal(t, expectedSchema, inputSchema)
+	})
+
+	t.Run("success - json patch replacing the whole document", func(t *testing.T) {
+		// arrange
+		inputSchema := schema{Name: "test", Price: 1.5, Tags: []string{"a"}, Nested: nested{Code: "x"}}
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/json-patch+json"}},
+			Body: io.NopCloser(strings.NewReader(`[
+				{"op":"replace","path":"","value":{"name":"other","price":2,"tags":["b"],"nested":{"code":"y"}}},
+				{"op":"replace","path":"/price","value":3}
+			]`)),
+		}
+		err := request.Patch(&inputRequest, &inputSchema)
+
+		// assert
+		expectedSchema := schema{Name: "other", Price: 3, Tags: []string{"b"}, Nested: nested{Code: "y"}}
+		require.NoError(t, err)
+		require.Equal(t, expectedSchema, inputSchema)
+	})
+
+	t.Run("error - content-type", func(t *testing.T) {
+		// arrange
+		inputSchema := schema{Name: "test"}
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"text/plain"}},
+			Body:   io.NopCloser(strings.NewReader(`{"name":"other"}`)),
+		}
+		err := request.Patch(&inputRequest, &inputSchema)
+
+		// assert
+		expectedSchema := schema{Name: "test"}
+		require.ErrorIs(t, err, request.ErrRequestContentTypeNotPatch)
+		require.Equal(t, expectedSchema, inputSchema)
+	})
+
+	t.Run("error - unknown field", func(t *testing.T) {
+		// arrange
+		inputSchema := schema{Name: "test"}
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/merge-patch+json"}},
+			Body:   io.NopCloser(strings.NewReader(`{"name":"other","color":"red"}`)),
+		}
+		err := request.Patch(&inputRequest, &inputSchema)
+
+		// assert
+		expectedSchema := schema{Name: "test"}
+		require.ErrorIs(t, err, request.ErrRequestPatchInvalid)
+		require.Equal(t, expectedSchema, inputSchema)
+	})
+
+	t.Run("error - json patch failed test", func(t *testing.T) {
+		// arrange
+		inputSchema := schema{Name: "test"}
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/json-patch+json"}},
+			Body: io.NopCloser(strings.NewReader(`[
+				{"op":"replace","path":"/name","value":"other"},
+				{"op":"test","path":"/price","value":10}
+			]`)),
+		}
+		err := request.Patch(&inputRequest, &inputSchema)
+
+		// assert
+		expectedSchema := schema{Name: "test"}
+		require.ErrorIs(t, err, request.ErrRequestPatchInvalid)
+		require.EqualError(t, err, `request patch invalid. operation 1: test failed for "/price"`)
+		require.Equal(t, expectedSchema, inputSchema)
+	})
+
+	t.Run("error - json patch path not found", func(t *testing.T) {
+		// arrange
+		inputSchema := schema{Name: "test"}
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/json-patch+json"}},
+			Body:   io.NopCloser(strings.NewReader(`[{"op":"replace","path":"/color","value":"red"}]`)),
+		}
+		err := request.Patch(&inputRequest, &inputSchema)
+
+		// assert
+		require.ErrorIs(t, err, request.ErrRequestPatchInvalid)
+		require.Equal(t, schema{Name: "test"}, inputSchema)
+	})
+
+	t.Run("error - too large", func(t *testing.T) {
+		// arrange
+		inputSchema := schema{Name: "test"}
+
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/merge-patch+json"}},
+			Body:   io.NopCloser(strings.NewReader(`{"name":"` + strings.Repeat("a", request.MaxBodySize) + `"}`)),
+		}
+		err := request.Patch(&inputRequest, &inputSchema)
+
+		// assert
+		require.ErrorIs(t, err, request.ErrRequestBodyTooLarge)
+		require.Equal(t, schema{Name: "test"}, inputSchema)
+	})
+}
@@ -0,0 +1,11 @@
+package response
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// ETag sets the entity tag header of a response
+func ETag(w http.ResponseWriter, tag string) {
+	w.Header().Set("ETag", strconv.Quote(tag))
+}
@@ -0,0 +1,26 @@
+package response_test
+
+import (
+	"app/platform/web/response"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for ETag function
+func TestETag(t *testing.T) {
+	t.Run("quoted tag", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		rr := httptest.NewRecorder()
+		response.ETag(rr, "3")
+
+		// assert
+		expectedHeader := http.Header{"Etag": []string{`"3"`}}
+		require.Equal(t, expectedHeader, rr.Header())
+	})
+}
@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"app/platform/web/logging"
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer returns the tracer of the requests and the repositories, from the global provider.
+func tracer() trace.Tracer {
+	return otel.Tracer("app/platform/web/tracing")
+}
+
+// Middleware is a middleware running each request in a span, named after its method and chi route pattern.
+// - a request carrying a traceparent header continues the trace of its client
+// - a request failing with a 5xx status ends its span with an error
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer().Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPRequestMethodKey.String(r.Method), semconv.URLPath(r.URL.Path)),
+		)
+		defer span.End()
+		if id, ok := logging.RequestIDFromContext(ctx); ok {
+			span.SetAttributes(attribute.String("request.id", id))
+		}
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+			span.SetName(r.Method + " " + rctx.RoutePattern())
+			span.SetAttributes(semconv.HTTPRoute(rctx.RoutePattern()))
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
+
+// Observer is an observer running each call to a repository in a span, e.g. product.FindById.
+type Observer struct{}
+
+// ObserveRepository starts the span of the call to a method of a repository, a child of the span of ctx.
+func (o Observer) ObserveRepository(ctx context.Context, repository, method string) (c context.Context, done func(err error)) {
+	c, span := tracer().Start(ctx, repository+"."+method,
+		trace.WithAttributes(attribute.String("repository.name", repository), attribute.String("repository.method", method)),
+	)
+	done = func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+	return
+}
+
+// ObserveStore observes nothing: a store is called without context, its span could not join the trace of the request.
+// - the reads and writes of a store are part of the span of the repository calling it
+func (o Observer) ObserveStore(store, method string) (done func(err error)) {
+	done = func(err error) {}
+	return
+}
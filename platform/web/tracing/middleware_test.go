@@ -0,0 +1,100 @@
+package tracing_test
+
+import (
+	"app/platform/web/tracing"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// record sets a global tracer provider recording the spans for the time of a test.
+func record(t *testing.T) (sr *tracetest.SpanRecorder) {
+	t.Helper()
+	tp, pr := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(pr)
+	})
+
+	sr = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return
+}
+
+// Tests for Middleware
+func TestMiddleware(t *testing.T) {
+	t.Run("case 1: a request is traced, named after its route", func(t *testing.T) {
+		// arrange
+		sr := record(t)
+		rt := chi.NewRouter()
+		rt.Use(tracing.Middleware)
+		rt.Patch("/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+			_, done := tracing.Observer{}.ObserveRepository(r.Context(), "product", "Update")
+			done(nil)
+			w.WriteHeader(http.StatusOK)
+		})
+		req := httptest.NewRequest(http.MethodPatch, "/products/1", nil)
+		req.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+		// act
+		rt.ServeHTTP(httptest.NewRecorder(), req)
+
+		// assert
+		spans := sr.Ended()
+		require.Len(t, spans, 2)
+		repository, server := spans[0], spans[1]
+		require.Equal(t, "PATCH /products/{id}", server.Name())
+		require.Equal(t, "0af7651916cd43dd8448eb211c80319c", server.SpanContext().TraceID().String())
+		require.Equal(t, "b7ad6b7169203331", server.Parent().SpanID().String())
+		require.Equal(t, codes.Unset, server.Status().Code)
+		require.Equal(t, "product.Update", repository.Name())
+		require.Equal(t, server.SpanContext().SpanID(), repository.Parent().SpanID())
+	})
+
+	t.Run("case 2: a request failing with a 5xx status ends its span with an error", func(t *testing.T) {
+		// arrange
+		sr := record(t)
+		hd := tracing.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+
+		// act
+		hd.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/products", nil))
+
+		// assert
+		spans := sr.Ended()
+		require.Len(t, spans, 1)
+		require.Equal(t, "GET", spans[0].Name())
+		require.Equal(t, codes.Error, spans[0].Status().Code)
+	})
+}
+
+// Tests for Observer
+func TestObserver_ObserveRepository(t *testing.T) {
+	t.Run("case 1: a call failing ends its span with its error", func(t *testing.T) {
+		// arrange
+		sr := record(t)
+
+		// act
+		_, done := tracing.Observer{}.ObserveRepository(context.Background(), "product", "FindById")
+		done(errors.New("connection refused"))
+
+		// assert
+		spans := sr.Ended()
+		require.Len(t, spans, 1)
+		require.Equal(t, "product.FindById", spans[0].Name())
+		require.Equal(t, codes.Error, spans[0].Status().Code)
+		require.Equal(t, "connection refused", spans[0].Status().Description)
+	})
+}
@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	// ExporterStdout exports the spans to an output as JSON, e.g. to debug locally.
+	ExporterStdout = "stdout"
+	// ExporterOTLP exports the spans to an OpenTelemetry collector over OTLP/HTTP.
+	ExporterOTLP = "otlp"
+)
+
+// Config is the configuration of the tracing.
+// - a configuration without exporter records no span, the global tracer provider stays a no-op
+type Config struct {
+	// Exporter is where the spans are exported: stdout or otlp, none if empty.
+	Exporter string
+	// Endpoint is the host:port of the OTLP/HTTP collector, localhost:4318 if empty.
+	Endpoint string
+	// Insecure tells whether the collector is reached over plain http rather than https.
+	Insecure bool
+	// Output is where the stdout exporter writes the spans, the standard output if nil.
+	Output io.Writer
+	// ServiceName is the name of the service of the spans, app if empty.
+	ServiceName string
+}
+
+// SetUp sets the global tracer provider exporting the spans as configured, and the W3C trace context propagation.
+// - shutdown flushes the spans not exported yet, and stops the exports
+func SetUp(cfg *Config) (shutdown func(ctx context.Context) error, err error) {
+	// default config
+	defaultConfig := &Config{
+		Output:      os.Stdout,
+		ServiceName: "app",
+	}
+	if cfg != nil {
+		defaultConfig.Exporter = cfg.Exporter
+		defaultConfig.Endpoint = cfg.Endpoint
+		defaultConfig.Insecure = cfg.Insecure
+		if cfg.Output != nil {
+			defaultConfig.Output = cfg.Output
+		}
+		if cfg.ServiceName != "" {
+			defaultConfig.ServiceName = cfg.ServiceName
+		}
+	}
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	// exporter
+	var ex sdktrace.SpanExporter
+	switch defaultConfig.Exporter {
+	case "":
+		shutdown = func(ctx context.Context) error { return nil }
+		return
+	case ExporterStdout:
+		ex, err = stdouttrace.New(stdouttrace.WithWriter(defaultConfig.Output))
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if defaultConfig.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(defaultConfig.Endpoint))
+		}
+		if defaultConfig.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		ex, err = otlptracehttp.New(context.Background(), opts...)
+	default:
+		err = fmt.Errorf("tracing: unknown exporter %q", defaultConfig.Exporter)
+	}
+	if err != nil {
+		return
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(ex),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(defaultConfig.ServiceName))),
+	)
+	otel.SetTracerProvider(tp)
+	shutdown = tp.Shutdown
+	return
+}
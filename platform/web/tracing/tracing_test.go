@@ -0,0 +1,59 @@
+package tracing_test
+
+import (
+	"app/platform/web/tracing"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+// Tests for SetUp
+func TestSetUp(t *testing.T) {
+	// the global tracer provider is restored after each case
+	tp := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(tp) })
+
+	t.Run("case 1: without exporter, the global tracer provider is kept", func(t *testing.T) {
+		// arrange
+		before := otel.GetTracerProvider()
+
+		// act
+		shutdown, err := tracing.SetUp(nil)
+
+		// assert
+		require.NoError(t, err)
+		require.NoError(t, shutdown(context.Background()))
+		require.Equal(t, before, otel.GetTracerProvider())
+	})
+
+	t.Run("case 2: the stdout exporter writes the spans to its output", func(t *testing.T) {
+		// arrange
+		var buf bytes.Buffer
+		shutdown, err := tracing.SetUp(&tracing.Config{Exporter: tracing.ExporterStdout, Output: &buf, ServiceName: "test"})
+		require.NoError(t, err)
+
+		// act
+		_, span := otel.Tracer("test").Start(context.Background(), "span")
+		span.End()
+		err = shutdown(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), `"Name":"span"`)
+		require.Contains(t, buf.String(), `"Value":"test"`)
+	})
+
+	t.Run("case 3: an unknown exporter is rejected", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		_, err := tracing.SetUp(&tracing.Config{Exporter: "zipkin"})
+
+		// assert
+		require.EqualError(t, err, `tracing: unknown exporter "zipkin"`)
+	})
+}